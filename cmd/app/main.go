@@ -26,5 +26,9 @@ func main() {
 	*/
 	defer stop()
 
-	app.Run(ctx)
+	/* 시작 실패(조립 에러, OnStart 훅 에러)는 0이 아닌 코드로 종료 (log.Fatal은 defer를 실행하지 않으므로 먼저 stop) */
+	if err := app.Run(ctx); err != nil {
+		stop()
+		log.Fatalf("application failed: %v", err)
+	}
 }
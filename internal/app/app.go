@@ -6,19 +6,36 @@ package app
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/fx"  // DI 컨테이너 및 라이프사이클 관리
 	"go.uber.org/zap" // 고성능 구조화 로깅 패키지
 	
-	"generic-api-scaffold/internal/bus"   // 이벤트 버스(내부 컴포넌트 간 이벤트 전달)
+	"generic-api-scaffold/internal/audit"   // 감사/접근 이벤트 기록 및 SIEM(syslog CEF/LEEF) 전송
+	"generic-api-scaffold/internal/auth"    // API 토큰 인증 (운영자 역할)
+	"generic-api-scaffold/internal/bus"     // 이벤트 버스(내부 컴포넌트 간 이벤트 전달)
+	"generic-api-scaffold/internal/codec"   // 저널/브리지용 이벤트 직렬화 (protobuf | json)
+	"generic-api-scaffold/internal/control" // 제어 명령 정책 (속도 제한 등)
+	"generic-api-scaffold/internal/device"  // 장치 메타데이터 레지스트리 (site/model/firmware/location)
+	"generic-api-scaffold/internal/drops"   // 드롭/거절 이벤트 기록 (사유별 카운터 + 최근 기록)
+	"generic-api-scaffold/internal/group"   // 장치 그룹 집계(가상 장치) 및 그룹 경보
 	"generic-api-scaffold/internal/infra" // 외부 연동(Infrastructure) 예: Influx 저장 시뮬
+	"generic-api-scaffold/internal/journal" // 수집 이벤트 로컬 영속 저널 (at-least-once)
+	"generic-api-scaffold/internal/latest" // 장치별 최신값 저장소 (EventBus 구독)
+	"generic-api-scaffold/internal/price" // 전력 가격 피드 (요금표 / day-ahead 시장)
+	"generic-api-scaffold/internal/sim"   // 과거 텔레메트리 기반 what-if 시뮬레이터
+	"generic-api-scaffold/internal/supervisor" // 모듈 단위 재시작 감독 (백오프 + 재시작 예산)
+	"generic-api-scaffold/internal/tracing"    // 분산 추적 (OpenTelemetry, OTLP 내보내기)
+	"generic-api-scaffold/internal/webhook"    // 웹훅 구독 및 서명된 이벤트 전달
 )
 
 /*
  * Run : main 함수에서 호출되는 애플리케이션 구동 함수
  * Fx 컨테이너(fx.New)를 통해 모든 구성요소를 등록(Provide) 및 실행(Invoke)합니다.
+ * 조립 실패(fx.New)나 OnStart 훅 실패(예: APP_INFLUX_STARTUP=fail인데 저장소에 닿지 않음)는 에러로 반환하여
+ * main이 0이 아닌 코드로 종료하게 합니다. (fx는 시작 실패 시 이미 시작한 훅을 되돌림)
  */
-func Run(ctx context.Context) {
+func Run(ctx context.Context) error {
 	app := fx.New(
 
 		/* 
@@ -31,34 +48,92 @@ func Run(ctx context.Context) {
 		fx.Provide(
 			NewLogger,
 			
+			infra.NewMetricsRegistry, // Prometheus 레지스트리 (관리 서버 /metrics)
+			drops.NewRecorder,
+			supervisor.NewSupervisor,
+			audit.NewRecorder,
 			bus.NewEventBus,
+			tracing.NewTracerProvider, // OpenTelemetry 제공자 (APP_OTEL_ENDPOINT 지정 시 OTLP로 내보냄)
+			bus.AsInterceptor(bus.NewTraceInterceptor), // 버스 발행/전달 Debug 추적 (APP_BUS_TRACE=true일 때)
+			bus.AsInterceptor(bus.NewOtelInterceptor),  // 버스 발행/처리 스팬 (HTTP 요청 트레이스에 연결)
+			latest.NewStore, // 장치별 최신값 저장소 (/api/devices/{id}/latest)
+			codec.NewCodec,
+			newEventSchemas, // 이벤트 스키마 레지스트리 (저널/브리지 직렬화, 버전 마이그레이션)
+			journal.NewJournal, // 수집 이벤트 영속 저널 (APP_JOURNAL_PATH 지정 시)
+			group.NewRegistry,
+			group.NewAlerter,
+			webhook.NewManager,
+			control.NewLimiter,
+			control.NewOverrideManager,
+			auth.NewAuthenticator,
+			control.NewDispatcher,
+			control.NewRolloutManager,
 			infra.NewHTTPServer,
-			infra.NewInfluxRepo, // ★ 추가: *infra.InfluxRepo 제공
+			infra.NewAdminServer, // 운영/진단용 서버 (metrics, pprof, config, loglevel — 내부 포트 전용)
+			device.NewRegistry, // 장치 메타데이터 레지스트리 (APP_DEVICE_REGISTRY_FILE)
+			// 저장 전 태그 보강 : 장치 레지스트리의 site/model/firmware/location을 포인트 태그로 (group:"tag_enrichers")
+			infra.AsTagEnricher(func(r *device.Registry) infra.TagEnricher { return r }),
+			infra.NewTagEnrichment,
+			infra.NewInfluxStore, // APP_INFLUX_VERSION에 따라 InfluxDB 1.x/2.x/3 저장소 제공
+			// 시계열 저장소 인터페이스 (다른 백엔드나 테스트용 가짜 저장소로 교체 가능)
+			func(s infra.InfluxStore) infra.TimeSeriesStore { return s },
 			NewCollector,
+			// 수동 수집 API(/api/collect)는 Collector를 infra.ManualCollector로 사용
+			func(c *Collector) infra.ManualCollector { return c },
+
+			// 시뮬레이터는 Influx 저장소를 과거 텔레메트리 조회원(sim.Source)으로 사용
+			func(s infra.InfluxStore) sim.Source { return s },
+			// 내보내기 API(/api/export)도 Influx 저장소에서 청크 단위로 읽음
+			func(s infra.InfluxStore) infra.Exporter { return s },
+			price.NewFeed,
+			sim.NewRunner,
     	),
+
+		/* /readyz에서 사용할 의존성 검사 등록 (group:"readiness") */
+		readinessChecks(),
+
+		/* /api/search에서 사용할 검색 공급원 등록 (group:"search") */
+		searchSources(),
+
+		/* Collector가 주기마다 호출할 수집 공급원 등록 (group:"sources") */
+		collectorSources(),
+
+		/* 빌드 프로필(full | edge)에 따른 선택 모듈 등록 */
+		modulesOption(),
 		
 		
 		/* Invoke : 앱 시작 시 실행할 초기 함수 등록 */
-		fx.Invoke(registerHandlers, infra.RegisterHooks),
+		fx.Invoke(registerHandlers, infra.RegisterHooks, infra.RegisterAdminHooks, auditOverrides),
 		
 		
 	)
 
+	/* 조립 실패 : 생성자 에러, 누락된 의존성 등 */
+	if err := app.Err(); err != nil {
+		return fmt.Errorf("build application: %w", err)
+	}
+
 	/* 앱 시작 : 내부적으로 모든 OnStart 훅을 실행 */
-	_ = app.Start(ctx)
+	if err := app.Start(ctx); err != nil {
+		return fmt.Errorf("start application: %w", err)
+	}
 
 	/* ctx.Done() : OS 종료 신호(SIGINT, SIGTERM) 수신 시까지 대기 */
 	<-ctx.Done()
 
 	/* 앱 종료 : 내부적으로 모든 OnStop 훅을 실행하여 자원 정리 */
 	_ = app.Stop(context.Background())
+	return nil
 }
 
 /*
  * NewLogger : 개발용 로거(Logger) 생성 함수
- * zap.NewDevelopment() → 사람이 보기 쉬운 포맷으로 로그를 출력
+ * zap.NewDevelopmentConfig() → 사람이 보기 쉬운 포맷으로 로그를 출력
+ * 로그 레벨(zap.AtomicLevel)을 함께 반환하여 관리 서버(/loglevel)에서 런타임에 변경할 수 있게 함
  * fx.Provide(NewLogger)를 통해 자동 주입 가능
  */
-func NewLogger() (*zap.Logger, error) {
-	return zap.NewDevelopment()
+func NewLogger() (*zap.Logger, zap.AtomicLevel, error) {
+	cfg := zap.NewDevelopmentConfig()
+	log, err := cfg.Build()
+	return log, cfg.Level, err
 }
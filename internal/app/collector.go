@@ -1,49 +1,170 @@
 /*
  * Collector : 주기적으로 데이터를 수집하고, 그 결과를 이벤트로 발행하는 컴포넌트입니다.
+ *  - 실제 수집은 등록된 수집 공급원(source.Source, group:"sources")이 담당합니다.
+ *  - 수집 주기 : 기본 APP_COLLECTOR_INTERVAL, 공급원별 APP_COLLECTOR_SOURCE_INTERVALS, 장치별 레지스트리의 interval
+ *      루프는 모든 주기의 최대공약수(최소 주기 이상)마다 깨어나 주기가 된 공급원만 호출합니다.
  */
 package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/fx"  // 애플리케이션 생명주기(Lifecycle) 훅 제공
 	"go.uber.org/zap" // 구조화 로그 출력 라이브러리
 
 	"generic-api-scaffold/internal/bus"   // 이벤트 정의 및 전달
-	"generic-api-scaffold/internal/infra" // 저장소(Infrastructure) 계층
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/device" // 장치별 수집 주기
+	"generic-api-scaffold/internal/source" // 수집 공급원
+	"generic-api-scaffold/internal/supervisor" // 모듈 재시작 감독
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
 )
 
 /*
  * Collector 구조체
  *  - 역할 : Spring의 @Service 또는 Bean 개념에 해당
- *  - 필드 : 의존성 주입 대상 (Logger, EventBus, InfluxRepo)
+ *  - 필드 : 의존성 주입 대상 (Logger, EventBus, 수집 공급원)
+ *    (저장은 저장소 계층이 버스를 구독하여 처리하므로 Collector는 저장소에 의존하지 않음)
+ *  - startedAt / lastTick : 수집 루프가 실제로 돌고 있는지 readiness 검사에서 확인하기 위한 시각(UnixNano)
  */
 type Collector struct {
-	log  *zap.Logger
-	bus  *bus.EventBus
-	repo *infra.InfluxRepo
+	log     *zap.Logger
+	bus     *bus.EventBus
+	sources []source.Source // APP_COLLECTOR_SOURCES로 고른 공급원 (등록 순서)
+
+	tick        time.Duration            // 루프가 깨어나는 주기
+	srcInterval map[string]time.Duration // 공급원 이름 → 수집 주기 (기본 주기 포함, 고른 공급원 모두)
+	devInterval map[string]time.Duration // 장치 → 수집 주기 (레지스트리에 interval이 있는 장치만)
+
+	startedAt atomic.Int64
+	lastTick  atomic.Int64
+}
+
+// CollectorParams : NewCollector가 fx로부터 주입받는 의존성 (공급원은 group:"sources")
+type CollectorParams struct {
+	fx.In
+
+	Log     *zap.Logger
+	Bus     *bus.EventBus
+	Devices *device.Registry
+	Sources []source.Source `group:"sources"`
 }
 
 /*
  * NewCollector : fx가 호출하는 Collector 생성자
  *  - Java Lombok의 @RequiredArgsConstructor 또는 Spring의 @Autowired 생성자와 동일한 개념
+ *  - APP_COLLECTOR_SOURCES : 사용할 공급원 이름 목록 (비어 있으면 등록된 모든 공급원)
+ *      등록되지 않은 이름, 이름이 겹치는 공급원, 고른 공급원이 하나도 없으면 Fatal
+ *  - APP_COLLECTOR_INTERVAL : 기본 수집 주기 (기본 3s)
+ *  - APP_COLLECTOR_SOURCE_INTERVALS : 공급원별 주기, "공급원:주기" 쉼표 구분 (예: "modbus:10s,snmp:1m")
+ *  - APP_COLLECTOR_MIN_INTERVAL : 허용하는 가장 짧은 주기 (기본 1s, 장치가 버틸 수 있는 폴링 한계)
+ *      기본·공급원별·장치별 주기가 이보다 짧거나, 고르지 않은 공급원의 주기를 지정하면 Fatal
  *  - 반환 : *Collector
  */
-func NewCollector(log *zap.Logger, b *bus.EventBus, r *infra.InfluxRepo) *Collector {
-	return &Collector{log: log, bus: b, repo: r}
+func NewCollector(p CollectorParams) *Collector {
+	byName := make(map[string]source.Source, len(p.Sources))
+	names := make([]string, 0, len(p.Sources))
+	for _, src := range p.Sources {
+		if _, dup := byName[src.Name()]; dup {
+			p.Log.Fatal("duplicate collector source name", zap.String("source", src.Name()))
+		}
+		byName[src.Name()] = src
+		names = append(names, src.Name())
+	}
+	selected := p.Sources
+	if want := config.List("APP_COLLECTOR_SOURCES", nil); len(want) > 0 {
+		selected = make([]source.Source, 0, len(want))
+		for _, name := range want {
+			src, ok := byName[name]
+			if !ok {
+				p.Log.Fatal("unknown source in APP_COLLECTOR_SOURCES", zap.String("source", name), zap.Strings("registered", names))
+			}
+			selected = append(selected, src)
+		}
+	}
+	if len(selected) == 0 {
+		p.Log.Fatal("no collector sources registered")
+	}
+
+	c := &Collector{log: p.Log, bus: p.Bus, sources: selected,
+		srcInterval: make(map[string]time.Duration, len(selected)), devInterval: p.Devices.Intervals()}
+	floor := config.Duration(p.Log, "APP_COLLECTOR_MIN_INTERVAL", time.Second)
+	if floor <= 0 {
+		p.Log.Fatal("APP_COLLECTOR_MIN_INTERVAL must be positive", zap.Duration("value", floor))
+	}
+	def := config.Duration(p.Log, "APP_COLLECTOR_INTERVAL", 3*time.Second)
+	if def < floor {
+		p.Log.Fatal("APP_COLLECTOR_INTERVAL is shorter than APP_COLLECTOR_MIN_INTERVAL", zap.Duration("value", def), zap.Duration("min", floor))
+	}
+	for _, src := range selected {
+		c.srcInterval[src.Name()] = def
+	}
+	for _, item := range config.List("APP_COLLECTOR_SOURCE_INTERVALS", nil) {
+		name, v, ok := strings.Cut(item, ":")
+		d, err := time.ParseDuration(v)
+		if !ok || err != nil {
+			p.Log.Fatal("invalid APP_COLLECTOR_SOURCE_INTERVALS entry, expected source:duration", zap.String("entry", item))
+		}
+		if _, ok := c.srcInterval[name]; !ok {
+			p.Log.Fatal("APP_COLLECTOR_SOURCE_INTERVALS names a source that is not collected", zap.String("source", name))
+		}
+		if d < floor {
+			p.Log.Fatal("source interval is shorter than APP_COLLECTOR_MIN_INTERVAL", zap.String("source", name), zap.Duration("value", d), zap.Duration("min", floor))
+		}
+		c.srcInterval[name] = d
+	}
+	for id, d := range c.devInterval {
+		if d < floor {
+			p.Log.Fatal("device interval is shorter than APP_COLLECTOR_MIN_INTERVAL", zap.String("device", id), zap.Duration("value", d), zap.Duration("min", floor))
+		}
+	}
+	c.tick = c.tickInterval(floor)
+	p.Log.Info("collector intervals", zap.Duration("tick", c.tick), zap.Duration("default", def), zap.Int("device_overrides", len(c.devInterval)))
+	return c
+}
+
+// tickInterval : 모든 수집 주기의 최대공약수 (최소 주기보다 짧으면 최소 주기, 이때 주기는 tick 단위로 맞춰짐)
+func (c *Collector) tickInterval(floor time.Duration) time.Duration {
+	var g time.Duration
+	gcd := func(d time.Duration) {
+		for a, b := g, d; ; {
+			if b == 0 {
+				g = a
+				return
+			}
+			a, b = b, a%b
+		}
+	}
+	for _, d := range c.srcInterval {
+		gcd(d)
+	}
+	for _, d := range c.devInterval {
+		gcd(d)
+	}
+	if g < floor {
+		return floor
+	}
+	return g
 }
 /*
  * registerHandlers : Collector의 시작(Start)·정지(Stop) 시점을 fx.Lifecycle에 등록
  *  - fx.Invoke(registerHandlers)로 실행되며, 애플리케이션 구동 시 자동으로 훅(Append) 추가
- *  - OnStart : Collector의 주기적 수집 루프를 고루틴으로 시작
- *  - OnStop  : 컨텍스트 종료 시 루프를 정리하고 로그 출력
+ *  - OnStart : Collector의 주기적 수집 루프를 Supervisor 감독 하에 시작
+ *              (루프가 패닉 등으로 죽으면 백오프 후 수집 루프만 다시 시작)
+ *  - OnStop  : 루프 정리는 Supervisor가 컨텍스트 취소로 처리하고, 여기서는 로그만 출력
  */
-func registerHandlers(lc fx.Lifecycle, c *Collector) {
+func registerHandlers(lc fx.Lifecycle, c *Collector, sv *supervisor.Supervisor) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			go c.Start(ctx)
-			return nil
+			return sv.Go("collector", func(ctx context.Context) error {
+				c.Start(ctx)
+				return nil
+			})
 		},
 		OnStop: func(ctx context.Context) error {
 			c.log.Info("collector stopped")
@@ -54,16 +175,19 @@ func registerHandlers(lc fx.Lifecycle, c *Collector) {
 
 /*
  * Start : Collector의 메인 루프
- *  - 3초 주기로 데이터 수집을 시뮬레이션하고, 이벤트 버스에 발행
+ *  - tick 주기로 깨어나 주기가 된 공급원에서 데이터를 수집하고, 이벤트 버스에 발행
  *  - ctx.Done() 신호가 오면 루프를 종료하고 리소스를 정리
  *  - 내부 동작 :
- *     ① time.Ticker 생성 (3초 주기)
- *     ② 매 주기마다 임의의 데이터(temp=23.5)를 생성
- *     ③ bus.Publish()를 통해 DataCollectedEvent 발행
+ *     ① time.Ticker 생성 (tick 주기)
+ *     ② 매 tick마다 주기가 된 공급원을 차례로 호출하여 샘플 수집 (실패한 공급원은 경고 로그 후 건너뜀)
+ *     ③ bus.Publish()를 통해 샘플마다 DataCollectedEvent 발행
  */
 func (c *Collector) Start(ctx context.Context) {
-	ticker := time.NewTicker(3 * time.Second)
+	ticker := time.NewTicker(c.tick)
 	defer ticker.Stop()
+	sch := newSchedule()
+
+	c.startedAt.Store(time.Now().UnixNano())
 
 	for {
 		select {
@@ -71,13 +195,175 @@ func (c *Collector) Start(ctx context.Context) {
 			c.log.Info("collector exit")
 			return
 		case <-ticker.C:
-			c.log.Info("collecting data...")
+			now := time.Now()
+			c.lastTick.Store(now.UnixNano())
+			if err := c.collectDue(ctx, sch, now); err != nil {
+				c.log.Warn("collection failed", zap.Error(err))
+			}
+		}
+	}
+}
 
-			data := map[string]float64{"temp": 23.5} // 샘플 데이터
-			c.bus.Publish(bus.DataCollectedEvent{
-				DeviceID: "A1",
-				Values:   data,
-			})
+/*
+ * CollectNow : 주기와 무관하게 즉시 한 번 수집하여 발행 (POST /api/collect)
+ *  - deviceID가 있으면 그 장치의 샘플만 발행, 비어 있으면 모든 샘플을 발행
+ *  - 주기 루프의 tick 시각은 갱신하지 않음 (readiness 판단은 주기 루프 기준)
+ *  - 반환 : 발행된 이벤트 (여럿이면 첫 번째), 해당 장치를 보고한 공급원이 없으면 에러
+ */
+func (c *Collector) CollectNow(ctx context.Context, deviceID string) (bus.DataCollectedEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return bus.DataCollectedEvent{}, err
+	}
+	c.log.Info("manual collection requested", zap.String("device", deviceID))
+	events, err := c.collect(ctx, deviceID)
+	if len(events) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no source reported device %q", deviceID)
 		}
+		return bus.DataCollectedEvent{}, err
+	}
+	return events[0], nil
+}
+
+/*
+ * schedule : 수집 루프의 다음 수집 시각 (루프가 다시 시작되면 새로 만들어 모두 즉시 수집)
+ *  - owner : 장치 → 그 장치를 보고한 공급원 (장치별 주기가 공급원 주기보다 짧으면 그 장치 때문에 공급원을 호출)
+ */
+type schedule struct {
+	source map[string]time.Time
+	device map[string]time.Time
+	owner  map[string]string
+}
+
+func newSchedule() *schedule {
+	return &schedule{source: make(map[string]time.Time), device: make(map[string]time.Time), owner: make(map[string]string)}
+}
+
+/*
+ * collectDue : 주기가 된 공급원만 수집하여 발행 (수집 루프)
+ *  - 공급원은 자기 주기가 되었거나, 그 공급원이 보고하는 장치 중 장치별 주기가 된 장치가 있으면 호출
+ *  - 장치별 주기가 있는 장치의 샘플은 그 장치의 주기가 되었을 때만, 나머지 장치의 샘플은 공급원의 주기가 되었을 때만 발행
+ *    (장치 때문에 일찍 호출된 폴링 공급원의 다른 장치 샘플은 버림, 공급원 주기가 무시되지 않도록)
+ *  - 버퍼형 공급원(source.Buffered)은 꺼낸 샘플을 되돌릴 수 없으므로 언제 호출되든 모든 샘플을 발행
+ *  - tick 시각이 조금 늦거나 빨라도 주기를 건너뛰지 않도록 tick의 절반까지는 주기가 된 것으로 봄
+ */
+func (c *Collector) collectDue(ctx context.Context, sch *schedule, now time.Time) error {
+	due := func(next time.Time) bool { return !now.Add(c.tick / 2).Before(next) }
+	var errs []error
+	for _, src := range c.sources {
+		name := src.Name()
+		srcDue := due(sch.source[name])
+		devDue := false
+		for id, owner := range sch.owner {
+			if _, ok := c.devInterval[id]; ok && owner == name && due(sch.device[id]) {
+				devDue = true
+				break
+			}
+		}
+		if !srcDue && !devDue {
+			continue
+		}
+		if srcDue {
+			sch.source[name] = now.Add(c.srcInterval[name])
+		}
+		c.log.Info("collecting data...", zap.String("source", name))
+		_, buffered := src.(source.Buffered)
+		published := make(map[string]bool)
+		_, err := c.collectFrom(ctx, src, func(s telemetry.Sample) bool {
+			sch.owner[s.DeviceID] = name
+			if _, ok := c.devInterval[s.DeviceID]; !ok {
+				return srcDue || buffered
+			}
+			if buffered {
+				if due(sch.device[s.DeviceID]) {
+					published[s.DeviceID] = true
+				}
+				return true
+			}
+			if !published[s.DeviceID] && !due(sch.device[s.DeviceID]) {
+				return false
+			}
+			published[s.DeviceID] = true
+			return true
+		})
+		for id := range published {
+			sch.device[id] = now.Add(c.devInterval[id])
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+/*
+ * collect : 공급원마다 수집하여 샘플을 이벤트 버스에 발행 (수동 수집, 주기와 무관)
+ *  - deviceID가 있으면 그 장치의 샘플만 발행
+ *    단, 버퍼형 공급원(source.Buffered)은 꺼낸 샘플을 되돌릴 수 없으므로 다른 장치의 샘플도 모두 발행
+ *  - ctx는 이벤트와 함께 구독자(Influx 쓰기 등)까지 전달됨 (수동 수집이면 HTTP 요청의 ctx)
+ *  - 반환 : 발행한 이벤트 중 deviceID의 것, 실패한 공급원의 에러 (실패한 공급원이 있어도 나머지는 발행)
+ */
+func (c *Collector) collect(ctx context.Context, deviceID string) ([]bus.DataCollectedEvent, error) {
+	match := func(s telemetry.Sample) bool { return deviceID == "" || s.DeviceID == deviceID }
+	var events []bus.DataCollectedEvent
+	var errs []error
+	for _, src := range c.sources {
+		keep := match
+		if _, ok := src.(source.Buffered); ok {
+			keep = func(telemetry.Sample) bool { return true }
+		}
+		evs, err := c.collectFrom(ctx, src, keep)
+		for _, ev := range evs {
+			if deviceID == "" || ev.DeviceID == deviceID {
+				events = append(events, ev)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return events, errors.Join(errs...)
+}
+
+// collectFrom : 공급원 하나를 호출하여 keep이 고른 샘플을 발행 (장치 ID가 없는 샘플은 버림)
+func (c *Collector) collectFrom(ctx context.Context, src source.Source, keep func(telemetry.Sample) bool) ([]bus.DataCollectedEvent, error) {
+	samples, err := src.Collect(ctx)
+	if err != nil {
+		c.log.Warn("source collect failed", zap.String("source", src.Name()), zap.Error(err))
+		err = fmt.Errorf("source %s: %w", src.Name(), err)
+	}
+	now := time.Now() // 공급원이 시각을 주지 않은 샘플의 측정 시각 (버퍼링·재생 후에도 이 시각으로 기록)
+	var events []bus.DataCollectedEvent
+	for _, s := range samples {
+		if s.DeviceID == "" || !keep(s) {
+			continue
+		}
+		if s.Time.IsZero() {
+			s.Time = now
+		}
+		ev := bus.DataCollectedEvent{DeviceID: s.DeviceID, Values: s.Values, Time: s.Time}
+		c.bus.Publish(ctx, ev)
+		events = append(events, ev)
+	}
+	return events, err
+}
+
+/*
+ * Healthy : 수집 루프가 주기적으로 돌고 있는지 확인
+ *  - 루프가 시작되지 않았으면 에러
+ *  - 마지막 tick(없으면 시작 시각)으로부터 tick 주기의 3배 이상 지났으면 멈춘 것으로 판단
+ */
+func (c *Collector) Healthy() error {
+	started := c.startedAt.Load()
+	if started == 0 {
+		return fmt.Errorf("collector loop not started")
+	}
+	last := c.lastTick.Load()
+	if last == 0 {
+		last = started
+	}
+	if since := time.Since(time.Unix(0, last)); since > 3*c.tick {
+		return fmt.Errorf("collector has not ticked for %s", since.Round(time.Second))
 	}
+	return nil
 }
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTickInterval(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   map[string]time.Duration
+		dev   map[string]time.Duration
+		floor time.Duration
+		want  time.Duration
+	}{
+		{name: "single source", src: map[string]time.Duration{"modbus": 10 * time.Second}, floor: time.Second, want: 10 * time.Second},
+		{name: "gcd of sources", src: map[string]time.Duration{"modbus": 10 * time.Second, "mqtt": 15 * time.Second}, floor: time.Second, want: 5 * time.Second},
+		{name: "device overrides included",
+			src: map[string]time.Duration{"modbus": 10 * time.Second}, dev: map[string]time.Duration{"A1": 4 * time.Second},
+			floor: time.Second, want: 2 * time.Second},
+		{name: "coprime falls back to floor",
+			src: map[string]time.Duration{"modbus": 7 * time.Second, "mqtt": 10 * time.Second}, floor: 2 * time.Second, want: 2 * time.Second},
+		{name: "sub-second intervals", src: map[string]time.Duration{"modbus": 1500 * time.Millisecond, "mqtt": time.Second}, floor: 100 * time.Millisecond, want: 500 * time.Millisecond},
+		{name: "no intervals", floor: time.Second, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Collector{srcInterval: tt.src, devInterval: tt.dev}
+			if got := c.tickInterval(tt.floor); got != tt.want {
+				t.Fatalf("tickInterval = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
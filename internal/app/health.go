@@ -0,0 +1,51 @@
+/*
+ * 준비 상태(readiness) 검사 등록 : 각 구성요소의 상태 확인 함수를 infra.ReadinessCheck로 감싸
+ * fx 값 그룹(group:"readiness")에 제공합니다. Server는 이 그룹을 모아 /readyz에서 검사합니다.
+ */
+package app
+
+import (
+	"context"
+
+	"go.uber.org/fx" // DI 컨테이너
+
+	"generic-api-scaffold/internal/bus"        // 이벤트 버스
+	"generic-api-scaffold/internal/infra"      // 저장소 및 HTTP 서버
+	"generic-api-scaffold/internal/supervisor" // 모듈 재시작 감독
+)
+
+/*
+ * readinessChecks : fx.Provide에 넘길 readiness 검사 생성자 목록
+ *  - 새 구성요소의 검사를 추가하려면 여기에 한 줄 추가합니다.
+ */
+func readinessChecks() fx.Option {
+	asCheck := func(f interface{}) interface{} {
+		return fx.Annotate(f, fx.ResultTags(`group:"readiness"`))
+	}
+	return fx.Provide(
+		asCheck(influxReadiness),
+		asCheck(busReadiness),
+		asCheck(collectorReadiness),
+		asCheck(supervisorReadiness),
+	)
+}
+
+// influxReadiness : 시계열 저장소(InfluxDB) 도달 가능 여부
+func influxReadiness(r infra.TimeSeriesStore) infra.ReadinessCheck {
+	return infra.ReadinessCheck{Name: "influx", Check: r.Ping}
+}
+
+// busReadiness : EventBus 동작 여부
+func busReadiness(b *bus.EventBus) infra.ReadinessCheck {
+	return infra.ReadinessCheck{Name: "bus", Check: func(context.Context) error { return b.Healthy() }}
+}
+
+// collectorReadiness : Collector 수집 루프 동작 여부
+func collectorReadiness(c *Collector) infra.ReadinessCheck {
+	return infra.ReadinessCheck{Name: "collector", Check: func(context.Context) error { return c.Healthy() }}
+}
+
+// supervisorReadiness : 재시작 예산을 소진한 모듈이 없는지
+func supervisorReadiness(sv *supervisor.Supervisor) infra.ReadinessCheck {
+	return infra.ReadinessCheck{Name: "modules", Check: func(context.Context) error { return sv.Healthy() }}
+}
@@ -0,0 +1,42 @@
+/*
+ * 선택 모듈(optional module) 레지스트리
+ *  - 무거운 선택 기능(대시보드, pprof 등)은 빌드 프로필에 따라 포함 여부가 달라집니다.
+ *      기본 빌드           : modules_full.go 가 선택 모듈 목록을 제공
+ *      edge 빌드(-tags edge) : modules_edge.go 가 빈 목록을 제공 → 모든 선택 모듈이 no-op
+ *  - 선택 모듈이 빠져도 핵심 파이프라인(수집 → 버스 → 저장, 제어 API)은 그대로 동작해야 하므로,
+ *    다른 구성요소는 선택 모듈의 결과를 `optional:"true"` 또는 값 그룹(group:"routes" 등)으로만 주입받습니다.
+ */
+package app
+
+import (
+	"go.uber.org/fx"  // DI 컨테이너
+	"go.uber.org/zap" // 로깅 도구
+)
+
+/*
+ * Module : 선택 모듈 하나
+ *  - Name    : 로그에 표시할 이름
+ *  - Options : 모듈이 fx에 등록하는 생성자/훅 묶음
+ */
+type Module struct {
+	Name    string
+	Options fx.Option
+}
+
+/*
+ * modulesOption : 현재 빌드 프로필의 선택 모듈을 하나의 fx.Option으로 묶음
+ *  - 어떤 모듈이 포함되었는지 시작 시 로그로 남김
+ */
+func modulesOption() fx.Option {
+	mods := optionalModules()
+	opts := make([]fx.Option, 0, len(mods)+1)
+	names := make([]string, 0, len(mods))
+	for _, m := range mods {
+		opts = append(opts, m.Options)
+		names = append(names, m.Name)
+	}
+	opts = append(opts, fx.Invoke(func(log *zap.Logger) {
+		log.Info("optional modules", zap.String("profile", buildProfile), zap.Strings("enabled", names))
+	}))
+	return fx.Options(opts...)
+}
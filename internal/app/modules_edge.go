@@ -0,0 +1,15 @@
+//go:build edge
+
+/*
+ * edge 빌드 프로필의 선택 모듈 목록
+ *  - `go build -tags edge` 로 빌드하면 이 파일이 사용되며, 선택 모듈을 하나도 포함하지 않습니다.
+ *  - 저메모리 게이트웨이용 최소 바이너리 : 대시보드 정적 파일 등이 링크되지 않음
+ */
+package app
+
+const buildProfile = "edge"
+
+// optionalModules : edge 빌드는 선택 모듈 없음 (모두 no-op)
+func optionalModules() []Module {
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build !edge
+
+/*
+ * 기본 빌드 프로필의 선택 모듈 목록
+ *  - edge 태그 없이 빌드하면 이 파일이 사용됩니다.
+ */
+package app
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux" // HTTP 라우팅
+	"go.uber.org/fx"         // DI 컨테이너
+
+	"generic-api-scaffold/internal/bridge" // 외부 브로커 이벤트 브리지 (NATS, Kafka, MQTT, Redis), remote-write 싱크, S3 보관 싱크
+	"generic-api-scaffold/internal/infra"  // 라우트 등록 확장점
+	"generic-api-scaffold/internal/ui"     // 내장 웹 대시보드 (embed.FS)
+)
+
+const buildProfile = "full"
+
+// optionalModules : 기본 빌드에 포함되는 선택 모듈
+func optionalModules() []Module {
+	return []Module{
+		{
+			// 대시보드 : group:"routes" 등록자로 /ui/ 에 정적 파일을 마운트
+			Name:    "ui",
+			Options: fx.Provide(infra.AsRouteRegistrar(uiRoutes)),
+		},
+		{
+			// NATS 브리지 : 버스 이벤트를 NATS 주제로 내보내고 가져옴 (APP_NATS_URL 지정 시)
+			Name:    "nats",
+			Options: fx.Invoke(bridge.NewNATSBridge),
+		},
+		{
+			// Kafka 브리지 : 버스 이벤트를 Kafka 토픽으로 내보냄, 분석 파이프라인용 (APP_KAFKA_BROKERS 지정 시)
+			Name:    "kafka",
+			Options: fx.Invoke(bridge.NewKafkaBridge),
+		},
+		{
+			// MQTT 브리지 : 버스 이벤트를 주제 템플릿(site/{device}/data)으로 발행, SCADA/IoT 도구용 (APP_MQTT_BROKER 지정 시)
+			Name:    "mqtt",
+			Options: fx.Invoke(bridge.NewMQTTBridge),
+		},
+		{
+			// Redis Streams 브리지 : XADD로 내보내고 소비자 그룹으로 가져옴, 가벼운 영속 전송 (APP_REDIS_URL 지정 시)
+			Name:    "redis",
+			Options: fx.Invoke(bridge.NewRedisBridge),
+		},
+		{
+			// Prometheus remote-write 싱크 : 수집 값을 메트릭으로 Mimir/Thanos/VictoriaMetrics에 보냄 (APP_REMOTE_WRITE_URL 지정 시)
+			Name:    "remote_write",
+			Options: fx.Invoke(bridge.NewRemoteWriteSink),
+		},
+		{
+			// S3/Parquet 보관 싱크 : 수집 값을 날짜·장치별 Parquet 파일로 모아 올림, 장기 보관과 Athena/Spark 분석용 (APP_ARCHIVE_S3_ENDPOINT 지정 시)
+			Name:    "archive",
+			Options: fx.Invoke(bridge.NewArchiver),
+		},
+	}
+}
+
+// uiRoutes : /ui → /ui/ 리다이렉트와 /ui/ 아래 정적 파일
+func uiRoutes() infra.RouteFunc {
+	h := ui.Handler("/ui/")
+	return func(r *mux.Router) {
+		r.Handle("/ui", http.RedirectHandler("/ui/", http.StatusMovedPermanently)).Methods(http.MethodGet)
+		r.PathPrefix("/ui/").Handler(h).Methods(http.MethodGet).Name("ui")
+	}
+}
@@ -0,0 +1,30 @@
+/*
+ * 오버라이드 세션 만료를 감사 기록에 연결
+ *  - 시작/조기 종료는 API 핸들러가 요청 정보(출발지 등)와 함께 기록하고,
+ *    요청 없이 일어나는 TTL 만료는 여기서 기록합니다.
+ */
+package app
+
+import (
+	"fmt"
+
+	"generic-api-scaffold/internal/audit"   // 감사 이벤트 기록
+	"generic-api-scaffold/internal/control" // 오버라이드 세션
+)
+
+// auditOverrides : 오버라이드 만료 시 심각도 8의 감사 이벤트 기록
+func auditOverrides(o *control.OverrideManager, rec *audit.Recorder) {
+	o.OnChange(func(typ control.OverrideEventType, ov control.Override) {
+		if typ != control.OverrideExpired {
+			return
+		}
+		rec.Record(audit.Event{
+			Category: audit.CategoryControl,
+			Action:   "override.expire",
+			Outcome:  audit.OutcomeSuccess,
+			Severity: 8,
+			DeviceID: ov.DeviceID,
+			Detail:   fmt.Sprintf("id=%s operator=%s reason=%s", ov.ID, ov.Operator, ov.Reason),
+		})
+	})
+}
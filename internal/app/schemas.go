@@ -0,0 +1,22 @@
+/*
+ * 이벤트 스키마 등록 : 저널/브리지로 직렬화될 수 있는 이벤트 타입을 스키마 레지스트리에 등록합니다.
+ *  - data.collected는 codec.NewRegistry가 기본 등록하며, 나머지 이벤트는 여기서 (타입 이름, 현재 스키마 버전)으로 등록합니다.
+ *  - 이벤트 구조체의 의미가 바뀌면 버전을 올리고 codec.RegisterMigration으로 이전 버전 변환 함수를 함께 등록합니다.
+ *  - 등록하지 않은 이벤트도 브리지로 내보낼 수는 있지만, 받는 쪽에서 원래 타입으로 복원되지 않습니다. (json.RawMessage)
+ */
+package app
+
+import (
+	"generic-api-scaffold/internal/codec"   // 스키마 레지스트리
+	"generic-api-scaffold/internal/control" // 제어 명령 이벤트
+	"generic-api-scaffold/internal/group"   // 그룹 경보 이벤트
+)
+
+// newEventSchemas : 기본 등록에 이 애플리케이션의 이벤트를 더한 스키마 레지스트리
+func newEventSchemas(c codec.Codec) *codec.Registry {
+	r := codec.NewRegistry(c)
+	codec.Register[control.CommandIssued](r, control.TopicCommandIssued, 1)
+	codec.Register[control.CommandCompleted](r, control.TopicCommandCompleted, 1)
+	codec.Register[group.AlertChanged](r, "alert.changed", 1)
+	return r
+}
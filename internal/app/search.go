@@ -0,0 +1,91 @@
+/*
+ * 검색 문서 공급원 등록 : 장치, 제어 명령, 그룹 경보를 search.Source로 감싸
+ * fx 값 그룹(group:"search")에 제공합니다. Server는 이 그룹을 모아 /api/search에서 검색합니다.
+ */
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/fx" // DI 컨테이너
+
+	"generic-api-scaffold/internal/bus"     // 장치별 마지막 이벤트
+	"generic-api-scaffold/internal/control" // 제어 명령
+	"generic-api-scaffold/internal/group"   // 장치 그룹 및 경보
+	"generic-api-scaffold/internal/search"  // 검색 문서 타입
+)
+
+/*
+ * searchSources : fx.Provide에 넘길 검색 공급원 생성자 목록
+ *  - 새 검색 대상을 추가하려면 여기에 한 줄 추가합니다.
+ */
+func searchSources() fx.Option {
+	asSource := func(f interface{}) interface{} {
+		return fx.Annotate(f, fx.ResultTags(`group:"search"`))
+	}
+	return fx.Provide(
+		asSource(deviceSearch),
+		asSource(commandSearch),
+		asSource(alertSearch),
+	)
+}
+
+// deviceSearch : 장치 ID, 속한 그룹, 보고 중인 필드 이름
+func deviceSearch(b *bus.EventBus, g *group.Registry) search.Source {
+	return search.Source{Kind: "device", Documents: func() []search.Document {
+		events := b.CatchUp()
+		docs := make([]search.Document, 0, len(events))
+		for _, e := range events {
+			fields := make([]string, 0, len(e.Values))
+			for k := range e.Values {
+				fields = append(fields, k)
+			}
+			sort.Strings(fields)
+			docs = append(docs, search.Document{
+				Kind:  "device",
+				ID:    e.DeviceID,
+				Title: e.DeviceID,
+				Text:  fmt.Sprintf("groups: %s fields: %s", strings.Join(g.GroupsOf(e.DeviceID), " "), strings.Join(fields, " ")),
+			})
+		}
+		return docs
+	}}
+}
+
+// commandSearch : 제어 명령 내용 (장치, 동작, 출력, 상태, 에러)
+func commandSearch(d *control.Dispatcher) search.Source {
+	return search.Source{Kind: "command", Documents: func() []search.Document {
+		cmds := d.List()
+		docs := make([]search.Document, 0, len(cmds))
+		for _, c := range cmds {
+			docs = append(docs, search.Document{
+				Kind:  "command",
+				ID:    c.ID,
+				Title: fmt.Sprintf("%s %s", c.DeviceID, c.Action),
+				Text:  fmt.Sprintf("device=%s action=%s kw10=%d status=%s %s", c.DeviceID, c.Action, c.KW10, c.Status, c.Error),
+			})
+		}
+		return docs
+	}}
+}
+
+// alertSearch : 그룹 경보 규칙과 발생 중인 경보 메시지
+func alertSearch(a *group.Alerter) search.Source {
+	return search.Source{Kind: "alert", Documents: func() []search.Document {
+		active := make(map[string]group.Alert)
+		for _, al := range a.Active() {
+			active[al.Rule] = al
+		}
+		var docs []search.Document
+		for _, r := range a.Rules() {
+			text := fmt.Sprintf("group %s: %s.%s %s %g", r.Group, r.Field, r.Agg, r.Op, r.Threshold)
+			if al, ok := active[r.Name]; ok {
+				text += fmt.Sprintf(" firing value=%g since %s", al.Value, al.Since.Format("2006-01-02 15:04:05"))
+			}
+			docs = append(docs, search.Document{Kind: "alert", ID: r.Name, Title: r.Name, Text: text})
+		}
+		return docs
+	}}
+}
@@ -0,0 +1,23 @@
+/*
+ * 수집 공급원 등록 : Collector가 주기마다 호출할 source.Source를 fx 값 그룹(group:"sources")에 제공합니다.
+ *  - 새 장치 프로토콜을 붙이려면 source.Source를 구현하고 여기에 한 줄 추가합니다. (다른 패키지·선택 모듈은 source.AsSource로 직접 등록)
+ *  - 필드 버스·산업용 프로토콜 공급원(Modbus, MQTT, SNMP, OPC-UA)은 기본 빌드에만 포함됩니다. (sources_full.go, sources_edge.go)
+ */
+package app
+
+import (
+	"go.uber.org/fx" // DI 컨테이너
+
+	"generic-api-scaffold/internal/source" // 수집 공급원
+)
+
+// collectorSources : fx.Provide에 넘길 기본 수집 공급원 목록
+func collectorSources() fx.Option {
+	return fx.Options(
+		fx.Provide(
+			source.AsSource(source.NewDemo),     // 예제 공급원 (장치 A1, temp=23.5)
+			source.AsSource(source.NewHTTPPoll), // 제조사 클라우드 API·장치 웹 서버 폴링 (APP_HTTP_SOURCE_CONFIG_FILE, 없으면 수집 결과 없음)
+		),
+		protocolSources(),
+	)
+}
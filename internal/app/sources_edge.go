@@ -0,0 +1,29 @@
+//go:build edge
+
+/*
+ * 프로토콜 수집 공급원 (edge 빌드용)
+ *  - edge 빌드에는 Modbus, MQTT, SNMP, OPC-UA 클라이언트가 포함되지 않으므로 해당 공급원 설정을 쓸 수 없습니다.
+ *    설정되어 있으면 수집 결과 없이 조용히 시작하지 않도록 시작을 막음
+ */
+package app
+
+import (
+	"go.uber.org/fx"  // DI 컨테이너
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// edgeExcludedSources : edge 빌드에서 쓸 수 없는 공급원의 설정 환경변수
+var edgeExcludedSources = []string{"APP_MODBUS_CONFIG_FILE", "APP_MQTT_SOURCE_BROKER", "APP_SNMP_CONFIG_FILE", "APP_OPCUA_CONFIG_FILE"}
+
+// protocolSources : edge 빌드는 공급원 없음 (설정되어 있으면 Fatal)
+func protocolSources() fx.Option {
+	return fx.Invoke(func(log *zap.Logger) {
+		for _, key := range edgeExcludedSources {
+			if config.String(key, "") != "" {
+				log.Fatal(key + " is not available in the edge build")
+			}
+		}
+	})
+}
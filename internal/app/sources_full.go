@@ -0,0 +1,22 @@
+//go:build !edge
+
+/*
+ * 기본 빌드의 프로토콜 수집 공급원 (edge 빌드는 sources_edge.go)
+ */
+package app
+
+import (
+	"go.uber.org/fx" // DI 컨테이너
+
+	"generic-api-scaffold/internal/source" // 수집 공급원
+)
+
+// protocolSources : 프로토콜 클라이언트가 필요한 수집 공급원
+func protocolSources() fx.Option {
+	return fx.Provide(
+		source.AsSource(source.NewModbus), // Modbus TCP / RTU 장치 (APP_MODBUS_CONFIG_FILE, 없으면 수집 결과 없음)
+		source.AsSource(source.NewMQTT),   // MQTT로 밀어 넣는 장치 (APP_MQTT_SOURCE_BROKER, 없으면 수집 결과 없음)
+		source.AsSource(source.NewSNMP),   // UPS·네트워크 장비 SNMP v2c / v3 폴링 (APP_SNMP_CONFIG_FILE, 없으면 수집 결과 없음)
+		source.AsSource(source.NewOPCUA),  // PLC·SCADA 서버 OPC-UA 구독 (APP_OPCUA_CONFIG_FILE, 없으면 수집 결과 없음)
+	)
+}
@@ -0,0 +1,143 @@
+/*
+ * Recorder : 감사(audit) 및 접근(access) 이벤트 기록기
+ *  - 누가(출발지) 언제 어떤 API를 호출했는지, 어떤 제어 명령·설정 변경이 있었는지를 이벤트로 남깁니다.
+ *  - 모든 이벤트는 "audit" 이름의 구조화 로그로 출력하고,
+ *    APP_AUDIT_SYSLOG_ADDR가 설정되어 있으면 SIEM으로 syslog(CEF 또는 LEEF 형식) 전송합니다.
+ *  - 전송은 큐를 거쳐 별도 고루틴(Supervisor 감독)에서 수행하므로 요청 처리를 막지 않습니다.
+ *    큐가 가득 차거나 전송에 실패한 이벤트는 드롭 기록기에 남깁니다.
+ */
+package audit
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"  // Fx 프레임워크를 통한 라이프사이클 관리
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config"     // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"      // 전송 실패/큐 초과 기록
+	"generic-api-scaffold/internal/supervisor" // 전송 고루틴 감독
+)
+
+// Category : 이벤트 분류
+const (
+	CategoryAccess  = "access"  // HTTP API 접근
+	CategoryControl = "control" // 장치 제어 명령 (접수, 거절, 배포)
+	CategoryConfig  = "config"  // 런타임 설정 변경 (웹훅, 로그 레벨 등)
+)
+
+// Outcome : 결과
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+/*
+ * Event : 감사 이벤트 하나
+ *  - Action   : 수행한 동작 (예: "http.request", "control.submit", "webhook.create")
+ *  - Severity : 0(정보) ~ 10(매우 심각), CEF/LEEF 심각도로 그대로 사용
+ *  - Source   : 요청 출발지 주소
+ */
+type Event struct {
+	Time     time.Time     `json:"time"`
+	Category string        `json:"category"`
+	Action   string        `json:"action"`
+	Outcome  string        `json:"outcome"`
+	Severity int           `json:"severity"`
+	Source   string        `json:"source,omitempty"`
+	Method   string        `json:"method,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"duration_ns,omitempty"`
+	DeviceID string        `json:"device,omitempty"`
+	Detail   string        `json:"detail,omitempty"`
+}
+
+// Recorder 구조체
+type Recorder struct {
+	log   *zap.Logger
+	drops *drops.Recorder
+
+	sink  *syslogSink // nil이면 SIEM 전송 비활성
+	queue chan Event
+}
+
+/*
+ * NewRecorder : fx가 호출하는 Recorder 생성자
+ *  - APP_AUDIT_SYSLOG_ADDR    : syslog 수신 주소 (예: "siem.local:514", 비어 있으면 전송 안 함)
+ *  - APP_AUDIT_SYSLOG_NETWORK : udp | tcp (기본 udp, tcp는 RFC 6587 옥텟 카운팅 프레이밍)
+ *  - APP_AUDIT_FORMAT         : cef | leef (기본 cef)
+ *  - APP_AUDIT_FACILITY       : syslog facility 번호 (기본 13, log audit)
+ *  - APP_AUDIT_QUEUE          : 전송 대기 큐 크기 (기본 1000)
+ */
+func NewRecorder(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, sv *supervisor.Supervisor) *Recorder {
+	a := &Recorder{
+		log:   log.Named("audit"),
+		drops: dr,
+		queue: make(chan Event, config.Int(log, "APP_AUDIT_QUEUE", 1000)),
+	}
+
+	if addr := config.String("APP_AUDIT_SYSLOG_ADDR", ""); addr != "" {
+		format := config.String("APP_AUDIT_FORMAT", "cef")
+		if format != "cef" && format != "leef" {
+			log.Fatal("invalid APP_AUDIT_FORMAT (expected cef|leef)", zap.String("value", format))
+		}
+		network := config.String("APP_AUDIT_SYSLOG_NETWORK", "udp")
+		if network != "udp" && network != "tcp" {
+			log.Fatal("invalid APP_AUDIT_SYSLOG_NETWORK (expected udp|tcp)", zap.String("value", network))
+		}
+		a.sink = newSyslogSink(network, addr, format, config.Int(log, "APP_AUDIT_FACILITY", 13))
+		log.Info("audit export enabled", zap.String("addr", addr), zap.String("network", network), zap.String("format", format))
+
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				return sv.Go("audit-export", a.export)
+			},
+			OnStop: func(ctx context.Context) error {
+				a.sink.close()
+				return nil
+			},
+		})
+	}
+	return a
+}
+
+/*
+ * Record : 감사 이벤트 기록
+ *  - 시각이 비어 있으면 현재 시각
+ *  - 로그 출력은 즉시, SIEM 전송은 큐에 넣고 반환 (가득 차면 드롭 기록)
+ */
+func (a *Recorder) Record(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	a.log.Info(e.Action,
+		zap.String("category", e.Category), zap.String("outcome", e.Outcome), zap.Int("severity", e.Severity),
+		zap.String("src", e.Source), zap.String("method", e.Method), zap.String("path", e.Path),
+		zap.Int("status", e.Status), zap.Duration("duration", e.Duration),
+		zap.String("device", e.DeviceID), zap.String("detail", e.Detail))
+
+	if a.sink == nil {
+		return
+	}
+	select {
+	case a.queue <- e:
+	default:
+		a.drops.Record("audit", drops.ReasonBackpressure, e.DeviceID, "audit queue full, dropped "+e.Action)
+	}
+}
+
+// export : 큐의 이벤트를 syslog로 전송 (실패한 이벤트는 드롭 기록 후 다음 이벤트에서 재연결)
+func (a *Recorder) export(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-a.queue:
+			if err := a.sink.send(e); err != nil {
+				a.drops.Record("audit", drops.ReasonWriteFailed, e.DeviceID, err.Error())
+			}
+		}
+	}
+}
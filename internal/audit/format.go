@@ -0,0 +1,129 @@
+/*
+ * SIEM 메시지 형식
+ *  - CEF  : CEF:0|Vendor|Product|Version|SignatureID|Name|Severity|key=value key=value ...
+ *  - LEEF : LEEF:2.0|Vendor|Product|Version|EventID|^|key=value^key=value ...  (구분자 '^')
+ *  - 두 형식 모두 헤더의 '|'와 '\', 확장 필드의 '='와 '\', 줄바꿈을 이스케이프합니다.
+ */
+package audit
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 제품 식별 정보 (CEF/LEEF 헤더)
+const (
+	vendor  = "generic-api-scaffold"
+	product = "scaffold"
+	version = "1.0"
+)
+
+// field : 확장 필드 하나 (순서 유지를 위해 map 대신 슬라이스 사용)
+type field struct{ k, v string }
+
+/*
+ * formatCEF : 이벤트 → CEF 메시지
+ *  - 표준 키 : rt(epoch ms), cat, act, outcome, src, requestMethod, request, cs1(장치), msg
+ */
+func formatCEF(e Event) string {
+	var b strings.Builder
+	b.WriteString("CEF:0|")
+	for _, h := range []string{vendor, product, version, e.Action, e.Action, strconv.Itoa(e.Severity)} {
+		b.WriteString(cefHeader(h))
+		b.WriteByte('|')
+	}
+
+	fields := []field{
+		{"rt", strconv.FormatInt(e.Time.UnixMilli(), 10)},
+		{"cat", e.Category},
+		{"act", e.Action},
+		{"outcome", e.Outcome},
+		{"src", hostOnly(e.Source)},
+		{"requestMethod", e.Method},
+		{"request", e.Path},
+	}
+	if e.Status != 0 {
+		fields = append(fields, field{"cn1Label", "status"}, field{"cn1", strconv.Itoa(e.Status)})
+	}
+	if e.DeviceID != "" {
+		fields = append(fields, field{"cs1Label", "device"}, field{"cs1", e.DeviceID})
+	}
+	fields = append(fields, field{"msg", e.Detail})
+
+	first := true
+	for _, f := range fields {
+		if f.v == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte(' ')
+		}
+		first = false
+		b.WriteString(f.k)
+		b.WriteByte('=')
+		b.WriteString(cefValue(f.v))
+	}
+	return b.String()
+}
+
+/*
+ * formatLEEF : 이벤트 → LEEF 2.0 메시지
+ *  - 키 : devTime(epoch ms), cat, sev, outcome, src, method, url, device, msg, status
+ */
+func formatLEEF(e Event) string {
+	var b strings.Builder
+	b.WriteString("LEEF:2.0|")
+	for _, h := range []string{vendor, product, version, e.Action} {
+		b.WriteString(cefHeader(h))
+		b.WriteByte('|')
+	}
+	b.WriteString("^|")
+
+	fields := []field{
+		{"devTime", strconv.FormatInt(e.Time.UnixMilli(), 10)},
+		{"cat", e.Category},
+		{"sev", strconv.Itoa(e.Severity)},
+		{"outcome", e.Outcome},
+		{"src", hostOnly(e.Source)},
+		{"method", e.Method},
+		{"url", e.Path},
+		{"device", e.DeviceID},
+		{"msg", e.Detail},
+	}
+	if e.Status != 0 {
+		fields = append(fields, field{"status", strconv.Itoa(e.Status)})
+	}
+
+	first := true
+	for _, f := range fields {
+		if f.v == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte('^')
+		}
+		first = false
+		b.WriteString(f.k)
+		b.WriteByte('=')
+		b.WriteString(leefValue(f.v))
+	}
+	return b.String()
+}
+
+var (
+	headerEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefEscaper    = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+	leefEscaper   = strings.NewReplacer(`\`, `\\`, `^`, `\^`, "\n", " ", "\r", " ")
+)
+
+func cefHeader(s string) string { return headerEscaper.Replace(s) }
+func cefValue(s string) string  { return cefEscaper.Replace(s) }
+func leefValue(s string) string { return leefEscaper.Replace(s) }
+
+// hostOnly : "ip:port" → "ip" (SIEM의 src 필드는 주소만 기대)
+func hostOnly(addr string) string {
+	if i := strings.LastIndexByte(addr, ':'); i > 0 && !strings.HasSuffix(addr, "]") {
+		return strings.Trim(addr[:i], "[]")
+	}
+	return addr
+}
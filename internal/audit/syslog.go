@@ -0,0 +1,101 @@
+/*
+ * syslogSink : RFC 5424 syslog 전송기
+ *  - UDP : 메시지 하나 = 데이터그램 하나
+ *  - TCP : RFC 6587 옥텟 카운팅 프레이밍 ("<길이> <메시지>")
+ *  - 연결은 처음 보낼 때 맺고, 쓰기에 실패하면 닫아 두었다가 다음 이벤트에서 다시 연결합니다.
+ */
+package audit
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// RFC 5424 severity (CEF 심각도 0~10을 syslog 심각도로 변환할 때 사용)
+const (
+	sevCritical = 2
+	sevError    = 3
+	sevWarning  = 4
+	sevNotice   = 5
+	sevInfo     = 6
+)
+
+type syslogSink struct {
+	network  string
+	addr     string
+	format   string
+	facility int
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newSyslogSink(network, addr, format string, facility int) *syslogSink {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "-"
+	}
+	return &syslogSink{network: network, addr: addr, format: format, facility: facility, hostname: host}
+}
+
+// send : 이벤트 하나를 syslog 메시지로 전송
+func (s *syslogSink) send(e Event) error {
+	var msg string
+	if s.format == "leef" {
+		msg = formatLEEF(e)
+	} else {
+		msg = formatCEF(e)
+	}
+	pri := s.facility*8 + syslogSeverity(e.Severity)
+	line := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		pri, e.Time.UTC().Format(time.RFC3339Nano), s.hostname, product, os.Getpid(), e.Category, msg)
+	if s.network == "tcp" {
+		line = fmt.Sprintf("%d %s", len(line), line)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.addr, 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("syslog dial %s: %w", s.addr, err)
+		}
+		s.conn = conn
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if _, err := s.conn.Write([]byte(line)); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("syslog write %s: %w", s.addr, err)
+	}
+	return nil
+}
+
+// close : 연결 종료
+func (s *syslogSink) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// syslogSeverity : CEF 심각도(0~10) → syslog 심각도
+func syslogSeverity(sev int) int {
+	switch {
+	case sev >= 9:
+		return sevCritical
+	case sev >= 7:
+		return sevError
+	case sev >= 5:
+		return sevWarning
+	case sev >= 3:
+		return sevNotice
+	}
+	return sevInfo
+}
@@ -0,0 +1,96 @@
+/*
+ * Authenticator : API 토큰 기반 호출자 식별 및 역할(role) 확인
+ *  - APP_API_TOKENS : "이름:토큰:역할|역할,이름:토큰:역할" 형식
+ *      예) "alice:s3cr3t-token:operator|override,ci:ci-token:operator"
+ *  - 요청의 Authorization: Bearer <토큰> 헤더로 호출자를 식별합니다.
+ *  - 토큰 비교는 상수 시간(constant-time)으로 수행합니다.
+ *  - 웹훅 관리는 operator, 운영자 오버라이드는 override 역할이 필요합니다. (그 외 API는 인증 없이 동작)
+ */
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// 역할 이름
+const (
+	RoleOperator = "operator" // 일반 운영자
+	RoleOverride = "override" // 정책 제한을 일시적으로 우회할 수 있는 운영자
+)
+
+// Principal : 식별된 호출자
+type Principal struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// HasRole : 역할 보유 여부
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// credential : 토큰 하나와 그 소유자
+type credential struct {
+	token     []byte
+	principal Principal
+}
+
+// Authenticator 구조체
+type Authenticator struct {
+	creds []credential
+}
+
+/*
+ * NewAuthenticator : fx가 호출하는 Authenticator 생성자
+ *  - 형식이 잘못되면 Fatal
+ */
+func NewAuthenticator(log *zap.Logger) *Authenticator {
+	a := &Authenticator{}
+	names := make([]string, 0)
+	for _, item := range config.List("APP_API_TOKENS", nil) {
+		parts := strings.SplitN(item, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			log.Fatal("invalid APP_API_TOKENS entry (expected name:token:role|role)", zap.String("name", parts[0]))
+		}
+		var roles []string
+		for _, r := range strings.Split(parts[2], "|") {
+			if r = strings.TrimSpace(r); r != "" {
+				roles = append(roles, r)
+			}
+		}
+		a.creds = append(a.creds, credential{token: []byte(parts[1]), principal: Principal{Name: parts[0], Roles: roles}})
+		names = append(names, parts[0])
+	}
+	log.Info("api tokens configured", zap.Strings("principals", names))
+	return a
+}
+
+/*
+ * Identify : Authorization: Bearer 헤더로 호출자 식별
+ *  - 헤더가 없거나 토큰이 일치하지 않으면 에러
+ */
+func (a *Authenticator) Identify(r *http.Request) (Principal, error) {
+	h := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(h, "Bearer ")
+	if !ok || token == "" {
+		return Principal{}, fmt.Errorf("missing bearer token")
+	}
+	for _, c := range a.creds {
+		if subtle.ConstantTimeCompare(c.token, []byte(token)) == 1 {
+			return c.principal, nil
+		}
+	}
+	return Principal{}, fmt.Errorf("invalid token")
+}
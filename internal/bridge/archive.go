@@ -0,0 +1,277 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"                   // S3 호환 오브젝트 스토리지 클라이언트
+	"github.com/minio/minio-go/v7/pkg/credentials"   // 접근 키 / IAM 자격 증명
+	"github.com/parquet-go/parquet-go"               // Parquet 파일 쓰기
+	"github.com/prometheus/client_golang/prometheus" // 브리지 메트릭
+	"go.uber.org/fx"                                 // 라이프사이클 훅
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 로컬 이벤트 버스
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 버퍼 초과·업로드 포기 기록
+)
+
+// archiveRow : Parquet 행 하나 (필드 하나의 값, 장치마다 필드가 달라도 같은 스키마)
+type archiveRow struct {
+	Time   time.Time `parquet:"time,timestamp(millisecond)"`
+	Device string    `parquet:"device,dict"`
+	Field  string    `parquet:"field,dict"`
+	Value  float64   `parquet:"value"`
+}
+
+// archivePart : 파일 하나로 모이는 파티션 (UTC 날짜, 장치)
+type archivePart struct {
+	date, device string
+}
+
+/*
+ * Archiver : 수집 이벤트를 모아 주기적으로 Parquet 파일로 S3 호환 스토리지에 올리는 콜드 스토리지 싱크 (내보내기 전용)
+ *  - 오브젝트 키 : <접두사>date=YYYY-MM-DD/device=<장치 ID>/<인스턴스 ID>-<유닉스 나노초>.parquet
+ *      Hive 형식 파티션이므로 Athena/Spark/Trino가 date, device 파티션 열로 바로 읽음
+ *  - 파일 스키마 : time (timestamp ms, UTC), device, field, value (double), zstd 압축
+ *  - 시각은 버스에서 이벤트를 받은 시각
+ *  - 버퍼는 메모리에만 있으므로 비정상 종료 시 마지막 업로드 이후 데이터는 잃음 (정상 종료 시에는 남은 버퍼를 올림)
+ */
+type Archiver struct {
+	log     *zap.Logger
+	client  *minio.Client
+	bucket  string
+	prefix  string
+	origin  string
+	drops   *drops.Recorder
+	metrics *bridgeMetrics
+
+	flushRows int // 이만큼 쌓이면 주기를 기다리지 않고 올림
+	maxRows   int // 버퍼 상한 (업로드가 계속 실패하는 동안 메모리 보호)
+	wake      chan struct{}
+
+	mu    sync.Mutex
+	parts map[archivePart][]archiveRow
+	rows  int
+}
+
+/*
+ * NewArchiver : fx가 호출하는 S3/Parquet 보관 싱크 생성자 (선택 모듈 "archive")
+ *  - APP_ARCHIVE_S3_ENDPOINT   : S3 호환 엔드포인트 host[:port] (비어 있으면 비활성, 기본 비활성, 예: s3.ap-northeast-2.amazonaws.com, minio:9000)
+ *  - APP_ARCHIVE_S3_BUCKET     : 버킷 (필수, 미리 만들어 두어야 함)
+ *  - APP_ARCHIVE_S3_REGION     : 리전 (기본 없음)
+ *  - APP_ARCHIVE_S3_ACCESS_KEY / APP_ARCHIVE_S3_SECRET_KEY : 접근 키 (비어 있으면 EC2/ECS IAM 역할)
+ *  - APP_ARCHIVE_S3_SECURE     : HTTPS 사용 (기본 true)
+ *  - APP_ARCHIVE_PREFIX        : 오브젝트 키 접두사 (기본 "telemetry/")
+ *  - APP_ARCHIVE_FLUSH_EVERY   : 업로드 주기 (기본 5m, 길수록 파일이 크고 적어짐)
+ *  - APP_ARCHIVE_FLUSH_ROWS    : 주기 전이라도 업로드하는 버퍼 행 수 (기본 100000)
+ *  - APP_ARCHIVE_MAX_ROWS      : 버퍼 상한 행 수 (기본 1000000, 넘으면 새 이벤트를 /drops(source="archive", reason=backpressure)에 남기고 버림)
+ *  - 업로드 실패한 파티션은 버퍼로 되돌려 다음 주기에 다시 시도
+ *  - 올린 행 수와 실패 수는 scaffold_bridge_messages_total / scaffold_bridge_failures_total{bridge="archive"}
+ */
+func NewArchiver(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, b *bus.EventBus, dr *drops.Recorder) *Archiver {
+	endpoint := config.String("APP_ARCHIVE_S3_ENDPOINT", "")
+	if endpoint == "" {
+		return nil
+	}
+	bucket := config.String("APP_ARCHIVE_S3_BUCKET", "")
+	if bucket == "" {
+		log.Fatal("APP_ARCHIVE_S3_BUCKET is required when APP_ARCHIVE_S3_ENDPOINT is set")
+	}
+	creds := credentials.NewIAM("")
+	if key := config.String("APP_ARCHIVE_S3_ACCESS_KEY", ""); key != "" {
+		creds = credentials.NewStaticV4(key, config.String("APP_ARCHIVE_S3_SECRET_KEY", ""), "")
+	}
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: config.Bool(log, "APP_ARCHIVE_S3_SECURE", true),
+		Region: config.String("APP_ARCHIVE_S3_REGION", ""),
+	})
+	if err != nil {
+		log.Fatal("invalid archive s3 settings", zap.String("endpoint", endpoint), zap.Error(err))
+	}
+	prefix := config.String("APP_ARCHIVE_PREFIX", "telemetry/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	every := config.Duration(log, "APP_ARCHIVE_FLUSH_EVERY", 5*time.Minute)
+	a := &Archiver{
+		log:       log.With(zap.String("bridge", "archive")),
+		client:    client,
+		bucket:    bucket,
+		prefix:    prefix,
+		origin:    InstanceID(),
+		drops:     dr,
+		metrics:   newBridgeMetrics(reg),
+		flushRows: config.Int(log, "APP_ARCHIVE_FLUSH_ROWS", 100000),
+		maxRows:   config.Int(log, "APP_ARCHIVE_MAX_ROWS", 1000000),
+		wake:      make(chan struct{}, 1),
+		parts:     make(map[archivePart][]archiveRow),
+	}
+	if every <= 0 || a.flushRows < 1 || a.maxRows < a.flushRows {
+		log.Fatal("APP_ARCHIVE_FLUSH_EVERY and APP_ARCHIVE_FLUSH_ROWS must be positive and APP_ARCHIVE_MAX_ROWS at least APP_ARCHIVE_FLUSH_ROWS",
+			zap.Duration("flush_every", every), zap.Int("flush_rows", a.flushRows), zap.Int("max_rows", a.maxRows))
+	}
+
+	var sub *bus.Subscription
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			sub = b.Subscribe(a.add,
+				bus.WithName("archive-bridge"),
+				bus.WithPriority(bus.PriorityLow), // 로컬 구독자가 먼저
+				bus.WithoutRetained())             // 이미 보관한 마지막 값을 다시 넣지 않음
+			go func() {
+				defer close(done)
+				a.loop(ctx, every)
+			}()
+			a.log.Info("archive sink started", zap.String("endpoint", endpoint), zap.String("bucket", bucket),
+				zap.String("prefix", prefix), zap.Duration("flush_every", every))
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			sub.Unsubscribe()
+			cancel()
+			<-done
+			a.flush(stopCtx) // 남은 버퍼 (실패하면 드롭 기록)
+			if n := a.buffered(); n > 0 {
+				a.drops.Record("archive", drops.ReasonShutdown, "", fmt.Sprintf("%d rows not uploaded before shutdown", n))
+			}
+			return nil
+		},
+	})
+	b.DrainBefore(lc) // 구독 해지·연결 종료 전에 버스에 남은 이벤트를 내보냄
+	return a
+}
+
+// add : 수집 이벤트를 파티션 버퍼에 추가 (버퍼 상한을 넘으면 드롭 기록)
+func (a *Archiver) add(_ context.Context, e bus.DataCollectedEvent) {
+	at := e.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	at = at.UTC()
+	part := archivePart{date: at.Format("2006-01-02"), device: e.DeviceID} // 측정 시각의 날짜 파티션
+
+	a.mu.Lock()
+	if a.rows+len(e.Values) > a.maxRows {
+		a.mu.Unlock()
+		a.drops.Record("archive", drops.ReasonBackpressure, e.DeviceID, "archive buffer full")
+		return
+	}
+	for field, v := range e.Values {
+		a.parts[part] = append(a.parts[part], archiveRow{Time: at, Device: e.DeviceID, Field: field, Value: v})
+	}
+	a.rows += len(e.Values)
+	full := a.rows >= a.flushRows
+	a.mu.Unlock()
+
+	if full {
+		select {
+		case a.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// buffered : 아직 올리지 않은 행 수
+func (a *Archiver) buffered() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rows
+}
+
+// loop : 주기마다, 또는 버퍼가 차면 업로드 (ctx가 끝나면 반환)
+func (a *Archiver) loop(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		case <-a.wake:
+		}
+		a.flush(ctx)
+	}
+}
+
+/*
+ * flush : 버퍼를 비우고 파티션마다 Parquet 파일 하나를 올림
+ *  - 실패한 파티션은 버퍼 앞쪽으로 되돌림 (다음 업로드에서 새 행과 함께 한 파일로)
+ */
+func (a *Archiver) flush(ctx context.Context) {
+	a.mu.Lock()
+	parts := a.parts
+	a.parts = make(map[archivePart][]archiveRow)
+	a.rows = 0
+	a.mu.Unlock()
+	if len(parts) == 0 {
+		return
+	}
+
+	keys := make([]archivePart, 0, len(parts))
+	for p := range parts {
+		keys = append(keys, p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		return keys[i].device < keys[j].device
+	})
+
+	uploaded, failed := 0, 0
+	for _, p := range keys {
+		rows := parts[p]
+		if err := a.upload(ctx, p, rows); err != nil {
+			a.metrics.failures.WithLabelValues("archive", directionExport).Add(float64(len(rows)))
+			a.log.Warn("archive upload failed, will retry", zap.String("date", p.date), zap.String("device", p.device),
+				zap.Int("rows", len(rows)), zap.Error(err))
+			a.requeue(p, rows)
+			failed += len(rows)
+			continue
+		}
+		a.metrics.messages.WithLabelValues("archive", directionExport).Add(float64(len(rows)))
+		uploaded += len(rows)
+	}
+	a.log.Debug("archive flushed", zap.Int("files", len(keys)), zap.Int("rows", uploaded), zap.Int("failed_rows", failed))
+}
+
+// requeue : 올리지 못한 행을 버퍼 앞쪽으로 되돌림 (상한을 넘는 만큼은 드롭 기록)
+func (a *Archiver) requeue(p archivePart, rows []archiveRow) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if room := a.maxRows - a.rows; len(rows) > room {
+		if room < 0 {
+			room = 0
+		}
+		a.drops.Record("archive", drops.ReasonWriteFailed, p.device,
+			fmt.Sprintf("%d rows for %s dropped after failed upload, buffer full", len(rows)-room, p.date))
+		rows = rows[len(rows)-room:] // 최근 행을 남김
+	}
+	a.parts[p] = append(rows, a.parts[p]...)
+	a.rows += len(rows)
+}
+
+// upload : 행을 Parquet 파일 하나로 써서 올림
+func (a *Archiver) upload(ctx context.Context, p archivePart, rows []archiveRow) error {
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[archiveRow](&buf, parquet.Compression(&parquet.Zstd))
+	if _, err := w.Write(rows); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%sdate=%s/device=%s/%s-%d.parquet", a.prefix, p.date, url.PathEscape(p.device), url.PathEscape(a.origin), time.Now().UnixNano())
+	_, err := a.client.PutObject(ctx, a.bucket, key, &buf, int64(buf.Len()), minio.PutObjectOptions{ContentType: "application/vnd.apache.parquet"})
+	return err
+}
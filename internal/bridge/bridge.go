@@ -0,0 +1,264 @@
+/*
+ * Bridge : 로컬 EventBus와 외부 메시지 브로커 사이의 이벤트 브리지
+ *  - 내보내기(outbound) : 지정한 버스 토픽(와일드카드 가능)의 이벤트를 브로커 주제(subject)로 다시 발행
+ *      주제 이름 = 접두사 + 버스 토픽 (예: "scaffold." + "data.collected")
+ *  - 가져오기(inbound)  : 지정한 브로커 주제의 메시지를 로컬 버스에 발행
+ *      → 여러 scaffold 인스턴스와 외부 서비스가 하나의 이벤트 스트림을 공유
+ *  - 전송 형식(frame) : JSON { origin, topic, event | json } (Plain이면 이벤트 JSON만, plainJSON)
+ *      스키마 레지스트리에 등록된 이벤트는 codec 외피(타입 이름, 스키마 버전, payload)로, 그 외 이벤트는 JSON으로 payload를 담음
+ *      가져온 등록 이벤트는 현재 스키마 버전까지 마이그레이션·검증 후 원래 타입으로 복원되어 타입 구독자도 받고,
+ *      그 외는 json.RawMessage payload로 토픽 구독자만 받음 (버전이 다른 인스턴스끼리도 이벤트를 주고받을 수 있음)
+ *  - 루프 방지 :
+ *      ① 자기 인스턴스(origin == APP_INSTANCE_ID)가 보낸 메시지는 가져오지 않음
+ *      ② 브리지가 가져와 발행한 이벤트는 다시 내보내지 않음 (발행 ctx 표시)
+ *  - 브로커별 연결은 Transport로 분리 (nats.go, kafka.go, mqtt.go, redis.go)
+ */
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 브리지 메트릭
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 로컬 이벤트 버스
+	"generic-api-scaffold/internal/codec"  // 이벤트 스키마 레지스트리
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 읽을 수 없는 메시지 기록
+)
+
+/*
+ * Transport : 브로커 연결 하나
+ *  - Publish   : 주제로 메시지 발행 (key는 이벤트 키(장치 ID), 파티션을 나누는 브로커에서 사용)
+ *  - Subscribe : 주제(브로커의 와일드카드 문법)의 메시지를 fn으로 받음 (fn은 브로커 클라이언트의 고루틴에서 호출됨)
+ *                내보내기 전용 브리지(Kafka 등)는 Import가 비어 있으므로 호출되지 않음
+ *  - Close     : 구독을 끝내고 연결 종료 (보내는 중인 메시지는 가능한 한 내보냄)
+ */
+type Transport interface {
+	Publish(ctx context.Context, subject, key string, data []byte) error
+	Subscribe(subject string, fn func(data []byte)) error
+	Close() error
+}
+
+/*
+ * Config : 브리지 설정
+ *  - Name      : 브로커 이름 (로그, 구독자 이름, 드롭 기록 source에 사용, 예: "nats")
+ *  - Export    : 내보낼 버스 토픽 패턴 목록 (비어 있으면 내보내지 않음)
+ *  - Import    : 가져올 브로커 주제 목록 (비어 있으면 가져오지 않음)
+ *  - Prefix    : 버스 토픽 앞에 붙일 주제 접두사
+ *  - Subject   : 내보낼 주제를 정하는 함수 (nil이면 Prefix + 버스 토픽, key는 이벤트 키(장치 ID))
+ *  - Plain     : frame 대신 이벤트 JSON만 보냄 (브리지를 모르는 외부 도구용, 내보내기 전용)
+ */
+type Config struct {
+	Name    string
+	Export  []string
+	Import  []string
+	Prefix  string
+	Subject func(topic, key string) string
+	Plain   bool
+}
+
+// frame : 브로커로 오가는 메시지 형식
+type frame struct {
+	Origin string          `json:"origin"`          // 보낸 인스턴스 ID
+	Topic  string          `json:"topic"`           // 버스 토픽
+	Event  []byte          `json:"event,omitempty"` // 등록된 이벤트 : codec 외피
+	JSON   json.RawMessage `json:"json,omitempty"`  // 그 외 이벤트 : JSON payload
+}
+
+// importedKey : 브리지가 가져와 발행한 이벤트의 ctx 표시 (다시 내보내지 않음)
+type importedKey struct{}
+
+/*
+ * Bridge 구조체
+ *  - subs : 내보내기용 버스 구독 (Stop에서 해지)
+ */
+type Bridge struct {
+	log       *zap.Logger
+	bus       *bus.EventBus
+	schemas   *codec.Registry
+	drops     *drops.Recorder
+	transport Transport
+	cfg       Config
+	origin    string
+	metrics   *bridgeMetrics
+
+	mu   sync.Mutex
+	subs []*bus.Subscription
+}
+
+/*
+ * New : 브리지 생성 (연결/구독은 Start에서)
+ */
+func New(log *zap.Logger, reg *prometheus.Registry, b *bus.EventBus, schemas *codec.Registry, dr *drops.Recorder, t Transport, cfg Config) *Bridge {
+	return &Bridge{log: log.With(zap.String("bridge", cfg.Name)), bus: b, schemas: schemas, drops: dr, transport: t, cfg: cfg,
+		origin: InstanceID(), metrics: newBridgeMetrics(reg)}
+}
+
+/*
+ * InstanceID : 이 프로세스의 인스턴스 ID
+ *  - APP_INSTANCE_ID (기본 "호스트이름-pid")
+ */
+func InstanceID() string {
+	host, _ := os.Hostname()
+	return config.String("APP_INSTANCE_ID", fmt.Sprintf("%s-%d", host, os.Getpid()))
+}
+
+/*
+ * Start : 가져오기 주제 구독과 내보내기 버스 구독 시작
+ */
+func (br *Bridge) Start() error {
+	for _, subject := range br.cfg.Import {
+		if err := br.transport.Subscribe(subject, br.inject); err != nil {
+			return fmt.Errorf("%s subscribe %q: %w", br.cfg.Name, subject, err)
+		}
+	}
+
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	for _, topic := range br.cfg.Export {
+		br.subs = append(br.subs, br.bus.SubscribeTopicErr(topic, br.export,
+			bus.WithName(br.cfg.Name+"-bridge"),
+			bus.WithPriority(bus.PriorityLow), // 로컬 구독자가 먼저
+			bus.WithoutRetained()))            // 이미 내보낸 마지막 값을 다시 보내지 않음
+	}
+	br.log.Info("bridge started", zap.Strings("export", br.cfg.Export), zap.Strings("import", br.cfg.Import),
+		zap.String("prefix", br.cfg.Prefix), zap.String("origin", br.origin))
+	return nil
+}
+
+/*
+ * Stop : 내보내기 구독 해지 후 연결 종료
+ */
+func (br *Bridge) Stop() error {
+	br.mu.Lock()
+	subs := br.subs
+	br.subs = nil
+	br.mu.Unlock()
+	for _, s := range subs {
+		s.Unsubscribe()
+	}
+	return br.transport.Close()
+}
+
+/*
+ * export : 버스 메시지를 브로커로 발행
+ *  - 발행 실패는 에러로 반환 → 버스 재시도 후 데드레터 큐
+ */
+func (br *Bridge) export(ctx context.Context, m bus.Message) error {
+	if ctx.Value(importedKey{}) != nil {
+		return nil // 다른 인스턴스에서 가져온 이벤트
+	}
+	data, err := br.encode(m)
+	if err != nil {
+		br.drops.Record(br.cfg.Name, drops.ReasonValidation, "", fmt.Sprintf("encode %s: %v", m.Topic, err))
+		return nil
+	}
+	var key string
+	if k, ok := m.Payload.(bus.Keyed); ok {
+		key = k.EventKey()
+	}
+	subject := br.cfg.Prefix + m.Topic
+	if br.cfg.Subject != nil {
+		subject = br.cfg.Subject(m.Topic, key)
+	}
+	if err := br.transport.Publish(ctx, subject, key, data); err != nil {
+		br.metrics.failures.WithLabelValues(br.cfg.Name, directionExport).Inc()
+		return fmt.Errorf("%s publish: %w", br.cfg.Name, err)
+	}
+	br.metrics.messages.WithLabelValues(br.cfg.Name, directionExport).Inc()
+	return nil
+}
+
+// encode : 버스 메시지를 전송 형식으로
+func (br *Bridge) encode(m bus.Message) ([]byte, error) {
+	if br.cfg.Plain {
+		return plainJSON(m)
+	}
+	f := frame{Origin: br.origin, Topic: m.Topic}
+	var err error
+	f.Event, err = br.schemas.Encode(m.Payload)
+	if errors.Is(err, codec.ErrUnregistered) {
+		f.JSON, err = json.Marshal(m.Payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(f)
+}
+
+/*
+ * plainJSON : 외부 도구가 바로 읽을 수 있는 이벤트 JSON
+ *  - data.collected : {"device": "...", "values": {...}, "time": "RFC3339"}
+ *  - 그 외 이벤트   : 이벤트 값의 JSON 그대로
+ */
+func plainJSON(m bus.Message) ([]byte, error) {
+	if e, ok := m.Payload.(bus.DataCollectedEvent); ok {
+		at := e.Time // 측정 시각 (없으면 내보내는 시각)
+		if at.IsZero() {
+			at = time.Now()
+		}
+		return json.Marshal(struct {
+			Device string             `json:"device"`
+			Values map[string]float64 `json:"values"`
+			Time   time.Time          `json:"time"`
+		}{e.DeviceID, e.Values, at.UTC()})
+	}
+	return json.Marshal(m.Payload)
+}
+
+/*
+ * ExportFailed : 비동기로 내보내는 브로커가 나중에 알려 온 전달 실패 기록
+ *  - 버스 재시도/DLQ를 거치지 않으므로 드롭 기록기(reason=write_failed)와 실패 메트릭에 남김
+ */
+func (br *Bridge) ExportFailed(n int, err error) {
+	br.metrics.failures.WithLabelValues(br.cfg.Name, directionExport).Add(float64(n))
+	br.drops.Record(br.cfg.Name, drops.ReasonWriteFailed, "", fmt.Sprintf("%d messages: %v", n, err))
+	br.log.Warn("bridge delivery failed", zap.Int("messages", n), zap.Error(err))
+}
+
+/*
+ * inject : 브로커 메시지를 로컬 버스에 발행
+ *  - 읽을 수 없는 메시지는 드롭 기록기(source=브로커 이름, reason=validation)에 남기고 버림
+ */
+func (br *Bridge) inject(data []byte) {
+	var f frame
+	if err := json.Unmarshal(data, &f); err != nil || f.Topic == "" {
+		br.metrics.failures.WithLabelValues(br.cfg.Name, directionImport).Inc()
+		br.drops.Record(br.cfg.Name, drops.ReasonValidation, "", fmt.Sprintf("invalid frame: %v", err))
+		return
+	}
+	if f.Origin == br.origin {
+		return // 자기 인스턴스가 내보낸 메시지
+	}
+	ctx := context.WithValue(context.Background(), importedKey{}, f.Origin)
+
+	if len(f.Event) > 0 {
+		e, _, err := br.schemas.Decode(f.Event)
+		if err != nil {
+			br.metrics.failures.WithLabelValues(br.cfg.Name, directionImport).Inc()
+			br.drops.Record(br.cfg.Name, drops.ReasonValidation, "", fmt.Sprintf("decode %s: %v", f.Topic, err))
+			return
+		}
+		br.metrics.messages.WithLabelValues(br.cfg.Name, directionImport).Inc()
+		br.bus.PublishTopic(ctx, f.Topic, e)
+		return
+	}
+	br.metrics.messages.WithLabelValues(br.cfg.Name, directionImport).Inc()
+	br.bus.PublishTopic(ctx, f.Topic, f.JSON)
+}
+
+/*
+ * Imported : 이벤트가 브리지를 통해 다른 인스턴스에서 들어왔는지와 보낸 인스턴스 ID
+ *  - 구독자가 원격 이벤트를 다르게 처리해야 할 때 사용 (예: 로컬 수집분만 저장)
+ */
+func Imported(ctx context.Context) (origin string, ok bool) {
+	origin, ok = ctx.Value(importedKey{}).(string)
+	return origin, ok
+}
@@ -0,0 +1,106 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 브리지 메트릭
+	"github.com/segmentio/kafka-go"                  // Kafka 클라이언트
+	"go.uber.org/fx"                                 // 라이프사이클 훅
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 로컬 이벤트 버스
+	"generic-api-scaffold/internal/codec"  // 이벤트 스키마 레지스트리
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 전달 실패 기록
+)
+
+/*
+ * kafkaTransport : Kafka Transport 구현 (내보내기 전용)
+ *  - 모든 이벤트를 하나의 Kafka 토픽에 쓰고, 버스 토픽은 "topic" 헤더와 메시지 본문(frame)에 담음
+ *  - 메시지 키는 이벤트 키(장치 ID) → 같은 장치의 이벤트는 같은 파티션에 순서대로 쌓임
+ *  - 비동기 배치 쓰기 : Publish는 배치에 넣고 바로 반환하며, 배치 전송 실패는 Completion으로 알림
+ */
+type kafkaTransport struct {
+	w *kafka.Writer
+}
+
+func (t *kafkaTransport) Publish(ctx context.Context, subject, key string, data []byte) error {
+	return t.w.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(key),
+		Value:   data,
+		Headers: []kafka.Header{{Key: "topic", Value: []byte(subject)}},
+	})
+}
+
+func (t *kafkaTransport) Subscribe(string, func([]byte)) error {
+	return fmt.Errorf("kafka bridge is export-only")
+}
+
+// Close : 배치에 남은 메시지를 보낸 뒤 종료
+func (t *kafkaTransport) Close() error {
+	return t.w.Close()
+}
+
+/*
+ * NewKafkaBridge : fx가 호출하는 Kafka 내보내기 브리지 생성자 (선택 모듈 "kafka")
+ *  - APP_KAFKA_BROKERS       : 브로커 주소, 쉼표 구분 (비어 있으면 비활성, 기본 비활성, 예: kafka-1:9092,kafka-2:9092)
+ *  - APP_KAFKA_TOPIC         : 쓸 Kafka 토픽 (기본 "scaffold.events")
+ *  - APP_KAFKA_EXPORT        : 내보낼 버스 토픽 패턴, 쉼표 구분 (기본 "data.collected", 새 이벤트 타입은 토픽만 추가)
+ *  - APP_KAFKA_BATCH_SIZE    : 배치당 최대 메시지 수 (기본 100)
+ *  - APP_KAFKA_BATCH_TIMEOUT : 배치가 차지 않아도 보내기까지 기다리는 시간 (기본 1s)
+ *  - APP_KAFKA_ACKS          : all | one | none (기본 all)
+ *  - 전달 실패는 scaffold_bridge_failures_total{bridge="kafka",direction="export"}와 /drops(reason=write_failed)에 기록
+ *  - OnStop : 버스 구독 해지 후 남은 배치를 보내고 종료
+ */
+func NewKafkaBridge(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, b *bus.EventBus, schemas *codec.Registry, dr *drops.Recorder) *Bridge {
+	brokers := config.List("APP_KAFKA_BROKERS", nil)
+	if len(brokers) == 0 {
+		return nil
+	}
+	acks, ok := map[string]kafka.RequiredAcks{
+		"all":  kafka.RequireAll,
+		"one":  kafka.RequireOne,
+		"none": kafka.RequireNone,
+	}[config.String("APP_KAFKA_ACKS", "all")]
+	if !ok {
+		log.Fatal("invalid APP_KAFKA_ACKS, expected all|one|none")
+	}
+	batchSize := config.Int(log, "APP_KAFKA_BATCH_SIZE", 100)
+	if batchSize < 1 {
+		log.Fatal("APP_KAFKA_BATCH_SIZE must be positive", zap.Int("value", batchSize))
+	}
+
+	w := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        config.String("APP_KAFKA_TOPIC", "scaffold.events"),
+		Balancer:     &kafka.Hash{}, // 키(장치 ID) 해시로 파티션 선택
+		BatchSize:    batchSize,
+		BatchTimeout: config.Duration(log, "APP_KAFKA_BATCH_TIMEOUT", time.Second),
+		RequiredAcks: acks,
+		Async:        true,
+	}
+	cfg := Config{
+		Name:   "kafka",
+		Export: config.List("APP_KAFKA_EXPORT", []string{bus.TopicDataCollected}),
+	}
+	br := New(log, reg, b, schemas, dr, &kafkaTransport{w: w}, cfg)
+	w.Completion = func(messages []kafka.Message, err error) {
+		if err != nil {
+			br.ExportFailed(len(messages), err)
+		}
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			br.log.Info("kafka writer configured", zap.Strings("brokers", brokers), zap.String("topic", w.Topic))
+			return br.Start()
+		},
+		OnStop: func(context.Context) error {
+			return br.Stop()
+		},
+	})
+	b.DrainBefore(lc) // 구독 해지·연결 종료 전에 버스에 남은 이벤트를 내보냄
+	return br
+}
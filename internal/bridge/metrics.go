@@ -0,0 +1,54 @@
+/*
+ * 브리지 메트릭
+ *  - scaffold_bridge_messages_total{bridge,direction}  : 브로커로 넘긴(export) / 브로커에서 받아 발행한(import) 메시지 수
+ *  - scaffold_bridge_failures_total{bridge,direction}  : 전달 실패 수 (export : 발행 실패·비동기 배치 실패, import : 읽을 수 없는 메시지)
+ *  - 여러 브리지가 같은 레지스트리를 쓰므로, 이미 등록된 메트릭이 있으면 그것을 이어서 사용
+ */
+package bridge
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 레지스트리
+)
+
+// 전달 방향 라벨
+const (
+	directionExport = "export"
+	directionImport = "import"
+)
+
+// bridgeMetrics : 브리지 메트릭 묶음
+type bridgeMetrics struct {
+	messages *prometheus.CounterVec
+	failures *prometheus.CounterVec
+}
+
+// newBridgeMetrics : 브리지 메트릭 생성 (이미 등록되어 있으면 기존 것 사용)
+func newBridgeMetrics(reg *prometheus.Registry) *bridgeMetrics {
+	return &bridgeMetrics{
+		messages: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bridge_messages_total",
+			Help:      "Messages handed to (export) or received from (import) an external broker, by bridge and direction.",
+		}),
+		failures: registerCounterVec(reg, prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bridge_failures_total",
+			Help:      "Messages that failed to reach (export) or could not be read from (import) an external broker, by bridge and direction.",
+		}),
+	}
+}
+
+// registerCounterVec : {bridge,direction} 카운터 등록
+func registerCounterVec(reg *prometheus.Registry, opts prometheus.CounterOpts) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(opts, []string{"bridge", "direction"})
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return are.ExistingCollector.(*prometheus.CounterVec)
+		}
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,125 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"       // MQTT 클라이언트
+	"github.com/prometheus/client_golang/prometheus" // 브리지 메트릭
+	"go.uber.org/fx"                                 // 라이프사이클 훅
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 로컬 이벤트 버스
+	"generic-api-scaffold/internal/codec"  // 이벤트 스키마 레지스트리
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 전달 실패 기록
+)
+
+/*
+ * mqttTransport : MQTT Transport 구현 (내보내기 전용)
+ *  - 브로커의 확인(PUBACK 등)을 timeout까지 기다리고, 넘으면 에러 → 버스 재시도 후 DLQ
+ */
+type mqttTransport struct {
+	client   mqtt.Client
+	qos      byte
+	retained bool
+	timeout  time.Duration
+}
+
+func (t *mqttTransport) Publish(_ context.Context, subject, _ string, data []byte) error {
+	tok := t.client.Publish(subject, t.qos, t.retained, data)
+	if !tok.WaitTimeout(t.timeout) {
+		return fmt.Errorf("mqtt publish %q timed out after %s", subject, t.timeout)
+	}
+	return tok.Error()
+}
+
+func (t *mqttTransport) Subscribe(string, func([]byte)) error {
+	return fmt.Errorf("mqtt bridge is export-only")
+}
+
+// Close : 보내는 중인 메시지를 최대 1초 기다린 뒤 연결 종료
+func (t *mqttTransport) Close() error {
+	t.client.Disconnect(1000)
+	return nil
+}
+
+/*
+ * mqttSubject : 주제 템플릿 채우기
+ *  - {device} : 이벤트 키(장치 ID, 없으면 "_"), {topic} : 버스 토픽(점은 /로 바꿈)
+ *  - MQTT 와일드카드/구분 문자(+ # /)가 장치 ID에 들어 있으면 _로 바꿈
+ */
+func mqttSubject(tpl, topic, key string) string {
+	if key == "" {
+		key = "_"
+	}
+	key = strings.NewReplacer("+", "_", "#", "_", "/", "_").Replace(key)
+	return strings.NewReplacer("{device}", key, "{topic}", strings.ReplaceAll(topic, ".", "/")).Replace(tpl)
+}
+
+/*
+ * NewMQTTBridge : fx가 호출하는 MQTT 내보내기 브리지 생성자 (선택 모듈 "mqtt")
+ *  - APP_MQTT_BROKER    : 브로커 주소 (비어 있으면 비활성, 기본 비활성, 예: tcp://localhost:1883, ssl://broker:8883)
+ *  - APP_MQTT_TOPIC     : 주제 템플릿 (기본 "site/{device}/data", {device}·{topic} 치환)
+ *  - APP_MQTT_EXPORT    : 내보낼 버스 토픽 패턴, 쉼표 구분 (기본 "data.collected")
+ *  - APP_MQTT_QOS       : 0 | 1 | 2 (기본 1)
+ *  - APP_MQTT_RETAINED  : retained 플래그 (기본 true → 새로 붙은 SCADA 클라이언트가 장치별 마지막 값을 바로 받음)
+ *  - APP_MQTT_CLIENT_ID : 클라이언트 ID (기본 "scaffold-" + 인스턴스 ID)
+ *  - APP_MQTT_USERNAME / APP_MQTT_PASSWORD : 인증 정보 (기본 없음)
+ *  - APP_MQTT_TIMEOUT   : 발행 확인 대기 시간 (기본 5s)
+ *  - 메시지 본문은 외부 도구가 바로 읽을 수 있는 이벤트 JSON (예: {"device":"A1","values":{...},"time":"..."})
+ *  - 브로커가 내려가 있어도 앱 시작을 막지 않고 백그라운드에서 계속 재연결
+ */
+func NewMQTTBridge(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, b *bus.EventBus, schemas *codec.Registry, dr *drops.Recorder) *Bridge {
+	broker := config.String("APP_MQTT_BROKER", "")
+	if broker == "" {
+		return nil
+	}
+	qos := config.Int(log, "APP_MQTT_QOS", 1)
+	if qos < 0 || qos > 2 {
+		log.Fatal("APP_MQTT_QOS must be 0, 1 or 2", zap.Int("value", qos))
+	}
+	tpl := config.String("APP_MQTT_TOPIC", "site/{device}/data")
+
+	t := &mqttTransport{
+		qos:      byte(qos),
+		retained: config.Bool(log, "APP_MQTT_RETAINED", true),
+		timeout:  config.Duration(log, "APP_MQTT_TIMEOUT", 5*time.Second),
+	}
+	cfg := Config{
+		Name:    "mqtt",
+		Export:  config.List("APP_MQTT_EXPORT", []string{bus.TopicDataCollected}),
+		Subject: func(topic, key string) string { return mqttSubject(tpl, topic, key) },
+		Plain:   true,
+	}
+	br := New(log, reg, b, schemas, dr, t, cfg)
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(config.String("APP_MQTT_CLIENT_ID", "scaffold-"+br.origin)).
+		SetUsername(config.String("APP_MQTT_USERNAME", "")).
+		SetPassword(config.String("APP_MQTT_PASSWORD", "")).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			br.log.Warn("mqtt connection lost", zap.Error(err))
+		}).
+		SetOnConnectHandler(func(mqtt.Client) {
+			br.log.Info("mqtt connected", zap.String("broker", broker))
+		})
+	t.client = mqtt.NewClient(opts)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			t.client.Connect() // SetConnectRetry : 연결될 때까지 백그라운드에서 재시도
+			return br.Start()
+		},
+		OnStop: func(context.Context) error {
+			return br.Stop()
+		},
+	})
+	b.DrainBefore(lc) // 구독 해지·연결 종료 전에 버스에 남은 이벤트를 내보냄
+	return br
+}
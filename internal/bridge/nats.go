@@ -0,0 +1,116 @@
+package bridge
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"                     // NATS 클라이언트
+	"github.com/prometheus/client_golang/prometheus" // 브리지 메트릭
+	"go.uber.org/fx"                                 // 라이프사이클 훅
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 로컬 이벤트 버스
+	"generic-api-scaffold/internal/codec"  // 이벤트 스키마 레지스트리
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 읽을 수 없는 메시지 기록
+)
+
+/*
+ * natsTransport : NATS Transport 구현
+ *  - queue가 있으면 큐 그룹으로 구독 (같은 큐 그룹의 인스턴스 중 하나만 받음, 작업 분배용)
+ */
+type natsTransport struct {
+	nc    *nats.Conn
+	queue string
+
+	mu   sync.Mutex
+	subs []*nats.Subscription
+}
+
+func (t *natsTransport) Publish(_ context.Context, subject, _ string, data []byte) error {
+	return t.nc.Publish(subject, data)
+}
+
+func (t *natsTransport) Subscribe(subject string, fn func(data []byte)) error {
+	handler := func(m *nats.Msg) { fn(m.Data) }
+	var (
+		sub *nats.Subscription
+		err error
+	)
+	if t.queue != "" {
+		sub, err = t.nc.QueueSubscribe(subject, t.queue, handler)
+	} else {
+		sub, err = t.nc.Subscribe(subject, handler)
+	}
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.subs = append(t.subs, sub)
+	t.mu.Unlock()
+	return nil
+}
+
+// Close : 처리 중인 메시지를 마저 처리하고 보낼 메시지를 내보낸 뒤 종료 (Drain)
+func (t *natsTransport) Close() error {
+	return t.nc.Drain()
+}
+
+/*
+ * NewNATSBridge : fx가 호출하는 NATS 브리지 생성자 (선택 모듈 "nats")
+ *  - APP_NATS_URL       : NATS 서버 주소 (비어 있으면 브리지 비활성, 기본 비활성, 예: nats://localhost:4222)
+ *  - APP_NATS_EXPORT    : 내보낼 버스 토픽 패턴, 쉼표 구분 (기본 "data.collected")
+ *  - APP_NATS_IMPORT    : 가져올 NATS 주제, 쉼표 구분·NATS 와일드카드(* / >) (기본 없음, 예: "scaffold.>")
+ *  - APP_NATS_PREFIX    : 주제 접두사 (기본 "scaffold.")
+ *  - APP_NATS_QUEUE     : 가져오기 큐 그룹 이름 (기본 없음 → 모든 인스턴스가 받음)
+ *  - APP_NATS_CREDS     : NATS 자격 증명(.creds) 파일 경로 (기본 없음)
+ *  - 서버가 내려가 있어도 앱 시작을 막지 않고 백그라운드에서 계속 재연결
+ *  - OnStart : 연결 후 구독 시작 / OnStop : 버스 구독 해지 후 연결 Drain
+ */
+func NewNATSBridge(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, b *bus.EventBus, schemas *codec.Registry, dr *drops.Recorder) *Bridge {
+	url := config.String("APP_NATS_URL", "")
+	if url == "" {
+		return nil
+	}
+	cfg := Config{
+		Name:   "nats",
+		Export: config.List("APP_NATS_EXPORT", []string{bus.TopicDataCollected}),
+		Import: config.List("APP_NATS_IMPORT", nil),
+		Prefix: config.String("APP_NATS_PREFIX", "scaffold."),
+	}
+	t := &natsTransport{queue: config.String("APP_NATS_QUEUE", "")}
+	br := New(log, reg, b, schemas, dr, t, cfg)
+
+	opts := []nats.Option{
+		nats.Name("generic-api-scaffold " + br.origin),
+		nats.MaxReconnects(-1), // 무한 재연결
+		nats.ReconnectWait(2 * time.Second),
+		nats.RetryOnFailedConnect(true),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			br.log.Warn("nats disconnected", zap.Error(err))
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			br.log.Info("nats reconnected", zap.String("url", nc.ConnectedUrl()))
+		}),
+	}
+	if creds := config.String("APP_NATS_CREDS", ""); creds != "" {
+		opts = append(opts, nats.UserCredentials(creds))
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			nc, err := nats.Connect(url, opts...)
+			if err != nil {
+				return err
+			}
+			t.nc = nc
+			return br.Start()
+		},
+		OnStop: func(context.Context) error {
+			return br.Stop()
+		},
+	})
+	b.DrainBefore(lc) // 구독 해지·연결 종료 전에 버스에 남은 이벤트를 내보냄
+	return br
+}
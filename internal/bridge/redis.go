@@ -0,0 +1,173 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 브리지 메트릭
+	"github.com/redis/go-redis/v9"                   // Redis 클라이언트
+	"go.uber.org/fx"                                 // 라이프사이클 훅
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 로컬 이벤트 버스
+	"generic-api-scaffold/internal/codec"  // 이벤트 스키마 레지스트리
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 읽을 수 없는 메시지 기록
+)
+
+// redisField : 스트림 항목에서 frame을 담는 필드 이름
+const redisField = "frame"
+
+/*
+ * redisTransport : Redis Streams Transport 구현
+ *  - 발행 : XADD (MAXLEN ~ maxLen으로 스트림 길이 제한)
+ *  - 구독 : 소비자 그룹으로 XREADGROUP → 로컬 버스에 발행한 뒤 XACK
+ *      시작할 때 자기 소비자의 미확인(pending) 항목부터 다시 읽으므로, 처리 도중 종료되어도 잃지 않음
+ *  - 소비자 그룹이 인스턴스마다 다르면(기본) 모든 인스턴스가 모든 항목을 받고, 같으면 인스턴스끼리 나눠 받음
+ */
+type redisTransport struct {
+	log      *zap.Logger
+	client   *redis.Client
+	group    string
+	consumer string
+	maxLen   int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (t *redisTransport) Publish(ctx context.Context, subject, _ string, data []byte) error {
+	return t.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: subject,
+		MaxLen: t.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{redisField: data},
+	}).Err()
+}
+
+func (t *redisTransport) Subscribe(stream string, fn func(data []byte)) error {
+	err := t.client.XGroupCreateMkStream(t.ctx, stream, t.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") { // 이미 있는 그룹
+		return err
+	}
+	t.wg.Add(1)
+	go t.read(stream, fn)
+	return nil
+}
+
+/*
+ * read : 스트림 하나의 읽기 루프
+ *  - 처음에는 "0"(자기 미확인 항목), 다 읽으면 ">"(새 항목)
+ *  - Redis 오류 시 1초 뒤 다시 시도
+ */
+func (t *redisTransport) read(stream string, fn func(data []byte)) {
+	defer t.wg.Done()
+	start := "0"
+	for t.ctx.Err() == nil {
+		res, err := t.client.XReadGroup(t.ctx, &redis.XReadGroupArgs{
+			Group:    t.group,
+			Consumer: t.consumer,
+			Streams:  []string{stream, start},
+			Count:    100,
+			Block:    5 * time.Second,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue // 대기 시간 동안 새 항목 없음
+		}
+		if err != nil {
+			if t.ctx.Err() == nil {
+				t.log.Warn("redis stream read failed", zap.String("stream", stream), zap.Error(err))
+				select {
+				case <-t.ctx.Done():
+				case <-time.After(time.Second):
+				}
+			}
+			continue
+		}
+
+		n := 0
+		for _, s := range res {
+			for _, msg := range s.Messages {
+				n++
+				if v, ok := msg.Values[redisField].(string); ok {
+					fn([]byte(v))
+				} else {
+					fn(nil) // frame이 없는 항목 → 읽을 수 없는 메시지로 기록
+				}
+				if err := t.client.XAck(t.ctx, stream, t.group, msg.ID).Err(); err != nil {
+					t.log.Warn("redis stream ack failed", zap.String("stream", stream), zap.String("id", msg.ID), zap.Error(err))
+				}
+			}
+		}
+		if start == "0" && n == 0 {
+			start = ">" // 미확인 항목을 모두 처리함
+		}
+	}
+}
+
+// Close : 읽기 루프를 멈춘 뒤 연결 종료
+func (t *redisTransport) Close() error {
+	t.cancel()
+	t.wg.Wait()
+	return t.client.Close()
+}
+
+/*
+ * NewRedisBridge : fx가 호출하는 Redis Streams 브리지 생성자 (선택 모듈 "redis")
+ *  - APP_REDIS_URL    : Redis 주소 (비어 있으면 비활성, 기본 비활성, 예: redis://:password@localhost:6379/0)
+ *  - APP_REDIS_EXPORT : 내보낼 버스 토픽 패턴, 쉼표 구분 (기본 "data.collected")
+ *  - APP_REDIS_IMPORT : 가져올 스트림 키, 쉼표 구분 (기본 "scaffold:data.collected")
+ *  - APP_REDIS_PREFIX : 스트림 키 접두사 (기본 "scaffold:", 스트림 키 = 접두사 + 버스 토픽)
+ *  - APP_REDIS_GROUP  : 소비자 그룹 (기본 인스턴스 ID → 인스턴스마다 모든 항목을 받음)
+ *  - APP_REDIS_MAXLEN : 스트림 최대 길이 (근사값, 기본 100000)
+ *  - OnStart : 소비자 그룹 생성 후 읽기 시작 / OnStop : 버스 구독 해지, 읽기 루프 종료 후 연결 닫기
+ */
+func NewRedisBridge(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, b *bus.EventBus, schemas *codec.Registry, dr *drops.Recorder) *Bridge {
+	url := config.String("APP_REDIS_URL", "")
+	if url == "" {
+		return nil
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		log.Fatal("invalid APP_REDIS_URL", zap.Error(err))
+	}
+	maxLen := config.Int(log, "APP_REDIS_MAXLEN", 100000)
+	if maxLen < 1 {
+		log.Fatal("APP_REDIS_MAXLEN must be positive", zap.Int("value", maxLen))
+	}
+	prefix := config.String("APP_REDIS_PREFIX", "scaffold:")
+	cfg := Config{
+		Name:   "redis",
+		Export: config.List("APP_REDIS_EXPORT", []string{bus.TopicDataCollected}),
+		Import: config.List("APP_REDIS_IMPORT", []string{prefix + bus.TopicDataCollected}),
+		Prefix: prefix,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	origin := InstanceID()
+	t := &redisTransport{
+		log:      log.With(zap.String("bridge", "redis")),
+		client:   redis.NewClient(opts),
+		group:    config.String("APP_REDIS_GROUP", origin),
+		consumer: origin,
+		maxLen:   int64(maxLen),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	br := New(log, reg, b, schemas, dr, t, cfg)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			return br.Start()
+		},
+		OnStop: func(context.Context) error {
+			return br.Stop()
+		},
+	})
+	b.DrainBefore(lc) // 구독 해지·연결 종료 전에 버스에 남은 이벤트를 내보냄
+	return br
+}
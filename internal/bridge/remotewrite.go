@@ -0,0 +1,286 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"                       // remote-write 본문 압축 (블록 형식)
+	"github.com/prometheus/client_golang/prometheus" // 브리지 메트릭
+	"go.uber.org/fx"                                 // 라이프사이클 훅
+	"go.uber.org/zap"                                // 로깅 도구
+	"google.golang.org/protobuf/encoding/protowire"  // remote-write protobuf 인코딩
+
+	"generic-api-scaffold/internal/bus"    // 로컬 이벤트 버스
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 전달 실패 기록
+)
+
+// remote-write 1.0 (prometheus/prompb/remote.proto, types.proto) 필드 번호
+const (
+	rwTimeseries protowire.Number = 1 // WriteRequest.timeseries
+
+	rwLabels  protowire.Number = 1 // TimeSeries.labels
+	rwSamples protowire.Number = 2 // TimeSeries.samples
+
+	rwLabelName  protowire.Number = 1 // Label.name
+	rwLabelValue protowire.Number = 2 // Label.value
+
+	rwSampleValue     protowire.Number = 1 // Sample.value (double)
+	rwSampleTimestamp protowire.Number = 2 // Sample.timestamp (밀리초)
+)
+
+// 다시 보내도 소용없는 응답 (본문·라벨 문제) → 재시도하지 않고 드롭 기록
+var errRemoteWriteRejected = errors.New("remote write rejected")
+
+// rwLabel : 라벨 하나
+type rwLabel struct{ name, value string }
+
+// rwSeries : 시계열 하나 (라벨은 이름순 정렬, 샘플은 시각순)
+type rwSeries struct {
+	labels []rwLabel
+	values []float64
+	stamps []int64 // 밀리초
+}
+
+/*
+ * RemoteWriteSink : 수집 값을 Prometheus remote-write 요청으로 보내는 싱크 (내보내기 전용)
+ *  - Mimir/Thanos Receive/VictoriaMetrics/Prometheus(--web.enable-remote-write-receiver)가 기존 수집 경로로 데이터를 받음
+ *  - 장치·필드 → 메트릭 : 이름은 접두사 + 필드(메트릭 이름에 쓸 수 없는 문자는 _), 라벨은 device + 고정 라벨
+ *      예) 장치 A1의 temperature=21.5 → scaffold_temperature{device="A1",site="resort-a"} 21.5
+ *  - 시각은 이벤트의 측정 시각 (밀리초, 없으면 버스에서 묶음을 받은 시각), 같은 묶음 안에 같은 시계열·같은 밀리초 값이 여러 번 있으면 마지막 값만 보냄
+ *    순서가 뒤바뀌어 도착한 이벤트도 시계열마다 시각순으로 정렬해 보냄
+ */
+type RemoteWriteSink struct {
+	log     *zap.Logger
+	client  *http.Client
+	url     string
+	prefix  string
+	labels  []rwLabel // 고정 라벨
+	headers map[string]string
+	drops   *drops.Recorder
+	metrics *bridgeMetrics
+	sub     *bus.Subscription
+}
+
+/*
+ * NewRemoteWriteSink : fx가 호출하는 remote-write 싱크 생성자 (선택 모듈 "remote_write")
+ *  - APP_REMOTE_WRITE_URL            : 수신 엔드포인트 (비어 있으면 비활성, 기본 비활성, 예: http://mimir:9009/api/v1/push)
+ *  - APP_REMOTE_WRITE_PREFIX         : 메트릭 이름 접두사 (기본 "scaffold_")
+ *  - APP_REMOTE_WRITE_LABELS         : 모든 시계열에 붙일 고정 라벨, 쉼표 구분 (예: site=resort-a,env=prod, device와 __name__은 예약)
+ *  - APP_REMOTE_WRITE_TENANT         : X-Scope-OrgID 헤더 (Mimir/Cortex 테넌트, 기본 없음)
+ *  - APP_REMOTE_WRITE_USERNAME / APP_REMOTE_WRITE_PASSWORD : 기본 인증 (기본 없음)
+ *  - APP_REMOTE_WRITE_BEARER_TOKEN   : Bearer 토큰 (기본 없음, 기본 인증보다 우선)
+ *  - APP_REMOTE_WRITE_TIMEOUT        : 요청 제한 시간 (기본 10s)
+ *  - APP_REMOTE_WRITE_BATCH_SIZE     : 요청 하나에 담을 최대 이벤트 수 (기본 500)
+ *  - APP_REMOTE_WRITE_BATCH_LATENCY  : 묶음이 차지 않아도 보내기까지 기다리는 시간 (기본 5s)
+ *  - APP_REMOTE_WRITE_RETRY_ATTEMPTS : 첫 전송 포함 최대 시도 횟수 (기본 3, 5xx/429/연결 실패만 재시도, 끝내 실패하면 데드레터 큐)
+ *  - 4xx 응답(429 제외)은 다시 보내도 소용없으므로 /drops(source="remote_write", reason=write_failed)에 남기고 버림
+ *  - 전송한 샘플 수와 실패 수는 scaffold_bridge_messages_total / scaffold_bridge_failures_total{bridge="remote_write"}
+ */
+func NewRemoteWriteSink(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, b *bus.EventBus, dr *drops.Recorder) *RemoteWriteSink {
+	url := config.String("APP_REMOTE_WRITE_URL", "")
+	if url == "" {
+		return nil
+	}
+	s := &RemoteWriteSink{
+		log:     log.With(zap.String("bridge", "remote_write")),
+		client:  &http.Client{Timeout: config.Duration(log, "APP_REMOTE_WRITE_TIMEOUT", 10*time.Second)},
+		url:     url,
+		prefix:  config.String("APP_REMOTE_WRITE_PREFIX", "scaffold_"),
+		headers: make(map[string]string),
+		drops:   dr,
+		metrics: newBridgeMetrics(reg),
+	}
+	if s.prefix != "" && metricName("", s.prefix) != s.prefix {
+		log.Fatal("invalid APP_REMOTE_WRITE_PREFIX, expected [a-zA-Z_:][a-zA-Z0-9_:]*", zap.String("value", s.prefix))
+	}
+	for _, kv := range config.List("APP_REMOTE_WRITE_LABELS", nil) {
+		k, v, ok := strings.Cut(kv, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" || k == "device" || k == "__name__" || metricName("", k) != k || strings.Contains(k, ":") {
+			log.Fatal("invalid APP_REMOTE_WRITE_LABELS entry, expected name=value (device and __name__ are reserved)", zap.String("entry", kv))
+		}
+		s.labels = append(s.labels, rwLabel{k, v})
+	}
+	if t := config.String("APP_REMOTE_WRITE_TENANT", ""); t != "" {
+		s.headers["X-Scope-OrgID"] = t
+	}
+	if tok := config.String("APP_REMOTE_WRITE_BEARER_TOKEN", ""); tok != "" {
+		s.headers["Authorization"] = "Bearer " + tok
+	} else if user := config.String("APP_REMOTE_WRITE_USERNAME", ""); user != "" {
+		cred := user + ":" + config.String("APP_REMOTE_WRITE_PASSWORD", "")
+		s.headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(cred))
+	}
+	size := config.Int(log, "APP_REMOTE_WRITE_BATCH_SIZE", 500)
+	if size < 1 {
+		log.Fatal("APP_REMOTE_WRITE_BATCH_SIZE must be positive", zap.Int("value", size))
+	}
+	opts := []bus.SubscribeOption{
+		bus.WithBatch(size, config.Duration(log, "APP_REMOTE_WRITE_BATCH_LATENCY", 5*time.Second)),
+		bus.WithRetry(config.Int(log, "APP_REMOTE_WRITE_RETRY_ATTEMPTS", 3), time.Second),
+		bus.WithName("remote_write-bridge"),
+		bus.WithPriority(bus.PriorityLow), // 로컬 구독자가 먼저
+		bus.WithoutRetained(),             // 이미 보낸 마지막 값을 다시 보내지 않음
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			s.sub = b.SubscribeBatch(s.write, opts...)
+			s.log.Info("remote write sink started", zap.String("url", url), zap.String("prefix", s.prefix))
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			if s.sub != nil {
+				s.sub.Unsubscribe()
+			}
+			return nil
+		},
+	})
+	b.DrainBefore(lc) // 구독 해지·연결 종료 전에 버스에 남은 이벤트를 내보냄
+	return s
+}
+
+/*
+ * write : 이벤트 묶음을 remote-write 요청 하나로 보냄
+ *  - 재시도할 만한 실패는 에러로 반환 → 버스 재시도 후 데드레터 큐
+ */
+func (s *RemoteWriteSink) write(ctx context.Context, es []bus.DataCollectedEvent) error {
+	series := s.series(es, time.Now().UnixMilli())
+	if len(series) == 0 {
+		return nil
+	}
+	samples := 0
+	for _, ts := range series {
+		samples += len(ts.values)
+	}
+
+	err := s.post(ctx, snappy.Encode(nil, encodeWriteRequest(series)))
+	switch {
+	case errors.Is(err, errRemoteWriteRejected):
+		s.metrics.failures.WithLabelValues("remote_write", directionExport).Add(float64(samples))
+		s.drops.Record("remote_write", drops.ReasonWriteFailed, "", fmt.Sprintf("%d samples: %v", samples, err))
+		s.log.Warn("remote write rejected", zap.Int("samples", samples), zap.Error(err))
+		return nil
+	case err != nil:
+		s.metrics.failures.WithLabelValues("remote_write", directionExport).Add(float64(samples))
+		return fmt.Errorf("remote write: %w", err)
+	}
+	s.metrics.messages.WithLabelValues("remote_write", directionExport).Add(float64(samples))
+	return nil
+}
+
+// series : 이벤트 묶음 → 시계열 목록 (NaN/±Inf도 그대로 보냄, Prometheus가 지원)
+func (s *RemoteWriteSink) series(es []bus.DataCollectedEvent, now int64) []*rwSeries {
+	index := make(map[string]*rwSeries)
+	var out []*rwSeries
+	for _, e := range es {
+		stamp := now
+		if !e.Time.IsZero() {
+			stamp = e.Time.UnixMilli()
+		}
+		for field, v := range e.Values {
+			name := metricName(s.prefix, field)
+			key := name + "\xff" + e.DeviceID
+			ts, ok := index[key]
+			if !ok {
+				labels := append([]rwLabel{{"__name__", name}, {"device", e.DeviceID}}, s.labels...)
+				sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+				ts = &rwSeries{labels: labels}
+				index[key] = ts
+				out = append(out, ts)
+			}
+			i := sort.Search(len(ts.stamps), func(i int) bool { return ts.stamps[i] >= stamp })
+			if i < len(ts.stamps) && ts.stamps[i] == stamp {
+				ts.values[i] = v // 같은 밀리초에 두 번 → 나중 값
+				continue
+			}
+			ts.values = append(ts.values, 0)
+			ts.stamps = append(ts.stamps, 0)
+			copy(ts.values[i+1:], ts.values[i:])
+			copy(ts.stamps[i+1:], ts.stamps[i:])
+			ts.values[i], ts.stamps[i] = v, stamp
+		}
+	}
+	return out
+}
+
+// post : 압축한 요청 본문 전송 (2xx 성공, 5xx/429는 재시도할 에러, 그 밖은 errRemoteWriteRejected)
+func (s *RemoteWriteSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "generic-api-scaffold")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) // 수신자가 알려 주는 거절 이유
+	switch {
+	case resp.StatusCode/100 == 2:
+		return nil
+	case resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return fmt.Errorf("%w: %s: %s", errRemoteWriteRejected, resp.Status, bytes.TrimSpace(msg))
+}
+
+// encodeWriteRequest : 시계열 목록 → WriteRequest protobuf
+func encodeWriteRequest(series []*rwSeries) []byte {
+	var out []byte
+	for _, ts := range series {
+		var b []byte
+		for _, l := range ts.labels {
+			var lb []byte
+			lb = protowire.AppendTag(lb, rwLabelName, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.name)
+			lb = protowire.AppendTag(lb, rwLabelValue, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.value)
+			b = protowire.AppendTag(b, rwLabels, protowire.BytesType)
+			b = protowire.AppendBytes(b, lb)
+		}
+		for i, v := range ts.values {
+			var sb []byte
+			sb = protowire.AppendTag(sb, rwSampleValue, protowire.Fixed64Type)
+			sb = protowire.AppendFixed64(sb, math.Float64bits(v))
+			sb = protowire.AppendTag(sb, rwSampleTimestamp, protowire.VarintType)
+			sb = protowire.AppendVarint(sb, uint64(ts.stamps[i]))
+			b = protowire.AppendTag(b, rwSamples, protowire.BytesType)
+			b = protowire.AppendBytes(b, sb)
+		}
+		out = protowire.AppendTag(out, rwTimeseries, protowire.BytesType)
+		out = protowire.AppendBytes(out, b)
+	}
+	return out
+}
+
+// metricName : 접두사 + 이름, Prometheus 메트릭 이름에 쓸 수 없는 문자는 _로 바꿈
+func metricName(prefix, name string) string {
+	var sb strings.Builder
+	for i, r := range prefix + name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			r = '_'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
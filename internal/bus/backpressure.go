@@ -0,0 +1,242 @@
+/*
+ * 백프레셔(backpressure) 정책 : ordered 구독자의 큐가 가득 찼을 때 새 메시지를 어떻게 처리할지 정합니다.
+ *  - block       : 큐에 자리가 날 때까지 발행자가 대기 (유실 없음, 느린 구독자가 발행자를 늦춤)
+ *  - drop_oldest : 가장 오래된 메시지를 버리고 새 메시지를 넣음 (최신 데이터 우선)
+ *  - drop_newest : 새 메시지를 버림 (이미 쌓인 데이터 우선)
+ *  - coalesce    : 같은 키(장치 ID, Keyed)의 메시지가 큐에 있으면 그 자리를 새 메시지로 교체,
+ *                  없으면 drop_oldest와 같이 동작 (장치별 최신 상태만 필요한 구독자용)
+ *  - 버린 메시지는 드롭 기록기(source="bus", reason="backpressure")에 남아
+ *    scaffold_events_dropped_total 메트릭과 관리 서버 /drops에서 확인할 수 있습니다.
+ *  - 버스 기본 정책은 scaffold_bus_backpressure_policy{policy="..."} = 1 로 노출합니다.
+ *  - 가득 찬 큐에 메시지가 들어올 때마다 scaffold_bus_queue_overflows_total{subscriber, policy}를 세고,
+ *    구독자마다 overflowWarnInterval에 한 번 경고 로그를 남깁니다. (그 사이 넘친 수 포함)
+ */
+package bus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 레지스트리
+	"go.uber.org/zap"                                // 넘침 경고 로그
+
+	"generic-api-scaffold/internal/drops" // 드롭/거절 이벤트 기록
+)
+
+// 큐 넘침 경고 로그의 최소 간격 (구독자마다)
+const overflowWarnInterval = 10 * time.Second
+
+// BackpressurePolicy : 큐가 가득 찼을 때의 정책
+type BackpressurePolicy string
+
+const (
+	BackpressureBlock      BackpressurePolicy = "block"
+	BackpressureDropOldest BackpressurePolicy = "drop_oldest"
+	BackpressureDropNewest BackpressurePolicy = "drop_newest"
+	BackpressureCoalesce   BackpressurePolicy = "coalesce"
+)
+
+// valid : 지원하는 정책인지
+func (p BackpressurePolicy) valid() bool {
+	switch p {
+	case BackpressureBlock, BackpressureDropOldest, BackpressureDropNewest, BackpressureCoalesce:
+		return true
+	}
+	return false
+}
+
+/*
+ * WithBackpressure : 이 구독의 큐가 가득 찼을 때의 정책 지정 (버스 기본값 대신, ordered 구독에만 적용)
+ */
+func WithBackpressure(p BackpressurePolicy) SubscribeOption {
+	return func(c *subscribeConfig) { c.backpressure = p }
+}
+
+// registerPolicyMetric : 버스 기본 정책을 게이지로 노출
+func registerPolicyMetric(reg *prometheus.Registry, p BackpressurePolicy) {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "scaffold",
+		Name:      "bus_backpressure_policy",
+		Help:      "Default backpressure policy for full event bus subscriber queues (1 for the active policy).",
+	}, []string{"policy"})
+	reg.MustRegister(g)
+	g.WithLabelValues(string(p)).Set(1)
+}
+
+/*
+ * queue : 정책을 적용하는 크기 제한 FIFO 큐 (ordered 구독자 전용)
+ *  - 채널 대신 슬라이스 + 조건 변수를 사용하여 "가장 오래된 항목 제거", "같은 키 교체"가 가능하도록 함
+ */
+type queue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  []Message
+	size   int
+	policy BackpressurePolicy
+	drops  *drops.Recorder
+	closed bool             // 구독 해지됨 (push는 무시, pop은 대기 중단)
+	depth  prometheus.Gauge // 큐 깊이 메트릭 (scaffold_bus_queue_depth)
+
+	pending *atomic.Int64 // 버스 전체의 처리 대기 메시지 수 (버린 메시지는 여기서 뺌, drain.go)
+
+	coalesced prometheus.Counter // nil이 아니면 항상 같은 키의 대기 메시지를 교체하고 센다 (WithCoalesce, ratelimit.go)
+
+	name      string             // 구독자 이름 (넘침 경고 로그)
+	log       *zap.Logger        // nil이면 경고 로그 없음
+	overflows prometheus.Counter // 넘침 수 (scaffold_bus_queue_overflows_total, nil이면 세지 않음)
+	warnedAt  time.Time          // 마지막 경고 로그 시각
+	unwarned  int                // 마지막 경고 이후 넘친 수
+}
+
+func newQueue(size int, policy BackpressurePolicy, dr *drops.Recorder, depth prometheus.Gauge, pending *atomic.Int64) *queue {
+	q := &queue{size: size, policy: policy, drops: dr, depth: depth, pending: pending}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+/*
+ * push : 메시지 추가 (가득 차 있으면 정책 적용)
+ *  - 버린 메시지가 있으면 잠금을 푼 뒤 드롭 기록기에 남김
+ */
+func (q *queue) push(m Message) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		q.pending.Add(-1)
+		return
+	}
+	dropped, ok := q.admit(m)
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	if ok {
+		q.pending.Add(-1)
+		q.record(dropped)
+	}
+}
+
+// admit : 정책에 따라 m을 큐에 넣고, 대신 버린 메시지를 반환 (호출자가 잠금을 잡고 있어야 함)
+func (q *queue) admit(m Message) (Message, bool) {
+	if q.coalesced != nil && q.replace(m) {
+		q.coalesced.Inc()
+		q.pending.Add(-1) // 교체된 메시지는 전달하지 않음 (드롭으로 기록하지 않음)
+		return Message{}, false
+	}
+	if len(q.items) < q.size {
+		q.items = append(q.items, m)
+		q.depth.Inc()
+		return Message{}, false
+	}
+	q.overflowed()
+	switch q.policy {
+	case BackpressureDropNewest:
+		return m, true
+	case BackpressureCoalesce:
+		if k, ok := m.Payload.(Keyed); ok {
+			for i, old := range q.items {
+				if old.Topic == m.Topic && sameKey(old, k.EventKey()) {
+					q.items[i] = m
+					return old, true
+				}
+			}
+		}
+	case BackpressureBlock:
+		for len(q.items) >= q.size && !q.closed {
+			q.cond.Wait()
+		}
+		if q.closed {
+			q.pending.Add(-1) // 기다리는 동안 닫힘 (넣지 못함)
+			return Message{}, false
+		}
+		q.items = append(q.items, m)
+		q.depth.Inc()
+		return Message{}, false
+	}
+	// drop_oldest (coalesce에서 같은 키가 없을 때 포함)
+	old := q.items[0]
+	q.items = append(q.items[1:], m)
+	return old, true
+}
+
+// overflowed : 가득 찬 큐에 메시지가 들어옴 (메트릭 + 주기적 경고 로그, 호출자가 잠금을 잡고 있어야 함)
+func (q *queue) overflowed() {
+	if q.overflows != nil {
+		q.overflows.Inc()
+	}
+	q.unwarned++
+	if q.log == nil || time.Since(q.warnedAt) < overflowWarnInterval {
+		return
+	}
+	q.log.Warn("event bus subscriber queue full", zap.String("subscriber", q.name), zap.String("policy", string(q.policy)),
+		zap.Int("size", q.size), zap.Int("overflows", q.unwarned))
+	q.warnedAt, q.unwarned = time.Now(), 0
+}
+
+// replace : 같은 토픽·같은 키의 대기 메시지가 있으면 m으로 교체 (호출자가 잠금을 잡고 있어야 함)
+func (q *queue) replace(m Message) bool {
+	k, ok := m.Payload.(Keyed)
+	if !ok {
+		return false
+	}
+	for i, old := range q.items {
+		if old.Topic == m.Topic && sameKey(old, k.EventKey()) {
+			q.items[i] = m
+			return true
+		}
+	}
+	return false
+}
+
+// sameKey : 메시지가 key와 같은 키의 이벤트인지
+func sameKey(m Message, key string) bool {
+	k, ok := m.Payload.(Keyed)
+	return ok && k.EventKey() == key
+}
+
+/*
+ * pop : 맨 앞 메시지를 꺼냄 (비어 있으면 들어올 때까지 대기)
+ *  - 큐가 닫히면 false (전용 고루틴 종료)
+ */
+func (q *queue) pop() (Message, bool) {
+	q.mu.Lock()
+	for len(q.items) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		q.mu.Unlock()
+		return Message{}, false
+	}
+	m := q.items[0]
+	q.items[0] = Message{} // 참조 해제
+	q.items = q.items[1:]
+	q.depth.Dec()
+	q.cond.Broadcast() // block 정책으로 대기 중인 발행자 깨우기
+	q.mu.Unlock()
+	return m, true
+}
+
+/*
+ * close : 큐 닫기 (구독 해지/버스 종료 시, 아직 전달되지 않은 메시지는 버리고 대기 중인 발행자/고루틴을 깨움)
+ *  - 버린 메시지를 반환 (버스 종료 시 드롭 기록용)
+ */
+func (q *queue) close() []Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	discarded := q.items
+	q.closed = true
+	q.depth.Sub(float64(len(discarded)))
+	q.pending.Add(-int64(len(discarded)))
+	q.items = nil
+	q.cond.Broadcast()
+	return discarded
+}
+
+// record : 버린 메시지를 드롭 기록기에 남김
+func (q *queue) record(m Message) {
+	if q.drops == nil {
+		return
+	}
+	q.drops.Record("bus", drops.ReasonBackpressure, keyOf(m.Payload), "topic="+m.Topic+" policy="+string(q.policy))
+}
@@ -0,0 +1,161 @@
+/*
+ * 묶음 전달(batching) : 처리량이 큰 구독자가 이벤트를 하나씩이 아니라 묶음으로 받습니다.
+ *  - SubscribeBatch[T]로 구독하면 구독자 함수가 func(ctx, []T) error 형태로 호출됩니다.
+ *    (예: Influx 기록이 이벤트마다 HTTP 쓰기 한 번 대신 500개 포인트를 한 번에 기록)
+ *  - 묶음 크기는 WithBatch(maxSize, maxLatency)로 정합니다.
+ *      maxSize    : 한 묶음의 최대 이벤트 수 (차면 바로 전달)
+ *      maxLatency : 묶음의 첫 이벤트가 기다리는 최대 시간 (지나면 차지 않아도 전달)
+ *  - 항상 ordered 큐를 사용하며(백프레셔 정책 그대로 적용), 큐 크기는 최소 maxSize로 맞춥니다.
+ *  - 재시도/DLQ는 묶음 단위입니다. (끝내 실패하면 묶음 전체가 DLQ 항목 하나가 되고, 재전송도 묶음으로)
+ *  - 구독 필터는 이벤트마다, 인터셉터는 묶음마다(payload가 []T인 메시지) 적용됩니다.
+ */
+package bus
+
+import (
+	"context"
+	"time"
+)
+
+// 묶음 설정을 지정하지 않았을 때의 기본값
+const (
+	defaultBatchSize    = 500
+	defaultBatchLatency = time.Second
+)
+
+/*
+ * batchConfig : 묶음 전달 설정
+ *  - collect : 꺼낸 메시지들을 구독자 타입의 슬라이스([]T)로 묶음 (SubscribeBatch가 지정)
+ */
+type batchConfig struct {
+	size    int
+	latency time.Duration
+	collect func([]Message) any
+}
+
+/*
+ * WithBatch : 묶음 전달의 최대 크기와 최대 대기 시간 지정 (SubscribeBatch에만 적용)
+ *  - 0 이하의 값은 기본값 (500개, 1s)
+ */
+func WithBatch(maxSize int, maxLatency time.Duration) SubscribeOption {
+	return func(c *subscribeConfig) { c.batch.size, c.batch.latency = maxSize, maxLatency }
+}
+
+/*
+ * SubscribeBatch : 이벤트 타입 T를 묶음으로 받는 구독자 등록
+ *  - fn이 에러를 반환하거나 panic이 나면 묶음 전체를 재시도 정책대로 다시 호출하고, 끝내 실패하면 묶음을 DLQ로 옮김
+ *  - 따라잡기/retained 메시지도 묶음(최대 maxSize개씩)으로 먼저 전달
+ */
+func SubscribeBatch[T any](b *EventBus, fn func(ctx context.Context, es []T) error, opts ...SubscribeOption) *Subscription {
+	var cfg subscribeConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	cfg.delivery = DeliveryOrdered
+	cfg.batch.collect = func(ms []Message) any {
+		es := make([]T, len(ms))
+		for i, m := range ms {
+			es[i] = m.Payload.(T)
+		}
+		return es
+	}
+	if cfg.batch.size < 1 {
+		cfg.batch.size = defaultBatchSize
+	}
+	if cfg.batch.latency <= 0 {
+		cfg.batch.latency = defaultBatchLatency
+	}
+	t := typeOf[T]()
+
+	b.mu.Lock()
+	backlog := b.typedBacklog(cfg)
+	sub := b.newSubscriber(cfg, t.String(), func(ctx context.Context, m Message) error { return fn(ctx, m.Payload.([]T)) })
+	sub.typ = t
+	if len(backlog) > 0 {
+		sub.holdLive() // 과거 메시지를 처리하는 동안의 새 발행분은 그 뒤로 (catchup.go)
+	}
+	b.subscribers[t] = append(b.subscribers[t], sub)
+	b.mu.Unlock()
+
+	var pending []Message
+	for _, m := range backlog {
+		if _, ok := m.Payload.(T); ok && sub.accepts(m) {
+			pending = append(pending, m)
+		}
+	}
+	for len(pending) > 0 {
+		n := min(len(pending), cfg.batch.size)
+		sub.handle(b.life, sub.batchOf(pending[:n]))
+		pending = pending[n:]
+	}
+	if len(backlog) > 0 {
+		sub.releaseHeld()
+	}
+	return newSubscription(b, sub)
+}
+
+/*
+ * SubscribeBatch : DataCollectedEvent 묶음 구독 (bus.SubscribeBatch[DataCollectedEvent]의 축약형)
+ */
+func (b *EventBus) SubscribeBatch(fn func(ctx context.Context, es []DataCollectedEvent) error, opts ...SubscribeOption) *Subscription {
+	return SubscribeBatch(b, fn, opts...)
+}
+
+/*
+ * batchOf : 꺼낸 메시지들을 묶음 메시지 하나로
+ *  - 토픽/발행 시각/ctx는 첫 메시지 기준 (전달 지연 메트릭은 묶음에서 가장 오래 기다린 이벤트 기준)
+ */
+func (s *subscriber) batchOf(ms []Message) Message {
+	return Message{Topic: ms[0].Topic, Payload: s.batch.collect(ms), at: ms[0].at, ctx: ms[0].ctx}
+}
+
+/*
+ * popBatch : 최대 limit개를 꺼냄
+ *  - 비어 있으면 첫 메시지가 들어올 때까지 대기하고, 그 뒤 limit개가 차거나 latency가 지날 때까지 더 기다림
+ *  - 큐가 닫히면 false
+ */
+func (q *queue) popBatch(limit int, latency time.Duration) ([]Message, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.items) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if len(q.items) < limit && !q.closed {
+		deadline := time.Now().Add(latency)
+		timer := time.AfterFunc(latency, func() {
+			q.mu.Lock()
+			q.cond.Broadcast()
+			q.mu.Unlock()
+		})
+		for len(q.items) < limit && !q.closed && time.Now().Before(deadline) {
+			q.cond.Wait()
+		}
+		timer.Stop()
+	}
+	if q.closed {
+		return nil, false
+	}
+
+	n := min(len(q.items), limit)
+	out := make([]Message, n)
+	copy(out, q.items)
+	clear(q.items[:n]) // 참조 해제
+	q.items = q.items[n:]
+	q.depth.Sub(float64(n))
+	q.cond.Broadcast() // block 정책으로 대기 중인 발행자 깨우기
+	return out, true
+}
+
+// runBatches : 묶음 구독자의 전용 고루틴 (큐에서 묶음 단위로 꺼내 처리)
+func (s *subscriber) runBatches() {
+	for {
+		s.throttle()
+		ms, ok := s.queue.popBatch(s.batch.size, s.batch.latency)
+		if !ok {
+			return
+		}
+		m := s.batchOf(ms)
+		s.handleDetached(m.ctx, m)
+		s.pending.Add(-int64(len(ms)))
+	}
+}
@@ -2,11 +2,30 @@
  * EventBus : 단순한 이벤트 발행/구독 시스템
  *  - 역할 : Spring의 ApplicationEventPublisher / Observer 패턴과 유사
  *  - Publish(발행) 시, 등록된 모든 구독자 함수가 비동기로 호출됩니다.
+ *  - 이벤트 타입별로 구독자를 나눠 보관하므로, 새 이벤트 타입은 각자의 패키지에 구조체를 정의하고
+ *    제네릭 함수 bus.Subscribe[T] / bus.Publish[T]로 바로 사용할 수 있습니다. (bus.go 수정 불필요)
+ *      예) bus.Subscribe(b, func(ctx context.Context, e control.CommandIssued) { ... })
+ *          bus.Publish(ctx, b, control.CommandIssued{...})
+ *  - 모든 이벤트는 토픽(예: data.collected, control.issued, device.A1.status)으로 발행되며,
+ *    토픽 이름으로 구독하면 그 토픽의 이벤트만 받습니다. (topic.go 참고)
  */
 package bus
 
 import (
-	"go.uber.org/zap" // 로깅(디버깅 및 오류 추적용)
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 레지스트리
+	"go.uber.org/fx"                                 // 애플리케이션 생명주기(Lifecycle) 훅 제공
+	"go.uber.org/zap"                                // 로깅(디버깅 및 오류 추적용)
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 백프레셔 드롭 기록
 )
 
 /*
@@ -15,11 +34,28 @@ import (
  *  - 필드 :
  *      DeviceID : 이벤트 발생 장치 식별자
  *      Values   : 수집된 데이터 (key-value 형태)
+ *      Time     : 측정 시각 (이벤트 시각, 비어 있으면 받는 쪽이 처리 시각을 씀)
+ *                 버퍼링·재시도·저널/WAL 재생으로 늦게 기록되거나 순서가 뒤바뀌어도 저장소에는 이 시각으로 남음
  *  - Java 대응 : ApplicationEvent 하위 클래스 또는 DTO
  */
 type DataCollectedEvent struct {
 	DeviceID string
 	Values   map[string]float64
+	Time     time.Time
+}
+
+// EventKey : 장치별 마지막 이벤트(따라잡기)를 보관할 키
+func (e DataCollectedEvent) EventKey() string { return e.DeviceID }
+
+// Topic : 수집 데이터 토픽
+func (e DataCollectedEvent) Topic() string { return TopicDataCollected }
+
+/*
+ * Keyed : 따라잡기용으로 키(보통 장치 ID)별 마지막 이벤트를 보관할 이벤트 타입이 구현
+ *  - 구현하지 않은 타입은 마지막 이벤트를 보관하지 않음 (따라잡기 대상 아님)
+ */
+type Keyed interface {
+	EventKey() string
 }
 
 /*
@@ -27,43 +63,408 @@ type DataCollectedEvent struct {
  *  - 역할 : 이벤트를 전달할 "버스" 객체 (Spring의 ApplicationEventPublisher 유사)
  *  - 필드 :
  *      log         : 로깅 도구 (*zap.Logger)
- *      subscribers : 구독자(Subscriber) 함수 목록
+ *      subscribers : 이벤트 타입 → 타입 구독자 함수 목록 (bus.Subscribe[T])
+ *      topics      : 토픽 이름 → 토픽 구독자 함수 목록 (SubscribeTopic)
+ *      patterns    : 와일드카드 토픽 구독자 목록 (예: device.*.status, data.#)
+ *      latest      : 토픽 → 키(장치 ID)별 마지막 메시지 (늦게 합류한 구독자의 따라잡기(catch-up)용)
+ *      retained    : 구독 즉시 마지막 메시지를 전달할 토픽 패턴 (retain.go)
+ *      running     : 라이프사이클 상 버스가 동작 중인지 여부 (OnStart~OnStop 구간)
+ *      life        : 버스 수명 ctx (OnStop에서 취소 → 진행 중인 ordered/async 구독자 처리 취소, context.go)
+ *      closing     : 종료 중 (새 발행을 받지 않음, drain.go)
+ *      pending     : ordered 큐와 async 고루틴에서 처리 대기 중인 메시지 수 (종료 시 비우기용)
+ *  - 구독 등록/발행은 mu로 보호되어, 앱 시작 이후(모듈의 on-demand 시작 등)에도 안전하게 구독할 수 있음
  */
 type EventBus struct {
 	log         *zap.Logger
-	subscribers []func(DataCollectedEvent)
+	mu          sync.RWMutex
+	subscribers map[reflect.Type][]*subscriber
+	topics      map[string][]*subscriber
+	patterns    []*subscriber
+	latest      map[string]map[string]Message
+	retained    []string
+	running     atomic.Bool
+	life        context.Context
+	stop        context.CancelFunc
+	closing     atomic.Bool
+	pending     atomic.Int64
+
+	delivery     DeliveryMode       // 구독 시 전달 방식을 지정하지 않았을 때의 기본값
+	queueSize    int                // ordered 구독자의 기본 큐 크기
+	backpressure BackpressurePolicy // 큐가 가득 찼을 때의 기본 정책 (backpressure.go)
+	drops        *drops.Recorder    // 백프레셔로 버린 메시지 기록
+	retry        retryPolicy        // 구독자 실패 시 기본 재시도 정책 (deadletter.go)
+	dlq          *DeadLetterQueue   // 재시도 후에도 실패한 메시지 보관
+	metrics      *busMetrics        // 발행/전달/큐/실패 메트릭 (metrics.go)
+	interceptors []Interceptor      // 발행/전달 인터셉터 체인 (interceptor.go)
+	seq          atomic.Uint64      // 구독 등록 순번 (같은 우선순위 안의 전달 순서)
+	drainTimeout time.Duration      // 종료 시 남은 이벤트를 비우는 최대 시간 (drain.go)
+	drainOnce    sync.Once          // 비우기는 먼저 부른 OnStop 훅에서 한 번만 (drain.go)
+}
+
+/*
+ * CatchUpSource : 따라잡기 이벤트 공급원
+ *  - 구독 시점에 새 구독자에게 먼저 전달할 과거 이벤트 목록을 반환
+ *  - 기본값은 버스 자체의 장치별 마지막 이벤트이며, 최신값 저장소나 저널 등으로 교체할 수 있음
+ *  - DataCollectedEvent 구독에만 적용됨 (다른 타입의 구독에서는 무시)
+ */
+type CatchUpSource interface {
+	CatchUp() []DataCollectedEvent
+}
+
+// subscribeConfig : 구독별 옵션
+type subscribeConfig struct {
+	catchUp      bool
+	source       CatchUpSource
+	delivery     DeliveryMode       // 비어 있으면 버스 기본값
+	backpressure BackpressurePolicy // 비어 있으면 버스 기본값
+	retry        *retryPolicy       // nil이면 버스 기본값
+	name         string             // 비어 있으면 토픽/타입 이름
+	noRetained   bool               // retained 토픽의 마지막 메시지를 받지 않음 (retain.go)
+	priority     Priority           // 전달 순서 (priority.go)
+	bufferSize   int                // ordered 큐 크기, 0이면 버스 기본값 (delivery.go)
+	filters      []Filter           // 전달 전 조건 (filter.go)
+	responder    bool               // 요청 응답자 (request.go)
+	batch        batchConfig        // 묶음 전달 설정 (SubscribeBatch만, batch.go)
+	rate         float64            // 초당 최대 전달 수, 0이면 제한 없음 (ratelimit.go)
+	burst        int                // 속도 제한의 순간 최대 전달 수
+	coalesce     bool               // 큐에서 기다리는 같은 장치의 메시지를 최신으로 교체
+}
+
+// SubscribeOption : Subscribe에 넘기는 구독별 옵션
+type SubscribeOption func(*subscribeConfig)
+
+/*
+ * WithCatchUp : 구독 즉시 장치별 마지막 이벤트를 먼저 전달받음
+ *  - 늦게 시작된 모듈이 "다음 수집 주기"까지 기다리지 않고 현재 상태를 알 수 있음
+ */
+func WithCatchUp() SubscribeOption {
+	return func(c *subscribeConfig) { c.catchUp = true }
+}
+
+/*
+ * WithCatchUpFrom : 버스의 마지막 이벤트 대신 지정한 공급원(최신값 저장소, 저널 등)에서 따라잡기
+ */
+func WithCatchUpFrom(src CatchUpSource) SubscribeOption {
+	return func(c *subscribeConfig) { c.catchUp, c.source = true, src }
+}
+
+/*
+ * EventBusParams : NewEventBus가 fx로부터 주입받는 의존성 묶음
+ *  - Interceptors : group:"bus_interceptors"로 등록된 발행/전달 인터셉터 (interceptor.go)
+ */
+type EventBusParams struct {
+	fx.In
+
+	Lifecycle    fx.Lifecycle
+	Log          *zap.Logger
+	Metrics      *prometheus.Registry
+	Drops        *drops.Recorder
+	Interceptors []Interceptor `group:"bus_interceptors"`
 }
 
 /*
  * NewEventBus : fx가 호출하는 EventBus 생성자
  *  - Java 대응 : @Bean ApplicationEventPublisher
+ *  - OnStart/OnStop 훅으로 동작 상태(running)를 관리 → readiness 검사에 사용
+ *  - OnStop에서 버스 수명 ctx를 취소하여 진행 중인 비동기 구독자 처리에 종료를 알림
+ *    (버스 훅은 가장 먼저 등록되어 가장 늦게 멈추므로, 자원을 닫는 구독자는 DrainBefore로 그보다 먼저 비우기를 요청)
+ *  - APP_BUS_DELIVERY   : 기본 전달 방식 sync | ordered | async (기본 ordered : 구독자별 큐와 고루틴)
+ *  - APP_BUS_QUEUE_SIZE : ordered 구독자의 기본 큐 크기 (기본 1024, 구독별 WithBufferSize)
+ *  - APP_BUS_BACKPRESSURE : 큐가 가득 찼을 때의 기본 정책 block | drop_oldest | drop_newest | coalesce (기본 block)
+ *  - APP_BUS_RETRY_ATTEMPTS : 에러를 반환한 구독자의 최대 시도 횟수 (기본 3)
+ *  - APP_BUS_RETRY_BACKOFF  : 첫 재시도 전 대기 시간, 이후 두 배씩 (기본 100ms)
+ *  - APP_BUS_DLQ_SIZE       : 데드레터 큐에 보관할 최대 항목 수 (기본 1000)
+ *  - APP_BUS_DLQ_FILE       : 데드레터 항목을 NDJSON으로 덧붙일 파일 (기본 없음)
+ *  - APP_BUS_RETAINED       : retained 토픽 목록, 쉼표 구분·와일드카드 가능 (기본 없음, retain.go)
+ *  - APP_BUS_DRAIN_TIMEOUT  : 종료 시 큐에 남은 이벤트를 구독자에게 전달하며 기다리는 최대 시간 (기본 10s, drain.go)
  *  - 반환 : *EventBus
  */
-func NewEventBus(log *zap.Logger) *EventBus {
-	return &EventBus{log: log}
+func NewEventBus(p EventBusParams) *EventBus {
+	lc, log, reg, dr := p.Lifecycle, p.Log, p.Metrics, p.Drops
+	b := &EventBus{
+		log:         log,
+		subscribers: make(map[reflect.Type][]*subscriber),
+		topics:      make(map[string][]*subscriber),
+		latest:      make(map[string]map[string]Message),
+		retained:    config.List("APP_BUS_RETAINED", nil),
+		delivery:    DeliveryMode(config.String("APP_BUS_DELIVERY", string(DeliveryOrdered))),
+		queueSize:   config.Int(log, "APP_BUS_QUEUE_SIZE", 1024),
+
+		backpressure: BackpressurePolicy(config.String("APP_BUS_BACKPRESSURE", string(BackpressureBlock))),
+		drops:        dr,
+		retry: retryPolicy{
+			attempts: config.Int(log, "APP_BUS_RETRY_ATTEMPTS", 3),
+			backoff:  config.Duration(log, "APP_BUS_RETRY_BACKOFF", 100*time.Millisecond),
+
+			maxBackoff: config.Duration(log, "APP_BUS_RETRY_MAX_BACKOFF", 10*time.Second),
+			jitter:     config.Float(log, "APP_BUS_RETRY_JITTER", 0.2),
+		},
+		interceptors: p.Interceptors,
+		drainTimeout: config.Duration(log, "APP_BUS_DRAIN_TIMEOUT", 10*time.Second),
+	}
+	b.life, b.stop = context.WithCancel(context.Background())
+	if !b.delivery.valid() {
+		log.Fatal("invalid APP_BUS_DELIVERY, expected sync|ordered|async", zap.String("value", string(b.delivery)))
+	}
+	if b.queueSize < 1 {
+		log.Fatal("APP_BUS_QUEUE_SIZE must be positive", zap.Int("value", b.queueSize))
+	}
+	if !b.backpressure.valid() {
+		log.Fatal("invalid APP_BUS_BACKPRESSURE, expected block|drop_oldest|drop_newest|coalesce", zap.String("value", string(b.backpressure)))
+	}
+	registerPolicyMetric(reg, b.backpressure)
+	if b.drainTimeout < 0 {
+		log.Fatal("APP_BUS_DRAIN_TIMEOUT must not be negative", zap.Duration("value", b.drainTimeout))
+	}
+	if b.retry.attempts < 1 {
+		log.Fatal("APP_BUS_RETRY_ATTEMPTS must be positive", zap.Int("value", b.retry.attempts))
+	}
+	if b.retry.maxBackoff <= 0 || b.retry.jitter < 0 || b.retry.jitter > 1 {
+		log.Fatal("APP_BUS_RETRY_MAX_BACKOFF must be positive and APP_BUS_RETRY_JITTER within 0..1",
+			zap.Duration("max_backoff", b.retry.maxBackoff), zap.Float64("jitter", b.retry.jitter))
+	}
+	dlqSize := config.Int(log, "APP_BUS_DLQ_SIZE", 1000)
+	if dlqSize < 1 {
+		log.Fatal("APP_BUS_DLQ_SIZE must be positive", zap.Int("value", dlqSize))
+	}
+	dlq, err := newDeadLetterQueue(log, dr, dlqSize, config.String("APP_BUS_DLQ_FILE", ""))
+	if err != nil {
+		log.Fatal("failed to open APP_BUS_DLQ_FILE", zap.Error(err))
+	}
+	b.dlq = dlq
+	b.metrics = newBusMetrics(reg, dlq)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			b.running.Store(true)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			b.running.Store(false)
+			b.Drain(ctx) // 구독자 훅(DrainBefore)에서 이미 비웠으면 바로 반환
+			return b.dlq.close()
+		},
+	})
+	return b
+}
+
+/*
+ * Healthy : 버스가 이벤트를 전달할 수 있는 상태인지 확인
+ *  - 라이프사이클이 시작되지 않았거나 이미 종료되었으면 에러
+ *  - 구독자가 하나도 없으면 발행된 이벤트가 버려지므로 에러
+ */
+func (b *EventBus) Healthy() error {
+	if !b.running.Load() {
+		return errors.New("event bus is not running")
+	}
+	b.mu.RLock()
+	n := 0
+	for _, subs := range b.subscribers {
+		n += len(subs)
+	}
+	for _, subs := range b.topics {
+		n += len(subs)
+	}
+	n += len(b.patterns)
+	b.mu.RUnlock()
+	if n == 0 {
+		return errors.New("event bus has no subscribers")
+	}
+	return nil
+}
+
+/*
+ * DeadLetters : 재시도 후에도 처리하지 못한 메시지 보관소 (관리 서버 /deadletters)
+ */
+func (b *EventBus) DeadLetters() *DeadLetterQueue {
+	return b.dlq
 }
 
 /*
- * Subscribe : 이벤트 수신 함수를 등록하는 메서드
- *  - 인자 : func(DataCollectedEvent)
- *  - 동작 : 이벤트가 발행될 때마다 해당 함수를 호출
+ * Subscribe : 이벤트 타입 T의 구독자 등록
+ *  - 동작 : T 타입 이벤트가 (어느 토픽으로든) 발행될 때마다 해당 함수를 호출 (다른 타입의 이벤트는 전달되지 않음)
+ *  - 따라잡기 옵션이 있거나 retained 토픽이면 등록과 같은 잠금 구간에서 과거 이벤트를 스냅샷하고,
+ *    그 처리가 끝날 때까지 새 발행분을 보류하여 누락/중복 없이 "과거 → 이후 발행분" 순서로 이어지도록 함 (catchup.go)
+ *  - 반환된 Subscription으로 구독 해지 (subscription.go)
  *  - Java 대응 : @EventListener 또는 addObserver()
  */
-func (b *EventBus) Subscribe(fn func(DataCollectedEvent)) {
-	b.subscribers = append(b.subscribers, fn)
+func Subscribe[T any](b *EventBus, fn func(ctx context.Context, e T), opts ...SubscribeOption) *Subscription {
+	return SubscribeErr(b, func(ctx context.Context, e T) error {
+		fn(ctx, e)
+		return nil
+	}, opts...)
 }
 
 /*
- * Publish : 이벤트를 실제로 발행하는 메서드
- *  - 인자 : DataCollectedEvent (발행할 이벤트)
+ * SubscribeErr : 에러를 반환하는 이벤트 타입 T의 구독자 등록
+ *  - fn이 에러를 반환하거나 panic이 발생하면 재시도 정책(WithRetry)대로 다시 호출하고,
+ *    끝내 실패하면 이벤트를 DLQ로 옮김 (deadletter.go, recover.go)
+ */
+func SubscribeErr[T any](b *EventBus, fn func(ctx context.Context, e T) error, opts ...SubscribeOption) *Subscription {
+	var cfg subscribeConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	t := typeOf[T]()
+
+	b.mu.Lock()
+	backlog := b.typedBacklog(cfg)
+	sub := b.newSubscriber(cfg, t.String(), func(ctx context.Context, m Message) error { return fn(ctx, m.Payload.(T)) })
+	sub.typ = t
+	if len(backlog) > 0 {
+		sub.holdLive() // 과거 메시지를 처리하는 동안의 새 발행분은 그 뒤로 (catchup.go)
+	}
+	b.subscribers[t] = append(b.subscribers[t], sub)
+	b.mu.Unlock()
+
+	if len(backlog) > 0 {
+		b.log.Debug("subscriber catch-up", zap.String("type", t.String()), zap.Int("events", len(backlog)))
+		for _, m := range backlog {
+			if _, ok := m.Payload.(T); ok && sub.accepts(m) {
+				sub.handle(b.life, m)
+			}
+		}
+		sub.releaseHeld()
+	}
+	return newSubscription(b, sub)
+}
+
+/*
+ * typedBacklog : 타입 구독자가 구독 즉시 받을 과거 메시지 (호출자가 잠금을 잡고 있어야 함)
+ *  - 따라잡기 옵션이면 공급원(없으면 버스)의 마지막 이벤트, 아니면 retained 토픽의 마지막 메시지
+ *  - 구독자 타입과 맞지 않는 메시지도 포함되므로 호출자가 걸러냄
+ */
+func (b *EventBus) typedBacklog(cfg subscribeConfig) []Message {
+	var backlog []Message
+	if cfg.catchUp {
+		if cfg.source != nil {
+			for _, e := range cfg.source.CatchUp() {
+				backlog = append(backlog, Message{Topic: e.Topic(), Payload: e})
+			}
+		} else {
+			for _, byKey := range b.latest {
+				backlog = append(backlog, sortedMessages(byKey)...)
+			}
+		}
+	} else if !cfg.noRetained {
+		backlog = b.retainedLatest(func(string) bool { return true })
+	}
+	return backlog
+}
+
+/*
+ * Subscribe : DataCollectedEvent 구독 (bus.Subscribe[DataCollectedEvent]의 축약형)
+ */
+func (b *EventBus) Subscribe(fn func(ctx context.Context, e DataCollectedEvent), opts ...SubscribeOption) *Subscription {
+	return Subscribe(b, fn, opts...)
+}
+
+/*
+ * CatchUp : 장치별 마지막 이벤트 목록 (장치 ID 순)
+ *  - EventBus 자체도 CatchUpSource를 구현
+ */
+func (b *EventBus) CatchUp() []DataCollectedEvent {
+	b.mu.RLock()
+	latest := sortedMessages(b.latest[TopicDataCollected])
+	b.mu.RUnlock()
+
+	out := make([]DataCollectedEvent, 0, len(latest))
+	for _, m := range latest {
+		if e, ok := m.Payload.(DataCollectedEvent); ok {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// sortedMessages : 키별 마지막 메시지를 키 순으로 (호출자가 잠금을 잡고 있어야 함)
+func sortedMessages(byKey map[string]Message) []Message {
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]Message, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, byKey[k])
+	}
+	return out
+}
+
+// typeOf : 타입 매개변수 T의 reflect.Type
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+/*
+ * Publish : 이벤트 타입 T의 이벤트를 발행
+ *  - 토픽은 이벤트가 Topic()을 구현하면 그 값, 아니면 타입 이름 (TopicOf)
  *  - 동작 :
- *      ① 등록된 모든 구독자 함수(subscribers)를 순회
- *      ② 각 함수를 별도의 고루틴으로 비동기 실행
- *  - 효과 : 빠른 반응, 비동기 이벤트 처리
+ *      ① 발행 인터셉터 체인 통과 (걸러지거나 보강될 수 있음, interceptor.go)
+ *      ② Keyed를 구현한 이벤트나 retained 토픽이면 토픽·키별 마지막 이벤트 갱신 (따라잡기/retained용)
+ *      ③ T 타입 구독자, 해당 토픽 구독자, 토픽에 맞는 와일드카드 구독자 중 구독 필터를 통과한 구독자를 모아 우선순위 순으로 정렬 (priority.go)
+ *      ④ 구독자별 전달 방식(sync | ordered | async)에 따라 전달 (delivery.go)
+ *  - 효과 : 빠른 반응, 비동기 이벤트 처리 (순서가 중요한 구독자는 ordered 선택)
  *  - Java 대응 : ApplicationEventPublisher.publishEvent() 또는 Observer.notifyObservers()
  */
-func (b *EventBus) Publish(e DataCollectedEvent) {
-	for _, sub := range b.subscribers {
-		go sub(e) // 비동기 실행(별도 고루틴)
+func Publish[T any](ctx context.Context, b *EventBus, e T) {
+	b.publish(ctx, Message{Topic: TopicOf(e), Payload: e}, typeOf[T]())
+}
+
+// publish : 발행 인터셉터 체인을 거쳐 메시지를 전달
+func (b *EventBus) publish(ctx context.Context, m Message, t reflect.Type) {
+	if b.rejectClosed(m) {
+		return
+	}
+	chainPublish(b.interceptors, func(ctx context.Context, m Message) { b.dispatch(ctx, m, payloadType(m, t)) })(ctx, m)
+}
+
+// dispatch : 인터셉터를 거친 메시지를 타입 구독자(t)와 토픽 구독자에게 전달
+func (b *EventBus) dispatch(ctx context.Context, m Message, t reflect.Type) {
+	b.metrics.published.WithLabelValues(m.Topic).Inc()
+
+	b.mu.Lock()
+	k, keyed := m.Payload.(Keyed)
+	if (keyed || b.isRetained(m.Topic)) && m.req == nil { // 요청은 보관하지 않음 (request.go)
+		byKey := b.latest[m.Topic]
+		if byKey == nil {
+			byKey = make(map[string]Message)
+			b.latest[m.Topic] = byKey
+		}
+		key := "" // 키가 없는 retained 이벤트는 토픽당 하나
+		if keyed {
+			key = k.EventKey()
+		}
+		byKey[key] = m
+	}
+	subs := make([]*subscriber, 0, len(b.subscribers[t])+len(b.topics[m.Topic]))
+	for _, sub := range b.subscribers[t] {
+		if sub.accepts(m) {
+			subs = append(subs, sub)
+		}
+	}
+	for _, sub := range b.topics[m.Topic] {
+		if sub.accepts(m) {
+			subs = append(subs, sub)
+		}
+	}
+	for _, sub := range b.patterns {
+		if MatchTopic(sub.topic, m.Topic) && sub.accepts(m) {
+			subs = append(subs, sub)
+		}
 	}
+	b.mu.Unlock()
+	byPriority(subs)
+
+	m.at = time.Now() // 따라잡기용으로 보관한 사본에는 남기지 않음
+	for _, sub := range subs {
+		sub.deliver(ctx, m)
+	}
+}
+
+/*
+ * Publish : DataCollectedEvent 발행 (bus.Publish[DataCollectedEvent]의 축약형)
+ */
+func (b *EventBus) Publish(ctx context.Context, e DataCollectedEvent) {
+	Publish(ctx, b, e)
 }
@@ -0,0 +1,64 @@
+/*
+ * 따라잡기(catch-up) 순서 보장 : 구독 즉시 받는 과거 메시지와 그 사이의 새 발행분이 섞이지 않게 합니다.
+ *  - 과거 메시지(backlog)는 등록과 같은 잠금 구간에서 스냅샷하지만, 전달은 잠금을 푼 뒤 구독 함수 안에서 이루어짐
+ *    (잠금을 잡은 채 구독자를 호출하면 구독자가 발행할 때 교착되므로)
+ *  - 그 사이에 발행된 메시지는 구독자에 보류(hold)해 두었다가 과거 메시지를 모두 처리한 뒤 발행 순서대로 전달
+ *    → 어떤 전달 방식이든 "과거 → 이후 발행분" 순서가 유지됨
+ *  - 보류 중인 메시지는 pending에 포함되어 종료 시 비우기(drain.go)가 기다림
+ */
+package bus
+
+// holdLive : 따라잡기가 끝날 때까지 새 발행분을 보류 (등록 잠금 구간에서, 목록에 넣기 전에 호출)
+func (s *subscriber) holdLive() {
+	s.catchMu.Lock()
+	s.catching.Store(true)
+	s.catchMu.Unlock()
+}
+
+/*
+ * hold : 따라잡기 중이면 메시지를 보류 목록에 넣고 true
+ *  - 발행자의 ctx는 값만 쓰도록 메시지에 남김 (발행자는 이미 반환했을 수 있음)
+ */
+func (s *subscriber) hold(m Message) bool {
+	if !s.catching.Load() {
+		return false
+	}
+	s.catchMu.Lock()
+	defer s.catchMu.Unlock()
+	if !s.catching.Load() {
+		return false // 확인과 잠금 사이에 따라잡기가 끝남
+	}
+	s.held = append(s.held, m)
+	s.pending.Add(1)
+	return true
+}
+
+/*
+ * releaseHeld : 과거 메시지를 모두 처리한 뒤 호출, 보류한 메시지를 발행 순서대로 전달하고 보류를 끝냄
+ *  - 전달하는 동안 새로 보류된 메시지도 이어서 전달하고, 보류 목록이 빈 상태에서만 보류를 끝냄
+ *  - sync 구독자는 발행자가 이미 반환했으므로 버스 수명 ctx로 처리 (context.go)
+ */
+func (s *subscriber) releaseHeld() {
+	for {
+		s.catchMu.Lock()
+		held := s.held
+		s.held = nil
+		if len(held) == 0 {
+			s.catching.Store(false)
+			s.catchMu.Unlock()
+			return
+		}
+		s.catchMu.Unlock()
+
+		for _, m := range held {
+			s.pending.Add(-1)
+			if s.mode == DeliverySync {
+				if !s.closed.Load() {
+					s.handleDetached(m.ctx, m)
+				}
+				continue
+			}
+			s.send(m.ctx, m)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+/*
+ * 컨텍스트 전파 : 발행자의 context.Context를 구독자까지 넘깁니다.
+ *  - 구독자 함수는 func(ctx context.Context, e T) 형태이며, Publish도 ctx를 받습니다.
+ *  - 요청 범위 값(추적 정보, 요청 메타데이터 등)은 HTTP 핸들러 → 발행 → 구독자(Influx 쓰기 등)로 그대로 전달됩니다.
+ *  - 취소는 전달 방식에 따라 다릅니다.
+ *      sync          : 발행자의 ctx를 그대로 사용 (발행자가 구독자 처리를 기다리므로 발행자의 취소가 곧 처리 취소)
+ *      ordered/async : 발행자의 값은 유지하되 발행자의 취소/마감은 끊고(context.WithoutCancel),
+ *                      버스 수명(OnStop)에 묶음 → HTTP 요청이 끝나도 처리는 계속되고, 앱 종료 시 진행 중인 처리가 취소됨
+ *  - 발행자가 없는 따라잡기 전달은 버스 수명 ctx를 사용합니다.
+ */
+package bus
+
+import "context"
+
+/*
+ * detach : 발행자의 값은 유지하고 취소는 버스 수명(life)에 묶은 ctx
+ *  - 반환된 cancel은 처리가 끝나면 반드시 호출 (AfterFunc 등록 해제)
+ */
+func detach(ctx, life context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(life, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// handleDetached : 버스 수명에 묶인 ctx로 처리 (ordered/async)
+func (s *subscriber) handleDetached(ctx context.Context, m Message) {
+	ctx, cancel := detach(ctx, s.life)
+	defer cancel()
+	s.handle(ctx, m)
+}
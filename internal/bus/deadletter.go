@@ -0,0 +1,289 @@
+/*
+ * 데드레터 큐(DLQ) : 구독자가 재시도 후에도 처리하지 못한 메시지를 버리지 않고 보관합니다.
+ *  - 에러를 반환하거나 panic이 난 구독자(SubscribeErr, SubscribeTopicErr)는 실패 시 재시도 정책(WithRetry, 기본 APP_BUS_RETRY_*)에 따라
+ *    지수 백오프 + 지터 간격으로 다시 호출되고(재시도 수는 scaffold_bus_retries_total), 끝내 실패하면 메시지가 실패한 구독자 이름·마지막 에러와 함께 DLQ로 옮겨집니다.
+ *  - 메모리 링 버퍼(APP_BUS_DLQ_SIZE)에 보관하며, 가득 차면 가장 오래된 항목을 드롭 기록기에 남기고 밀어냅니다.
+ *  - APP_BUS_DLQ_FILE을 지정하면 같은 항목을 NDJSON 한 줄씩 파일에 덧붙입니다. (재시작 후 분석용 기록,
+ *    재전송(Replay)은 메모리에 남아 있는 항목만 가능)
+ *  - 관리 서버 /deadletters에서 조회, 재전송, 삭제할 수 있습니다.
+ */
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/drops" // DLQ에서 밀려난 항목 기록
+)
+
+// ErrDeadLetterNotFound : 존재하지 않는(이미 재전송/삭제된) DLQ 항목
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// ErrUnsubscribed : 실패했던 구독자가 이미 구독을 해지하여 재전송할 수 없음
+var ErrUnsubscribed = errors.New("subscriber has unsubscribed")
+
+/*
+ * retryPolicy : 구독자 실패 시 재시도 정책
+ *  - attempts   : 첫 호출을 포함한 최대 시도 횟수 (1이면 재시도 없음)
+ *  - backoff    : 첫 재시도 전 대기 시간, 이후 재시도마다 두 배
+ *  - maxBackoff : 재시도 간격 상한
+ *  - jitter     : 간격을 ±jitter 비율만큼 무작위로 흔듦 (0~1, 같은 순간 실패한 구독자들이 동시에 재시도하지 않도록)
+ *  - 재시도 동안 sync는 발행자가, ordered는 그 구독자의 큐가 대기합니다. (순서 유지)
+ */
+type retryPolicy struct {
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	jitter     float64
+}
+
+/*
+ * WithRetry : 이 구독의 시도 횟수와 첫 재시도 간격 지정 (버스 기본값 대신, 간격 상한과 지터는 버스 기본값)
+ */
+func WithRetry(attempts int, backoff time.Duration) SubscribeOption {
+	return func(c *subscribeConfig) { c.retry = &retryPolicy{attempts: attempts, backoff: backoff} }
+}
+
+// delay : n번째 재시도(1부터) 전 대기 시간
+func (p retryPolicy) delay(n int) time.Duration {
+	d := p.backoff
+	for i := 1; i < n && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.maxBackoff)
+	if p.jitter > 0 {
+		d = time.Duration(float64(d) * (1 + p.jitter*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+/*
+ * WithName : 구독자 이름 지정 (DLQ 항목과 로그에 표시, 기본값은 토픽 또는 이벤트 타입 이름)
+ */
+func WithName(name string) SubscribeOption {
+	return func(c *subscribeConfig) { c.name = name }
+}
+
+/*
+ * DeadLetter : DLQ 항목 하나
+ *  - Subscriber : 처리에 실패한 구독자 이름
+ *  - Attempts   : 지금까지 시도한 횟수 (재전송 실패분 포함)
+ *  - Error      : 마지막 에러 메시지
+ */
+type DeadLetter struct {
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	Topic      string    `json:"topic"`
+	Subscriber string    `json:"subscriber"`
+	Attempts   int       `json:"attempts"`
+	Error      string    `json:"error"`
+	Payload    any       `json:"payload"`
+
+	sub *subscriber // 재전송 대상
+}
+
+/*
+ * DeadLetterQueue 구조체
+ *  - entries : 보관 중인 항목 (오래된 것부터, 최대 size개)
+ *  - file    : 항목을 덧붙일 NDJSON 파일 (nil이면 메모리만)
+ */
+type DeadLetterQueue struct {
+	log   *zap.Logger
+	drops *drops.Recorder
+
+	mu      sync.Mutex
+	entries []DeadLetter
+	size    int
+	seq     uint64
+	file    *os.File
+}
+
+// newDeadLetterQueue : path가 비어 있지 않으면 파일을 덧붙이기 모드로 엶
+func newDeadLetterQueue(log *zap.Logger, dr *drops.Recorder, size int, path string) (*DeadLetterQueue, error) {
+	q := &DeadLetterQueue{log: log, drops: dr, size: size}
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+		if err != nil {
+			return nil, err
+		}
+		q.file = f
+	}
+	return q, nil
+}
+
+/*
+ * add : 실패한 메시지를 보관 (가득 차 있으면 가장 오래된 항목을 밀어냄)
+ */
+func (q *DeadLetterQueue) add(s *subscriber, m Message, attempts int, err error) {
+	q.mu.Lock()
+	q.seq++
+	e := DeadLetter{
+		ID:         strconv.FormatUint(q.seq, 10),
+		Time:       time.Now(),
+		Topic:      m.Topic,
+		Subscriber: s.name,
+		Attempts:   attempts,
+		Error:      err.Error(),
+		Payload:    m.Payload,
+		sub:        s,
+	}
+	var evicted *DeadLetter
+	if len(q.entries) >= q.size {
+		old := q.entries[0]
+		evicted = &old
+		q.entries = q.entries[1:]
+	}
+	q.entries = append(q.entries, e)
+	q.writeFile(e)
+	q.mu.Unlock()
+
+	q.log.Error("bus delivery dead-lettered", zap.String("id", e.ID), zap.String("topic", e.Topic),
+		zap.String("subscriber", e.Subscriber), zap.Int("attempts", attempts), zap.Error(err))
+	if evicted != nil {
+		q.drops.Record("bus", drops.ReasonBackpressure, keyOf(evicted.Payload),
+			"dead letter "+evicted.ID+" evicted (topic="+evicted.Topic+" subscriber="+evicted.Subscriber+")")
+	}
+}
+
+// writeFile : 항목을 파일에 NDJSON으로 덧붙임 (호출자가 잠금을 잡고 있어야 함)
+func (q *DeadLetterQueue) writeFile(e DeadLetter) {
+	if q.file == nil {
+		return
+	}
+	line, err := json.Marshal(e)
+	if err == nil {
+		_, err = q.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		q.log.Warn("dead letter file write failed", zap.String("id", e.ID), zap.Error(err))
+	}
+}
+
+/*
+ * List : 보관 중인 항목 사본 (오래된 것부터)
+ */
+func (q *DeadLetterQueue) List() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]DeadLetter{}, q.entries...)
+}
+
+// Len : 보관 중인 항목 수
+func (q *DeadLetterQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+/*
+ * Replay : 항목을 실패했던 구독자에게 한 번 다시 전달 (호출한 고루틴에서 동기 실행)
+ *  - 성공하면 항목을 제거하고, 실패하면 시도 횟수와 에러를 갱신해 그대로 둔 뒤 에러 반환
+ *  - 없는 항목이면 ErrDeadLetterNotFound, 구독자가 이미 해지했으면 ErrUnsubscribed
+ */
+func (q *DeadLetterQueue) Replay(ctx context.Context, id string) (DeadLetter, error) {
+	q.mu.Lock()
+	i := q.index(id)
+	if i < 0 {
+		q.mu.Unlock()
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	e := q.entries[i]
+	q.mu.Unlock()
+	if e.sub.closed.Load() {
+		return e, ErrUnsubscribed
+	}
+
+	err := e.sub.invoke(ctx, Message{Topic: e.Topic, Payload: e.Payload})
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i = q.index(id) // 그 사이 밀려났거나 삭제되었을 수 있음
+	if err == nil {
+		if i >= 0 {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+		}
+		q.log.Info("dead letter replayed", zap.String("id", id), zap.String("subscriber", e.Subscriber))
+		return e, nil
+	}
+	e.Attempts++
+	e.Error = err.Error()
+	if i >= 0 {
+		q.entries[i] = e
+	}
+	return e, err
+}
+
+/*
+ * Discard : 항목 삭제 (재전송하지 않고 포기)
+ */
+func (q *DeadLetterQueue) Discard(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.index(id)
+	if i < 0 {
+		return ErrDeadLetterNotFound
+	}
+	q.entries = append(q.entries[:i], q.entries[i+1:]...)
+	return nil
+}
+
+// index : ID의 위치 (없으면 -1, 호출자가 잠금을 잡고 있어야 함)
+func (q *DeadLetterQueue) index(id string) int {
+	for i, e := range q.entries {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// close : 파일 닫기
+func (q *DeadLetterQueue) close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.file == nil {
+		return nil
+	}
+	return q.file.Close()
+}
+
+/*
+ * handle : 구독자 호출 + 재시도, 끝내 실패하면 DLQ로 이동
+ *  - ctx가 취소되면(앱 종료 등) 남은 재시도를 건너뛰고 바로 DLQ로 옮김 (파일 기록 시 재시작 후 확인 가능)
+ */
+func (s *subscriber) handle(ctx context.Context, m Message) {
+	err := s.invoke(ctx, m)
+	attempts := 1
+	for err != nil && attempts < s.retry.attempts && ctx.Err() == nil {
+		select {
+		case <-time.After(s.retry.delay(attempts)):
+		case <-ctx.Done():
+			continue
+		}
+		s.metrics.retries.WithLabelValues(s.name).Inc()
+		err = s.invoke(ctx, m)
+		attempts++
+	}
+	if err != nil {
+		s.metrics.deadLetters.WithLabelValues(s.name).Inc()
+		s.dlq.add(s, m, attempts, err)
+		return
+	}
+	s.metrics.observeDelivery(s.name, m)
+}
+
+// keyOf : Keyed 이벤트면 그 키 (보통 장치 ID), 아니면 빈 문자열
+func keyOf(payload any) string {
+	if k, ok := payload.(Keyed); ok {
+		return k.EventKey()
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+/*
+ * 전달 방식(delivery mode) : 발행된 메시지를 구독자에게 어떻게 넘길지 정합니다.
+ *  - sync    : 발행자 고루틴에서 우선순위 → 구독 등록 순서대로 바로 호출 (Publish가 구독자 처리를 기다림, priority.go)
+ *  - ordered : 구독자마다 크기가 정해진 큐와 전용 고루틴 하나 (기본값)
+ *              → 구독자별로 발행 순서 보장 (장치별 Influx 쓰기 순서 등)
+ *              → 느린 구독자(멈춘 Influx 쓰기 등)는 자기 큐만 채우므로 다른 구독자의 전달을 늦추지 않음
+ *                (큐가 가득 찬 뒤의 동작은 백프레셔 정책에 따름, backpressure.go)
+ *  - async   : 메시지마다 새 고루틴 (순서 보장 없음, 고루틴 수에 상한이 없음)
+ *  - 구독 시 WithDelivery로 고르거나, 지정하지 않으면 버스 기본값(APP_BUS_DELIVERY)을 따릅니다.
+ *  - 큐 크기는 구독 시 WithBufferSize로 정하거나, 지정하지 않으면 버스 기본값(APP_BUS_QUEUE_SIZE)을 따릅니다.
+ */
+package bus
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+// DeliveryMode : 전달 방식
+type DeliveryMode string
+
+const (
+	DeliverySync    DeliveryMode = "sync"
+	DeliveryOrdered DeliveryMode = "ordered"
+	DeliveryAsync   DeliveryMode = "async"
+)
+
+// valid : 지원하는 전달 방식인지
+func (m DeliveryMode) valid() bool {
+	return m == DeliverySync || m == DeliveryOrdered || m == DeliveryAsync
+}
+
+/*
+ * WithDelivery : 이 구독의 전달 방식 지정 (버스 기본값 대신)
+ */
+func WithDelivery(mode DeliveryMode) SubscribeOption {
+	return func(c *subscribeConfig) { c.delivery = mode }
+}
+
+/*
+ * WithBufferSize : 이 구독자의 큐 크기 지정 (버스 기본값 대신)
+ *  - 전달 방식을 따로 지정하지 않았으면 ordered로 전달 (sync/async에는 큐가 없으므로 무시)
+ *  - 처리가 가끔 오래 멈추는 구독자(외부 저장소 쓰기 등)는 크게 잡아 멈춘 동안의 메시지를 버퍼링
+ */
+func WithBufferSize(n int) SubscribeOption {
+	return func(c *subscribeConfig) { c.bufferSize = n }
+}
+
+/*
+ * subscriber : 구독자 하나
+ *  - name  : 구독자 이름 (DLQ 항목과 로그에 표시)
+ *  - fn    : 메시지 처리 함수 (에러를 반환하거나 panic이 나면 retry 정책대로 재시도 후 DLQ로 이동)
+ *  - mode  : 전달 방식
+ *  - queue : ordered 방식의 대기열 (전용 고루틴이 순서대로 꺼내 처리, 가득 차면 백프레셔 정책 적용)
+ *  - typ/topic : 등록된 위치 (타입 구독이면 typ, 토픽 구독이면 topic) → 구독 해지 시 사용
+ *  - closed    : 구독 해지됨 (이후 전달은 건너뜀)
+ *  - priority/seq : 전달 순서 (우선순위, 같으면 등록 순서, priority.go)
+ *  - catching/held : 따라잡기 중 보류한 새 발행분 (catchup.go)
+ */
+type subscriber struct {
+	name  string
+	fn    DeliverHandler
+	mode  DeliveryMode
+	queue *queue
+	retry retryPolicy
+	dlq   *DeadLetterQueue
+
+	typ    reflect.Type
+	topic  string
+	closed atomic.Bool
+
+	priority Priority
+	seq      uint64
+	filters  []Filter // 전달 전 조건 (filter.go)
+
+	responder bool         // 요청 응답자 (request.go)
+	batch     *batchConfig // 묶음 전달 설정 (SubscribeBatch만, 아니면 nil, batch.go)
+	limiter   *rateLimiter // 전달 속도 제한 (WithRateLimit, 아니면 nil, ratelimit.go)
+
+	catchMu  sync.Mutex
+	catching atomic.Bool // 과거 메시지를 처리하는 중 (새 발행분은 held에 보류)
+	held     []Message
+
+	life    context.Context // 버스 수명 (OnStop에서 취소)
+	pending *atomic.Int64   // 버스 전체의 처리 대기 메시지 수 (ordered/async, drain.go)
+	log     *zap.Logger
+	metrics *busMetrics
+}
+
+/*
+ * newSubscriber : 구독 옵션에 맞는 구독자 생성
+ *  - name은 WithName으로 지정하지 않았을 때의 기본 이름
+ *  - ordered면 큐와 전용 고루틴을 시작
+ *  - 알 수 없는 전달 방식/백프레셔 정책, 0 이하의 큐 크기는 버스 기본값으로 대체
+ */
+func (b *EventBus) newSubscriber(cfg subscribeConfig, name string, fn DeliverHandler) *subscriber {
+	mode := cfg.delivery
+	if !mode.valid() {
+		mode = b.delivery
+		if cfg.bufferSize > 0 || cfg.rate > 0 || cfg.coalesce {
+			mode = DeliveryOrdered
+		}
+	}
+	if cfg.name != "" {
+		name = cfg.name
+	}
+	retry := b.retry
+	if cfg.retry != nil {
+		retry.attempts, retry.backoff = cfg.retry.attempts, cfg.retry.backoff
+	}
+	fn = chainDeliver(b.interceptors, name, fn)
+	s := &subscriber{name: name, fn: fn, mode: mode, retry: retry, dlq: b.dlq,
+		priority: cfg.priority, seq: b.seq.Add(1), filters: cfg.filters, responder: cfg.responder, life: b.life, pending: &b.pending, log: b.log, metrics: b.metrics}
+	if mode == DeliveryOrdered {
+		policy := cfg.backpressure
+		if !policy.valid() {
+			policy = b.backpressure
+		}
+		size := cfg.bufferSize
+		if size < 1 {
+			size = b.queueSize
+		}
+		if cfg.batch.collect != nil {
+			s.batch = &cfg.batch
+			size = max(size, cfg.batch.size) // 묶음 하나가 큐에 다 들어가도록
+		}
+		s.queue = newQueue(size, policy, b.drops, b.metrics.queueDepth.WithLabelValues(name), &b.pending)
+		s.queue.name, s.queue.log = name, b.log
+		s.queue.overflows = b.metrics.overflows.WithLabelValues(name, string(policy))
+		if cfg.coalesce {
+			s.queue.coalesced = b.metrics.coalesced.WithLabelValues(name)
+		}
+		if cfg.rate > 0 {
+			s.limiter = newRateLimiter(cfg.rate, cfg.burst)
+		}
+		if s.batch != nil {
+			go s.runBatches()
+			return s
+		}
+		go func() {
+			for {
+				s.throttle()
+				m, ok := s.queue.pop()
+				if !ok {
+					return
+				}
+				s.handleDetached(m.ctx, m)
+				s.pending.Add(-1)
+			}
+		}()
+	}
+	return s
+}
+
+/*
+ * deliver : 전달 방식에 따라 메시지 전달 (ordered는 큐가 가득 차면 백프레셔 정책에 따름)
+ *  - sync는 발행자의 ctx, ordered/async는 발행자의 값만 유지하고 버스 수명에 묶인 ctx로 처리 (context.go)
+ *  - 구독자가 따라잡기 중이면 과거 메시지를 다 처리할 때까지 보류 (catchup.go)
+ */
+func (s *subscriber) deliver(ctx context.Context, m Message) {
+	if s.closed.Load() {
+		return // 발행 중 스냅샷 이후 구독 해지됨
+	}
+	m.ctx = ctx // 보류되었다가 전달될 때 사용
+	if s.hold(m) {
+		return
+	}
+	if s.mode == DeliverySync {
+		s.handle(ctx, m)
+		return
+	}
+	s.send(ctx, m)
+}
+
+// send : ordered는 큐에 넣고, async는 새 고루틴에서 처리
+func (s *subscriber) send(ctx context.Context, m Message) {
+	if s.closed.Load() {
+		return
+	}
+	switch s.mode {
+	case DeliveryOrdered:
+		m.ctx = ctx // 큐에서 꺼낼 때 사용
+		s.pending.Add(1)
+		s.queue.push(m)
+	default:
+		s.pending.Add(1)
+		go func() { // 비동기 실행(별도 고루틴)
+			defer s.pending.Add(-1)
+			s.handleDetached(ctx, m)
+		}()
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * 종료 시 비우기(drain) : 앱이 종료될 때 큐에 남은 이벤트를 버리지 않고 구독자에게 끝까지 전달합니다.
+ *  - 비우기 시점 : fx는 OnStop을 등록 역순으로 부르므로, 가장 먼저 만들어지는 버스의 훅은 저장소·큐·브리지가 닫힌 뒤에야 불림
+ *      → 자원을 닫는 구독자는 자기 훅을 등록한 직후 DrainBefore를 불러, 닫기 전에 비우기가 끝나게 함
+ *      → 비우기는 처음 불린 훅에서 한 번만 하고, 이후(다른 구독자의 훅, 버스 자신의 훅)는 바로 반환
+ *  - 비우기 순서 :
+ *      ① 새 발행을 받지 않음 (종료 중 발행된 이벤트는 드롭 기록기에 reason="shutdown"으로 남김)
+ *      ② ordered 큐와 async 고루틴에 남은 이벤트가 모두 처리될 때까지 대기 (APP_BUS_DRAIN_TIMEOUT, 기본 10s)
+ *      ③ 마감이 지나면 버스 수명 ctx를 취소하여 진행 중인 처리(Influx 쓰기 등)에 종료를 알리고,
+ *         아직 큐에 남은 이벤트는 버린 뒤 구독자별로 드롭 기록 (reason="shutdown")
+ *  - 비우기 결과(전달 대기 중이던 수, 버린 수, 걸린 시간)는 로그로 남습니다.
+ *  - 처리 중에 취소된 이벤트는 재시도 후 데드레터 큐로 옮겨집니다. (APP_BUS_DLQ_FILE이면 파일에 남음)
+ */
+package bus
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/fx"  // 비우기 훅 등록
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/drops" // 종료로 버린 이벤트 기록
+)
+
+// ErrClosed : 버스가 종료 중이라 발행/요청을 받지 않음
+var ErrClosed = errors.New("bus: closed")
+
+// 비우기 중 남은 이벤트 수를 확인하는 간격
+const drainPoll = 10 * time.Millisecond
+
+// Drain : 버스를 비움 (여러 번 불러도 한 번만, 진행 중이면 끝날 때까지 대기)
+func (b *EventBus) Drain(ctx context.Context) {
+	b.drainOnce.Do(func() { b.drain(ctx, b.drainTimeout) })
+}
+
+/*
+ * DrainBefore : 지금까지 lc에 등록된 훅보다 먼저 버스를 비우는 OnStop 훅 등록
+ *  - 구독자가 자원을 닫는 훅을 등록한 직후 부름 (예: 저장소 파이프라인, 브리지)
+ *  - 그 뒤에 등록되는 발행자(수집 루프, HTTP 서버)의 훅은 여전히 비우기보다 먼저 멈춤
+ */
+func (b *EventBus) DrainBefore(lc fx.Lifecycle) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			b.Drain(ctx)
+			return nil
+		},
+	})
+}
+
+/*
+ * drain : 새 발행을 막고, 남은 이벤트가 처리되거나 마감(timeout 또는 ctx)이 될 때까지 대기한 뒤 버스 수명을 끝냄
+ */
+func (b *EventBus) drain(ctx context.Context, timeout time.Duration) {
+	b.closing.Store(true)
+	start := time.Now()
+	queued := b.pending.Load()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if !b.waitIdle(ctx) {
+		b.log.Warn("bus drain deadline exceeded", zap.Int64("remaining", b.pending.Load()), zap.Duration("timeout", timeout))
+	}
+	b.stop() // 진행 중인 처리 취소
+
+	dropped := 0
+	b.mu.RLock()
+	subs := b.allSubscribers()
+	b.mu.RUnlock()
+	for _, sub := range subs {
+		if sub.queue == nil {
+			continue
+		}
+		for _, m := range sub.queue.close() {
+			b.drops.Record("bus", drops.ReasonShutdown, keyOf(m.Payload), "topic="+m.Topic+" subscriber="+sub.name)
+			dropped++
+		}
+	}
+	b.log.Info("bus drained", zap.Int64("queued", queued), zap.Int("dropped", dropped), zap.Duration("took", time.Since(start)))
+}
+
+// waitIdle : 처리 대기 중인 이벤트가 없어질 때까지 대기 (ctx가 먼저 끝나면 false)
+func (b *EventBus) waitIdle(ctx context.Context) bool {
+	ticker := time.NewTicker(drainPoll)
+	defer ticker.Stop()
+	for b.pending.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// allSubscribers : 등록된 모든 구독자 (호출자가 잠금을 잡고 있어야 함)
+func (b *EventBus) allSubscribers() []*subscriber {
+	var out []*subscriber
+	for _, subs := range b.subscribers {
+		out = append(out, subs...)
+	}
+	for _, subs := range b.topics {
+		out = append(out, subs...)
+	}
+	return append(out, b.patterns...)
+}
+
+// rejectClosed : 종료 중이면 발행된 메시지를 드롭 기록하고 true
+func (b *EventBus) rejectClosed(m Message) bool {
+	if !b.closing.Load() {
+		return false
+	}
+	b.drops.Record("bus", drops.ReasonShutdown, keyOf(m.Payload), "topic="+m.Topic+" published during shutdown")
+	return true
+}
@@ -0,0 +1,140 @@
+/*
+ * 구독 필터 : 버스가 전달 전에 평가하여, 관심 없는 메시지는 구독자에게 넘기지 않습니다.
+ *  - 걸러진 메시지는 고루틴/큐 자리를 쓰지 않으며 재시도·DLQ 대상도 아님
+ *  - WithFilter : 임의의 조건 함수 (Message 단위, 토픽/페이로드 모두 볼 수 있음)
+ *  - Where[T]   : 타입 T 페이로드에 대한 조건 함수 (다른 타입의 메시지는 거름)
+ *  - WithMatch  : 선언적 조건 (장치 ID 집합, 필드 존재, 값 임계치) → DataCollectedEvent에 적용
+ *  - 여러 필터를 주면 모두 통과해야 전달 (AND)
+ *  - 따라잡기/retained 메시지에도 같은 필터가 적용됨
+ */
+package bus
+
+import "fmt"
+
+// Filter : 메시지를 구독자에게 전달할지 결정 (false면 버림)
+type Filter func(m Message) bool
+
+/*
+ * WithFilter : 조건 함수를 통과한 메시지만 전달
+ */
+func WithFilter(f Filter) SubscribeOption {
+	return func(c *subscribeConfig) { c.filters = append(c.filters, f) }
+}
+
+/*
+ * Where : 페이로드가 T이고 조건 함수를 통과한 메시지만 전달
+ *  - 예) bus.Where(func(e bus.DataCollectedEvent) bool { return e.Values["soc"] < 20 })
+ */
+func Where[T any](fn func(e T) bool) SubscribeOption {
+	return WithFilter(func(m Message) bool {
+		e, ok := m.Payload.(T)
+		return ok && fn(e)
+	})
+}
+
+/*
+ * Threshold : 값 임계치 조건 하나
+ *  - Op : gt | gte | lt | lte | eq (규칙 엔진과 동일)
+ */
+type Threshold struct {
+	Field string  `json:"field"`
+	Op    string  `json:"op"`
+	Value float64 `json:"value"`
+}
+
+/*
+ * Match : 선언적 구독 조건 (비어 있는 항목은 검사하지 않음, 모든 항목을 만족해야 통과)
+ *  - Devices    : 이 장치 ID 중 하나의 이벤트만 (Keyed 이벤트는 EventKey로 비교)
+ *  - Fields     : 이 필드가 모두 있는 DataCollectedEvent만
+ *  - Thresholds : 모든 임계치 조건을 만족하는 DataCollectedEvent만 (필드가 없으면 불만족)
+ */
+type Match struct {
+	Devices    []string    `json:"devices,omitempty"`
+	Fields     []string    `json:"fields,omitempty"`
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+}
+
+/*
+ * WithMatch : 선언적 조건을 만족하는 메시지만 전달
+ *  - 알 수 없는 임계치 연산자는 구독 시점에 panic (설정 실수를 조용히 넘기지 않음, 설정에서 읽었다면 Validate로 미리 검사)
+ */
+func WithMatch(mt Match) SubscribeOption {
+	if err := mt.Validate(); err != nil {
+		panic(err)
+	}
+	devices := make(map[string]bool, len(mt.Devices))
+	for _, d := range mt.Devices {
+		devices[d] = true
+	}
+	return WithFilter(func(m Message) bool {
+		if len(devices) > 0 {
+			k, ok := m.Payload.(Keyed)
+			if !ok || !devices[k.EventKey()] {
+				return false
+			}
+		}
+		if len(mt.Fields) == 0 && len(mt.Thresholds) == 0 {
+			return true
+		}
+		e, ok := m.Payload.(DataCollectedEvent)
+		if !ok {
+			return false
+		}
+		for _, f := range mt.Fields {
+			if _, ok := e.Values[f]; !ok {
+				return false
+			}
+		}
+		for _, t := range mt.Thresholds {
+			v, ok := e.Values[t.Field]
+			if !ok || !compare(t.Op, v, t.Value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+/*
+ * Validate : 임계치 조건의 필드/연산자 검사
+ */
+func (mt Match) Validate() error {
+	for _, t := range mt.Thresholds {
+		if t.Field == "" {
+			return fmt.Errorf("threshold field is required")
+		}
+		switch t.Op {
+		case "gt", "gte", "lt", "lte", "eq":
+		default:
+			return fmt.Errorf("unknown threshold op %q for field %q", t.Op, t.Field)
+		}
+	}
+	return nil
+}
+
+// compare : 연산자에 따라 값 비교
+func compare(op string, v, threshold float64) bool {
+	switch op {
+	case "gt":
+		return v > threshold
+	case "gte":
+		return v >= threshold
+	case "lt":
+		return v < threshold
+	case "lte":
+		return v <= threshold
+	case "eq":
+		return v == threshold
+	}
+	return false
+}
+
+// accepts : 구독자의 모든 필터를 통과하는지
+func (s *subscriber) accepts(m Message) bool {
+	for _, f := range s.filters {
+		if !f(m) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+/*
+ * 인터셉터(interceptor) 체인 : HTTP 미들웨어처럼 모든 발행과 구독자 전달을 감쌉니다.
+ *  - 로깅, 메트릭, 보강(enrichment), 필터링 같은 공통 처리를 구독자마다 복사하지 않고 한 곳에 둡니다.
+ *  - Interceptor를 구현한 값을 fx 값 그룹(group:"bus_interceptors")으로 제공하면 EventBus가 생성될 때 체인에 넣습니다.
+ *      예) fx.Provide(bus.AsInterceptor(mymodule.NewInterceptor))
+ *  - 단계 :
+ *      InterceptPublish : 발행 한 번을 감쌈 (next를 호출하지 않으면 발행 자체를 걸러냄, 메시지나 ctx를 바꿔 넘기면 보강)
+ *      InterceptDeliver : 구독자 호출 한 번을 감쌈 (구독자 이름을 함께 받음, 반환한 에러는 재시도/DLQ 대상)
+ *  - 먼저 등록된 인터셉터가 바깥쪽에서 실행됩니다. (mux.Router.Use와 같은 순서)
+ *  - 구독자 호출 단계의 panic은 인터셉터 안에서 나더라도 recover.go에서 복구됩니다.
+ */
+package bus
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go.uber.org/fx"  // 값 그룹 등록
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// PublishHandler : 발행 단계 (토픽/타입 구독자에게 전달)
+type PublishHandler func(ctx context.Context, m Message)
+
+// DeliverHandler : 구독자 호출 단계
+type DeliverHandler func(ctx context.Context, m Message) error
+
+/*
+ * Interceptor : 발행/전달을 감싸는 버스 미들웨어
+ */
+type Interceptor interface {
+	InterceptPublish(next PublishHandler) PublishHandler
+	InterceptDeliver(subscriber string, next DeliverHandler) DeliverHandler
+}
+
+/*
+ * InterceptorFuncs : 필요한 단계만 함수로 지정하는 Interceptor 구현 (nil인 단계는 그대로 통과)
+ */
+type InterceptorFuncs struct {
+	Publish func(next PublishHandler) PublishHandler
+	Deliver func(subscriber string, next DeliverHandler) DeliverHandler
+}
+
+// InterceptPublish : Interceptor 구현
+func (f InterceptorFuncs) InterceptPublish(next PublishHandler) PublishHandler {
+	if f.Publish == nil {
+		return next
+	}
+	return f.Publish(next)
+}
+
+// InterceptDeliver : Interceptor 구현
+func (f InterceptorFuncs) InterceptDeliver(subscriber string, next DeliverHandler) DeliverHandler {
+	if f.Deliver == nil {
+		return next
+	}
+	return f.Deliver(subscriber, next)
+}
+
+/*
+ * AsInterceptor : 생성자의 결과를 Interceptor로서 group:"bus_interceptors"에 제공하도록 감쌈
+ *  - 생성자의 결과 타입은 Interceptor를 구현해야 함
+ *  - 생성자가 *EventBus에 의존하면 순환 의존이 되므로 주의
+ */
+func AsInterceptor(f interface{}) interface{} {
+	return fx.Annotate(f, fx.As(new(Interceptor)), fx.ResultTags(`group:"bus_interceptors"`))
+}
+
+// chainPublish : 발행 단계에 인터셉터 적용 (첫 번째가 가장 바깥)
+func chainPublish(ics []Interceptor, h PublishHandler) PublishHandler {
+	for i := len(ics) - 1; i >= 0; i-- {
+		h = ics[i].InterceptPublish(h)
+	}
+	return h
+}
+
+// chainDeliver : 구독자 호출 단계에 인터셉터 적용 (첫 번째가 가장 바깥)
+func chainDeliver(ics []Interceptor, subscriber string, h DeliverHandler) DeliverHandler {
+	for i := len(ics) - 1; i >= 0; i-- {
+		h = ics[i].InterceptDeliver(subscriber, h)
+	}
+	return h
+}
+
+// payloadType : 인터셉터가 payload를 다른 타입으로 바꿨으면 그 타입, 아니면 발행 시의 타입 t
+func payloadType(m Message, t reflect.Type) reflect.Type {
+	if m.Payload == nil {
+		return t
+	}
+	if pt := reflect.TypeOf(m.Payload); !pt.AssignableTo(t) {
+		return pt
+	}
+	return t
+}
+
+/*
+ * NewTraceInterceptor : 모든 발행과 구독자 처리 시간을 Debug 로그로 남기는 인터셉터
+ *  - APP_BUS_TRACE=true일 때만 기록 (기본 false, 이벤트마다 로그가 남으므로 진단할 때만 켬)
+ */
+func NewTraceInterceptor(log *zap.Logger) InterceptorFuncs {
+	if !config.Bool(log, "APP_BUS_TRACE", false) {
+		return InterceptorFuncs{}
+	}
+	return InterceptorFuncs{
+		Publish: func(next PublishHandler) PublishHandler {
+			return func(ctx context.Context, m Message) {
+				log.Debug("bus publish", zap.String("topic", m.Topic), zap.String("key", keyOf(m.Payload)))
+				next(ctx, m)
+			}
+		},
+		Deliver: func(subscriber string, next DeliverHandler) DeliverHandler {
+			return func(ctx context.Context, m Message) error {
+				start := time.Now()
+				err := next(ctx, m)
+				log.Debug("bus deliver", zap.String("topic", m.Topic), zap.String("subscriber", subscriber),
+					zap.Duration("took", time.Since(start)), zap.Error(err))
+				return err
+			}
+		},
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * EventBus 메트릭 : 발행/전달 흐름을 Prometheus 레지스트리로 노출합니다.
+ *  - scaffold_bus_published_total{topic}                     : 발행 수 (발행 인터셉터를 통과한 것)
+ *  - scaffold_bus_delivered_total{subscriber}                : 구독자가 처리에 성공한 메시지 수
+ *  - scaffold_bus_delivery_latency_seconds{subscriber}       : 발행부터 구독자 처리 완료까지 걸린 시간 (큐 대기 + 재시도 + 처리)
+ *  - scaffold_bus_queue_depth{subscriber}                    : ordered 구독자 큐에 쌓인 메시지 수
+ *  - scaffold_bus_subscriber_failures_total{subscriber,kind} : 에러/panic 횟수 (kind: error | panic)
+ *  - scaffold_bus_retries_total{subscriber}                  : 실패 후 다시 호출한 횟수 (재시도 정책, deadletter.go)
+ *  - scaffold_bus_dead_letters_total{subscriber}             : 데드레터 큐로 옮겨진 메시지 수
+ *  - scaffold_bus_dead_letters                               : 데드레터 큐에 보관 중인 항목 수
+ *  - scaffold_bus_coalesced_total{subscriber}                : 큐에서 같은 장치의 최신 메시지로 교체되어 전달하지 않은 메시지 수 (WithCoalesce)
+ *  - 백프레셔로 버린 메시지는 scaffold_events_dropped_total{source="bus"}에 함께 집계됩니다. (drops)
+ *  - Collector 발행 수와 influx 구독자의 처리 수·큐 깊이·지연을 비교하면 Influx 쓰기가 밀리는지 알 수 있습니다.
+ *  - subscriber 라벨은 구독자 이름이므로, 같은 타입/토픽을 여러 번 구독하면 WithName으로 구분하는 것이 좋습니다.
+ */
+package bus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 레지스트리
+)
+
+// busMetrics : EventBus 메트릭 묶음
+type busMetrics struct {
+	published   *prometheus.CounterVec
+	delivered   *prometheus.CounterVec
+	latency     *prometheus.HistogramVec
+	queueDepth  *prometheus.GaugeVec
+	failures    *prometheus.CounterVec
+	retries     *prometheus.CounterVec
+	deadLetters *prometheus.CounterVec
+	coalesced   *prometheus.CounterVec
+	overflows   *prometheus.CounterVec
+}
+
+// newBusMetrics : 버스 메트릭 생성 및 레지스트리 등록
+func newBusMetrics(reg *prometheus.Registry, dlq *DeadLetterQueue) *busMetrics {
+	m := &busMetrics{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bus_published_total",
+			Help:      "Messages published on the event bus, by topic.",
+		}, []string{"topic"}),
+		delivered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bus_delivered_total",
+			Help:      "Messages successfully handled by event bus subscribers, by subscriber.",
+		}, []string{"subscriber"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "scaffold",
+			Name:      "bus_delivery_latency_seconds",
+			Help:      "Time from publish until a subscriber finished handling the message, including queueing and retries.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 10), // 0.5ms ~ 131s
+		}, []string{"subscriber"}),
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "scaffold",
+			Name:      "bus_queue_depth",
+			Help:      "Messages waiting in ordered event bus subscriber queues, by subscriber.",
+		}, []string{"subscriber"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bus_subscriber_failures_total",
+			Help:      "Event bus subscriber invocations that returned an error or panicked, by subscriber and kind.",
+		}, []string{"subscriber", "kind"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bus_retries_total",
+			Help:      "Event bus subscriber retries after a failed invocation, by subscriber.",
+		}, []string{"subscriber"}),
+		deadLetters: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bus_dead_letters_total",
+			Help:      "Messages moved to the event bus dead-letter queue, by subscriber.",
+		}, []string{"subscriber"}),
+		coalesced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bus_coalesced_total",
+			Help:      "Queued messages replaced by a newer message for the same key before delivery, by subscriber.",
+		}, []string{"subscriber"}),
+		overflows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "bus_queue_overflows_total",
+			Help:      "Messages that arrived at a full ordered subscriber queue, by subscriber and backpressure policy applied.",
+		}, []string{"subscriber", "policy"}),
+	}
+	reg.MustRegister(m.published, m.delivered, m.latency, m.queueDepth, m.failures, m.retries, m.deadLetters, m.coalesced, m.overflows,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "scaffold",
+			Name:      "bus_dead_letters",
+			Help:      "Entries currently held in the event bus dead-letter queue.",
+		}, func() float64 { return float64(dlq.Len()) }))
+	return m
+}
+
+// observeDelivery : 처리 성공 기록 (발행 시각을 모르는 따라잡기/재전송 메시지는 지연을 기록하지 않음)
+func (m *busMetrics) observeDelivery(subscriber string, msg Message) {
+	m.delivered.WithLabelValues(subscriber).Inc()
+	if !msg.at.IsZero() {
+		m.latency.WithLabelValues(subscriber).Observe(time.Since(msg.at).Seconds())
+	}
+}
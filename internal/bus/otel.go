@@ -0,0 +1,69 @@
+/*
+ * OpenTelemetry 인터셉터 : 버스 발행과 구독자 처리를 스팬으로 기록합니다.
+ *  - 발행 : "<토픽> publish" 생산자(producer) 스팬을 발행자 ctx의 스팬(HTTP 요청 등) 아래에 만들고, 그 ctx로 구독자에게 전달
+ *  - 처리 : "<토픽> process" 소비자(consumer) 스팬을 발행 스팬 아래에 만들고, 구독자 fn은 이 스팬의 ctx를 받음
+ *    → 구독자가 ctx로 만든 하위 스팬(Influx 쓰기 등)까지 하나의 트레이스로 이어짐
+ *  - ordered/async 구독자는 발행자의 ctx 값을 유지하므로(context.go) 큐를 거쳐도 스팬 컨텍스트가 전달됨
+ *  - 큐 대기 등 버스로 인한 지연은 처리 스팬의 messaging.bus.wait_ms 속성으로 보임
+ *  - 구독자가 에러를 반환하거나 panic이 나면 처리 스팬을 에러로 표시 (재시도마다 스팬 하나)
+ */
+package bus
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute" // 스팬 속성
+	"go.opentelemetry.io/otel/codes"     // 스팬 상태
+	"go.opentelemetry.io/otel/trace"     // 스팬 생성
+)
+
+/*
+ * NewOtelInterceptor : 발행/처리 스팬을 만드는 인터셉터
+ *  - 추적이 비활성(no-op 제공자)이면 스팬이 기록되지 않으므로 비용이 거의 없음
+ */
+func NewOtelInterceptor(tp trace.TracerProvider) InterceptorFuncs {
+	tracer := tp.Tracer("generic-api-scaffold/internal/bus")
+	return InterceptorFuncs{
+		Publish: func(next PublishHandler) PublishHandler {
+			return func(ctx context.Context, m Message) {
+				ctx, span := tracer.Start(ctx, m.Topic+" publish",
+					trace.WithSpanKind(trace.SpanKindProducer),
+					trace.WithAttributes(
+						attribute.String("messaging.system", "scaffold-bus"),
+						attribute.String("messaging.destination.name", m.Topic),
+						attribute.String("messaging.message.key", keyOf(m.Payload)),
+					))
+				defer span.End()
+				next(ctx, m)
+			}
+		},
+		Deliver: func(subscriber string, next DeliverHandler) DeliverHandler {
+			return func(ctx context.Context, m Message) (err error) {
+				attrs := []attribute.KeyValue{
+					attribute.String("messaging.system", "scaffold-bus"),
+					attribute.String("messaging.destination.name", m.Topic),
+					attribute.String("messaging.consumer.name", subscriber),
+				}
+				if !m.at.IsZero() {
+					attrs = append(attrs, attribute.Float64("messaging.bus.wait_ms", float64(time.Since(m.at))/float64(time.Millisecond)))
+				}
+				ctx, span := tracer.Start(ctx, m.Topic+" process",
+					trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attrs...))
+				defer func() {
+					if r := recover(); r != nil {
+						span.SetStatus(codes.Error, "panic")
+						span.End()
+						panic(r) // recover.go에서 복구
+					}
+					if err != nil {
+						span.RecordError(err)
+						span.SetStatus(codes.Error, err.Error())
+					}
+					span.End()
+				}()
+				return next(ctx, m)
+			}
+		},
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * 와일드카드 토픽 구독 : 토픽 이름을 하나하나 나열하지 않고 패턴으로 넓게 구독합니다.
+ *  - 토픽은 점(.)으로 구분된 세그먼트로 봅니다. (AMQP 토픽 교환기와 같은 규칙)
+ *      *  : 세그먼트 정확히 하나       예) device.*.status → device.A1.status (device.A1.x.status는 아님)
+ *      #  : 세그먼트 0개 이상         예) data.# → data, data.collected, data.collected.raw
+ *                                        # → 모든 토픽
+ *  - SubscribeTopic에 * 또는 #이 들어간 세그먼트가 있으면 패턴 구독으로 등록됩니다.
+ *  - 감사 로거, 웹훅 전달기처럼 여러 스트림을 가로질러 듣는 소비자용입니다.
+ *  - 패턴 구독자는 발행마다 패턴 비교를 하므로, 토픽이 고정이면 일반 토픽 구독이 더 저렴합니다.
+ */
+package bus
+
+import (
+	"sort"
+	"strings"
+)
+
+// isPattern : 와일드카드 세그먼트가 있는 토픽 패턴인지
+func isPattern(topic string) bool {
+	for _, seg := range strings.Split(topic, ".") {
+		if seg == "*" || seg == "#" {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * MatchTopic : 토픽이 패턴에 맞는지 (* : 세그먼트 하나, # : 세그먼트 0개 이상)
+ */
+func MatchTopic(pattern, topic string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(topic, "."))
+}
+
+// matchSegments : 세그먼트 단위 비교 (#은 뒤에서부터 0개 이상을 소비해 보며 재귀)
+func matchSegments(pat, top []string) bool {
+	for len(pat) > 0 {
+		switch pat[0] {
+		case "#":
+			if len(pat) == 1 {
+				return true // 마지막 #은 나머지 전부
+			}
+			for i := 0; i <= len(top); i++ {
+				if matchSegments(pat[1:], top[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(top) == 0 {
+				return false
+			}
+		default:
+			if len(top) == 0 || pat[0] != top[0] {
+				return false
+			}
+		}
+		pat, top = pat[1:], top[1:]
+	}
+	return len(top) == 0
+}
+
+// matchingLatest : 패턴에 맞는 모든 토픽의 키별 마지막 메시지 (토픽 순, 호출자가 잠금을 잡고 있어야 함)
+func (b *EventBus) matchingLatest(pattern string) []Message {
+	topics := make([]string, 0, len(b.latest))
+	for t := range b.latest {
+		if MatchTopic(pattern, t) {
+			topics = append(topics, t)
+		}
+	}
+	sort.Strings(topics)
+
+	var out []Message
+	for _, t := range topics {
+		out = append(out, sortedMessages(b.latest[t])...)
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package bus
+
+import "testing"
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern, topic string
+		want           bool
+	}{
+		{"data.collected", "data.collected", true},
+		{"data.collected", "data.other", false},
+		{"data.*", "data.collected", true},
+		{"data.*", "data", false},
+		{"data.*", "data.collected.A1", false},
+		{"*.collected", "data.collected", true},
+		{"data.#", "data", true},
+		{"data.#", "data.collected.A1", true},
+		{"#", "data.collected", true},
+		{"#.A1", "data.collected.A1", true},
+		{"#.A1", "A1", true},
+		{"#.A1", "data.collected.B2", false},
+		{"data.#.A1", "data.A1", true},
+		{"data.#.A1", "data.collected.x.A1", true},
+		{"data.#.A1", "data.collected.A1.x", false},
+		{"data.*.#", "data", false},
+		{"data.*.#", "data.collected", true},
+	}
+	for _, tt := range tests {
+		if got := MatchTopic(tt.pattern, tt.topic); got != tt.want {
+			t.Errorf("MatchTopic(%q, %q) = %v, want %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+/*
+ * 구독자 우선순위
+ *  - 중요한 구독자(최신값 저장소, 저널 등)가 최선 노력(best-effort) 구독자(웹훅, 분석 등)보다 항상 먼저 이벤트를 받도록 합니다.
+ *  - 발행 시 구독자를 우선순위가 높은 순으로 정렬하고, 같은 우선순위 안에서는 구독 등록 순서를 따릅니다.
+ *      - sync    : 높은 우선순위 구독자의 처리가 끝난 뒤 다음 구독자를 호출 (순서가 결정적)
+ *      - ordered : 높은 우선순위 구독자의 큐에 먼저 넣음
+ *      - async   : 높은 우선순위 구독자의 고루틴을 먼저 시작 (처리 완료 순서는 보장하지 않음)
+ *  - 구독 시 WithPriority로 지정하며, 지정하지 않으면 PriorityNormal
+ */
+package bus
+
+import "sort"
+
+// Priority : 구독자 우선순위 (클수록 먼저 전달)
+type Priority int
+
+const (
+	PriorityLow      Priority = -10 // 최선 노력 (웹훅, 분석 등)
+	PriorityNormal   Priority = 0   // 기본값
+	PriorityHigh     Priority = 10  // 경보 평가 등
+	PriorityCritical Priority = 20  // 다른 구독자보다 먼저 반영되어야 하는 상태 (최신값 저장소, 저널)
+)
+
+/*
+ * WithPriority : 이 구독의 우선순위 지정 (기본 PriorityNormal)
+ */
+func WithPriority(p Priority) SubscribeOption {
+	return func(c *subscribeConfig) { c.priority = p }
+}
+
+// byPriority : 우선순위 내림차순, 같으면 등록 순서 (타입/토픽/와일드카드 구독이 섞여도 결정적)
+func byPriority(subs []*subscriber) {
+	sort.SliceStable(subs, func(i, j int) bool {
+		if subs[i].priority != subs[j].priority {
+			return subs[i].priority > subs[j].priority
+		}
+		return subs[i].seq < subs[j].seq
+	})
+}
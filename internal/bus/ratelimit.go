@@ -0,0 +1,79 @@
+/*
+ * 구독자별 전달 속도 제한과 병합(coalescing)
+ *  - WithRateLimit(perSecond, burst) : 구독자에게 초당 perSecond개까지만 전달 (토큰 버킷, 순간적으로는 burst개까지)
+ *      → 느린 외부 싱크(웹훅 등)가 수많은 장치의 동시 이벤트에 몰리지 않게 함
+ *      → 제한에 걸린 메시지는 그 구독자의 ordered 큐에서 기다리며, 큐가 가득 차면 백프레셔 정책을 따름
+ *  - WithCoalesce() : 큐에서 기다리는 같은 토픽·같은 키(장치 ID, Keyed)의 메시지를 새 메시지로 교체
+ *      → 밀린 동안 장치마다 최신 상태 하나만 남아, 늦게라도 최신 값을 전달 (교체된 메시지는 드롭이 아니라 병합으로 집계)
+ *      → 큐가 가득 찼을 때만 적용되는 백프레셔 정책 coalesce와 달리 항상 적용
+ *  - 둘 다 ordered 전달에만 적용되며, 전달 방식을 따로 지정하지 않았으면 ordered로 전달합니다.
+ *  - 병합된 메시지 수는 scaffold_bus_coalesced_total{subscriber}로 노출합니다.
+ */
+package bus
+
+import (
+	"context"
+	"time"
+)
+
+/*
+ * WithRateLimit : 이 구독자에게 전달하는 속도를 초당 perSecond개로 제한
+ *  - burst : 한동안 쉬었을 때 연달아 전달할 수 있는 최대 개수 (1 미만이면 1)
+ *  - perSecond가 0 이하면 제한 없음
+ */
+func WithRateLimit(perSecond float64, burst int) SubscribeOption {
+	return func(c *subscribeConfig) { c.rate, c.burst = perSecond, burst }
+}
+
+/*
+ * WithCoalesce : 큐에서 기다리는 같은 장치의 메시지를 최신 메시지로 교체
+ */
+func WithCoalesce() SubscribeOption {
+	return func(c *subscribeConfig) { c.coalesce = true }
+}
+
+/*
+ * rateLimiter : 토큰 버킷 (구독자 전용 고루틴 하나에서만 사용하므로 잠금 없음)
+ *  - tokens가 음수면 그만큼 미리 빌려 쓴 것 (채워질 때까지 대기)
+ */
+type rateLimiter struct {
+	rate   float64 // 초당 채워지는 토큰 수
+	burst  float64 // 최대 토큰 수
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+/*
+ * wait : 토큰 하나를 쓰고, 모자라면 채워질 때까지 대기
+ *  - ctx가 먼저 끝나면 바로 반환 (종료 중에는 남은 메시지를 제한 없이 처리하도록)
+ */
+func (l *rateLimiter) wait(ctx context.Context) {
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return
+	}
+
+	t := time.NewTimer(time.Duration(-l.tokens / l.rate * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// throttle : 속도 제한이 있으면 다음 메시지를 꺼내기 전에 대기 (꺼내기 전에 기다려야 병합된 최신 메시지를 전달)
+func (s *subscriber) throttle() {
+	if s.limiter != nil {
+		s.limiter.wait(s.life)
+	}
+}
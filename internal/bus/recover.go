@@ -0,0 +1,37 @@
+/*
+ * 구독자 panic 복구와 실패 집계
+ *  - 구독자 호출은 모두 invoke를 거치며, panic이 나면 recover로 잡아 스택과 함께 Error 로그를 남기고
+ *    에러로 바꿔 반환합니다. → 구독자 하나의 버그가 프로세스 전체를 죽이지 않고, 에러와 같은 재시도/DLQ 경로를 탐
+ *  - 구독자가 에러를 반환하거나 panic이 날 때마다 scaffold_bus_subscriber_failures_total{subscriber, kind}를 올립니다.
+ *      kind : error | panic (metrics.go)
+ */
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+/*
+ * invoke : 구독자 함수를 한 번 호출 (panic은 에러로 변환)
+ */
+func (s *subscriber) invoke(ctx context.Context, m Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("bus subscriber panicked",
+				zap.String("subscriber", s.name),
+				zap.String("topic", m.Topic),
+				zap.Any("panic", r),
+				zap.Stack("stack"))
+			s.metrics.failures.WithLabelValues(s.name, "panic").Inc()
+			err = fmt.Errorf("subscriber panicked: %v", r)
+		}
+	}()
+
+	if err = s.fn(ctx, m); err != nil {
+		s.metrics.failures.WithLabelValues(s.name, "error").Inc()
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+/*
+ * 요청-응답(request-reply) : 버스로 질의를 보내고 응답을 기다립니다.
+ *  - Request로 토픽에 요청을 발행하면, Respond로 등록한 응답자가 처리 결과를 돌려줍니다.
+ *  - 요청마다 상관 ID(correlation ID)와 응답 채널이 붙으며, 먼저 도착한 응답 하나만 사용합니다.
+ *  - 요청자와 응답자는 토픽 이름과 payload 타입만 공유하므로 서로의 패키지를 직접 참조하지 않아도 됩니다.
+ *    (예: HTTP 핸들러가 제어 명령 접수기에 명령 결과를 묻기, control.TopicCommandWait)
+ *  - 요청은 일반 토픽 메시지처럼 인터셉터(추적 등)를 거치며, 같은 토픽의 일반 구독자도 관찰할 수 있습니다.
+ *    단, 따라잡기/retained 보관 대상은 아님 (지난 요청에 다시 응답하지 않도록)
+ *  - 버스 안에서만 동작하며, 브리지(NATS 등)로 내보낸 요청에는 응답이 돌아오지 않습니다.
+ */
+package bus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"reflect"
+)
+
+// ErrNoResponder : 요청 토픽에 등록된 응답자가 없음
+var ErrNoResponder = errors.New("bus: no responder for topic")
+
+/*
+ * request : 요청 메시지에 붙는 응답 정보
+ *  - id    : 상관 ID (로그/추적용)
+ *  - ctx   : 요청자의 ctx (요청자가 포기하면 응답자 처리도 취소)
+ *  - reply : 응답 채널 (크기 1, 먼저 보낸 응답만 들어감)
+ */
+type request struct {
+	id    string
+	ctx   context.Context
+	reply chan reply
+}
+
+// reply : 응답자 처리 결과
+type reply struct {
+	value any
+	err   error
+}
+
+// CorrelationID : 요청 메시지의 상관 ID (요청이 아니면 빈 문자열)
+func (m Message) CorrelationID() string {
+	if m.req == nil {
+		return ""
+	}
+	return m.req.id
+}
+
+/*
+ * Request : topic으로 payload를 보내고 응답을 기다림
+ *  - 응답자가 반환한 값과 에러를 그대로 반환 (값과 에러가 함께 올 수도 있음)
+ *  - 응답자가 없으면 즉시 ErrNoResponder, 버스가 종료 중이면 ErrClosed
+ *  - ctx가 먼저 끝나면 ctx.Err() (응답자 처리도 취소됨)
+ */
+func (b *EventBus) Request(ctx context.Context, topic string, payload any) (any, error) {
+	if b.closing.Load() {
+		return nil, ErrClosed
+	}
+	if !b.hasResponder(topic) {
+		return nil, ErrNoResponder
+	}
+
+	req := &request{id: newCorrelationID(), ctx: ctx, reply: make(chan reply, 1)}
+	b.publish(ctx, Message{Topic: topic, Payload: payload, req: req}, reflect.TypeOf(payload))
+
+	select {
+	case r := <-req.reply:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+/*
+ * Respond : topic(와일드카드 가능)의 요청에 응답하는 구독 등록
+ *  - fn의 반환값이 요청자에게 전달됨 (fn의 에러는 재시도/DLQ 대상이 아니라 요청자에게 그대로 전달)
+ *  - 요청이 아닌 일반 메시지와 retained 메시지는 무시
+ *  - fn의 ctx는 요청자가 포기하면(ctx 취소/마감) 함께 취소됨
+ *  - 응답에 오래 걸리는 응답자(대기형 질의)는 WithDelivery(DeliveryAsync)로 요청끼리 서로 막지 않게 함
+ */
+func (b *EventBus) Respond(topic string, fn func(ctx context.Context, m Message) (any, error), opts ...SubscribeOption) *Subscription {
+	opts = append(opts, WithoutRetained(), func(c *subscribeConfig) { c.responder = true })
+	return b.SubscribeTopicErr(topic, func(ctx context.Context, m Message) error {
+		if m.req == nil {
+			return nil
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(m.req.ctx, cancel)
+		defer stop()
+
+		v, err := fn(ctx, m)
+		select {
+		case m.req.reply <- reply{value: v, err: err}:
+		default: // 다른 응답자가 먼저 응답함
+		}
+		return nil
+	}, opts...)
+}
+
+// hasResponder : topic의 요청을 받을 응답자가 있는지
+func (b *EventBus) hasResponder(topic string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.topics[topic] {
+		if sub.responder && !sub.closed.Load() {
+			return true
+		}
+	}
+	for _, sub := range b.patterns {
+		if sub.responder && !sub.closed.Load() && MatchTopic(sub.topic, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCorrelationID : 16진수 16자리 임의 상관 ID
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,60 @@
+/*
+ * retained 토픽 : MQTT의 retained 메시지처럼, 늦게 합류한 구독자가 구독 즉시 마지막 이벤트를 받는 토픽
+ *  - APP_BUS_RETAINED(쉼표 구분) 또는 EventBus.Retain으로 지정하며, 와일드카드(* / #)도 쓸 수 있음 (예: data.collected, device.*.status)
+ *  - retained 토픽에 맞는 구독은 WithCatchUp 없이도 그 토픽의 키(장치 ID)별 마지막 메시지를 먼저 받음
+ *    (키가 없는 이벤트는 토픽당 마지막 하나)
+ *  - WebSocket 클라이언트나 재시작된 모듈이 다음 발행까지 기다리지 않고 현재 상태를 바로 알 수 있음
+ *  - 과거 이벤트를 받으면 안 되는 구독자(저장소 기록 등 중복이 문제가 되는 경우)는 WithoutRetained로 제외
+ */
+package bus
+
+import (
+	"sort"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+/*
+ * WithoutRetained : retained 토픽이라도 구독 시 마지막 메시지를 받지 않음
+ */
+func WithoutRetained() SubscribeOption {
+	return func(c *subscribeConfig) { c.noRetained = true }
+}
+
+/*
+ * Retain : 토픽(와일드카드 가능)을 retained로 표시
+ *  - 표시하기 전에 발행된 메시지도 키가 있는 이벤트(Keyed)라면 이미 보관되어 있으므로 바로 적용됨
+ */
+func (b *EventBus) Retain(topics ...string) {
+	b.mu.Lock()
+	b.retained = append(b.retained, topics...)
+	b.mu.Unlock()
+	b.log.Info("bus topics retained", zap.Strings("topics", topics))
+}
+
+// isRetained : 토픽이 retained로 표시되었는지 (호출자가 잠금을 잡고 있어야 함)
+func (b *EventBus) isRetained(topic string) bool {
+	for _, p := range b.retained {
+		if MatchTopic(p, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// retainedLatest : match에 맞는 retained 토픽의 키별 마지막 메시지 (토픽 순, 호출자가 잠금을 잡고 있어야 함)
+func (b *EventBus) retainedLatest(match func(topic string) bool) []Message {
+	var topics []string
+	for t := range b.latest {
+		if b.isRetained(t) && match(t) {
+			topics = append(topics, t)
+		}
+	}
+	sort.Strings(topics)
+
+	var out []Message
+	for _, t := range topics {
+		out = append(out, sortedMessages(b.latest[t])...)
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+/*
+ * Subscription : 구독 핸들
+ *  - 모든 Subscribe 계열 함수가 반환하며, WebSocket 연결이나 일시적인 리스너처럼 수명이 짧은 소비자가
+ *    더 이상 필요 없을 때 Unsubscribe로 버스에서 떨어져 나갈 수 있게 합니다. (해지하지 않으면 구독자가 계속 쌓임)
+ *  - 발행 중에도 구독/해지가 안전합니다.
+ *      발행은 잠금 구간에서 구독자 목록의 사본을 만든 뒤 잠금 밖에서 전달하고,
+ *      해지된 구독자는 사본에 남아 있더라도 전달을 건너뜁니다.
+ *  - 앱 수명 동안 유지되는 구독은 반환값을 무시해도 됩니다.
+ */
+package bus
+
+import "sync"
+
+// Subscription : 구독 하나에 대한 핸들
+type Subscription struct {
+	bus  *EventBus
+	sub  *subscriber
+	once sync.Once
+	done chan struct{}
+}
+
+func newSubscription(b *EventBus, s *subscriber) *Subscription {
+	return &Subscription{bus: b, sub: s, done: make(chan struct{})}
+}
+
+/*
+ * Unsubscribe : 구독 해지 (여러 번 호출해도 안전, 구독자 함수 안에서 호출해도 됨)
+ *  - 이후 발행되는 메시지는 전달되지 않음
+ *  - ordered 구독의 큐에 남아 있던 메시지는 버리고 전용 고루틴을 종료
+ *  - 이미 실행 중인 구독자 호출은 끝까지 실행됨 (기다리지 않음)
+ */
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.sub.closed.Store(true)
+		s.bus.remove(s.sub)
+		if s.sub.queue != nil {
+			s.sub.queue.close() // 해지하면서 버린 메시지는 드롭으로 기록하지 않음
+		}
+		close(s.done)
+	})
+}
+
+/*
+ * Done : 구독이 해지되면 닫히는 채널
+ */
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// remove : 버스의 구독자 목록에서 제거 (발행 중인 사본에 영향이 없도록 새 슬라이스로 교체)
+func (b *EventBus) remove(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s.typ != nil {
+		if subs := without(b.subscribers[s.typ], s); len(subs) > 0 {
+			b.subscribers[s.typ] = subs
+		} else {
+			delete(b.subscribers, s.typ)
+		}
+		return
+	}
+	if isPattern(s.topic) {
+		b.patterns = without(b.patterns, s)
+		return
+	}
+	if subs := without(b.topics[s.topic], s); len(subs) > 0 {
+		b.topics[s.topic] = subs
+	} else {
+		delete(b.topics, s.topic)
+	}
+}
+
+// without : s를 뺀 새 슬라이스
+func without(subs []*subscriber, s *subscriber) []*subscriber {
+	out := make([]*subscriber, 0, len(subs))
+	for _, sub := range subs {
+		if sub != s {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
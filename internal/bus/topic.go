@@ -0,0 +1,116 @@
+/*
+ * 토픽 기반 발행/구독
+ *  - 모든 이벤트는 토픽 이름과 함께 발행됩니다. (점으로 구분, 예: data.collected, control.issued, device.A1.status)
+ *  - SubscribeTopic으로 구독하면 그 토픽의 메시지만 받으므로, 관심 없는 스트림 때문에 고루틴을 쓰지 않습니다.
+ *  - 이벤트의 토픽 결정 (TopicOf) :
+ *      ① 이벤트가 Topic() string을 구현하면 그 값 (장치별로 다른 토픽도 가능)
+ *      ② 아니면 Go 타입 이름 (예: "control.SomeEvent")
+ *  - PublishTopic으로 토픽을 직접 지정해 발행할 수도 있습니다. (이 경우에도 payload 타입의 타입 구독자는 받음)
+ */
+package bus
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// 기본 제공 토픽
+const (
+	TopicDataCollected = "data.collected" // 장치 데이터 수집 (DataCollectedEvent)
+)
+
+/*
+ * Message : 토픽 구독자에게 전달되는 메시지
+ *  - Topic   : 발행된 토픽
+ *  - Payload : 이벤트 값 (발행한 타입 그대로, 구독자가 타입 단언으로 꺼냄)
+ */
+type Message struct {
+	Topic   string
+	Payload any
+
+	at  time.Time       // 발행 시각 (전달 지연 메트릭용, 따라잡기/재전송 메시지는 비어 있음)
+	ctx context.Context // 발행자의 ctx (ordered 큐에서 꺼낼 때 사용)
+	req *request        // 요청-응답의 응답 정보 (Request로 발행한 메시지만, request.go)
+}
+
+// Topicer : 자신의 토픽을 정하는 이벤트가 구현
+type Topicer interface {
+	Topic() string
+}
+
+// TopicOf : 이벤트의 토픽 (Topicer 구현 값, 아니면 타입 이름)
+func TopicOf(e any) string {
+	if t, ok := e.(Topicer); ok {
+		return t.Topic()
+	}
+	return reflect.TypeOf(e).String()
+}
+
+/*
+ * SubscribeTopic : 토픽 이름으로 구독
+ *  - 해당 토픽으로 발행된 메시지만 전달 (payload 타입과 무관)
+ *  - 토픽에 와일드카드(* : 세그먼트 하나, # : 0개 이상)가 있으면 맞는 모든 토픽을 구독 (pattern.go)
+ *  - 따라잡기 옵션(WithCatchUp)이 있거나 retained 토픽이면 그 토픽의 키별 마지막 메시지를 먼저 전달 (retain.go)
+ */
+func (b *EventBus) SubscribeTopic(topic string, fn func(ctx context.Context, m Message), opts ...SubscribeOption) *Subscription {
+	return b.SubscribeTopicErr(topic, func(ctx context.Context, m Message) error {
+		fn(ctx, m)
+		return nil
+	}, opts...)
+}
+
+/*
+ * SubscribeTopicErr : 에러를 반환하는 토픽 구독자 등록
+ *  - fn이 에러를 반환하면 재시도 정책(WithRetry)대로 다시 호출하고, 끝내 실패하면 메시지를 DLQ로 옮김 (deadletter.go)
+ *  - 일시적인 외부 장애(저장소 쓰기 실패 등)로 데이터를 잃지 않아야 하는 구독자용
+ */
+func (b *EventBus) SubscribeTopicErr(topic string, fn func(ctx context.Context, m Message) error, opts ...SubscribeOption) *Subscription {
+	var cfg subscribeConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	pattern := isPattern(topic)
+
+	b.mu.Lock()
+	var backlog []Message
+	if cfg.catchUp {
+		if pattern {
+			backlog = b.matchingLatest(topic)
+		} else {
+			backlog = sortedMessages(b.latest[topic])
+		}
+	} else if !cfg.noRetained {
+		backlog = b.retainedLatest(func(t string) bool { return t == topic || pattern && MatchTopic(topic, t) })
+	}
+	sub := b.newSubscriber(cfg, "topic:"+topic, fn)
+	sub.topic = topic
+	if len(backlog) > 0 {
+		sub.holdLive()
+	}
+	if pattern {
+		b.patterns = append(b.patterns, sub)
+	} else {
+		b.topics[topic] = append(b.topics[topic], sub)
+	}
+	b.mu.Unlock()
+
+	for _, m := range backlog {
+		if sub.accepts(m) {
+			sub.handle(b.life, m)
+		}
+	}
+	if len(backlog) > 0 {
+		sub.releaseHeld()
+	}
+	return newSubscription(b, sub)
+}
+
+/*
+ * PublishTopic : 토픽을 직접 지정하여 발행
+ *  - 토픽 구독자와, payload의 실제 타입으로 구독한 타입 구독자에게 전달
+ */
+func (b *EventBus) PublishTopic(ctx context.Context, topic string, payload any) {
+	b.publish(ctx, Message{Topic: topic, Payload: payload}, reflect.TypeOf(payload))
+}
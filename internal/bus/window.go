@@ -0,0 +1,157 @@
+/*
+ * 시간 창(window) 집계 도우미 : 이벤트를 장치(키)별 시간 창으로 모아 창이 닫힐 때마다 한 번에 넘깁니다.
+ *  - 집계/경보 모듈이 원시 이벤트 위에 각자 창 관리(버퍼, 타이머, 만료)를 다시 구현하지 않도록 합니다.
+ *  - 창 종류 :
+ *      tumbling : Size 길이의 겹치지 않는 창 (예: 1분마다 지난 1분)
+ *      sliding  : Size 길이의 창을 Slide 간격으로 (예: 10초마다 지난 1분, 이벤트가 여러 창에 포함됨)
+ *  - 창 경계는 벽시계 기준으로 Slide(tumbling이면 Size)의 배수에 맞춥니다. (예: 1분 창은 매분 0초에 닫힘)
+ *  - 이벤트 시각은 버스에서 받은 시각이며, 창 안에 이벤트가 없는 장치는 콜백을 호출하지 않습니다.
+ *  - 콜백은 창이 닫힐 때 도우미 전용 고루틴에서 (장치 ID 순으로) 호출되며, 구독을 해지하거나 버스가 종료되면 멈춥니다.
+ *    (닫히지 않은 창은 넘기지 않고 버림)
+ */
+package bus
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+/*
+ * WindowSpec : 시간 창 설정
+ *  - Size  : 창 길이 (양수)
+ *  - Slide : 창이 닫히는 간격 (0이면 Size와 같음 = tumbling, Size보다 작으면 sliding)
+ */
+type WindowSpec struct {
+	Size  time.Duration
+	Slide time.Duration
+}
+
+// Tumbling : 겹치지 않는 size 길이의 창
+func Tumbling(size time.Duration) WindowSpec {
+	return WindowSpec{Size: size, Slide: size}
+}
+
+// Sliding : slide 간격으로 닫히는 size 길이의 창
+func Sliding(size, slide time.Duration) WindowSpec {
+	return WindowSpec{Size: size, Slide: slide}
+}
+
+/*
+ * Window : 닫힌 창 하나의 내용
+ *  - Key    : 장치 ID (이벤트의 EventKey)
+ *  - Start/End : 창 구간 [Start, End)
+ *  - Events : 구간 안에 받은 이벤트 (받은 순서)
+ */
+type Window[T any] struct {
+	Key    string
+	Start  time.Time
+	End    time.Time
+	Events []T
+}
+
+// windowed : 받은 시각과 함께 보관한 이벤트
+type windowed[T any] struct {
+	at time.Time
+	e  T
+}
+
+/*
+ * SubscribeWindow : 이벤트 타입 T를 장치별 시간 창으로 모아 창이 닫힐 때마다 fn 호출
+ *  - 이벤트 수신은 기본 sync 전달 (버퍼에 넣기만 하므로 발행자를 늦추지 않음, opts로 바꿀 수 있음)
+ *  - 필터 등 나머지 구독 옵션은 그대로 적용 (예: Where로 관심 장치만 모음)
+ *  - Size가 0 이하이거나 Slide가 0 이하/Size보다 크면 panic (설정 오류는 시작 시 드러나도록)
+ */
+func SubscribeWindow[T Keyed](b *EventBus, spec WindowSpec, fn func(ctx context.Context, w Window[T]), opts ...SubscribeOption) *Subscription {
+	if spec.Slide == 0 {
+		spec.Slide = spec.Size
+	}
+	if spec.Size <= 0 || spec.Slide <= 0 || spec.Slide > spec.Size {
+		panic("bus: invalid window spec, need 0 < slide <= size")
+	}
+
+	var (
+		mu      sync.Mutex
+		buffers = make(map[string][]windowed[T])
+	)
+	opts = append([]SubscribeOption{WithDelivery(DeliverySync), WithoutRetained()}, opts...)
+	sub := Subscribe(b, func(_ context.Context, e T) {
+		mu.Lock()
+		key := e.EventKey()
+		buffers[key] = append(buffers[key], windowed[T]{at: time.Now(), e: e})
+		mu.Unlock()
+	}, opts...)
+
+	go func() {
+		end := time.Now().Truncate(spec.Slide).Add(spec.Slide)
+		for {
+			t := time.NewTimer(time.Until(end))
+			select {
+			case <-t.C:
+			case <-sub.Done():
+				t.Stop()
+				return
+			case <-b.life.Done():
+				t.Stop()
+				return
+			}
+
+			mu.Lock()
+			closed := closeWindows(buffers, end.Add(-spec.Size), end, end.Add(spec.Slide-spec.Size))
+			mu.Unlock()
+			for _, w := range closed {
+				b.callWindow(func() { fn(b.life, w) })
+			}
+			end = end.Add(spec.Slide)
+		}
+	}()
+	return sub
+}
+
+/*
+ * closeWindows : [start, end) 구간의 창을 장치 ID 순으로 만들고, 다음 창(keepFrom 이후)에 필요 없는 이벤트는 버림
+ *  - 호출자가 잠금을 잡고 있어야 함
+ */
+func closeWindows[T Keyed](buffers map[string][]windowed[T], start, end, keepFrom time.Time) []Window[T] {
+	keys := make([]string, 0, len(buffers))
+	for k := range buffers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []Window[T]
+	for _, k := range keys {
+		w := Window[T]{Key: k, Start: start, End: end}
+		var keep []windowed[T]
+		for _, it := range buffers[k] {
+			if !it.at.Before(start) && it.at.Before(end) {
+				w.Events = append(w.Events, it.e)
+			}
+			if !it.at.Before(keepFrom) {
+				keep = append(keep, it)
+			}
+		}
+		if len(w.Events) > 0 {
+			out = append(out, w)
+		}
+		if len(keep) > 0 {
+			buffers[k] = keep
+		} else {
+			delete(buffers, k)
+		}
+	}
+	return out
+}
+
+// callWindow : 창 콜백 호출 (panic은 로그로 남기고 다음 창을 계속 처리)
+func (b *EventBus) callWindow(call func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("bus window callback panicked", zap.Any("panic", r), zap.Stack("stack"))
+		}
+	}()
+	call()
+}
@@ -0,0 +1,129 @@
+/*
+ * Codec : 이벤트 직렬화 방식 추상화
+ *  - 저널(영속 큐)이나 외부 브리지로 내보내는 이벤트를 바이트로 변환합니다.
+ *  - 모든 이벤트는 Envelope(타입 이름, 스키마 버전, payload)로 감싸 저장하므로,
+ *    이벤트 구조체가 바뀌어도 예전에 저장된 데이터를 마이그레이션하여 읽을 수 있습니다.
+ *  - 구현 : protobuf(기본, events.proto 스키마), json(사람이 읽기 쉬운 디버깅용)
+ */
+package codec
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 직렬화 대상 이벤트
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+const (
+	// TypeDataCollected : bus.DataCollectedEvent의 타입 이름
+	TypeDataCollected = "data.collected"
+
+	// DataCollectedVersion : 현재 코드가 쓰는 DataCollected 스키마 버전
+	DataCollectedVersion = 1
+)
+
+/*
+ * Envelope : 직렬화된 이벤트의 공통 외피 (events.proto의 EventEnvelope)
+ */
+type Envelope struct {
+	Type          string    `json:"type"`
+	SchemaVersion uint32    `json:"schema_version"`
+	Payload       []byte    `json:"payload"`
+	Time          time.Time `json:"time"`
+}
+
+/*
+ * Codec 인터페이스
+ *  - Name            : 설정에서 쓰는 이름 ("protobuf" | "json")
+ *  - MarshalEnvelope : 외피 직렬화 / UnmarshalEnvelope : 외피 역직렬화
+ *  - MarshalData     : DataCollectedEvent → payload (현재 스키마 버전)
+ *  - UnmarshalData   : payload(현재 스키마 버전) → DataCollectedEvent
+ */
+type Codec interface {
+	Name() string
+	MarshalEnvelope(env Envelope) ([]byte, error)
+	UnmarshalEnvelope(b []byte) (Envelope, error)
+	MarshalData(e bus.DataCollectedEvent) ([]byte, error)
+	UnmarshalData(b []byte) (bus.DataCollectedEvent, error)
+}
+
+/*
+ * NewCodec : fx가 호출하는 Codec 생성자
+ *  - APP_EVENT_CODEC : protobuf(기본) | json
+ */
+func NewCodec(log *zap.Logger) Codec {
+	c, err := ByName(config.String("APP_EVENT_CODEC", "protobuf"))
+	if err != nil {
+		log.Fatal("invalid event codec", zap.Error(err))
+	}
+	return c
+}
+
+// ByName : 이름으로 Codec 선택
+func ByName(name string) (Codec, error) {
+	switch name {
+	case "protobuf":
+		return Protobuf{}, nil
+	case "json":
+		return JSON{}, nil
+	}
+	return nil, fmt.Errorf("unknown codec %q", name)
+}
+
+/*
+ * EncodeData : DataCollectedEvent를 외피에 담아 직렬화
+ */
+func EncodeData(c Codec, e bus.DataCollectedEvent) ([]byte, error) {
+	payload, err := c.MarshalData(e)
+	if err != nil {
+		return nil, err
+	}
+	return c.MarshalEnvelope(Envelope{
+		Type:          TypeDataCollected,
+		SchemaVersion: DataCollectedVersion,
+		Payload:       payload,
+		Time:          time.Now(),
+	})
+}
+
+/*
+ * DecodeData : 직렬화된 외피를 풀고, 필요하면 현재 스키마 버전까지 마이그레이션한 뒤 이벤트로 복원
+ *  - 측정 시각이 없는 이벤트(측정 시각을 싣기 전에 저장된 저널 항목 등)는 외피의 직렬화 시각을 씀
+ */
+func DecodeData(c Codec, b []byte) (bus.DataCollectedEvent, error) {
+	env, err := c.UnmarshalEnvelope(b)
+	if err != nil {
+		return bus.DataCollectedEvent{}, err
+	}
+	if env.Type != TypeDataCollected {
+		return bus.DataCollectedEvent{}, fmt.Errorf("unexpected event type %q", env.Type)
+	}
+	payload, err := Migrate(c, env.Type, env.SchemaVersion, DataCollectedVersion, env.Payload)
+	if err != nil {
+		return bus.DataCollectedEvent{}, err
+	}
+	e, err := c.UnmarshalData(payload)
+	if err == nil && e.Time.IsZero() {
+		e.Time = env.Time
+	}
+	return e, err
+}
+
+// unixNano : 시각 → 유닉스 나노초 (비어 있으면 0)
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano : 유닉스 나노초 → 시각 (0이면 빈 시각)
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
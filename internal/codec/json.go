@@ -0,0 +1,45 @@
+/*
+ * JSON : 사람이 읽을 수 있는 JSON 직렬화
+ *  - 저널 파일을 직접 열어 보며 디버깅할 때 유용하지만 protobuf보다 크고 느립니다.
+ */
+package codec
+
+import (
+	"encoding/json"
+
+	"generic-api-scaffold/internal/bus" // 직렬화 대상 이벤트
+)
+
+// JSON Codec 구현
+type JSON struct{}
+
+// dataJSON : DataCollected v1의 JSON 표현 (필드 이름은 events.proto와 동일)
+type dataJSON struct {
+	DeviceID string             `json:"device_id"`
+	Values   map[string]float64 `json:"values"`
+	Time     int64              `json:"time_unix_nano,omitempty"` // 측정 시각 (0이면 없음)
+}
+
+func (JSON) Name() string { return "json" }
+
+func (JSON) MarshalEnvelope(env Envelope) ([]byte, error) {
+	return json.Marshal(env)
+}
+
+func (JSON) UnmarshalEnvelope(b []byte) (Envelope, error) {
+	var env Envelope
+	err := json.Unmarshal(b, &env)
+	return env, err
+}
+
+func (JSON) MarshalData(e bus.DataCollectedEvent) ([]byte, error) {
+	return json.Marshal(dataJSON{DeviceID: e.DeviceID, Values: e.Values, Time: unixNano(e.Time)})
+}
+
+func (JSON) UnmarshalData(b []byte) (bus.DataCollectedEvent, error) {
+	var d dataJSON
+	if err := json.Unmarshal(b, &d); err != nil {
+		return bus.DataCollectedEvent{}, err
+	}
+	return bus.DataCollectedEvent{DeviceID: d.DeviceID, Values: d.Values, Time: fromUnixNano(d.Time)}, nil
+}
@@ -0,0 +1,51 @@
+/*
+ * 스키마 마이그레이션 : 예전 버전으로 저장된 payload를 현재 버전으로 끌어올립니다.
+ *  - (타입, 시작 버전)마다 "한 단계 위 버전으로 변환" 함수를 등록하고, 현재 버전까지 연쇄 적용합니다.
+ *  - 스키마 버전을 올릴 때는 이전 버전 → 새 버전 변환 함수를 Migrations에 추가합니다.
+ *      예) Migrations[migrationKey{TypeDataCollected, 1}] = func(c Codec, p []byte) ([]byte, error) { ... }
+ *      다른 패키지에서 등록한 이벤트 타입(Registry.Register)은 RegisterMigration으로 추가합니다.
+ */
+package codec
+
+import "fmt"
+
+// migrationKey : (이벤트 타입, 변환 전 버전)
+type migrationKey struct {
+	Type    string
+	Version uint32
+}
+
+// MigrationFunc : payload를 (Version) → (Version+1) 스키마로 변환
+type MigrationFunc func(c Codec, payload []byte) ([]byte, error)
+
+// Migrations : 등록된 변환 함수 목록 (현재 DataCollected는 v1이 최초 버전이라 비어 있음)
+var Migrations = map[migrationKey]MigrationFunc{}
+
+/*
+ * RegisterMigration : 이벤트 타입 typ의 from 버전 → from+1 버전 변환 함수 등록 (시작 시 호출)
+ */
+func RegisterMigration(typ string, from uint32, fn MigrationFunc) {
+	Migrations[migrationKey{Type: typ, Version: from}] = fn
+}
+
+/*
+ * Migrate : payload를 from 버전에서 to 버전까지 단계적으로 변환
+ *  - 현재 코드보다 새로운 버전(from > to)은 읽을 수 없으므로 에러
+ *  - 중간 단계의 변환 함수가 없으면 에러
+ */
+func Migrate(c Codec, typ string, from, to uint32, payload []byte) ([]byte, error) {
+	if from > to {
+		return nil, fmt.Errorf("%s schema version %d is newer than supported version %d", typ, from, to)
+	}
+	for v := from; v < to; v++ {
+		fn, ok := Migrations[migrationKey{Type: typ, Version: v}]
+		if !ok {
+			return nil, fmt.Errorf("no migration for %s from schema version %d", typ, v)
+		}
+		var err error
+		if payload, err = fn(c, payload); err != nil {
+			return nil, fmt.Errorf("migrate %s v%d→v%d: %w", typ, v, v+1, err)
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,170 @@
+/*
+ * Protobuf : events.proto 스키마를 따르는 protobuf 바이너리 직렬화
+ *  - protoc 생성 코드 없이 protowire로 필드 번호/와이어 타입을 직접 다룹니다.
+ *    (events.proto가 계약이며, 이 파일의 필드 번호는 반드시 그와 일치해야 합니다)
+ *  - 디코딩 시 모르는 필드는 건너뛰므로, 새 버전이 추가한 필드가 있어도 옛 코드가 읽을 수 있습니다.
+ */
+package codec
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire" // protobuf 와이어 포맷 인코딩/디코딩
+
+	"generic-api-scaffold/internal/bus" // 직렬화 대상 이벤트
+)
+
+// Protobuf Codec 구현
+type Protobuf struct{}
+
+// events.proto 필드 번호
+const (
+	envType          protowire.Number = 1
+	envSchemaVersion protowire.Number = 2
+	envPayload       protowire.Number = 3
+	envTimeUnixNano  protowire.Number = 4
+
+	dataDeviceID protowire.Number = 1
+	dataValues   protowire.Number = 2
+	dataTime     protowire.Number = 3
+
+	mapKey   protowire.Number = 1
+	mapValue protowire.Number = 2
+)
+
+var errTruncated = errors.New("protobuf: truncated or malformed message")
+
+func (Protobuf) Name() string { return "protobuf" }
+
+func (Protobuf) MarshalEnvelope(env Envelope) ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, envType, protowire.BytesType)
+	b = protowire.AppendString(b, env.Type)
+	b = protowire.AppendTag(b, envSchemaVersion, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(env.SchemaVersion))
+	b = protowire.AppendTag(b, envPayload, protowire.BytesType)
+	b = protowire.AppendBytes(b, env.Payload)
+	b = protowire.AppendTag(b, envTimeUnixNano, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(env.Time.UnixNano()))
+	return b, nil
+}
+
+func (Protobuf) UnmarshalEnvelope(b []byte) (Envelope, error) {
+	var env Envelope
+	err := eachField(b, func(num protowire.Number, typ protowire.Type, v []byte) (int, error) {
+		switch {
+		case num == envType && typ == protowire.BytesType:
+			s, n := protowire.ConsumeString(v)
+			env.Type = s
+			return n, nil
+		case num == envSchemaVersion && typ == protowire.VarintType:
+			x, n := protowire.ConsumeVarint(v)
+			env.SchemaVersion = uint32(x)
+			return n, nil
+		case num == envPayload && typ == protowire.BytesType:
+			p, n := protowire.ConsumeBytes(v)
+			env.Payload = append([]byte(nil), p...)
+			return n, nil
+		case num == envTimeUnixNano && typ == protowire.VarintType:
+			x, n := protowire.ConsumeVarint(v)
+			env.Time = time.Unix(0, int64(x))
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, v), nil
+	})
+	return env, err
+}
+
+func (Protobuf) MarshalData(e bus.DataCollectedEvent) ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, dataDeviceID, protowire.BytesType)
+	b = protowire.AppendString(b, e.DeviceID)
+	for k, v := range e.Values {
+		// map<string,double> 항목 하나 = {1: key, 2: value} 메시지
+		var entry []byte
+		entry = protowire.AppendTag(entry, mapKey, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, mapValue, protowire.Fixed64Type)
+		entry = protowire.AppendFixed64(entry, math.Float64bits(v))
+
+		b = protowire.AppendTag(b, dataValues, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	if t := unixNano(e.Time); t != 0 {
+		b = protowire.AppendTag(b, dataTime, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(t))
+	}
+	return b, nil
+}
+
+func (Protobuf) UnmarshalData(b []byte) (bus.DataCollectedEvent, error) {
+	e := bus.DataCollectedEvent{Values: make(map[string]float64)}
+	err := eachField(b, func(num protowire.Number, typ protowire.Type, v []byte) (int, error) {
+		switch {
+		case num == dataDeviceID && typ == protowire.BytesType:
+			s, n := protowire.ConsumeString(v)
+			e.DeviceID = s
+			return n, nil
+		case num == dataValues && typ == protowire.BytesType:
+			entry, n := protowire.ConsumeBytes(v)
+			if n < 0 {
+				return n, nil
+			}
+			var key string
+			var val float64
+			err := eachField(entry, func(num protowire.Number, typ protowire.Type, v []byte) (int, error) {
+				switch {
+				case num == mapKey && typ == protowire.BytesType:
+					s, m := protowire.ConsumeString(v)
+					key = s
+					return m, nil
+				case num == mapValue && typ == protowire.Fixed64Type:
+					x, m := protowire.ConsumeFixed64(v)
+					val = math.Float64frombits(x)
+					return m, nil
+				}
+				return protowire.ConsumeFieldValue(num, typ, v), nil
+			})
+			if err != nil {
+				return 0, err
+			}
+			e.Values[key] = val
+			return n, nil
+		case num == dataTime && typ == protowire.VarintType:
+			x, n := protowire.ConsumeVarint(v)
+			e.Time = fromUnixNano(int64(x))
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, v), nil
+	})
+	return e, err
+}
+
+/*
+ * eachField : 메시지의 필드를 순회하며 fn 호출
+ *  - fn은 태그 뒤 값 부분을 소비하고 소비한 바이트 수를 반환 (음수면 파싱 실패)
+ */
+func eachField(b []byte, fn func(num protowire.Number, typ protowire.Type, v []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		m, err := fn(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		if m > len(b) {
+			return errTruncated
+		}
+		b = b[m:]
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+ * Registry : 이벤트 스키마 레지스트리
+ *  - 이벤트 Go 타입마다 (타입 이름, 현재 스키마 버전, payload 직렬화 방법)을 등록합니다.
+ *  - Encode : 등록된 이벤트 → Envelope(타입 이름, 현재 스키마 버전, payload) 바이트
+ *  - Decode : Envelope 바이트 → 타입 확인 → 예전 버전이면 Migrations로 현재 버전까지 변환 → 이벤트 복원 → 검증
+ *      → 저널에 저장된 이벤트나 다른 버전의 인스턴스가 브리지로 보낸 이벤트도, 구조체가 바뀐 뒤에 읽을 수 있음
+ *  - data.collected는 기본 등록(코덱별 스키마, events.proto)이며, 다른 이벤트는 Register로 등록 (payload는 JSON)
+ *  - 스키마 버전을 올릴 때는 Register의 버전을 올리고 이전 버전 → 새 버전 변환 함수를 Migrations에 추가합니다. (migrate.go)
+ */
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"generic-api-scaffold/internal/bus" // 기본 등록 이벤트
+)
+
+// ErrUnregistered : 레지스트리에 없는 이벤트 타입
+var ErrUnregistered = errors.New("event type is not registered")
+
+// Validator : 복원한 이벤트를 받기 전에 검사할 이벤트 타입이 구현 (에러면 Decode 실패)
+type Validator interface {
+	Validate() error
+}
+
+// schema : 이벤트 타입 하나의 등록 정보
+type schema struct {
+	name      string
+	version   uint32
+	goType    reflect.Type
+	marshal   func(c Codec, v any) ([]byte, error)
+	unmarshal func(c Codec, b []byte) (any, error)
+}
+
+/*
+ * SchemaInfo : 등록된 스키마 요약 (조회용)
+ */
+type SchemaInfo struct {
+	Type    string `json:"type"`
+	Version uint32 `json:"version"`
+	GoType  string `json:"go_type"`
+}
+
+/*
+ * Registry 구조체
+ *  - byName : 타입 이름 → 스키마 (Decode)
+ *  - byType : Go 타입 → 스키마 (Encode)
+ */
+type Registry struct {
+	codec Codec
+
+	mu     sync.RWMutex
+	byName map[string]*schema
+	byType map[reflect.Type]*schema
+}
+
+/*
+ * NewRegistry : fx가 호출하는 Registry 생성자
+ *  - data.collected(bus.DataCollectedEvent)를 코덱별 스키마로 기본 등록
+ */
+func NewRegistry(c Codec) *Registry {
+	r := &Registry{codec: c, byName: make(map[string]*schema), byType: make(map[reflect.Type]*schema)}
+	r.add(&schema{
+		name:    TypeDataCollected,
+		version: DataCollectedVersion,
+		goType:  reflect.TypeOf(bus.DataCollectedEvent{}),
+		marshal: func(c Codec, v any) ([]byte, error) {
+			return c.MarshalData(v.(bus.DataCollectedEvent))
+		},
+		unmarshal: func(c Codec, b []byte) (any, error) {
+			return c.UnmarshalData(b)
+		},
+	})
+	return r
+}
+
+/*
+ * Register : 이벤트 타입 T를 타입 이름 name, 현재 스키마 버전 version으로 등록 (payload는 JSON)
+ *  - 같은 이름이나 같은 Go 타입을 두 번 등록하면 panic (시작 시 배선 실수)
+ */
+func Register[T any](r *Registry, name string, version uint32) {
+	r.add(&schema{
+		name:    name,
+		version: version,
+		goType:  reflect.TypeOf((*T)(nil)).Elem(),
+		marshal: func(_ Codec, v any) ([]byte, error) {
+			return json.Marshal(v)
+		},
+		unmarshal: func(_ Codec, b []byte) (any, error) {
+			var v T
+			err := json.Unmarshal(b, &v)
+			return v, err
+		},
+	})
+}
+
+// add : 스키마 등록
+func (r *Registry) add(s *schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[s.name]; ok {
+		panic(fmt.Sprintf("codec: event type %q registered twice", s.name))
+	}
+	if _, ok := r.byType[s.goType]; ok {
+		panic(fmt.Sprintf("codec: Go type %s registered twice", s.goType))
+	}
+	r.byName[s.name] = s
+	r.byType[s.goType] = s
+}
+
+/*
+ * Encode : 등록된 이벤트를 현재 스키마 버전의 외피로 직렬화
+ *  - 등록되지 않은 타입이면 ErrUnregistered
+ */
+func (r *Registry) Encode(v any) ([]byte, error) {
+	r.mu.RLock()
+	s, ok := r.byType[reflect.TypeOf(v)]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnregistered, v)
+	}
+	payload, err := s.marshal(r.codec, v)
+	if err != nil {
+		return nil, err
+	}
+	return r.codec.MarshalEnvelope(Envelope{Type: s.name, SchemaVersion: s.version, Payload: payload, Time: time.Now()})
+}
+
+/*
+ * Decode : 외피를 풀고 현재 스키마 버전까지 마이그레이션한 뒤 이벤트로 복원
+ *  - 등록되지 않은 타입이면 ErrUnregistered, 현재보다 새 버전이거나 변환 함수가 없으면 에러
+ *  - 이벤트가 Validator를 구현하면 검사까지 통과해야 반환
+ */
+func (r *Registry) Decode(b []byte) (any, Envelope, error) {
+	env, err := r.codec.UnmarshalEnvelope(b)
+	if err != nil {
+		return nil, env, err
+	}
+	r.mu.RLock()
+	s, ok := r.byName[env.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, env, fmt.Errorf("%w: %q", ErrUnregistered, env.Type)
+	}
+	payload, err := Migrate(r.codec, env.Type, env.SchemaVersion, s.version, env.Payload)
+	if err != nil {
+		return nil, env, err
+	}
+	v, err := s.unmarshal(r.codec, payload)
+	if err != nil {
+		return nil, env, fmt.Errorf("decode %s v%d: %w", env.Type, s.version, err)
+	}
+	if val, ok := v.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return nil, env, fmt.Errorf("invalid %s: %w", env.Type, err)
+		}
+	}
+	return v, env, nil
+}
+
+/*
+ * Schemas : 등록된 스키마 목록 (타입 이름 순)
+ */
+func (r *Registry) Schemas() []SchemaInfo {
+	r.mu.RLock()
+	out := make([]SchemaInfo, 0, len(r.byName))
+	for _, s := range r.byName {
+		out = append(out, SchemaInfo{Type: s.name, Version: s.version, GoType: s.goType.String()})
+	}
+	r.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
@@ -0,0 +1,186 @@
+/*
+ * 환경변수 읽기 도우미 모음입니다.
+ *  - 각 생성자(NewXxx)에서 반복되던 "os.Getenv → 기본값 → 변환 → 실패 시 Fatal" 패턴을 한 곳으로 모읍니다.
+ *  - 변환에 실패하면 기존 코드와 동일하게 log.Fatal로 애플리케이션을 종료합니다.
+ */
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+/*
+ * String : 문자열 환경변수 조회
+ *  - 값이 비어 있으면 기본값(def)을 반환
+ */
+func String(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+/*
+ * Int : 정수 환경변수 조회
+ *  - 값이 비어 있으면 기본값(def)을 반환
+ *  - 정수로 변환할 수 없으면 Fatal
+ */
+func Int(log *zap.Logger, key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatal("invalid integer env value", zap.String("key", key), zap.Error(err))
+	}
+	return n
+}
+
+/*
+ * Duration : 시간 간격 환경변수 조회 (예: "5s", "250ms")
+ *  - 값이 비어 있으면 기본값(def)을 반환
+ *  - time.ParseDuration 실패 시 Fatal
+ */
+func Duration(log *zap.Logger, key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatal("invalid duration env value", zap.String("key", key), zap.Error(err))
+	}
+	return d
+}
+
+/*
+ * Bool : 참/거짓 환경변수 조회 (true/false, 1/0 등 strconv.ParseBool 규칙)
+ *  - 값이 비어 있으면 기본값(def)을 반환
+ */
+func Bool(log *zap.Logger, key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatal("invalid bool env value", zap.String("key", key), zap.Error(err))
+	}
+	return b
+}
+
+/*
+ * List : 콤마(,)로 구분된 목록 환경변수 조회
+ *  - 각 항목의 앞뒤 공백은 제거하고, 빈 항목은 건너뜀
+ *  - 값이 비어 있으면 기본값(def)을 반환
+ */
+func List(key string, def []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+/*
+ * Float : 실수 환경변수 조회
+ *  - 값이 비어 있으면 기본값(def)을 반환
+ *  - 실수로 변환할 수 없으면 Fatal
+ */
+func Float(log *zap.Logger, key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Fatal("invalid float env value", zap.String("key", key), zap.Error(err))
+	}
+	return f
+}
+
+/*
+ * Bytes : 바이트 크기 환경변수 조회 (예: "1048576", "512KB", "4MB", "1GB")
+ *  - 단위는 1024 배수이며 대소문자를 구분하지 않음
+ *  - 값이 비어 있으면 기본값(def)을 반환, 변환 실패 시 Fatal
+ */
+func Bytes(log *zap.Logger, key string, def int64) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := ParseBytes(v)
+	if err != nil {
+		log.Fatal("invalid byte size env value", zap.String("key", key), zap.Error(err))
+	}
+	return n
+}
+
+// ParseBytes : "512KB", "4MB" 같은 크기 문자열을 바이트 수로 변환
+func ParseBytes(v string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(v))
+	mult := int64(1)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * mult, nil
+}
+
+/*
+ * Overlay : prefix로 시작하는 환경변수를 "APP_" 키로 바꿔 덮어쓴 상태에서 fn 실행
+ *  - 같은 설정 키를 두 벌 쓰는 생성자용 (예: 보조 저장소, prefix "APP_MIRROR_" → APP_MIRROR_INFLUX_URL이 APP_INFLUX_URL로 보임)
+ *  - 덮어쓰지 않은 키는 원래 값을 그대로 씀, fn이 끝나면 원래 값으로 되돌림
+ *  - 환경변수는 프로세스 전체에 걸리므로 다른 고루틴이 설정을 읽지 않는 생성 단계에서만 사용
+ */
+func Overlay(prefix string, fn func()) {
+	type saved struct {
+		value string
+		ok    bool
+	}
+	restore := make(map[string]saved)
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		rest, ok := strings.CutPrefix(k, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		key := "APP_" + rest
+		if _, seen := restore[key]; !seen {
+			old, had := os.LookupEnv(key)
+			restore[key] = saved{old, had}
+		}
+		os.Setenv(key, v)
+	}
+	defer func() {
+		for k, s := range restore {
+			if s.ok {
+				os.Setenv(k, s.value)
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+	}()
+	fn()
+}
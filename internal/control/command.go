@@ -0,0 +1,356 @@
+/*
+ * Dispatcher : 제어 명령 접수기
+ *  - 제어 명령(Command)에 ID를 부여하고 메모리에 보관합니다.
+ *  - 접수 전에 Limiter로 장치별 속도 제한을 검사합니다.
+ *    장치에 운영자 오버라이드 세션이 진행 중이면 속도 제한을 건너뛰고, 명령에 세션 ID를 남깁니다.
+ *  - 실제 장치로의 전송은 나중에 연결될 수 있음 (현재는 "queued" 상태로 보관)
+ *  - 접수된 명령은 장치 텔레메트리가 명령을 반영하면 succeeded, 확인 시간을 지정했다면 그 안에 반영되지 않을 때 failed로 끝남 (confirm.go)
+ *    끝난 명령은 보관 기간(APP_CONTROL_RETENTION)이 지나면 지움
+ */
+package control
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"  // 확인 타이머 정리
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 명령 이벤트 발행, 텔레메트리 구독
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// Status : 명령 처리 상태
+type Status string
+
+const (
+	StatusQueued    Status = "queued"    // 접수됨
+	StatusSucceeded Status = "succeeded" // 처리 완료
+	StatusFailed    Status = "failed"    // 처리 실패
+)
+
+// Terminal : 더 이상 바뀌지 않는 종료 상태인지
+func (s Status) Terminal() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+// ErrNotFound : 존재하지 않는 명령/리소스 조회
+var ErrNotFound = errors.New("not found")
+
+// ErrCompleted : 이미 종료 상태인 명령을 다시 완료하려 함
+var ErrCompleted = errors.New("command already completed")
+
+/*
+ * Command : 장치 하나에 대한 제어 명령
+ *  - Action : charge|discharge|ready|on|off
+ *  - KW10   : kW*10 (예: 50 => 5.0kW)
+ */
+type Command struct {
+	ID        string    `json:"id"`
+	DeviceID  string    `json:"device"`
+	Action    string    `json:"action"`
+	KW10      int       `json:"kw10"`
+	Override  string    `json:"override,omitempty"` // 속도 제한을 우회한 오버라이드 세션 ID
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	timer *time.Timer // 확인 시간 초과 타이머 (queued 동안만)
+}
+
+/*
+ * Request : 접수 전 명령 요청 (단건/일괄 접수용)
+ *  - 형식 규칙은 validate 태그가 유일한 기준 (API 계층이 접수 전에 검사)
+ *  - KW10 : kW 단위의 10배수 (예: 50은 5.0kW, 최대 100kW)
+ */
+type Request struct {
+	DeviceID string `json:"device" validate:"required"`
+	Action   string `json:"action" validate:"required,oneof=charge discharge ready on off"`
+	KW10     int    `json:"kw10" validate:"gte=0,lte=1000"`
+}
+
+/*
+ * Dispatcher 구조체
+ *  - limiter   : 장치별 속도 제한기
+ *  - overrides : 운영자 오버라이드 세션 (진행 중인 장치는 속도 제한 생략)
+ *  - bus       : 명령 접수/완료 이벤트(CommandIssued, CommandCompleted) 발행
+ *  - effect    : 텔레메트리가 명령을 반영했는지 판정 (effect.go)
+ *  - commands : 명령 ID → 명령 (메모리 보관)
+ *  - done     : 명령 ID → 종료 시 닫히는 채널 (Wait 대기용, 종료되면 제거)
+ *  - queued   : 장치 ID → 확인을 기다리는 명령 ID (접수 순)
+ */
+type Dispatcher struct {
+	log       *zap.Logger
+	limiter   *Limiter
+	overrides *OverrideManager
+	bus       *bus.EventBus
+	effect    effectCheck
+	timeout   time.Duration // 텔레메트리 확인 대기 시간 (0이면 무기한)
+	retention time.Duration // 끝난 명령 보관 시간
+
+	mu       sync.RWMutex
+	commands map[string]*Command
+	done     map[string]chan struct{}
+	queued   map[string][]string
+	prunedAt time.Time
+
+	listenersMu sync.RWMutex
+	listeners   []func(Command) // 명령 완료 리스너 (웹훅 등)
+}
+
+/*
+ * NewDispatcher : fx가 호출하는 Dispatcher 생성자
+ *  - APP_CONTROL_CONFIRM_TIMEOUT : 접수 후 텔레메트리로 반영을 확인할 때까지 기다리는 시간, 지나면 failed
+ *                                  (기본 0 = 끔, 장치가 운전 모드 필드를 보고하지 않으면 켜지 말 것)
+ *  - APP_CONTROL_RETENTION       : 끝난 명령을 조회할 수 있게 보관하는 시간 (기본 1h)
+ *  - 확인 필드는 APP_CONTROL_MODE_FIELD, APP_CONTROL_POWER_FIELD, APP_CONTROL_POWER_TOLERANCE (effect.go)
+ *  - EventBus를 구독하여 텔레메트리로 명령을 확인하고, OnStop 시 확인 타이머를 모두 멈춤
+ */
+func NewDispatcher(lc fx.Lifecycle, log *zap.Logger, l *Limiter, o *OverrideManager, b *bus.EventBus) *Dispatcher {
+	d := &Dispatcher{log: log, limiter: l, overrides: o, bus: b,
+		effect:    newEffectCheck(log),
+		timeout:   config.Duration(log, "APP_CONTROL_CONFIRM_TIMEOUT", 0),
+		retention: config.Duration(log, "APP_CONTROL_RETENTION", time.Hour),
+		commands:  make(map[string]*Command), done: make(map[string]chan struct{}), queued: make(map[string][]string)}
+	if d.timeout < 0 || d.retention <= 0 {
+		log.Fatal("APP_CONTROL_CONFIRM_TIMEOUT must not be negative and APP_CONTROL_RETENTION must be positive",
+			zap.Duration("confirm_timeout", d.timeout), zap.Duration("retention", d.retention))
+	}
+	d.respondWait()
+	b.Subscribe(d.confirm)
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			d.stopTimers()
+			return nil
+		},
+	})
+	return d
+}
+
+/*
+ * Submit : 명령 접수
+ *  - 속도 제한에 걸리면 *LimitError 반환
+ *  - 접수되면 ID가 부여된 명령의 사본을 반환
+ *  - ctx는 CommandIssued 이벤트와 함께 구독자에게 전달됨 (요청 메타데이터 전파)
+ */
+func (d *Dispatcher) Submit(ctx context.Context, deviceID, action string, kw10 int) (Command, error) {
+	req := Request{DeviceID: deviceID, Action: action, KW10: kw10}
+	if o, ok := d.overrides.Active(deviceID); ok {
+		return d.enqueue(ctx, req, o.ID, time.Now()), nil
+	}
+	if err := d.limiter.Allow(deviceID, action); err != nil {
+		return Command{}, err
+	}
+	return d.enqueue(ctx, req, "", time.Now()), nil
+}
+
+/*
+ * SubmitBatch : 여러 장치의 명령을 한꺼번에 접수 (전부 접수 또는 전부 거절)
+ *  - 요청은 미리 Validate를 통과해야 하며, 같은 장치가 두 번 들어오면 안 됨 (호출자가 검사)
+ *  - 하나라도 속도 제한에 걸리면 아무 명령도 접수하지 않고, 항목별 에러를 요청 순서대로 반환
+ *  - 모두 허용되면 각자 ID가 부여된 명령 사본을 요청 순서대로 반환
+ */
+func (d *Dispatcher) SubmitBatch(ctx context.Context, reqs []Request) ([]Command, []error) {
+	// 오버라이드 중인 장치는 속도 제한 검사에서 제외
+	overrideIDs := make([]string, len(reqs))
+	var limited []Request
+	var limitedIdx []int
+	for i, r := range reqs {
+		if o, ok := d.overrides.Active(r.DeviceID); ok {
+			overrideIDs[i] = o.ID
+			continue
+		}
+		limited = append(limited, r)
+		limitedIdx = append(limitedIdx, i)
+	}
+
+	if len(limited) > 0 {
+		limitErrs := d.limiter.AllowAll(limited)
+		var errs []error
+		for j, err := range limitErrs {
+			if err != nil {
+				if errs == nil {
+					errs = make([]error, len(reqs))
+				}
+				errs[limitedIdx[j]] = err
+			}
+		}
+		if errs != nil {
+			return nil, errs
+		}
+	}
+
+	now := time.Now()
+	cmds := make([]Command, len(reqs))
+	for i, r := range reqs {
+		cmds[i] = d.enqueue(ctx, r, overrideIDs[i], now)
+	}
+	return cmds, nil
+}
+
+// enqueue : 속도 제한을 통과한(또는 오버라이드로 우회한) 요청에 ID를 부여하고 보관 (확인 시간을 지정했으면 타이머 시작)
+func (d *Dispatcher) enqueue(ctx context.Context, r Request, overrideID string, now time.Time) Command {
+	cmd := &Command{
+		ID:        newID(),
+		DeviceID:  r.DeviceID,
+		Action:    r.Action,
+		KW10:      r.KW10,
+		Override:  overrideID,
+		Status:    StatusQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id := cmd.ID
+	d.mu.Lock()
+	d.pruneLocked(now)
+	d.commands[id] = cmd
+	d.done[id] = make(chan struct{})
+	d.queued[r.DeviceID] = append(d.queued[r.DeviceID], id)
+	if d.timeout > 0 {
+		cmd.timer = time.AfterFunc(d.timeout, func() {
+			_ = d.Complete(context.Background(), id, fmt.Errorf("no confirming telemetry within %s", d.timeout))
+		})
+	}
+	issued := *cmd // 잠금 안에서 복사 (텔레메트리 확인이 곧바로 상태를 바꿀 수 있음)
+	d.mu.Unlock()
+
+	if overrideID != "" {
+		d.log.Warn("command queued under override", zap.String("id", cmd.ID), zap.String("device", r.DeviceID),
+			zap.String("action", r.Action), zap.String("override", overrideID))
+	} else {
+		d.log.Info("command queued", zap.String("id", cmd.ID), zap.String("device", r.DeviceID), zap.String("action", r.Action))
+	}
+	bus.Publish(ctx, d.bus, CommandIssued{Command: issued})
+	return issued
+}
+
+/*
+ * Get : ID로 명령 조회 (사본 반환)
+ */
+func (d *Dispatcher) Get(id string) (Command, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	cmd, ok := d.commands[id]
+	if !ok {
+		return Command{}, ErrNotFound
+	}
+	return *cmd, nil
+}
+
+/*
+ * List : 보관 중인 모든 명령 (접수 시각 순, 사본)
+ */
+func (d *Dispatcher) List() []Command {
+	d.mu.RLock()
+	out := make([]Command, 0, len(d.commands))
+	for _, cmd := range d.commands {
+		out = append(out, *cmd)
+	}
+	d.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+/*
+ * Complete : 명령을 종료 상태로 전환
+ *  - err가 nil이면 succeeded, 아니면 failed
+ *  - 이미 종료 상태면 바꾸지 않고 ErrCompleted (텔레메트리 확인, 시간 초과, 배포 검증이 겹칠 수 있음)
+ *  - 전환 후 OnComplete로 등록된 리스너에 알림
+ */
+func (d *Dispatcher) Complete(ctx context.Context, id string, err error) error {
+	d.mu.Lock()
+	cmd, ok := d.commands[id]
+	if !ok {
+		d.mu.Unlock()
+		return ErrNotFound
+	}
+	if cmd.Status.Terminal() {
+		d.mu.Unlock()
+		return ErrCompleted
+	}
+	if cmd.timer != nil {
+		cmd.timer.Stop()
+		cmd.timer = nil
+	}
+	d.unqueueLocked(cmd)
+	cmd.Status = StatusSucceeded
+	if err != nil {
+		cmd.Status = StatusFailed
+		cmd.Error = err.Error()
+	}
+	cmd.UpdatedAt = time.Now()
+	done := *cmd
+	if ch, ok := d.done[id]; ok {
+		close(ch) // Wait 중인 호출자 깨우기
+		delete(d.done, id)
+	}
+	d.mu.Unlock()
+
+	bus.Publish(ctx, d.bus, CommandCompleted{Command: done})
+	d.listenersMu.RLock()
+	listeners := d.listeners
+	d.listenersMu.RUnlock()
+	for _, fn := range listeners {
+		fn(done)
+	}
+	return nil
+}
+
+/*
+ * Wait : 명령이 종료 상태가 되거나 ctx가 끝날 때까지 대기
+ *  - 이미 종료된 명령이면 즉시 반환
+ *  - ctx가 먼저 끝나면 그 시점의 명령(아직 queued)과 ctx.Err()를 함께 반환
+ *  - 없는 명령이면 ErrNotFound
+ */
+func (d *Dispatcher) Wait(ctx context.Context, id string) (Command, error) {
+	d.mu.RLock()
+	cmd, ok := d.commands[id]
+	if !ok {
+		d.mu.RUnlock()
+		return Command{}, ErrNotFound
+	}
+	ch, pending := d.done[id]
+	snap := *cmd
+	d.mu.RUnlock()
+	if !pending {
+		return snap, nil
+	}
+
+	select {
+	case <-ch:
+		return d.Get(id)
+	case <-ctx.Done():
+		cur, err := d.Get(id)
+		if err != nil {
+			return Command{}, err
+		}
+		return cur, ctx.Err()
+	}
+}
+
+/*
+ * OnComplete : 명령이 종료 상태(succeeded/failed)가 될 때 호출될 함수 등록
+ *  - 리스너는 Complete를 호출한 고루틴에서 잠금 없이 호출되므로 오래 블록하면 안 됨
+ */
+func (d *Dispatcher) OnComplete(fn func(Command)) {
+	d.listenersMu.Lock()
+	d.listeners = append(d.listeners, fn)
+	d.listenersMu.Unlock()
+}
+
+// newID : 16진수 16자리 임의 ID 생성
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
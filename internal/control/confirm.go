@@ -0,0 +1,93 @@
+/*
+ * 명령 완료 확인 : 접수된(queued) 명령을 장치 텔레메트리로 확인하여 종료 상태로 바꿉니다.
+ *  - 명령 접수 이후 측정된 샘플이 명령을 반영하면(effect.go) succeeded
+ *    같은 장치에 먼저 접수되어 아직 확인되지 않은 명령은 failed ("superseded by <id>")
+ *  - APP_CONTROL_CONFIRM_TIMEOUT을 지정하면 그 안에 확인되지 않은 명령은 failed (enqueue의 타이머)
+ *  - /api/control, /api/control/batch, 배포(rollout.go) 명령 모두 같은 경로로 끝나므로
+ *    GET /api/control/{id}/wait는 확인 또는 시간 초과 시점에 응답함
+ */
+package control
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus" // 텔레메트리 이벤트
+)
+
+// confirm : 텔레메트리 이벤트 하나로 그 장치의 대기 중인 명령을 확인 (버스 구독자)
+func (d *Dispatcher) confirm(ctx context.Context, e bus.DataCollectedEvent) {
+	d.mu.RLock()
+	ids := d.queued[e.DeviceID]
+	confirmed := -1 // 반영된 가장 최근 명령의 위치
+	for i, id := range ids {
+		cmd := d.commands[id]
+		if !e.Time.IsZero() && e.Time.Before(cmd.CreatedAt) {
+			continue // 명령 전에 측정된 샘플
+		}
+		if known, err := d.effect.verify(cmd.Action, cmd.KW10, e.Values); known && err == nil {
+			confirmed = i
+		}
+	}
+	var older []string
+	var id string
+	if confirmed >= 0 {
+		older = append(older, ids[:confirmed]...)
+		id = ids[confirmed]
+	}
+	d.mu.RUnlock()
+	if confirmed < 0 {
+		return
+	}
+
+	for _, o := range older {
+		_ = d.Complete(ctx, o, fmt.Errorf("superseded by %s", id))
+	}
+	if d.Complete(ctx, id, nil) == nil {
+		d.log.Info("command confirmed by telemetry", zap.String("id", id), zap.String("device", e.DeviceID))
+	}
+}
+
+// unqueueLocked : 확인 대기 목록에서 명령 제거 (호출자가 잠금을 잡고 있어야 함)
+func (d *Dispatcher) unqueueLocked(cmd *Command) {
+	ids := d.queued[cmd.DeviceID]
+	for i, id := range ids {
+		if id == cmd.ID {
+			ids = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+	if len(ids) == 0 {
+		delete(d.queued, cmd.DeviceID)
+		return
+	}
+	d.queued[cmd.DeviceID] = ids
+}
+
+// pruneLocked : 끝난 지 보관 기간이 지난 명령 삭제 (호출자가 잠금을 잡고 있어야 함, pruneInterval마다 한 번 - limiter.go)
+func (d *Dispatcher) pruneLocked(now time.Time) {
+	if now.Sub(d.prunedAt) < pruneInterval {
+		return
+	}
+	d.prunedAt = now
+	for id, cmd := range d.commands {
+		if cmd.Status.Terminal() && now.Sub(cmd.UpdatedAt) > d.retention {
+			delete(d.commands, id)
+		}
+	}
+}
+
+// stopTimers : 확인 시간 초과 타이머를 모두 멈춤 (종료 시, 명령은 queued로 남음)
+func (d *Dispatcher) stopTimers() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, cmd := range d.commands {
+		if cmd.timer != nil {
+			cmd.timer.Stop()
+			cmd.timer = nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * 명령 효과 확인 : 장치가 보고한 텔레메트리가 명령이 반영된 상태인지 판정합니다. (명령 완료 확인 confirm.go, 배포 검증 rollout.go)
+ *  - 운전 모드 필드는 액션별 코드로 보고되어야 함 : off=0, on=1, ready=2, charge=3, discharge=4
+ *  - charge/discharge는 출력 필드(kW*10 단위, 방전은 음수여도 됨)가 명령의 kw10과 허용 오차 안이어야 함
+ *  - 모드 필드가 없는 샘플(다른 필드만 보고한 샘플)로는 판정하지 않음
+ */
+package control
+
+import (
+	"fmt"
+	"math"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// modeCodes : 액션 → 장치가 보고하는 운전 모드 코드
+var modeCodes = map[string]float64{"off": 0, "on": 1, "ready": 2, "charge": 3, "discharge": 4}
+
+/*
+ * effectCheck : 텔레메트리 필드 이름과 출력 허용 오차
+ *  - APP_CONTROL_MODE_FIELD      : 운전 모드 필드 (기본 mode)
+ *  - APP_CONTROL_POWER_FIELD     : 출력 필드, kW*10 단위 (기본 kw10)
+ *  - APP_CONTROL_POWER_TOLERANCE : 출력 허용 오차, kW*10 단위 (기본 5 = 0.5kW)
+ */
+type effectCheck struct {
+	modeField  string
+	powerField string
+	tolerance  float64
+}
+
+// newEffectCheck : 환경변수로 effectCheck 구성
+func newEffectCheck(log *zap.Logger) effectCheck {
+	c := effectCheck{
+		modeField:  config.String("APP_CONTROL_MODE_FIELD", "mode"),
+		powerField: config.String("APP_CONTROL_POWER_FIELD", "kw10"),
+		tolerance:  config.Float(log, "APP_CONTROL_POWER_TOLERANCE", 5),
+	}
+	if c.tolerance < 0 {
+		log.Fatal("APP_CONTROL_POWER_TOLERANCE must not be negative", zap.Float64("value", c.tolerance))
+	}
+	return c
+}
+
+/*
+ * verify : 샘플 값이 명령(action, kw10)이 반영된 상태인지
+ *  - known=false : 판정할 수 없음 (모드 필드가 없거나, charge/discharge인데 출력 필드가 없음)
+ *  - known=true  : err가 nil이면 반영됨, 아니면 어긋난 내용
+ */
+func (c effectCheck) verify(action string, kw10 int, values map[string]float64) (known bool, err error) {
+	mode, ok := values[c.modeField]
+	if !ok {
+		return false, nil
+	}
+	if want := modeCodes[action]; mode != want {
+		return true, fmt.Errorf("reported %s=%g, expected %g (%s)", c.modeField, mode, want, action)
+	}
+	if direction(action) == 0 {
+		return true, nil
+	}
+	power, ok := values[c.powerField]
+	if !ok {
+		return false, nil
+	}
+	if math.Abs(math.Abs(power)-float64(kw10)) > c.tolerance {
+		return true, fmt.Errorf("reported %s=%g, expected %d", c.powerField, power, kw10)
+	}
+	return true, nil
+}
@@ -0,0 +1,34 @@
+/*
+ * 제어 명령 이벤트 : 버스(bus.Publish[T])로 발행되는 명령 관련 이벤트 타입
+ *  - 다른 모듈은 Dispatcher에 직접 의존하지 않고 bus.Subscribe로 명령 흐름을 관찰할 수 있습니다.
+ */
+package control
+
+// 명령 이벤트 토픽
+const (
+	TopicCommandIssued    = "control.issued"
+	TopicCommandCompleted = "control.completed"
+	TopicCommandWait      = "control.wait" // 요청-응답 : 명령 결과 대기 (WaitRequest → Command, reply.go)
+)
+
+// CommandIssued : 명령이 접수됨 (속도 제한 통과 또는 오버라이드로 우회)
+type CommandIssued struct {
+	Command Command
+}
+
+// EventKey : 장치별 마지막 접수 명령을 따라잡기용으로 보관
+func (e CommandIssued) EventKey() string { return e.Command.DeviceID }
+
+// Topic : 명령 접수 토픽
+func (e CommandIssued) Topic() string { return TopicCommandIssued }
+
+// CommandCompleted : 명령이 종료 상태(succeeded/failed)가 됨
+type CommandCompleted struct {
+	Command Command
+}
+
+// EventKey : 장치별 마지막 완료 명령을 따라잡기용으로 보관
+func (e CommandCompleted) EventKey() string { return e.Command.DeviceID }
+
+// Topic : 명령 완료 토픽
+func (e CommandCompleted) Topic() string { return TopicCommandCompleted }
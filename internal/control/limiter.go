@@ -0,0 +1,216 @@
+/*
+ * Limiter : 장치별 제어 명령 속도 제한기
+ *  - 같은 장치로 연달아 들어오는 명령 사이에 최소 간격(cooldown)을 강제합니다.
+ *  - 충전(charge) ↔ 방전(discharge) 방향 전환 횟수를 시간당 상한으로 제한합니다.
+ *  - 목적 : 버그 있는 클라이언트나 자동화 루프 때문에 인버터 같은 하드웨어가 급격히 반복 동작하는 것을 방지
+ *  - 최소 간격과 방향 전환 구간(1시간)이 모두 지난 장치의 이력은 주기적으로 지웁니다. (장치 ID가 계속 바뀌어도 메모리가 늘지 않도록)
+ */
+package control
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// 방향 전환 횟수를 세는 기준 구간
+const flipWindow = time.Hour
+
+// 오래된 이력 정리 간격 (요청마다 전체를 훑지 않도록)
+const pruneInterval = time.Minute
+
+/*
+ * LimitError : 속도 제한에 걸렸을 때 반환되는 에러
+ *  - Reason     : 거절 사유 ("cooldown" | "flip_limit")
+ *  - RetryAfter : 다시 시도해도 되는 시점까지 남은 시간 (HTTP Retry-After 헤더용)
+ */
+type LimitError struct {
+	DeviceID   string
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("device %s rate limited (%s), retry after %s", e.DeviceID, e.Reason, e.RetryAfter)
+}
+
+// deviceState : 장치 하나에 대한 최근 명령 이력
+type deviceState struct {
+	lastAt  time.Time   // 마지막으로 허용된 명령 시각
+	lastDir int         // 마지막 방향 (+1 충전, -1 방전, 0 방향 없음)
+	flips   []time.Time // 최근 1시간 내 방향 전환 시각 목록
+}
+
+/*
+ * Limiter 구조체
+ *  - minInterval : 같은 장치에 대한 명령 사이 최소 간격 (0이면 비활성)
+ *  - maxFlips    : 시간당 허용되는 방향 전환 횟수 (0이면 비활성)
+ */
+type Limiter struct {
+	log         *zap.Logger
+	minInterval time.Duration
+	maxFlips    int
+
+	mu       sync.Mutex
+	devices  map[string]*deviceState
+	prunedAt time.Time // 마지막 이력 정리 시각
+}
+
+/*
+ * NewLimiter : fx가 호출하는 Limiter 생성자
+ *  - APP_CONTROL_MIN_INTERVAL       : 명령 최소 간격 (기본 5s)
+ *  - APP_CONTROL_MAX_FLIPS_PER_HOUR : 시간당 최대 방향 전환 횟수 (기본 6)
+ */
+func NewLimiter(log *zap.Logger) *Limiter {
+	l := &Limiter{
+		log:         log,
+		minInterval: config.Duration(log, "APP_CONTROL_MIN_INTERVAL", 5*time.Second),
+		maxFlips:    config.Int(log, "APP_CONTROL_MAX_FLIPS_PER_HOUR", 6),
+		devices:     make(map[string]*deviceState),
+	}
+	log.Info("control limiter configured",
+		zap.Duration("min_interval", l.minInterval),
+		zap.Int("max_flips_per_hour", l.maxFlips))
+	return l
+}
+
+/*
+ * direction : 액션을 방향 값으로 변환
+ *  - charge → +1, discharge → -1, 그 외(on/off/ready) → 0 (방향 전환으로 세지 않음)
+ */
+func direction(action string) int {
+	switch action {
+	case "charge":
+		return 1
+	case "discharge":
+		return -1
+	}
+	return 0
+}
+
+/*
+ * Allow : 명령을 허용할지 판단하고, 허용하면 이력에 기록
+ *  - 거절 시 *LimitError 반환 (이력은 변경하지 않음)
+ */
+func (l *Limiter) Allow(deviceID, action string) error {
+	return l.allowAt(deviceID, action, time.Now())
+}
+
+func (l *Limiter) allowAt(deviceID, action string, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pruneLocked(now)
+	st := l.state(deviceID)
+	dir := direction(action)
+	flip, err := l.check(st, deviceID, dir, now)
+	if err != nil {
+		return err
+	}
+	st.record(dir, flip, now)
+	return nil
+}
+
+/*
+ * AllowAll : 여러 장치의 명령을 한꺼번에 판단 (전부 허용 또는 전부 거절)
+ *  - 반환 슬라이스는 요청과 같은 순서이며, 거절된 항목 자리에 *LimitError가 들어감
+ *  - 하나라도 거절되면 어떤 장치의 이력도 변경하지 않음
+ *  - 같은 장치가 두 번 들어오는 경우는 호출자가 미리 걸러야 함
+ */
+func (l *Limiter) AllowAll(reqs []Request) []error {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pruneLocked(now)
+	errs := make([]error, len(reqs))
+	flips := make([]bool, len(reqs))
+	failed := false
+	for i, r := range reqs {
+		flip, err := l.check(l.state(r.DeviceID), r.DeviceID, direction(r.Action), now)
+		errs[i], flips[i] = err, flip
+		if err != nil {
+			failed = true
+		}
+	}
+	if failed {
+		return errs
+	}
+	for i, r := range reqs {
+		l.devices[r.DeviceID].record(direction(r.Action), flips[i], now)
+	}
+	return errs
+}
+
+// state : 장치 이력 조회 (없으면 생성, 잠금 보유 상태에서 호출)
+func (l *Limiter) state(deviceID string) *deviceState {
+	st, ok := l.devices[deviceID]
+	if !ok {
+		st = &deviceState{}
+		l.devices[deviceID] = st
+	}
+	return st
+}
+
+/*
+ * pruneLocked : 더 이상 판단에 쓰이지 않는 장치 이력 삭제 (잠금 보유 상태에서 호출, pruneInterval마다 한 번)
+ *  - 마지막 명령 이후 최소 간격과 방향 전환 구간이 모두 지났으면 삭제
+ *    (방향 전환 구간이 지나기 전에 지우면 마지막 방향을 잊어 충전 ↔ 방전 반복을 세지 못함)
+ */
+func (l *Limiter) pruneLocked(now time.Time) {
+	if now.Sub(l.prunedAt) < pruneInterval {
+		return
+	}
+	l.prunedAt = now
+	idle := max(l.minInterval, flipWindow)
+	for id, st := range l.devices {
+		if now.Sub(st.lastAt) >= idle {
+			delete(l.devices, id)
+		}
+	}
+}
+
+/*
+ * check : 이력을 바꾸지 않고 명령 허용 여부만 판단
+ *  - 반환 flip : 이 명령이 방향 전환인지 (허용 시 record에 전달)
+ */
+func (l *Limiter) check(st *deviceState, deviceID string, dir int, now time.Time) (bool, error) {
+	// ① 최소 간격 검사
+	if l.minInterval > 0 && !st.lastAt.IsZero() {
+		if elapsed := now.Sub(st.lastAt); elapsed < l.minInterval {
+			return false, &LimitError{DeviceID: deviceID, Reason: "cooldown", RetryAfter: l.minInterval - elapsed}
+		}
+	}
+
+	// ② 1시간이 지난 방향 전환 기록 정리
+	cutoff := now.Add(-flipWindow)
+	kept := st.flips[:0]
+	for _, t := range st.flips {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	st.flips = kept
+
+	// ③ 방향 전환 횟수 검사 (충전 ↔ 방전으로 바뀌는 경우만 카운트)
+	flip := dir != 0 && st.lastDir != 0 && dir != st.lastDir
+	if flip && l.maxFlips > 0 && len(st.flips) >= l.maxFlips {
+		return false, &LimitError{DeviceID: deviceID, Reason: "flip_limit", RetryAfter: st.flips[0].Add(flipWindow).Sub(now)}
+	}
+	return flip, nil
+}
+
+// record : 허용된 명령을 이력에 반영
+func (st *deviceState) record(dir int, flip bool, now time.Time) {
+	st.lastAt = now
+	if dir != 0 {
+		st.lastDir = dir
+	}
+	if flip {
+		st.flips = append(st.flips, now)
+	}
+}
@@ -0,0 +1,161 @@
+package control
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestLimiter(minInterval time.Duration, maxFlips int) *Limiter {
+	return &Limiter{log: zap.NewNop(), minInterval: minInterval, maxFlips: maxFlips, devices: make(map[string]*deviceState)}
+}
+
+// step : at 시점에 들어온 명령과 기대 결과 (reason이 비면 허용)
+type step struct {
+	at     time.Duration
+	device string
+	action string
+	reason string
+	retry  time.Duration
+}
+
+func TestAllowAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		minInterval time.Duration
+		maxFlips    int
+		steps       []step
+	}{
+		{
+			name:        "cooldown between commands",
+			minInterval: 5 * time.Second,
+			steps: []step{
+				{at: 0, device: "A1", action: "charge"},
+				{at: 2 * time.Second, device: "A1", action: "charge", reason: "cooldown", retry: 3 * time.Second},
+				{at: 2 * time.Second, device: "B2", action: "charge"}, // 장치마다 따로 셈
+				{at: 5 * time.Second, device: "A1", action: "charge"},
+			},
+		},
+		{
+			name:        "rejected command does not reset cooldown",
+			minInterval: 5 * time.Second,
+			steps: []step{
+				{at: 0, device: "A1", action: "on"},
+				{at: 4 * time.Second, device: "A1", action: "off", reason: "cooldown", retry: time.Second},
+				{at: 5 * time.Second, device: "A1", action: "off"},
+			},
+		},
+		{
+			name:        "flip limit per hour",
+			minInterval: time.Second,
+			maxFlips:    2,
+			steps: []step{
+				{at: 0, device: "A1", action: "charge"},
+				{at: 10 * time.Second, device: "A1", action: "discharge"}, // 1번째 전환
+				{at: 20 * time.Second, device: "A1", action: "charge"},    // 2번째 전환
+				{at: 30 * time.Second, device: "A1", action: "charge"},    // 같은 방향은 전환 아님
+				{at: 40 * time.Second, device: "A1", action: "discharge", reason: "flip_limit", retry: time.Hour - 30*time.Second},
+				{at: time.Hour + 10*time.Second, device: "A1", action: "discharge"}, // 1번째 전환이 구간 밖으로
+			},
+		},
+		{
+			name:        "non-directional actions keep last direction",
+			minInterval: time.Second,
+			maxFlips:    1,
+			steps: []step{
+				{at: 0, device: "A1", action: "charge"},
+				{at: 10 * time.Second, device: "A1", action: "off"},
+				{at: 20 * time.Second, device: "A1", action: "discharge"}, // off를 사이에 둬도 전환
+				{at: 30 * time.Second, device: "A1", action: "ready"},
+				{at: 40 * time.Second, device: "A1", action: "charge", reason: "flip_limit", retry: time.Hour - 20*time.Second},
+			},
+		},
+		{
+			name: "disabled limits",
+			steps: []step{
+				{at: 0, device: "A1", action: "charge"},
+				{at: 0, device: "A1", action: "discharge"},
+				{at: 0, device: "A1", action: "charge"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLimiter(tt.minInterval, tt.maxFlips)
+			start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i, s := range tt.steps {
+				err := l.allowAt(s.device, s.action, start.Add(s.at))
+				if s.reason == "" {
+					if err != nil {
+						t.Fatalf("step %d (%s %s): unexpected %v", i, s.device, s.action, err)
+					}
+					continue
+				}
+				var le *LimitError
+				if !errors.As(err, &le) {
+					t.Fatalf("step %d (%s %s): err=%v, want LimitError %s", i, s.device, s.action, err, s.reason)
+				}
+				if le.Reason != s.reason || le.RetryAfter != s.retry {
+					t.Fatalf("step %d: got %s retry %s, want %s retry %s", i, le.Reason, le.RetryAfter, s.reason, s.retry)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneIdleDevices(t *testing.T) {
+	tests := []struct {
+		name  string
+		idle  time.Duration // A1의 마지막 명령 이후 경과 시간
+		kept  bool
+		flips bool // 재등장한 A1의 반대 방향 명령이 전환으로 세어지는지
+	}{
+		{name: "within flip window", idle: 30 * time.Minute, kept: true, flips: true},
+		{name: "past flip window", idle: flipWindow, kept: false, flips: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLimiter(5*time.Second, 1)
+			start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			if err := l.allowAt("A1", "charge", start); err != nil {
+				t.Fatal(err)
+			}
+			if err := l.allowAt("B2", "on", start.Add(tt.idle)); err != nil {
+				t.Fatal(err)
+			}
+			st, ok := l.devices["A1"]
+			if ok != tt.kept {
+				t.Fatalf("A1 kept=%v, want %v", ok, tt.kept)
+			}
+			if ok && st.lastDir != 1 {
+				t.Fatalf("A1 lastDir=%d, want 1", st.lastDir)
+			}
+			if err := l.allowAt("A1", "discharge", start.Add(tt.idle)); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(l.devices["A1"].flips) == 1; got != tt.flips {
+				t.Fatalf("discharge counted as flip=%v, want %v", got, tt.flips)
+			}
+		})
+	}
+}
+
+func TestAllowAllIsAtomic(t *testing.T) {
+	l := newTestLimiter(time.Hour, 0) // 실제 시계를 쓰므로 간격을 길게
+	if err := l.Allow("B2", "on"); err != nil {
+		t.Fatal(err)
+	}
+	errs := l.AllowAll([]Request{{DeviceID: "A1", Action: "charge"}, {DeviceID: "B2", Action: "off"}})
+	if errs[0] != nil {
+		t.Fatalf("A1: unexpected %v", errs[0])
+	}
+	var le *LimitError
+	if !errors.As(errs[1], &le) || le.Reason != "cooldown" {
+		t.Fatalf("B2: err=%v, want cooldown", errs[1])
+	}
+	if st := l.devices["A1"]; !st.lastAt.IsZero() {
+		t.Fatal("A1 history recorded although the batch was rejected")
+	}
+}
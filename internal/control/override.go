@@ -0,0 +1,194 @@
+/*
+ * OverrideManager : 시간 제한이 있는 운영자 오버라이드(override) 세션 관리자
+ *  - 긴급 수동 제어를 위해, 권한 있는 운영자가 특정 장치의 정책 제한(속도 제한 등)을 일시적으로 우회합니다.
+ *  - 사유(reason)와 TTL은 필수이며, TTL은 상한(APP_OVERRIDE_MAX_TTL)을 넘을 수 없습니다.
+ *  - 세션이 시작·종료·만료될 때마다 리스너(감사 기록, 웹훅 등)에 알려 눈에 띄게 남깁니다.
+ *  - 기본 상태는 항상 "제한 적용"이며, 만료 시 자동으로 제한이 되살아납니다.
+ */
+package control
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// ErrOverrideActive : 장치에 이미 진행 중인 세션이 있음 (나머지 Start 에러는 요청 형식 오류)
+var ErrOverrideActive = errors.New("override already active")
+
+// OverrideEventType : 오버라이드 상태 변화 종류
+type OverrideEventType string
+
+const (
+	OverrideStarted OverrideEventType = "started" // 세션 시작
+	OverrideEnded   OverrideEventType = "ended"   // 운영자가 직접 종료
+	OverrideExpired OverrideEventType = "expired" // TTL 만료
+)
+
+/*
+ * Override : 오버라이드 세션 하나
+ *  - Operator : 세션을 연 운영자 (인증된 호출자 이름)
+ *  - EndedBy  : 직접 종료한 운영자 (만료 시 비어 있음)
+ */
+type Override struct {
+	ID        string    `json:"id"`
+	DeviceID  string    `json:"device"`
+	Operator  string    `json:"operator"`
+	Reason    string    `json:"reason"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+	EndedBy   string    `json:"ended_by,omitempty"`
+
+	timer *time.Timer
+}
+
+// OverrideManager 구조체
+type OverrideManager struct {
+	log    *zap.Logger
+	maxTTL time.Duration
+
+	mu        sync.Mutex
+	active    map[string]*Override // 장치 ID → 진행 중인 세션 (장치당 하나)
+	byID      map[string]*Override // 세션 ID → 세션
+	listeners []func(OverrideEventType, Override)
+}
+
+/*
+ * NewOverrideManager : fx가 호출하는 OverrideManager 생성자
+ *  - APP_OVERRIDE_MAX_TTL : 세션 최대 길이 (기본 1h)
+ */
+func NewOverrideManager(log *zap.Logger) *OverrideManager {
+	m := &OverrideManager{
+		log:    log,
+		maxTTL: config.Duration(log, "APP_OVERRIDE_MAX_TTL", time.Hour),
+		active: make(map[string]*Override),
+		byID:   make(map[string]*Override),
+	}
+	if m.maxTTL <= 0 {
+		log.Fatal("APP_OVERRIDE_MAX_TTL must be positive", zap.Duration("value", m.maxTTL))
+	}
+	return m
+}
+
+/*
+ * Start : 오버라이드 세션 시작
+ *  - 같은 장치에 진행 중인 세션이 있으면 ErrOverrideActive (먼저 종료해야 함)
+ *  - 그 외 에러는 요청 값 오류 (장치/사유 누락, TTL이 0 이하이거나 상한 초과)
+ */
+func (m *OverrideManager) Start(deviceID, operator, reason string, ttl time.Duration) (Override, error) {
+	switch {
+	case deviceID == "":
+		return Override{}, fmt.Errorf("device is required")
+	case reason == "":
+		return Override{}, fmt.Errorf("reason is required")
+	case ttl <= 0:
+		return Override{}, fmt.Errorf("ttl is required")
+	case ttl > m.maxTTL:
+		return Override{}, fmt.Errorf("ttl must not exceed %s", m.maxTTL)
+	}
+
+	m.mu.Lock()
+	if cur, ok := m.active[deviceID]; ok {
+		m.mu.Unlock()
+		return Override{}, fmt.Errorf("%w: device %s has override %s until %s", ErrOverrideActive, deviceID, cur.ID, cur.ExpiresAt.Format(time.RFC3339))
+	}
+	now := time.Now()
+	o := &Override{ID: newID(), DeviceID: deviceID, Operator: operator, Reason: reason, StartedAt: now, ExpiresAt: now.Add(ttl)}
+	o.timer = time.AfterFunc(ttl, func() { m.finish(o.ID, "", OverrideExpired) })
+	m.active[deviceID] = o
+	m.byID[o.ID] = o
+	snapshot := *o
+	m.mu.Unlock()
+
+	m.log.Warn("override started", zap.String("id", o.ID), zap.String("device", deviceID),
+		zap.String("operator", operator), zap.String("reason", reason), zap.Time("expires_at", o.ExpiresAt))
+	m.notify(OverrideStarted, snapshot)
+	return snapshot, nil
+}
+
+// MaxTTL : 세션 최대 길이 (APP_OVERRIDE_MAX_TTL)
+func (m *OverrideManager) MaxTTL() time.Duration {
+	return m.maxTTL
+}
+
+// End : 운영자가 세션을 직접 종료
+func (m *OverrideManager) End(id, operator string) (Override, error) {
+	o, ok := m.finish(id, operator, OverrideEnded)
+	if !ok {
+		return Override{}, ErrNotFound
+	}
+	return o, nil
+}
+
+// finish : 세션 종료 처리 (이미 끝난 세션이면 false)
+func (m *OverrideManager) finish(id, operator string, typ OverrideEventType) (Override, bool) {
+	m.mu.Lock()
+	o, ok := m.byID[id]
+	if !ok || !o.EndedAt.IsZero() {
+		m.mu.Unlock()
+		return Override{}, false
+	}
+	o.timer.Stop()
+	o.EndedAt = time.Now()
+	o.EndedBy = operator
+	delete(m.active, o.DeviceID)
+	delete(m.byID, id)
+	snapshot := *o
+	m.mu.Unlock()
+
+	m.log.Warn("override "+string(typ), zap.String("id", id), zap.String("device", o.DeviceID), zap.String("by", operator))
+	m.notify(typ, snapshot)
+	return snapshot, true
+}
+
+/*
+ * Active : 장치에 진행 중인 세션 (없으면 false)
+ *  - 타이머가 늦게 돌더라도 만료 시각이 지났으면 진행 중으로 보지 않음
+ */
+func (m *OverrideManager) Active(deviceID string) (Override, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.active[deviceID]
+	if !ok || time.Now().After(o.ExpiresAt) {
+		return Override{}, false
+	}
+	return *o, true
+}
+
+// List : 진행 중인 세션 목록 (시작 시각 순)
+func (m *OverrideManager) List() []Override {
+	m.mu.Lock()
+	out := make([]Override, 0, len(m.active))
+	for _, o := range m.active {
+		out = append(out, *o)
+	}
+	m.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out
+}
+
+/*
+ * OnChange : 세션 시작/종료/만료 시 호출될 함수 등록
+ */
+func (m *OverrideManager) OnChange(fn func(OverrideEventType, Override)) {
+	m.mu.Lock()
+	m.listeners = append(m.listeners, fn)
+	m.mu.Unlock()
+}
+
+// notify : 리스너 호출 (잠금 밖에서)
+func (m *OverrideManager) notify(typ OverrideEventType, o Override) {
+	m.mu.Lock()
+	listeners := m.listeners
+	m.mu.Unlock()
+	for _, fn := range listeners {
+		fn(typ, o)
+	}
+}
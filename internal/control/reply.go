@@ -0,0 +1,44 @@
+/*
+ * 명령 결과 질의 : 버스 요청-응답(bus.Request)으로 Dispatcher에 명령 결과를 묻습니다.
+ *  - 요청 : TopicCommandWait 토픽, WaitRequest payload
+ *  - 응답 : Command (Wait과 같은 의미 : 종료 상태가 되거나 Timeout이 지나면 그 시점의 명령)
+ *  - HTTP 핸들러 등 다른 모듈은 *Dispatcher를 주입받지 않고 버스만으로 결과를 기다릴 수 있습니다.
+ */
+package control
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"generic-api-scaffold/internal/bus" // 요청-응답
+)
+
+/*
+ * WaitRequest : 명령 결과 대기 요청
+ *  - ID      : 명령 ID
+ *  - Timeout : 최대 대기 시간 (0이면 기다리지 않고 현재 상태, 지나면 현재 명령과 context.DeadlineExceeded)
+ */
+type WaitRequest struct {
+	ID      string
+	Timeout time.Duration
+}
+
+// Topic : 명령 결과 대기 토픽
+func (r WaitRequest) Topic() string { return TopicCommandWait }
+
+/*
+ * respondWait : TopicCommandWait 요청에 응답하는 구독 등록
+ *  - 대기가 길 수 있으므로 요청마다 고루틴으로 처리 (요청끼리 서로 막지 않음)
+ */
+func (d *Dispatcher) respondWait() {
+	d.bus.Respond(TopicCommandWait, func(ctx context.Context, m bus.Message) (any, error) {
+		req, ok := m.Payload.(WaitRequest)
+		if !ok {
+			return nil, fmt.Errorf("control: unexpected %s payload %T", TopicCommandWait, m.Payload)
+		}
+		ctx, cancel := context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+		return d.Wait(ctx, req.ID)
+	}, bus.WithName("control.wait"), bus.WithDelivery(bus.DeliveryAsync))
+}
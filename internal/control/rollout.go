@@ -0,0 +1,446 @@
+/*
+ * RolloutManager : 여러 장치에 대한 단계적(staged) 명령 배포 관리자
+ *  - 대상 장치를 일정 비율(예: 10%)씩 나누어 명령을 보내고, 대기 후 텔레메트리로 효과(운전 모드·출력)를 검증합니다. (effect.go)
+ *  - 단계별 실패율이 임계치를 넘으면 자동으로 중단(halted)합니다.
+ *  - 진행 상황(단계, 성공/실패 수, 장치별 결과)은 Get으로 조회할 수 있습니다.
+ *  - 끝난 배포는 APP_ROLLOUT_RETENTION이 지나면 목록에서 지웁니다.
+ */
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"  // 애플리케이션 생명주기(Lifecycle) 훅 제공
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 텔레메트리 수신 (DataCollectedEvent)
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// RolloutState : 배포 진행 상태
+type RolloutState string
+
+const (
+	RolloutRunning   RolloutState = "running"   // 진행 중
+	RolloutCompleted RolloutState = "completed" // 모든 단계 완료
+	RolloutHalted    RolloutState = "halted"    // 실패율 초과로 자동 중단
+	RolloutAborted   RolloutState = "aborted"   // 운영자 요청 또는 종료로 중단
+)
+
+/*
+ * RolloutSpec : 배포 요청
+ *  - StagePercent : 단계마다 명령을 보낼 장치 비율(%) (0이면 기본값)
+ *  - StageWait    : 단계 사이 대기 및 텔레메트리 검증 시간 (0이면 기본값, JSON에서는 "30s" 같은 duration 문자열)
+ *  - MaxErrorRate : 단계 실패율 상한 (0~1, nil이면 기본값, 0이면 실패를 하나도 허용하지 않음)
+ */
+type RolloutSpec struct {
+	Action       string        `json:"action"`
+	KW10         int           `json:"kw10"`
+	Devices      []string      `json:"devices"`
+	StagePercent int           `json:"stage_percent"`
+	StageWait    time.Duration `json:"-"` // MarshalJSON에서 stage_wait 문자열로
+	MaxErrorRate *float64      `json:"max_error_rate"`
+}
+
+// MarshalJSON : StageWait를 요청 본문(stage_wait)과 같은 duration 문자열로 내보냄
+func (s RolloutSpec) MarshalJSON() ([]byte, error) {
+	type plain RolloutSpec
+	return json.Marshal(struct {
+		plain
+		StageWait string `json:"stage_wait"`
+	}{plain(s), s.StageWait.String()})
+}
+
+// DeviceResult : 장치 하나의 배포 결과
+type DeviceResult struct {
+	CommandID string `json:"command_id,omitempty"`
+	Stage     int    `json:"stage"`
+	Status    Status `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+/*
+ * Rollout : 진행 중이거나 끝난 배포 하나
+ *  - Stage / Stages : 현재 단계(1부터) / 전체 단계 수
+ */
+type Rollout struct {
+	ID        string                  `json:"id"`
+	Spec      RolloutSpec             `json:"spec"`
+	State     RolloutState            `json:"state"`
+	Stage     int                     `json:"stage"`
+	Stages    int                     `json:"stages"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Reason    string                  `json:"reason,omitempty"`
+	Results   map[string]DeviceResult `json:"results"`
+	StartedAt time.Time               `json:"started_at"`
+	EndedAt   time.Time               `json:"ended_at,omitempty"`
+
+	cancel context.CancelFunc
+}
+
+// seenValue : 장치가 마지막으로 보고한 필드 값과 수신 시각
+type seenValue struct {
+	v  float64
+	at time.Time
+}
+
+/*
+ * RolloutManager 구조체
+ *  - lastSeen  : 장치 → 필드별 마지막 텔레메트리 값과 수신 시각 (효과 검증용)
+ *  - retention : 끝난 배포를 보관하는 시간
+ */
+type RolloutManager struct {
+	log        *zap.Logger
+	dispatcher *Dispatcher
+	defaults   RolloutSpec
+	effect     effectCheck
+	retention  time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.RWMutex
+	rollouts map[string]*Rollout
+	lastSeen map[string]map[string]seenValue
+}
+
+/*
+ * NewRolloutManager : fx가 호출하는 RolloutManager 생성자
+ *  - APP_ROLLOUT_STAGE_PERCENT  : 단계별 장치 비율 기본값 (기본 10)
+ *  - APP_ROLLOUT_STAGE_WAIT     : 단계 대기/검증 시간 기본값 (기본 30s)
+ *  - APP_ROLLOUT_MAX_ERROR_RATE : 단계 실패율 상한 기본값 (기본 0.2)
+ *  - APP_ROLLOUT_RETENTION      : 끝난 배포를 조회할 수 있게 보관하는 시간 (기본 1h)
+ *  - 효과 검증 필드는 APP_CONTROL_MODE_FIELD, APP_CONTROL_POWER_FIELD, APP_CONTROL_POWER_TOLERANCE (effect.go)
+ *  - EventBus를 구독하여 장치별 마지막 텔레메트리 값을 기록
+ *  - OnStop 시 진행 중인 배포를 모두 중단
+ */
+func NewRolloutManager(lc fx.Lifecycle, log *zap.Logger, eb *bus.EventBus, d *Dispatcher) *RolloutManager {
+	ctx, cancel := context.WithCancel(context.Background())
+	maxErrorRate := config.Float(log, "APP_ROLLOUT_MAX_ERROR_RATE", 0.2)
+	m := &RolloutManager{
+		log:        log,
+		dispatcher: d,
+		defaults: RolloutSpec{
+			StagePercent: config.Int(log, "APP_ROLLOUT_STAGE_PERCENT", 10),
+			StageWait:    config.Duration(log, "APP_ROLLOUT_STAGE_WAIT", 30*time.Second),
+			MaxErrorRate: &maxErrorRate,
+		},
+		effect:    newEffectCheck(log),
+		retention: config.Duration(log, "APP_ROLLOUT_RETENTION", time.Hour),
+		ctx:       ctx,
+		cancel:    cancel,
+		rollouts:  make(map[string]*Rollout),
+		lastSeen:  make(map[string]map[string]seenValue),
+	}
+	if m.retention <= 0 {
+		log.Fatal("APP_ROLLOUT_RETENTION must be positive", zap.Duration("value", m.retention))
+	}
+
+	// 텔레메트리 값과 수신 시각 기록
+	eb.Subscribe(func(_ context.Context, e bus.DataCollectedEvent) {
+		now := time.Now()
+		m.mu.Lock()
+		fields := m.lastSeen[e.DeviceID]
+		if fields == nil {
+			fields = make(map[string]seenValue)
+			m.lastSeen[e.DeviceID] = fields
+		}
+		for k, v := range e.Values {
+			fields[k] = seenValue{v: v, at: now}
+		}
+		m.mu.Unlock()
+	})
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			m.cancel()
+			m.wg.Wait()
+			return nil
+		},
+	})
+	return m
+}
+
+/*
+ * Start : 배포 시작
+ *  - 요청값이 비어 있는 항목은 기본값으로 채운 뒤 검증 (같은 장치가 두 번 들어오면 거절)
+ *  - 보관 기간(APP_ROLLOUT_RETENTION)이 지난 끝난 배포를 정리
+ *  - 실제 진행은 별도 고루틴에서 수행하고, 즉시 배포 사본을 반환
+ */
+func (m *RolloutManager) Start(spec RolloutSpec) (Rollout, error) {
+	if spec.Action == "" {
+		return Rollout{}, errors.New("action is required")
+	}
+	if len(spec.Devices) == 0 {
+		return Rollout{}, errors.New("devices must not be empty")
+	}
+	seen := make(map[string]bool, len(spec.Devices))
+	for _, dev := range spec.Devices {
+		if seen[dev] {
+			return Rollout{}, fmt.Errorf("duplicate device %q", dev)
+		}
+		seen[dev] = true
+	}
+	if spec.StagePercent == 0 {
+		spec.StagePercent = m.defaults.StagePercent
+	}
+	if spec.StageWait == 0 {
+		spec.StageWait = m.defaults.StageWait
+	}
+	if spec.MaxErrorRate == nil {
+		rate := *m.defaults.MaxErrorRate
+		spec.MaxErrorRate = &rate
+	}
+	if spec.StagePercent < 1 || spec.StagePercent > 100 {
+		return Rollout{}, fmt.Errorf("stage_percent must be between 1 and 100, got %d", spec.StagePercent)
+	}
+	if *spec.MaxErrorRate < 0 || *spec.MaxErrorRate > 1 {
+		return Rollout{}, fmt.Errorf("max_error_rate must be between 0 and 1, got %g", *spec.MaxErrorRate)
+	}
+
+	stageSize := int(math.Ceil(float64(len(spec.Devices)) * float64(spec.StagePercent) / 100))
+	ctx, cancel := context.WithCancel(m.ctx)
+	ro := &Rollout{
+		ID:        newID(),
+		Spec:      spec,
+		State:     RolloutRunning,
+		Stages:    (len(spec.Devices) + stageSize - 1) / stageSize,
+		Results:   make(map[string]DeviceResult, len(spec.Devices)),
+		StartedAt: time.Now(),
+		cancel:    cancel,
+	}
+
+	m.mu.Lock()
+	m.pruneLocked(ro.StartedAt)
+	m.rollouts[ro.ID] = ro
+	m.mu.Unlock()
+
+	m.log.Info("rollout started", zap.String("id", ro.ID), zap.Int("devices", len(spec.Devices)), zap.Int("stages", ro.Stages))
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(ctx, ro, stageSize)
+	}()
+	return m.snapshot(ro), nil
+}
+
+/*
+ * Get : 배포 진행 상황 조회 (사본 반환)
+ */
+func (m *RolloutManager) Get(id string) (Rollout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ro, ok := m.rollouts[id]
+	if !ok {
+		return Rollout{}, ErrNotFound
+	}
+	return m.snapshotLocked(ro), nil
+}
+
+/*
+ * Abort : 진행 중인 배포를 중단
+ *  - 이미 보낸 명령은 되돌리지 않으며, 다음 단계로 진행하지 않습니다.
+ */
+func (m *RolloutManager) Abort(id string) error {
+	m.mu.RLock()
+	ro, ok := m.rollouts[id]
+	m.mu.RUnlock()
+	if !ok {
+		return ErrNotFound
+	}
+	ro.cancel()
+	return nil
+}
+
+/*
+ * run : 배포 메인 루프
+ *  - 내부 동작 (단계마다) :
+ *     ① 단계 대상 장치에 명령 접수 (속도 제한 등으로 거절되면 즉시 실패)
+ *     ② StageWait 동안 대기
+ *     ③ 명령이 이미 끝났으면(텔레메트리 확인·확인 시간 초과, confirm.go) 그 결과를 따르고,
+ *        아니면 명령 이후 보고된 운전 모드·출력이 명령과 맞으면 성공, 맞지 않거나 보고가 없으면 실패로 판정 (effect.go)
+ *     ④ 단계 실패율이 MaxErrorRate를 넘으면 중단(halted)
+ */
+func (m *RolloutManager) run(ctx context.Context, ro *Rollout, stageSize int) {
+	defer ro.cancel()
+	devices := ro.Spec.Devices
+
+	for stage := 1; len(devices) > 0; stage++ {
+		n := stageSize
+		if n > len(devices) {
+			n = len(devices)
+		}
+		batch := devices[:n]
+		devices = devices[n:]
+
+		m.mu.Lock()
+		ro.Stage = stage
+		m.mu.Unlock()
+
+		// ① 명령 접수
+		issuedAt := time.Now()
+		for _, dev := range batch {
+			res := DeviceResult{Stage: stage, Status: StatusQueued}
+			cmd, err := m.dispatcher.Submit(ctx, dev, ro.Spec.Action, ro.Spec.KW10)
+			if err != nil {
+				res.Status, res.Error = StatusFailed, err.Error()
+			}
+			res.CommandID = cmd.ID
+			m.setResult(ro, dev, res)
+		}
+
+		// ② 대기 (중단되면 이번 단계에 보낸 명령을 "aborted"로 완료하여 대기열에 남지 않게 함)
+		select {
+		case <-ctx.Done():
+			m.abortBatch(ctx, ro, batch)
+			m.finish(ro, RolloutAborted, "aborted")
+			return
+		case <-time.After(ro.Spec.StageWait):
+		}
+
+		// ③ 텔레메트리 검증
+		failed := 0
+		for _, dev := range batch {
+			m.mu.RLock()
+			res := ro.Results[dev]
+			m.mu.RUnlock()
+			if res.Status == StatusQueued {
+				if cmd, err := m.dispatcher.Get(res.CommandID); err == nil && cmd.Status.Terminal() {
+					res.Status, res.Error = cmd.Status, cmd.Error // 텔레메트리 확인 또는 확인 시간 초과로 이미 끝남 (confirm.go)
+				} else {
+					m.mu.RLock()
+					verr := m.verifyLocked(ro.Spec, dev, issuedAt)
+					m.mu.RUnlock()
+					res.Status = StatusSucceeded
+					if verr != nil {
+						res.Status, res.Error = StatusFailed, verr.Error()
+					}
+					_ = m.dispatcher.Complete(ctx, res.CommandID, verr)
+				}
+			}
+			if res.Status == StatusFailed {
+				failed++
+			}
+			m.setResult(ro, dev, res)
+		}
+
+		// ④ 실패율 검사
+		rate := float64(failed) / float64(len(batch))
+		m.log.Info("rollout stage done", zap.String("id", ro.ID), zap.Int("stage", stage), zap.Int("failed", failed), zap.Float64("error_rate", rate))
+		if limit := *ro.Spec.MaxErrorRate; rate > limit {
+			m.finish(ro, RolloutHalted, fmt.Sprintf("stage %d error rate %.2f exceeded %.2f", stage, rate, limit))
+			return
+		}
+	}
+	m.finish(ro, RolloutCompleted, "")
+}
+
+/*
+ * verifyLocked : issuedAt 이후 보고된 필드만으로 명령이 반영되었는지 판정 (호출자가 잠금을 잡고 있어야 함)
+ *  - 명령 이후 보고가 없거나, 모드·출력 필드를 판정할 수 없거나, 값이 어긋나면 에러
+ */
+func (m *RolloutManager) verifyLocked(spec RolloutSpec, dev string, issuedAt time.Time) error {
+	after := make(map[string]float64)
+	for k, sv := range m.lastSeen[dev] {
+		if sv.at.After(issuedAt) {
+			after[k] = sv.v
+		}
+	}
+	if len(after) == 0 {
+		return errors.New("no telemetry after command")
+	}
+	known, err := m.effect.verify(spec.Action, spec.KW10, after)
+	switch {
+	case !known:
+		return errors.New("telemetry after command does not report mode/power")
+	case err != nil:
+		return fmt.Errorf("command not applied: %w", err)
+	}
+	return nil
+}
+
+/*
+ * abortBatch : 검증 전에 중단된 단계의 명령을 실패("aborted")로 완료
+ *  - ctx는 이미 취소되었으므로 완료 알림(버스 발행)은 취소되지 않는 ctx로 보냄
+ */
+func (m *RolloutManager) abortBatch(ctx context.Context, ro *Rollout, batch []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, dev := range batch {
+		m.mu.RLock()
+		res := ro.Results[dev]
+		m.mu.RUnlock()
+		if res.Status != StatusQueued {
+			continue
+		}
+		res.Status, res.Error = StatusFailed, "aborted"
+		if res.CommandID != "" {
+			_ = m.dispatcher.Complete(ctx, res.CommandID, errors.New(res.Error))
+		}
+		m.setResult(ro, dev, res)
+	}
+}
+
+// setResult : 장치 결과를 기록하고 성공/실패 집계를 다시 계산
+func (m *RolloutManager) setResult(ro *Rollout, dev string, res DeviceResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ro.Results[dev] = res
+	ro.Succeeded, ro.Failed = 0, 0
+	for _, r := range ro.Results {
+		switch r.Status {
+		case StatusSucceeded:
+			ro.Succeeded++
+		case StatusFailed:
+			ro.Failed++
+		}
+	}
+}
+
+// finish : 배포를 종료 상태로 전환
+func (m *RolloutManager) finish(ro *Rollout, state RolloutState, reason string) {
+	m.mu.Lock()
+	ro.State = state
+	ro.Reason = reason
+	ro.EndedAt = time.Now()
+	m.mu.Unlock()
+
+	m.log.Info("rollout finished", zap.String("id", ro.ID), zap.String("state", string(state)), zap.String("reason", reason))
+}
+
+// pruneLocked : 끝난 지 보관 기간이 지난 배포 삭제 (호출자가 잠금을 잡고 있어야 함)
+func (m *RolloutManager) pruneLocked(now time.Time) {
+	for id, ro := range m.rollouts {
+		if ro.State != RolloutRunning && now.Sub(ro.EndedAt) > m.retention {
+			delete(m.rollouts, id)
+		}
+	}
+}
+
+// snapshot : 잠금을 잡고 배포 사본 생성
+func (m *RolloutManager) snapshot(ro *Rollout) Rollout {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshotLocked(ro)
+}
+
+// snapshotLocked : 배포 사본 생성 (호출자가 잠금을 잡고 있어야 함)
+func (m *RolloutManager) snapshotLocked(ro *Rollout) Rollout {
+	cp := *ro
+	cp.cancel = nil
+	cp.Results = make(map[string]DeviceResult, len(ro.Results))
+	for k, v := range ro.Results {
+		cp.Results[k] = v
+	}
+	return cp
+}
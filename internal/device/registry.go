@@ -0,0 +1,134 @@
+/*
+ * 장치 레지스트리 : 장치 ID별 메타데이터 (사이트, 모델, 펌웨어, 설치 위치, 그 밖의 태그)
+ *  - 수집 값에는 없는 장치의 고정 정보를 한 곳에 두고, 저장 전 태그 보강(infra.TagEnricher) 등에서 씁니다.
+ *  - APP_DEVICE_REGISTRY_FILE : 장치 ID → 메타데이터 JSON 객체 파일 (비어 있으면 빈 레지스트리)
+ *      예) {"A1": {"site": "resort-a", "model": "PV-3000", "firmware": "2.4.1", "location": "roof-east", "tags": {"zone": "north"}, "interval": "30s"}}
+ *      interval : 이 장치의 수집 주기 (비어 있으면 공급원 주기, Collector가 최소 주기를 검사)
+ *  - 파일은 시작할 때 한 번 읽으며, 형식이 잘못되면 Fatal
+ */
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// device 태그는 저장소가 장치 ID로 붙이므로 메타데이터 태그로 쓸 수 없음
+const reservedTag = "device"
+
+// Info : 장치 하나의 메타데이터 (비어 있는 항목은 태그로 붙이지 않음)
+type Info struct {
+	Site     string            `json:"site,omitempty"`
+	Model    string            `json:"model,omitempty"`
+	Firmware string            `json:"firmware,omitempty"`
+	Location string            `json:"location,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`     // 그 밖의 태그 (예: zone, owner)
+	Interval string            `json:"interval,omitempty"` // 수집 주기 (태그가 아님)
+}
+
+// Registry : 장치 ID → 메타데이터 (시작 후 바뀌지 않으므로 잠금 없이 읽음)
+type Registry struct {
+	devices   map[string]Info
+	intervals map[string]time.Duration // interval이 있는 장치만
+}
+
+/*
+ * NewRegistry : fx가 호출하는 장치 레지스트리 생성자
+ */
+func NewRegistry(log *zap.Logger) *Registry {
+	r := &Registry{devices: make(map[string]Info), intervals: make(map[string]time.Duration)}
+	path := config.String("APP_DEVICE_REGISTRY_FILE", "")
+	if path == "" {
+		return r
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("failed to read APP_DEVICE_REGISTRY_FILE", zap.String("path", path), zap.Error(err))
+	}
+	if err := json.Unmarshal(data, &r.devices); err != nil {
+		log.Fatal("invalid APP_DEVICE_REGISTRY_FILE", zap.String("path", path), zap.Error(err))
+	}
+	for id, info := range r.devices {
+		if err := info.validate(); err != nil {
+			log.Fatal("invalid APP_DEVICE_REGISTRY_FILE entry", zap.String("device", id), zap.Error(err))
+		}
+		if info.Interval != "" {
+			r.intervals[id], _ = time.ParseDuration(info.Interval)
+		}
+	}
+	log.Info("device registry loaded", zap.String("path", path), zap.Int("devices", len(r.devices)))
+	return r
+}
+
+// validate : 태그 이름 검사 (빈 이름, 예약 이름, 고정 항목과 겹치는 이름 금지)와 수집 주기 형식 검사
+func (i Info) validate() error {
+	if i.Interval != "" {
+		if d, err := time.ParseDuration(i.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid interval %q", i.Interval)
+		}
+	}
+	for k := range i.Tags {
+		switch k {
+		case "", reservedTag:
+			return fmt.Errorf("tag name %q is not allowed", k)
+		case "site", "model", "firmware", "location":
+			return fmt.Errorf("tag %q must be set as a top-level field", k)
+		}
+	}
+	return nil
+}
+
+// Get : 장치 메타데이터 (등록되지 않은 장치면 false)
+func (r *Registry) Get(deviceID string) (Info, bool) {
+	info, ok := r.devices[deviceID]
+	return info, ok
+}
+
+// IDs : 등록된 장치 ID 목록 (정렬)
+func (r *Registry) IDs() []string {
+	ids := make([]string, 0, len(r.devices))
+	for id := range r.devices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Intervals : 수집 주기를 지정한 장치 ID → 주기 (사본)
+func (r *Registry) Intervals() map[string]time.Duration {
+	out := make(map[string]time.Duration, len(r.intervals))
+	for id, d := range r.intervals {
+		out[id] = d
+	}
+	return out
+}
+
+/*
+ * EnrichTags : 장치 메타데이터를 저장 태그로 (infra.TagEnricher)
+ *  - site, model, firmware, location과 tags의 비어 있지 않은 값, 등록되지 않은 장치면 nil
+ */
+func (r *Registry) EnrichTags(deviceID string) map[string]string {
+	info, ok := r.devices[deviceID]
+	if !ok {
+		return nil
+	}
+	tags := make(map[string]string, 4+len(info.Tags))
+	for k, v := range info.Tags {
+		if v != "" {
+			tags[k] = v
+		}
+	}
+	for k, v := range map[string]string{"site": info.Site, "model": info.Model, "firmware": info.Firmware, "location": info.Location} {
+		if v != "" {
+			tags[k] = v
+		}
+	}
+	return tags
+}
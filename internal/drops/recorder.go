@@ -0,0 +1,130 @@
+/*
+ * Recorder : 파이프라인에서 버려지거나(drop), 잘리거나, 거절된 이벤트 기록기
+ *  - 사유 코드(Reason)별 카운터를 Prometheus 메트릭으로 노출합니다.
+ *      scaffold_events_dropped_total{reason="write_failed",source="influx"}
+ *  - 최근 기록을 고정 크기 링 버퍼에 보관하여 관리 서버(/drops)에서 조회할 수 있게 합니다.
+ *  - 목적 : "조용한 데이터 손실"을 진단 가능하게 만들기
+ */
+package drops
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 레지스트리
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// Reason : 드롭 사유 코드
+type Reason string
+
+const (
+	ReasonBackpressure Reason = "backpressure" // 큐가 가득 차서 버림
+	ReasonValidation   Reason = "validation"   // 형식/스키마 검증 실패
+	ReasonQuota        Reason = "quota"        // 속도 제한·할당량 초과로 거절
+	ReasonOversized    Reason = "oversized"    // 크기 제한 초과
+	ReasonWriteFailed  Reason = "write_failed" // 저장소 쓰기 실패로 유실
+	ReasonShutdown     Reason = "shutdown"     // 종료 중이라 처리하지 못함
+)
+
+/*
+ * Entry : 드롭 기록 하나
+ *  - Source : 드롭이 발생한 구성요소 (예: "influx", "bus", "http", "control")
+ *  - Detail : 사람이 읽을 수 있는 부가 설명 (에러 메시지 등)
+ */
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Reason   Reason    `json:"reason"`
+	Source   string    `json:"source"`
+	DeviceID string    `json:"device,omitempty"`
+	Detail   string    `json:"detail,omitempty"`
+}
+
+// Snapshot : 관리 API 응답용 현재 상태
+type Snapshot struct {
+	Counts map[string]uint64 `json:"counts"` // "source/reason" → 누적 횟수
+	Recent []Entry           `json:"recent"` // 최근 기록 (오래된 것부터)
+}
+
+// Recorder 구조체
+type Recorder struct {
+	log     *zap.Logger
+	counter *prometheus.CounterVec
+
+	mu     sync.Mutex
+	counts map[string]uint64
+	ring   []Entry // 고정 크기 링 버퍼
+	next   int     // 다음에 쓸 위치
+	full   bool    // 링 버퍼가 한 바퀴 이상 찼는지
+}
+
+/*
+ * NewRecorder : fx가 호출하는 Recorder 생성자
+ *  - APP_DROPS_RECENT : 보관할 최근 기록 수 (기본 100)
+ */
+func NewRecorder(log *zap.Logger, reg *prometheus.Registry) *Recorder {
+	size := config.Int(log, "APP_DROPS_RECENT", 100)
+	if size < 1 {
+		size = 1
+	}
+	r := &Recorder{
+		log: log,
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "events_dropped_total",
+			Help:      "Events dropped, truncated or rejected by the pipeline, by source and reason.",
+		}, []string{"source", "reason"}),
+		counts: make(map[string]uint64),
+		ring:   make([]Entry, size),
+	}
+	reg.MustRegister(r.counter)
+	return r
+}
+
+/*
+ * Record : 드롭 한 건 기록
+ *  - 카운터 증가 + 링 버퍼 추가 + Warn 로그
+ */
+func (r *Recorder) Record(source string, reason Reason, deviceID, detail string) {
+	r.counter.WithLabelValues(source, string(reason)).Inc()
+
+	e := Entry{Time: time.Now(), Reason: reason, Source: source, DeviceID: deviceID, Detail: detail}
+
+	r.mu.Lock()
+	r.counts[source+"/"+string(reason)]++
+	r.ring[r.next] = e
+	r.next = (r.next + 1) % len(r.ring)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+
+	r.log.Warn("event dropped",
+		zap.String("source", source),
+		zap.String("reason", string(reason)),
+		zap.String("device", deviceID),
+		zap.String("detail", detail))
+}
+
+/*
+ * Snapshot : 누적 카운트와 최근 기록(시간순) 사본 반환
+ */
+func (r *Recorder) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snap := Snapshot{Counts: make(map[string]uint64, len(r.counts))}
+	for k, v := range r.counts {
+		snap.Counts[k] = v
+	}
+	if r.full {
+		snap.Recent = append(snap.Recent, r.ring[r.next:]...)
+	}
+	snap.Recent = append(snap.Recent, r.ring[:r.next]...)
+	if snap.Recent == nil {
+		snap.Recent = []Entry{}
+	}
+	return snap
+}
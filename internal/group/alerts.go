@@ -0,0 +1,232 @@
+/*
+ * Alerter : 그룹 집계 값에 대한 경보 규칙 평가기
+ *  - 장치 하나의 값이 아니라 그룹 집계 값(사이트 전력 합계, 랙 평균 온도 등)에 임계치를 적용합니다.
+ *  - 구성 장치의 이벤트가 들어올 때마다 해당 그룹을 다시 집계하고 규칙을 평가합니다.
+ *  - 상태가 바뀔 때(정상 → 발생, 발생 → 해소)만 로그를 남기고, 현재 발생 중인 경보는 Active()로 조회합니다.
+ */
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 구성 장치 이벤트 구독
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/rules"  // 임계치 비교 (규칙 엔진 재사용)
+)
+
+/*
+ * AlertRule : 그룹 경보 규칙 하나
+ *  - Group : 대상 그룹 이름
+ *  - Field : 구성 장치의 필드 이름 (예: "power")
+ *  - Agg   : sum | avg | min | max
+ *  - Op    : gt | gte | lt | lte | eq (규칙 엔진과 동일)
+ */
+type AlertRule struct {
+	Name      string  `json:"name"`
+	Group     string  `json:"group"`
+	Field     string  `json:"field"`
+	Agg       string  `json:"agg"`
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+}
+
+// Alert : 발생 중인 경보
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Group     string    `json:"group"`
+	Field     string    `json:"field"` // "<필드>.<집계>"
+	Value     float64   `json:"value"`
+	Op        string    `json:"op"`
+	Threshold float64   `json:"threshold"`
+	Since     time.Time `json:"since"`
+}
+
+// Alerter 구조체
+type Alerter struct {
+	log    *zap.Logger
+	bus    *bus.EventBus // 경보 변화 이벤트(AlertChanged) 발행
+	groups *Registry
+	rules  []AlertRule
+
+	mu        sync.Mutex
+	active    map[string]Alert    // 규칙 이름 → 발생 중인 경보
+	listeners []func(Alert, bool) // 상태 변화 리스너 (웹훅 등)
+}
+
+/*
+ * NewAlerter : fx가 호출하는 Alerter 생성자
+ *  - APP_GROUP_ALERTS : 경보 규칙 JSON 배열
+ *      예) [{"name":"site-a-overload","group":"site-a","field":"power","agg":"sum","op":"gt","threshold":50}]
+ *  - 규칙이 없는 그룹·집계를 참조하거나 형식이 잘못되면 Fatal
+ *  - 구성 장치의 마지막 값으로 즉시 평가할 수 있도록 따라잡기(catch-up) 구독
+ */
+func NewAlerter(log *zap.Logger, b *bus.EventBus, g *Registry) *Alerter {
+	a := &Alerter{log: log, bus: b, groups: g, active: make(map[string]Alert)}
+	if raw := config.String("APP_GROUP_ALERTS", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &a.rules); err != nil {
+			log.Fatal("invalid APP_GROUP_ALERTS", zap.Error(err))
+		}
+	}
+	if err := a.validate(); err != nil {
+		log.Fatal("invalid APP_GROUP_ALERTS", zap.Error(err))
+	}
+	if len(a.rules) > 0 {
+		// 그룹에 속하지 않은 장치의 이벤트는 버스에서 걸러 고루틴을 쓰지 않음
+		b.Subscribe(a.onEvent, bus.WithCatchUp(), bus.WithPriority(bus.PriorityHigh),
+			bus.Where(func(e bus.DataCollectedEvent) bool { return len(a.groups.GroupsOf(e.DeviceID)) > 0 }))
+	}
+	return a
+}
+
+// validate : 규칙이 존재하는 그룹과 지원하는 집계·연산자를 쓰는지 검사
+func (a *Alerter) validate() error {
+	seen := make(map[string]bool, len(a.rules))
+	for i, r := range a.rules {
+		if r.Name == "" || seen[r.Name] {
+			return fmt.Errorf("rule %d: name is required and must be unique", i)
+		}
+		seen[r.Name] = true
+		if !a.groups.Has(r.Group) {
+			return fmt.Errorf("rule %q: unknown group %q", r.Name, r.Group)
+		}
+		okAgg := false
+		for _, agg := range aggregations {
+			okAgg = okAgg || r.Agg == agg
+		}
+		if !okAgg {
+			return fmt.Errorf("rule %q: unsupported agg %q", r.Name, r.Agg)
+		}
+		if err := r.policy().Validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// policy : 경보 규칙을 규칙 엔진의 단일 규칙 정책으로 변환
+func (r AlertRule) policy() rules.Policy {
+	return rules.Policy{Rules: []rules.Rule{{Name: r.Name, Field: r.aggField(), Op: r.Op, Threshold: r.Threshold, Action: "alert"}}}
+}
+
+// aggField : 집계 샘플에서의 필드 이름
+func (r AlertRule) aggField() string { return r.Field + "." + r.Agg }
+
+/*
+ * onEvent : 구성 장치 이벤트 수신 → 해당 장치가 속한 그룹들의 규칙 재평가
+ */
+func (a *Alerter) onEvent(ctx context.Context, e bus.DataCollectedEvent) {
+	for _, name := range a.groups.GroupsOf(e.DeviceID) {
+		a.evaluate(ctx, name)
+	}
+}
+
+/*
+ * evaluate : 그룹 하나를 집계하고 그 그룹의 규칙을 평가
+ *  - 집계 필드가 없으면(아직 해당 필드를 보고한 장치가 없으면) 상태를 바꾸지 않음
+ */
+func (a *Alerter) evaluate(ctx context.Context, name string) {
+	sample, _, err := a.groups.Aggregate(name)
+	if err != nil {
+		return
+	}
+
+	type change struct {
+		alert  Alert
+		firing bool
+	}
+	var changes []change
+
+	a.mu.Lock()
+	for _, r := range a.rules {
+		if r.Group != name {
+			continue
+		}
+		v, ok := sample.Values[r.aggField()]
+		if !ok {
+			continue
+		}
+		_, firing := r.policy().Evaluate(sample)
+		_, wasFiring := a.active[r.Name]
+		switch {
+		case firing && !wasFiring:
+			al := Alert{Rule: r.Name, Group: name, Field: r.aggField(), Value: v, Op: r.Op, Threshold: r.Threshold, Since: sample.Time}
+			a.active[r.Name] = al
+			changes = append(changes, change{alert: al, firing: true})
+			a.log.Warn("group alert firing", zap.String("rule", r.Name), zap.String("group", name),
+				zap.String("field", r.aggField()), zap.Float64("value", v), zap.Float64("threshold", r.Threshold))
+		case firing:
+			al := a.active[r.Name]
+			al.Value = v
+			a.active[r.Name] = al
+		case wasFiring:
+			al := a.active[r.Name]
+			al.Value = v
+			delete(a.active, r.Name)
+			changes = append(changes, change{alert: al, firing: false})
+			a.log.Info("group alert resolved", zap.String("rule", r.Name), zap.String("group", name), zap.Float64("value", v))
+		}
+	}
+	listeners := a.listeners
+	a.mu.Unlock()
+
+	// 리스너는 잠금 밖에서 호출 (리스너가 Active()를 불러도 교착되지 않도록)
+	for _, c := range changes {
+		bus.Publish(ctx, a.bus, AlertChanged{Alert: c.alert, Firing: c.firing})
+		for _, fn := range listeners {
+			fn(c.alert, c.firing)
+		}
+	}
+}
+
+/*
+ * OnChange : 경보가 발생(firing=true)하거나 해소(firing=false)될 때 호출될 함수 등록
+ */
+func (a *Alerter) OnChange(fn func(alert Alert, firing bool)) {
+	a.mu.Lock()
+	a.listeners = append(a.listeners, fn)
+	a.mu.Unlock()
+}
+
+// Rules : 설정된 경보 규칙 목록
+func (a *Alerter) Rules() []AlertRule { return a.rules }
+
+/*
+ * Active : 발생 중인 경보 목록 (발생 시각 순)
+ */
+func (a *Alerter) Active() []Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]Alert, 0, len(a.active))
+	for _, al := range a.active {
+		out = append(out, al)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Since.Before(out[j].Since) })
+	return out
+}
+
+/*
+ * AlertChanged : 경보 발생/해소 이벤트 (bus.Publish[AlertChanged]로 발행)
+ *  - Firing : true면 발생, false면 해소
+ */
+type AlertChanged struct {
+	Alert  Alert
+	Firing bool
+}
+
+// EventKey : 규칙별 마지막 상태를 따라잡기용으로 보관
+func (e AlertChanged) EventKey() string { return e.Alert.Rule }
+
+// Topic : 발생이면 alert.firing, 해소면 alert.resolved
+func (e AlertChanged) Topic() string {
+	if e.Firing {
+		return "alert.firing"
+	}
+	return "alert.resolved"
+}
@@ -0,0 +1,167 @@
+/*
+ * 장치 그룹과 가상 장치(virtual device) 집계
+ *  - 여러 물리 장치를 묶은 그룹(사이트, 랙 등)을 하나의 가상 장치로 보고, 구성 장치들의 마지막 값을 집계합니다.
+ *  - 집계 결과는 "<필드>.<집계>" 이름의 값을 가진 telemetry.Sample 입니다.
+ *      예) site-a 그룹 : {"power.sum": 42.0, "power.avg": 14.0, "temp.max": 31.5, ...}
+ *  - 가상 장치 ID는 "group:<그룹 이름>" 형식이므로 물리 장치 ID와 겹치지 않습니다.
+ */
+package group
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"       // 장치별 마지막 이벤트
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/telemetry" // 집계 결과 샘플
+)
+
+// 지원하는 집계 함수
+var aggregations = []string{"sum", "avg", "min", "max"}
+
+// VirtualID : 그룹 이름 → 가상 장치 ID
+func VirtualID(name string) string { return "group:" + name }
+
+/*
+ * Registry : 그룹 정의와 집계
+ *  - groups : 그룹 이름 → 구성 장치 ID 목록
+ *  - member : 장치 ID → 속한 그룹 이름 목록 (이벤트가 어느 그룹에 영향을 주는지 찾을 때 사용)
+ */
+type Registry struct {
+	bus    *bus.EventBus
+	groups map[string][]string
+	member map[string][]string
+}
+
+/*
+ * NewRegistry : fx가 호출하는 Registry 생성자
+ *  - APP_DEVICE_GROUPS : "그룹:장치|장치,그룹:장치|장치" 형식 (예: "site-a:A1|A2|A3,rack-1:B1|B2")
+ *  - 형식이 잘못되면 Fatal
+ */
+func NewRegistry(log *zap.Logger, b *bus.EventBus) *Registry {
+	groups, err := ParseGroups(config.List("APP_DEVICE_GROUPS", nil))
+	if err != nil {
+		log.Fatal("invalid APP_DEVICE_GROUPS", zap.Error(err))
+	}
+	r := &Registry{bus: b, groups: groups, member: make(map[string][]string)}
+	for name, devices := range groups {
+		for _, d := range devices {
+			r.member[d] = append(r.member[d], name)
+		}
+	}
+	log.Info("device groups configured", zap.Strings("groups", r.Names()))
+	return r
+}
+
+/*
+ * ParseGroups : "그룹:장치|장치" 항목 목록 파싱
+ */
+func ParseGroups(items []string) (map[string][]string, error) {
+	out := make(map[string][]string, len(items))
+	for _, item := range items {
+		name, list, ok := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("group entry %q: expected name:dev|dev", item)
+		}
+		if _, dup := out[name]; dup {
+			return nil, fmt.Errorf("group %q defined twice", name)
+		}
+		var devices []string
+		for _, d := range strings.Split(list, "|") {
+			if d = strings.TrimSpace(d); d != "" {
+				devices = append(devices, d)
+			}
+		}
+		if len(devices) == 0 {
+			return nil, fmt.Errorf("group %q has no devices", name)
+		}
+		out[name] = devices
+	}
+	return out, nil
+}
+
+// Names : 그룹 이름 목록 (정렬)
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.groups))
+	for n := range r.groups {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has : 그룹 존재 여부
+func (r *Registry) Has(name string) bool {
+	_, ok := r.groups[name]
+	return ok
+}
+
+// Members : 그룹의 구성 장치 목록
+func (r *Registry) Members(name string) []string { return r.groups[name] }
+
+// GroupsOf : 장치가 속한 그룹 이름 목록
+func (r *Registry) GroupsOf(deviceID string) []string { return r.member[deviceID] }
+
+/*
+ * Aggregate : 그룹 구성 장치들의 마지막 값을 집계한 가상 장치 샘플
+ *  - 값을 보고한 적 없는 장치는 집계에서 제외 (Reporting 필드로 보고 장치 수 확인)
+ *  - 필드마다 해당 필드를 보고한 장치만으로 sum/avg/min/max 계산
+ */
+func (r *Registry) Aggregate(name string) (telemetry.Sample, int, error) {
+	devices, ok := r.groups[name]
+	if !ok {
+		return telemetry.Sample{}, 0, fmt.Errorf("unknown group %q", name)
+	}
+	in := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		in[d] = true
+	}
+
+	var events []bus.DataCollectedEvent
+	for _, e := range r.bus.CatchUp() {
+		if in[e.DeviceID] {
+			events = append(events, e)
+		}
+	}
+	return telemetry.Sample{Time: time.Now(), DeviceID: VirtualID(name), Values: aggregate(events)}, len(events), nil
+}
+
+// aggregate : 이벤트 목록의 필드별 sum/avg/min/max
+func aggregate(events []bus.DataCollectedEvent) map[string]float64 {
+	type acc struct {
+		sum, min, max float64
+		n             int
+	}
+	accs := make(map[string]*acc)
+	for _, e := range events {
+		for k, v := range e.Values {
+			a, ok := accs[k]
+			if !ok {
+				a = &acc{min: v, max: v}
+				accs[k] = a
+			}
+			a.sum += v
+			a.n++
+			if v < a.min {
+				a.min = v
+			}
+			if v > a.max {
+				a.max = v
+			}
+		}
+	}
+
+	out := make(map[string]float64, len(accs)*len(aggregations))
+	for k, a := range accs {
+		out[k+".sum"] = a.sum
+		out[k+".avg"] = a.sum / float64(a.n)
+		out[k+".min"] = a.min
+		out[k+".max"] = a.max
+	}
+	return out
+}
@@ -0,0 +1,258 @@
+/*
+ * AdminServer : 운영/진단용 HTTP 서버
+ *  - 공개 API 서버(Server)와 별도의 리스너(기본 127.0.0.1:6060)에서만 동작하는 두 번째 *http.Server 입니다.
+ *  - 운영 엔드포인트를 공개 API 표면에서 분리합니다.
+ *      /metrics       : Prometheus 메트릭
+ *      /config        : 현재 적용된 APP_* 환경변수 (비밀 값은 가림)
+ *      /loglevel      : 로그 레벨 조회(GET) / 변경(PUT {"level":"debug"})
+ *      /drops         : 사유별 드롭 카운트와 최근 드롭 기록
+ *      /deadletters   : 이벤트 버스 데드레터 큐 조회, 재전송(POST /deadletters/{id}/replay), 삭제(DELETE)
+ *      /modules       : Supervisor가 감독 중인 모듈의 상태와 재시작 횟수
+ *      /schemas       : 저널/브리지 직렬화용 이벤트 스키마 레지스트리 (타입 이름, 현재 스키마 버전)
+ *      /debug/pprof/  : Go 런타임 프로파일 (APP_PPROF_ENABLED=true일 때만, edge 빌드에는 미포함)
+ *  - APP_ADMIN_ENABLED=false이면 리스너 자체를 열지 않습니다.
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"                         // HTTP 라우팅을 위한 Gorilla Mux
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 레지스트리
+	"go.uber.org/fx"                                 // Fx 프레임워크를 통한 라이프사이클 관리
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/audit"      // 감사 이벤트 기록
+	"generic-api-scaffold/internal/bus"        // 이벤트 버스 (데드레터 큐)
+	"generic-api-scaffold/internal/codec"      // 이벤트 스키마 레지스트리
+	"generic-api-scaffold/internal/config"     // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"      // 드롭 기록
+	"generic-api-scaffold/internal/supervisor" // 모듈 재시작 감독
+)
+
+// 값을 가려야 하는 환경변수 이름에 포함되는 단어
+var secretKeyMarkers = []string{"PASSWORD", "SECRET", "TOKEN", "KEY", "CREDS", "CREDENTIALS"}
+
+// 이름으로는 가리지 않는 값 안의 비밀 (DSN·URL의 user:password@, key=value DSN·쿼리의 password=, token= 등)
+var (
+	secretUserinfo = regexp.MustCompile(`([A-Za-z][A-Za-z0-9+.-]*://)[^/@\s"',]+@`)
+	secretParam    = regexp.MustCompile(`(?i)\b(password|passwd|pwd|pass|secret|token|access_token|api_key|apikey)=([^\s&;"',]+)`)
+)
+
+/*
+ * AdminParams : NewAdminServer가 fx로부터 주입받는 의존성 묶음
+ *  - Level   : 런타임에 변경 가능한 로그 레벨 (NewLogger가 제공)
+ *  - Metrics : /metrics로 노출할 레지스트리
+ */
+type AdminParams struct {
+	fx.In
+
+	Log     *zap.Logger
+	Level   zap.AtomicLevel
+	Metrics *prometheus.Registry
+	Drops   *drops.Recorder
+	Bus     *bus.EventBus
+	Modules *supervisor.Supervisor
+	Audit   *audit.Recorder
+	Schemas *codec.Registry
+}
+
+// AdminServer : 운영/진단용 HTTP 서버 컨테이너
+type AdminServer struct {
+	log    *zap.Logger  // 로깅 도구
+	router *mux.Router  // 관리용 라우터
+	srv    *http.Server // 실제 HTTP 서버
+	addr   string       // 리스닝 주소 (예: 127.0.0.1:6060)
+	listen listenSpec   // 리스닝 대상 (TCP 주소 또는 유닉스 소켓)
+
+	drops       *drops.Recorder        // 드롭 기록기 (/drops)
+	deadLetters *bus.DeadLetterQueue   // 이벤트 버스 데드레터 큐 (/deadletters)
+	modules     *supervisor.Supervisor // 모듈 감독자 (/modules)
+	schemas     *codec.Registry        // 이벤트 스키마 레지스트리 (/schemas)
+
+	enabled bool // 관리 서버 활성화 여부
+	pprof   bool // pprof 핸들러 활성화 여부
+}
+
+/*
+ * NewAdminServer : AdminServer 생성자
+ *  - APP_ADMIN_ENABLED : 관리 서버 활성화 (기본 true)
+ *  - APP_ADMIN_ADDR    : 관리 서버 주소 (기본 127.0.0.1:6060, 외부 인터페이스에 바인딩하지 않도록 주의)
+ *  - APP_ADMIN_SOCKET  : 지정하면 TCP 대신 이 경로의 유닉스 소켓에서만 리스닝 (권한: APP_ADMIN_SOCKET_MODE, 기본 660)
+ *  - APP_PPROF_ENABLED : pprof 핸들러 활성화 (기본 false)
+ */
+func NewAdminServer(p AdminParams) *AdminServer {
+	log := p.Log
+	a := &AdminServer{
+		log:         log,
+		router:      mux.NewRouter(),
+		drops:       p.Drops,
+		deadLetters: p.Bus.DeadLetters(),
+		modules:     p.Modules,
+		schemas:     p.Schemas,
+		addr:        config.String("APP_ADMIN_ADDR", "127.0.0.1:6060"),
+		enabled:     config.Bool(log, "APP_ADMIN_ENABLED", true),
+		pprof:       config.Bool(log, "APP_PPROF_ENABLED", false),
+	}
+	a.listen = socketSpec(log, "APP_ADMIN", a.addr)
+
+	// === 라우팅 등록 ===
+	a.router.Handle("/metrics", metricsHandler(p.Metrics)).Methods(http.MethodGet)
+	a.router.HandleFunc("/config", a.handleConfig).Methods(http.MethodGet)
+	a.router.HandleFunc("/drops", a.handleDrops).Methods(http.MethodGet)
+	a.router.HandleFunc("/deadletters", a.handleDeadLetters).Methods(http.MethodGet)
+	a.router.HandleFunc("/deadletters/{id}/replay", a.handleDeadLetterReplay).Methods(http.MethodPost)
+	a.router.HandleFunc("/deadletters/{id}", a.handleDeadLetterDiscard).Methods(http.MethodDelete)
+	a.router.HandleFunc("/modules", a.handleModules).Methods(http.MethodGet)
+	a.router.HandleFunc("/schemas", a.handleSchemas).Methods(http.MethodGet)
+	// zap.AtomicLevel은 GET(조회)/PUT(변경)을 처리하는 http.Handler를 내장
+	a.router.Handle("/loglevel", p.Level).Methods(http.MethodGet)
+	a.router.Handle("/loglevel", auditLevelChange(p.Audit, p.Level)).Methods(http.MethodPut)
+
+	if a.pprof && !pprofAvailable {
+		log.Warn("APP_PPROF_ENABLED ignored: pprof is not included in this build profile")
+		a.pprof = false
+	}
+	if a.pprof {
+		mountPprof(a.router)
+	}
+	return a
+}
+
+/*
+ * RegisterAdminHooks : 관리 서버 시작/종료 훅 등록
+ *  - 공개 API 서버와 독립된 자체 훅을 가지므로, 한쪽의 설정 변경이 다른 쪽에 영향을 주지 않음
+ *  - 비활성화되어 있으면 리스너를 열지 않음
+ */
+func RegisterAdminHooks(lc fx.Lifecycle, a *AdminServer) {
+	if !a.enabled {
+		return
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := a.listen.listen()
+			if err != nil {
+				return fmt.Errorf("admin listen on %s: %w", a.listen, err)
+			}
+			a.srv = &http.Server{
+				Addr:              a.listen.Addr,
+				Handler:           a.router,
+				ReadHeaderTimeout: 5 * time.Second,
+				// CPU 프로파일/트레이스는 수십 초 동안 응답을 쓰므로 WriteTimeout을 넉넉히 둔다
+				WriteTimeout: 2 * time.Minute,
+				IdleTimeout:  60 * time.Second,
+			}
+			go func() {
+				a.log.Info("admin server starting", zap.Stringer("listen", a.listen), zap.Bool("pprof", a.pprof))
+				if err := a.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+					a.log.Error("admin server error", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			a.log.Info("admin server stopping")
+			shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return a.srv.Shutdown(shutdownCtx)
+		},
+	})
+}
+
+/*
+ * handleConfig : 현재 프로세스의 APP_* 환경변수 덤프
+ *  - 이름에 PASSWORD/SECRET/TOKEN/KEY/CREDS가 포함된 값은 "***"로 가림
+ *  - 그 밖의 값도 URL·DSN 안의 인증 정보는 가림 (redactValue, 예: APP_POSTGRES_DSN, APP_REDIS_URL, APP_NATS_URL)
+ */
+func (a *AdminServer) handleConfig(w http.ResponseWriter, r *http.Request) {
+	out := make(map[string]string)
+	for _, kv := range os.Environ() {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(k, "APP_") {
+			continue
+		}
+		if isSecretKey(k) && v != "" {
+			v = "***"
+		}
+		out[k] = redactValue(v)
+	}
+
+	// JSON 객체는 키 순서가 보장되지 않으므로 정렬된 키 목록도 함께 제공
+	keys := make([]string, 0, len(out))
+	for k := range out {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	respond(w, r, http.StatusOK, map[string]interface{}{"keys": keys, "values": out})
+}
+
+/*
+ * handleDrops : 사유별 드롭 카운트와 최근 드롭 기록 조회
+ */
+func (a *AdminServer) handleDrops(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, http.StatusOK, a.drops.Snapshot())
+}
+
+/*
+ * handleModules : 감독 중인 모듈의 상태 조회
+ */
+func (a *AdminServer) handleModules(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, http.StatusOK, a.modules.Statuses())
+}
+
+/*
+ * handleSchemas : 등록된 이벤트 스키마 목록 조회
+ */
+func (a *AdminServer) handleSchemas(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, http.StatusOK, a.schemas.Schemas())
+}
+
+/*
+ * auditLevelChange : 로그 레벨 변경(PUT /loglevel)을 감사 이벤트로 남기는 래퍼
+ */
+func auditLevelChange(rec *audit.Recorder, level zap.AtomicLevel) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		before := level.String()
+		sr := &statusRecorder{ResponseWriter: w}
+		level.ServeHTTP(sr, r)
+
+		e := audit.Event{
+			Category: audit.CategoryConfig,
+			Action:   "loglevel.change",
+			Outcome:  audit.OutcomeSuccess,
+			Severity: 3,
+			Source:   r.RemoteAddr,
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			Status:   sr.status,
+			Detail:   before + " -> " + level.String(),
+		}
+		if sr.status >= 400 {
+			e.Outcome, e.Severity = audit.OutcomeFailure, 5
+		}
+		rec.Record(e)
+	})
+}
+
+// redactValue : 값 안의 URL 인증 정보(user:password@)와 비밀 매개변수(password=... 등)를 "***"로 (목록·JSON 값 안도 포함)
+func redactValue(v string) string {
+	v = secretUserinfo.ReplaceAllString(v, "${1}***@")
+	return secretParam.ReplaceAllString(v, "${1}=***")
+}
+
+// isSecretKey : 값을 가려야 하는 환경변수인지 판단
+func isSecretKey(k string) bool {
+	for _, m := range secretKeyMarkers {
+		if strings.Contains(k, m) {
+			return true
+		}
+	}
+	return false
+}
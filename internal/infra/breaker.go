@@ -0,0 +1,225 @@
+/*
+ * 시계열 저장소 서킷 브레이커 : InfluxDB가 내려가 있는 동안 쓰기마다 타임아웃(APP_INFLUX_TIMEOUT)을 기다리지 않도록 합니다.
+ *  - closed    : 정상. 쓰기가 연속 APP_INFLUX_BREAKER_FAILURES번 실패하면 open
+ *  - open      : 쓰기를 저장소로 보내지 않고 대기시킴 → 이벤트는 버퍼(버스 구독자 큐 APP_INFLUX_BUFFER 또는 저널)에 쌓임
+ *                APP_INFLUX_BREAKER_COOLDOWN마다 Ping으로 탐침하여 성공하면 half-open, 실패하면 다시 open
+ *  - half-open : 쓰기를 다시 보내 보고, 성공하면 closed(쌓인 버퍼부터 기록), 실패하면 바로 open
+ *  - 쓰기(WritePoint, WriteBatch)에만 적용되며 조회와 readiness Ping은 그대로 저장소로 보냅니다.
+ *  - 상태 전환은 로그와 scaffold_influx_breaker_state / scaffold_influx_breaker_transitions_total{to}로 남깁니다.
+ */
+package infra
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 브레이커 상태 메트릭
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/telemetry" // 기록 단위 샘플
+)
+
+// breakerState : 서킷 브레이커 상태 (메트릭 값 순서)
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerHalfOpen:
+		return "half_open"
+	case breakerOpen:
+		return "open"
+	}
+	return "closed"
+}
+
+/*
+ * circuitBreaker : 연속 실패 수 기반 서킷 브레이커
+ *  - changed는 상태가 바뀔 때마다 닫고 새로 만듦 (대기 중인 쓰기를 깨움)
+ */
+type circuitBreaker struct {
+	log         *zap.Logger
+	threshold   int
+	cooldown    time.Duration
+	probe       func(ctx context.Context) error
+	state       prometheus.Gauge
+	transitions *prometheus.CounterVec
+
+	mu       sync.Mutex
+	current  breakerState
+	failures int       // 연속 실패 수
+	retryAt  time.Time // open 상태에서 다음 탐침 시각
+	probing  bool      // 탐침 진행 중 (한 번에 하나만)
+	changed  chan struct{}
+}
+
+/*
+ * breakerStore : 쓰기를 서킷 브레이커로 감싼 TimeSeriesStore
+ */
+type breakerStore struct {
+	TimeSeriesStore
+	br *circuitBreaker
+}
+
+/*
+ * withBreaker : 저장소 쓰기에 서킷 브레이커 적용
+ *  - APP_INFLUX_BREAKER_FAILURES : open으로 바꾸는 연속 실패 수 (기본 5, 0이면 브레이커 없음)
+ *  - APP_INFLUX_BREAKER_COOLDOWN : open 상태에서 탐침 간격 (기본 10s)
+ */
+func withBreaker(log *zap.Logger, reg *prometheus.Registry, s TimeSeriesStore) TimeSeriesStore {
+	threshold := config.Int(log, "APP_INFLUX_BREAKER_FAILURES", 5)
+	cooldown := config.Duration(log, "APP_INFLUX_BREAKER_COOLDOWN", 10*time.Second)
+	if threshold < 0 || cooldown <= 0 {
+		log.Fatal("APP_INFLUX_BREAKER_FAILURES must not be negative and APP_INFLUX_BREAKER_COOLDOWN must be positive",
+			zap.Int("failures", threshold), zap.Duration("cooldown", cooldown))
+	}
+	if threshold == 0 {
+		return s
+	}
+
+	br := &circuitBreaker{
+		log:       log,
+		threshold: threshold,
+		cooldown:  cooldown,
+		probe:     s.Ping,
+		state: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "influx_breaker_state",
+			Help:      "Time-series store circuit breaker state (0 closed, 1 half-open, 2 open).",
+		}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "influx_breaker_transitions_total",
+			Help:      "Time-series store circuit breaker state transitions, by new state.",
+		}, []string{"to"}),
+		changed: make(chan struct{}),
+	}
+	reg.MustRegister(br.state, br.transitions)
+	return &breakerStore{TimeSeriesStore: s, br: br}
+}
+
+// WritePoint : 브레이커를 거쳐 샘플 하나 기록
+func (s *breakerStore) WritePoint(ctx context.Context, p telemetry.Sample) error {
+	return s.WriteBatch(ctx, []telemetry.Sample{p})
+}
+
+// WriteBatch : 브레이커가 허용할 때까지 기다린 뒤 기록하고 결과를 브레이커에 알림
+func (s *breakerStore) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := s.br.wait(ctx); err != nil {
+		return err
+	}
+	err := s.TimeSeriesStore.WriteBatch(ctx, ss)
+	s.br.done(ctx, err)
+	return err
+}
+
+/*
+ * wait : 쓰기를 보내도 될 때까지 대기
+ *  - closed/half-open이면 바로 반환
+ *  - open이면 탐침 시각까지 기다렸다가 Ping으로 탐침 (다른 쓰기는 상태가 바뀔 때까지 대기)
+ *  - ctx가 끝나면 ctx.Err() (종료 중이면 쓰지 않고 버퍼/저널에 남김)
+ */
+func (b *circuitBreaker) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		if b.current != breakerOpen {
+			b.mu.Unlock()
+			return nil
+		}
+		changed, left := b.changed, time.Until(b.retryAt)
+		if left <= 0 && !b.probing {
+			b.probing = true
+			b.mu.Unlock()
+			b.runProbe(ctx)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			continue
+		}
+		b.mu.Unlock()
+
+		if left <= 0 {
+			left = b.cooldown // 다른 쓰기가 탐침 중이면 상태가 바뀔 때까지 대기
+		}
+		t := time.NewTimer(left)
+		select {
+		case <-changed:
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+		t.Stop()
+	}
+}
+
+// runProbe : Ping으로 탐침하여 성공하면 half-open, 실패하면 다음 탐침 시각을 미룸
+func (b *circuitBreaker) runProbe(ctx context.Context) {
+	pctx, cancel := context.WithTimeout(ctx, b.cooldown)
+	err := b.probe(pctx)
+	cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err != nil {
+		if ctx.Err() == nil {
+			b.retryAt = time.Now().Add(b.cooldown)
+			b.log.Debug("influx breaker probe failed", zap.Error(err))
+		}
+		return
+	}
+	b.transition(breakerHalfOpen)
+}
+
+/*
+ * done : 쓰기 결과 반영
+ *  - 성공 : 연속 실패 수 초기화, half-open이면 closed
+ *  - 실패 : 연속 실패 수 증가, 상한에 닿았거나 half-open이면 open
+ *  - ctx 취소/마감으로 인한 실패(종료 중)는 저장소 상태와 무관하므로 반영하지 않음
+ */
+func (b *circuitBreaker) done(ctx context.Context, err error) {
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.failures = 0
+		if b.current != breakerClosed {
+			b.transition(breakerClosed)
+		}
+		return
+	}
+	b.failures++
+	if b.current == breakerHalfOpen || (b.current == breakerClosed && b.failures >= b.threshold) {
+		b.retryAt = time.Now().Add(b.cooldown)
+		b.transition(breakerOpen)
+	}
+}
+
+// transition : 상태 전환 기록 + 대기 중인 쓰기 깨움 (호출자가 b.mu를 잡고 있어야 함)
+func (b *circuitBreaker) transition(to breakerState) {
+	from := b.current
+	b.current = to
+	b.state.Set(float64(to))
+	b.transitions.WithLabelValues(to.String()).Inc()
+	close(b.changed)
+	b.changed = make(chan struct{})
+
+	fields := []zap.Field{zap.Stringer("from", from), zap.Stringer("to", to)}
+	if to == breakerOpen {
+		b.log.Warn("influx breaker opened, holding writes in buffer",
+			append(fields, zap.Int("failures", b.failures), zap.Duration("cooldown", b.cooldown))...)
+		return
+	}
+	b.log.Info("influx breaker state changed", fields...)
+}
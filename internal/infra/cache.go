@@ -0,0 +1,259 @@
+/*
+ * 읽기 API 응답 캐시 미들웨어
+ *  - 대시보드 폴링이 몰릴 때 Influx/집계 계산을 보호하기 위해 GET 응답을 메모리에 잠깐 보관합니다.
+ *  - 캐시 대상은 라우트 이름(Route.Name)별 TTL로 지정합니다. (기본: devices.list, devices.latest, groups.list, groups.get, alerts.list 각 2s)
+ *  - 키 : 라우트 + 경로 + 정렬된 쿼리 + 협상된 응답 형식(Accept) + 인증 주체(토큰 이름, 없으면 익명)
+ *  - 무효화 : 새 장치가 처음 보고하면 장치 관련 라우트를 모두 비움 (invalidate로 직접 비울 수도 있음)
+ *            이미 본 장치가 보고하면(갱신) 장치 목록(/api/devices)과 그 장치의 최신값(/api/devices/{id}/latest),
+ *            그리고 장치 값을 집계하는 그룹·경보 라우트(groups.list, groups.get, alerts.list)를 비움
+ *            (invalidateDevice : 장치 갱신/삭제 시 부르는 훅)
+ *  - 요청에 Cache-Control: no-cache가 있으면 캐시를 건너뛰고 새 응답으로 갱신합니다.
+ *  - 응답 헤더 X-Cache : HIT | MISS
+ */
+package infra
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux" // 현재 라우트 이름 조회
+	"go.uber.org/zap"        // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 장치 변화 감지
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// deviceRoutes : 장치 목록/값이 바뀌면 무효화할 라우트
+var deviceRoutes = []string{"devices.list", "devices.latest", "groups.list", "groups.get", "alerts.list"}
+
+// cacheEntry : 캐시된 응답 하나
+type cacheEntry struct {
+	route   string
+	path    string // 요청 경로 (장치별 무효화용)
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+/*
+ * responseCache : 라우트별 TTL 응답 캐시
+ *  - ttls       : 라우트 이름 → TTL (없는 라우트는 캐시하지 않음)
+ *  - maxEntries : 최대 항목 수 (가득 차면 만료된 항목을 먼저 비우고, 그래도 가득 차면 저장하지 않음)
+ */
+type responseCache struct {
+	ttls       map[string]time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	devices map[string]bool // 이미 본 장치 (새 장치 감지용)
+}
+
+/*
+ * newResponseCache : 환경변수로부터 응답 캐시 생성
+ *  - APP_HTTP_CACHE_TTLS        : 라우트별 TTL (예: "devices.list=5s,groups.get=0s", 0이면 해당 라우트 캐시 끔, 지정하지 않은 deviceRoutes는 2s)
+ *  - APP_HTTP_CACHE_MAX_ENTRIES : 최대 항목 수 (기본 1024)
+ *  - 버스를 구독해 새 장치가 나타나면 장치 관련 라우트를 무효화
+ */
+func newResponseCache(log *zap.Logger, b *bus.EventBus) *responseCache {
+	c := &responseCache{
+		ttls:       make(map[string]time.Duration, len(deviceRoutes)),
+		maxEntries: config.Int(log, "APP_HTTP_CACHE_MAX_ENTRIES", 1024),
+		entries:    make(map[string]*cacheEntry),
+		devices:    make(map[string]bool),
+	}
+	for _, name := range deviceRoutes {
+		c.ttls[name] = 2 * time.Second
+	}
+	for _, item := range config.List("APP_HTTP_CACHE_TTLS", nil) {
+		name, v, ok := strings.Cut(item, "=")
+		if !ok {
+			log.Fatal("invalid APP_HTTP_CACHE_TTLS entry", zap.String("entry", item))
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil || d < 0 {
+			log.Fatal("invalid APP_HTTP_CACHE_TTLS duration", zap.String("entry", item), zap.Error(err))
+		}
+		c.ttls[strings.TrimSpace(name)] = d
+	}
+	b.Subscribe(c.onEvent)
+	return c
+}
+
+// onEvent : 처음 보는 장치면 장치 관련 라우트 무효화, 이미 본 장치면 그 장치 값이 들어 있는 항목만 무효화
+func (c *responseCache) onEvent(_ context.Context, e bus.DataCollectedEvent) {
+	c.mu.Lock()
+	seen := c.devices[e.DeviceID]
+	c.devices[e.DeviceID] = true
+	c.mu.Unlock()
+	if !seen {
+		c.invalidate(deviceRoutes...)
+		return
+	}
+	c.invalidateDevice(e.DeviceID)
+}
+
+/*
+ * invalidateDevice : 장치 하나가 갱신되거나 삭제되었을 때 그 장치 값이 들어 있는 항목 제거
+ *  - devices.list : 장치 목록 전부
+ *  - devices.latest : 그 장치의 최신값(/api/devices/{id}/latest)만, 다른 장치의 최신값 항목은 유지
+ *  - groups.list, groups.get, alerts.list : 그룹 집계가 어느 장치를 포함하는지 캐시는 모르므로 전부
+ */
+func (c *responseCache) invalidateDevice(deviceID string) {
+	latest := "/api/devices/" + deviceID + "/latest" // 캐시 키의 경로는 디코딩된 r.URL.Path
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		switch e.route {
+		case "devices.latest":
+			if e.path == latest {
+				delete(c.entries, k)
+			}
+		case "devices.list", "groups.list", "groups.get", "alerts.list":
+			delete(c.entries, k)
+		}
+	}
+}
+
+/*
+ * invalidate : 지정한 라우트의 캐시 항목을 모두 제거 (라우트를 지정하지 않으면 전체)
+ */
+func (c *responseCache) invalidate(routes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(routes) == 0 {
+		c.entries = make(map[string]*cacheEntry)
+		return
+	}
+	drop := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		drop[r] = true
+	}
+	for k, e := range c.entries {
+		if drop[e.route] {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// get : 만료되지 않은 항목 조회
+func (c *responseCache) get(key string, now time.Time) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || now.After(e.expires) {
+		return nil, false
+	}
+	return e, true
+}
+
+// put : 항목 저장 (가득 차면 만료 항목 정리 후 재시도)
+func (c *responseCache) put(key string, e *cacheEntry, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.maxEntries {
+		for k, old := range c.entries {
+			if now.After(old.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+	c.entries[key] = e
+}
+
+/*
+ * cacheKey : 라우트 + 경로 + 정렬된 쿼리 + Accept + 인증 주체
+ *  - 쿼리는 url.Values.Encode로 정렬하여 같은 조회가 같은 키가 되도록 함
+ *  - 응답 인코딩(JSON/CBOR/MessagePack)이 Accept로 정해지므로 키에 포함
+ */
+func (s *Server) cacheKey(route string, r *http.Request) string {
+	subject := "-"
+	if p, err := s.auth.Identify(r); err == nil {
+		subject = p.Name
+	}
+	return strings.Join([]string{route, r.URL.Path, r.URL.Query().Encode(), r.Header.Get("Accept"), subject}, "\x00")
+}
+
+/*
+ * responseCacheMW : GET 응답 캐시 미들웨어
+ *  - TTL이 설정된 라우트의 GET 요청만 대상
+ *  - 200 응답만 저장
+ */
+func (s *Server) responseCacheMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if r.Method != http.MethodGet || route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		name := route.GetName()
+		ttl := s.cache.ttls[name]
+		if ttl <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := s.cacheKey(name, r)
+		now := time.Now()
+		if !strings.Contains(r.Header.Get("Cache-Control"), "no-cache") {
+			if e, ok := s.cache.get(key, now); ok {
+				for k, v := range e.header {
+					w.Header()[k] = slices.Clone(v) // 뒤쪽 미들웨어가 헤더를 고쳐도 캐시 항목은 그대로
+				}
+				w.Header().Set("X-Cache", "HIT")
+				w.WriteHeader(e.status)
+				w.Write(e.body)
+				return
+			}
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+		cw := &cacheWriter{ResponseWriter: w}
+		next.ServeHTTP(cw, r)
+		if cw.status == http.StatusOK {
+			header := w.Header().Clone()
+			header.Del("X-Cache")
+			s.cache.put(key, &cacheEntry{
+				route:   name,
+				path:    r.URL.Path,
+				status:  cw.status,
+				header:  header,
+				body:    cw.buf.Bytes(),
+				expires: now.Add(ttl),
+			}, now)
+		}
+	})
+}
+
+/*
+ * cacheWriter : 응답을 그대로 흘려보내면서 본문 사본을 모으는 ResponseWriter 래퍼
+ */
+type cacheWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (cw *cacheWriter) WriteHeader(code int) {
+	if cw.status == 0 {
+		cw.status = code
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *cacheWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	cw.buf.Write(b)
+	return cw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,396 @@
+//go:build !edge
+
+/*
+ * ClickHouseRepo : ClickHouse 저장소 (APP_STORE_BACKEND=clickhouse, 기본 빌드 전용)
+ *  - Influx 1.x로 감당하기 어려운 대량 수집·분석 부하용이며, 같은 저장소 인터페이스(InfluxStore)를 구현합니다.
+ *  - 테이블 하나 (APP_CLICKHOUSE_TABLE, 기본 telemetry), 없으면 시작 시 만듦
+ *      time DateTime64(9, 'UTC'), device LowCardinality(String), field LowCardinality(String), value Float64
+ *      ReplacingMergeTree, 정렬 키 (device, field, time), 월 파티션 (toYYYYMM(time)), 선택 TTL (APP_CLICKHOUSE_TTL)
+ *    같은 포인트를 다시 기록하면 백그라운드 병합 때 한 행으로 합쳐짐 (병합 전에는 조회의 count/sum에 중복이 보일 수 있음)
+ *  - 묶음 쓰기는 네이티브 프로토콜 배치 INSERT 한 번이며, 기본으로 서버 비동기 삽입(async_insert)을 켜서
+ *    여러 인스턴스의 작은 묶음을 서버가 모아 파트 하나로 기록합니다. (wait_for_async_insert=1 이라 실패는 에러로 돌아옴)
+ *  - 조회 집계는 SQL로 서버에서 계산 (clickhouseAggregates)
+ *  - 태그(고정 태그, 태그로 올린 값)는 저장하지 않으며, 라우팅 규칙과 관계없이 모든 장치를 같은 테이블에 기록합니다.
+ *    필드 타입 선언은 APP_INFLUX_MEASUREMENT 측정값 기준 (bool은 1/0, string 필드는 드롭 기록)
+ *  - edge 빌드에서는 clickhouse_edge.go가 사용되어 ClickHouse 클라이언트가 링크되지 않습니다.
+ */
+package infra
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"            // ClickHouse 네이티브 프로토콜 클라이언트
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver" // 연결/배치 인터페이스
+	"go.opentelemetry.io/otel/attribute"                // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"                    // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"                    // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                                    // Fx 프레임워크
+	"go.uber.org/zap"                                   // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+var _ InfluxStore = (*ClickHouseRepo)(nil)
+
+// clickhouseAggregates : 조회 집계 함수 (모두 Float64, first/last는 시각 기준 argMin/argMax)
+var clickhouseAggregates = map[telemetry.Aggregate]string{
+	telemetry.AggMean:  "avg(value)",
+	telemetry.AggMin:   "min(value)",
+	telemetry.AggMax:   "max(value)",
+	telemetry.AggSum:   "sum(value)",
+	telemetry.AggCount: "toFloat64(count())",
+	telemetry.AggFirst: "argMin(value, time)",
+	telemetry.AggLast:  "argMax(value, time)",
+}
+
+// ClickHouseRepo : MergeTree 테이블에 데이터를 쓰고 SQL로 읽는 저장소
+type ClickHouseRepo struct {
+	log    *zap.Logger
+	conn   driver.Conn
+	table  string
+	ttl    time.Duration
+	async  bool
+	schema influxSchema
+	drops  *drops.Recorder
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	ready bool // 이번 실행에서 테이블을 확인했는지
+}
+
+/*
+ * newClickHouseStore : ClickHouseRepo 생성자 (NewInfluxStore가 APP_STORE_BACKEND=clickhouse일 때 호출)
+ *  - APP_CLICKHOUSE_ADDR         : 네이티브 프로토콜 주소 host:port, 쉼표 구분 (필수, 예: clickhouse:9000)
+ *  - APP_CLICKHOUSE_DATABASE     : 데이터베이스 (기본 default, 미리 만들어 두어야 함)
+ *  - APP_CLICKHOUSE_USERNAME / APP_CLICKHOUSE_PASSWORD : 계정 (기본 default / 없음)
+ *  - APP_CLICKHOUSE_TABLE        : 테이블 이름 (기본 telemetry)
+ *  - APP_CLICKHOUSE_TTL          : 보존 기간 (기본 0, 지우지 않음, 테이블을 만들 때만 적용)
+ *  - APP_CLICKHOUSE_ASYNC_INSERT : 서버 비동기 삽입 사용 (기본 true)
+ *  - APP_CLICKHOUSE_TLS          : TLS 연결 (기본 false, 보통 포트 9440)
+ *  - APP_CLICKHOUSE_TIMEOUT      : 연결·조회 제한 시간 (기본 10s)
+ *  - OnStart 시 테이블 준비 (DB에 닿지 않아도 시작은 막지 않고 첫 쓰기에서 다시 시도) / OnStop 시 연결 종료
+ */
+func newClickHouseStore(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	addrs := config.List("APP_CLICKHOUSE_ADDR", nil)
+	if len(addrs) == 0 {
+		log.Fatal("APP_CLICKHOUSE_ADDR is required for APP_STORE_BACKEND=clickhouse")
+	}
+	ttl := config.Duration(log, "APP_CLICKHOUSE_TTL", 0)
+	timeout := config.Duration(log, "APP_CLICKHOUSE_TIMEOUT", 10*time.Second)
+	if ttl < 0 || timeout <= 0 {
+		log.Fatal("APP_CLICKHOUSE_TTL must not be negative and APP_CLICKHOUSE_TIMEOUT must be positive",
+			zap.Duration("ttl", ttl), zap.Duration("timeout", timeout))
+	}
+	opts := &clickhouse.Options{
+		Addr: addrs,
+		Auth: clickhouse.Auth{
+			Database: config.String("APP_CLICKHOUSE_DATABASE", "default"),
+			Username: config.String("APP_CLICKHOUSE_USERNAME", "default"),
+			Password: config.String("APP_CLICKHOUSE_PASSWORD", ""),
+		},
+		DialTimeout: timeout,
+		ReadTimeout: timeout,
+	}
+	if config.Bool(log, "APP_CLICKHOUSE_TLS", false) {
+		opts.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	conn, err := clickhouse.Open(opts) // 연결은 처음 쓸 때 맺음
+	if err != nil {
+		log.Fatal("invalid clickhouse settings", zap.Strings("addr", addrs), zap.Error(err))
+	}
+	repo := &ClickHouseRepo{
+		log:    log,
+		conn:   conn,
+		table:  config.String("APP_CLICKHOUSE_TABLE", "telemetry"),
+		ttl:    ttl,
+		async:  config.Bool(log, "APP_CLICKHOUSE_ASYNC_INSERT", true),
+		schema: loadInfluxSchema(log),
+		drops:  dr,
+		tracer: tp.Tracer(tracerName),
+	}
+	if len(repo.schema.routes) > 0 {
+		log.Warn("APP_INFLUX_ROUTES is ignored by the clickhouse store, all devices are written to one table", zap.String("table", repo.table))
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := repo.ensureTable(ctx); err != nil {
+				log.Error("clickhouse table not ready", zap.String("table", repo.table), zap.Error(err))
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return repo.Close()
+		},
+	})
+	log.Info("clickhouse store", zap.Strings("addr", addrs), zap.String("database", opts.Auth.Database),
+		zap.String("table", repo.table), zap.Bool("async_insert", repo.async))
+	return repo
+}
+
+/*
+ * ensureTable : 테이블이 없으면 만듦 (이번 실행에서 한 번만 확인)
+ *  - 이미 있는 테이블의 엔진·TTL은 바꾸지 않음 (ALTER TABLE ... MODIFY TTL로 직접 변경)
+ */
+func (r *ClickHouseRepo) ensureTable(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ready {
+		return nil
+	}
+	ttl := ""
+	if r.ttl > 0 {
+		ttl = fmt.Sprintf("\nTTL toDateTime(time) + INTERVAL %d SECOND", int64(r.ttl/time.Second))
+	}
+	if err := r.conn.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  time   DateTime64(9, 'UTC')   CODEC(Delta, ZSTD),
+  device LowCardinality(String),
+  field  LowCardinality(String),
+  value  Float64                CODEC(Gorilla, ZSTD)
+) ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(time)
+ORDER BY (device, field, time)%s`, sqlIdent(r.table), ttl)); err != nil {
+		return err
+	}
+	r.ready = true
+	return nil
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *ClickHouseRepo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 배치 INSERT 한 번으로 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 필드가 없는 샘플, 숫자로 저장할 수 없는 필드는 드롭 기록 후 건너뜀
+ *  - 쓰기 실패는 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도)
+ */
+func (r *ClickHouseRepo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := r.tracer.Start(ctx, "clickhouse write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "clickhouse"),
+			attribute.Int("clickhouse.samples", len(ss)),
+		))
+	defer span.End()
+
+	points, err := r.insert(ctx, ss)
+	if err != nil {
+		r.log.Error("clickhouse write failed", zap.String("table", r.table), zap.Error(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("clickhouse write: %w", err)
+	}
+	if points > 0 {
+		r.log.Debug("clickhouse write success", zap.Int("points", points))
+	}
+	return nil
+}
+
+// insert : 묶음의 필드마다 한 행씩 배치에 담아 보냄 (기록한 행 수 반환)
+func (r *ClickHouseRepo) insert(ctx context.Context, ss []telemetry.Sample) (int, error) {
+	if err := r.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+	if r.async {
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+			"async_insert":          1,
+			"wait_for_async_insert": 1, // 서버가 기록을 마칠 때까지 기다려 실패를 돌려받음
+		}))
+	}
+	batch, err := r.conn.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s (time, device, field, value)", sqlIdent(r.table)))
+	if err != nil {
+		return 0, err
+	}
+	defer batch.Abort() // Send 뒤에는 아무것도 하지 않음
+
+	points := 0
+	for _, s := range ss {
+		_, fields := r.schema.point(s)
+		if len(fields) == 0 {
+			r.drops.Record("clickhouse", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+			continue
+		}
+		fields, ok := r.schema.checkFields(r.drops, r.schema.measurement, s.DeviceID, fields)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		for k, v := range fields {
+			f, ok := toFloat(v)
+			if !ok {
+				r.drops.Record("clickhouse", drops.ReasonValidation, s.DeviceID, k+": non-numeric field cannot be stored")
+				continue
+			}
+			if err := batch.Append(at.UTC(), s.DeviceID, k, f); err != nil {
+				return 0, err
+			}
+			points++
+		}
+	}
+	if points == 0 {
+		return 0, nil
+	}
+	return points, batch.Send()
+}
+
+/*
+ * Ping : 서버 도달 가능 여부 확인 (readiness 검사)
+ */
+func (r *ClickHouseRepo) Ping(ctx context.Context) error {
+	return r.conn.Ping(ctx)
+}
+
+/*
+ * Close : 연결 종료
+ */
+func (r *ClickHouseRepo) Close() error {
+	return r.conn.Close()
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회 (sim.Source)
+ */
+func (r *ClickHouseRepo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	var out []telemetry.Sample
+	err := r.Stream(ctx, deviceID, from, to, func(s telemetry.Sample) error {
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 읽으며 샘플마다 fn 호출 (Exporter)
+ *  - 필드별 행을 시각마다 샘플 하나로 모음
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 조회를 중단하고 그 에러를 반환
+ */
+func (r *ClickHouseRepo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	rows, err := r.conn.Query(ctx, fmt.Sprintf(`SELECT time, field, value FROM %s
+WHERE device = ? AND time >= %s AND time < %s ORDER BY time`, sqlIdent(r.table), clickhouseTime(from), clickhouseTime(to)), deviceID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var cur *telemetry.Sample
+	for rows.Next() {
+		var (
+			at    time.Time
+			field string
+			v     float64
+		)
+		if err := rows.Scan(&at, &field, &v); err != nil {
+			return err
+		}
+		if cur != nil && !cur.Time.Equal(at) {
+			if err := fn(*cur); err != nil {
+				return err
+			}
+			cur = nil
+		}
+		if cur == nil {
+			cur = &telemetry.Sample{Time: at, DeviceID: deviceID, Values: make(map[string]float64)}
+		}
+		cur.Values[field] = v
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if cur != nil {
+		return fn(*cur)
+	}
+	return nil
+}
+
+/*
+ * FieldKeys : 저장된 필드 이름 목록 (정렬, 최근 30일 기준 - 2.x와 같음)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *ClickHouseRepo) FieldKeys(ctx context.Context) ([]string, error) {
+	rows, err := r.conn.Query(ctx, fmt.Sprintf(`SELECT DISTINCT field FROM %s WHERE time > now() - INTERVAL 30 DAY ORDER BY field`, sqlIdent(r.table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (TimeSeriesStore, SQL)
+ *  - 집계 구간은 From부터 Every 폭으로 나눔 (Every가 0이면 전체 구간을 From 시각 하나로)
+ *  - Limit은 시계열마다 앞에서부터 적용
+ */
+func (r *ClickHouseRepo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	args := []any{spec.Devices}
+	where := fmt.Sprintf("has(?, device) AND time >= %s AND time < %s", clickhouseTime(spec.From), clickhouseTime(spec.To))
+	if len(spec.Fields) > 0 {
+		args = append(args, spec.Fields)
+		where += " AND has(?, field)"
+	}
+	var q string
+	if spec.Aggregate == telemetry.AggNone {
+		q = fmt.Sprintf("SELECT device, field, time, value FROM %s WHERE %s ORDER BY device, field, time", sqlIdent(r.table), where)
+	} else {
+		bucket := clickhouseTime(spec.From)
+		if spec.Every > 0 {
+			from, every := spec.From.UnixNano(), spec.Every.Nanoseconds()
+			bucket = fmt.Sprintf("fromUnixTimestamp64Nano(toInt64(%d + intDiv(toUnixTimestamp64Nano(time) - %d, %d) * %d), 'UTC')", from, from, every, every)
+		}
+		q = fmt.Sprintf("SELECT device, field, %s AS bucket, %s FROM %s WHERE %s GROUP BY device, field, bucket ORDER BY device, field, bucket",
+			bucket, clickhouseAggregates[spec.Aggregate], sqlIdent(r.table), where)
+	}
+
+	rows, err := r.conn.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	acc := newSeriesSet()
+	for rows.Next() {
+		var (
+			device, field string
+			at            time.Time
+			v             float64
+		)
+		if err := rows.Scan(&device, &field, &at, &v); err != nil {
+			return nil, err
+		}
+		acc.add(device, field, at, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return limitSeries(acc.list(), spec.Limit), nil
+}
+
+// clickhouseTime : 시각 → DateTime64(9) 식 (나노초 정밀도를 잃지 않도록 정수로 전달)
+func clickhouseTime(t time.Time) string {
+	return fmt.Sprintf("fromUnixTimestamp64Nano(toInt64(%d), 'UTC')", t.UnixNano())
+}
@@ -0,0 +1,37 @@
+/*
+ * 수동 수집 API 핸들러
+ *  - POST /api/collect[?device=A1] : 다음 수집 주기를 기다리지 않고 즉시 한 번 수집하여 발행 (device가 없으면 모든 장치)
+ *    센서 디버깅 시 결과를 바로 확인하기 위한 용도이며, 발행된 이벤트(여럿이면 첫 번째)를 그대로 응답합니다.
+ */
+package infra
+
+import (
+	"context"
+	"net/http"
+
+	"generic-api-scaffold/internal/bus" // 발행되는 이벤트 타입
+)
+
+/*
+ * ManualCollector : 주기 밖 즉시 수집을 수행하는 구성요소
+ *  - app.Collector가 구현 (infra가 app을 import할 수 없으므로 인터페이스로 분리)
+ *  - deviceID가 비어 있으면 모든 장치를 수집, 해당 장치를 보고한 공급원이 없으면 에러
+ */
+type ManualCollector interface {
+	CollectNow(ctx context.Context, deviceID string) (bus.DataCollectedEvent, error)
+}
+
+/*
+ * handleCollect : 즉시 수집 요청
+ *  - 성공 시 200과 함께 발행된 이벤트 반환
+ */
+func (s *Server) handleCollect(w http.ResponseWriter, r *http.Request) {
+	device := r.URL.Query().Get("device")
+
+	ev, err := s.collector.CollectNow(r.Context(), device)
+	if err != nil {
+		respondError(w, r, http.StatusServiceUnavailable, "collect_failed", err.Error())
+		return
+	}
+	respond(w, r, http.StatusOK, deviceView{DeviceID: ev.DeviceID, Values: ev.Values})
+}
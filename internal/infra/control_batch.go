@@ -0,0 +1,154 @@
+/*
+ * 일괄 제어 API 핸들러
+ *  - POST /api/control/batch : 여러 장치에 대한 명령을 한 번에 접수
+ *      요청 : {"commands": [{"device":"A1","action":"charge","kw10":50}, ...]}
+ *  - 모든 항목을 먼저 검사(형식, 중복 장치, 속도 제한)하고, 하나라도 실패하면 아무것도 접수하지 않습니다.
+ *  - 항목 형식은 control.Request의 validate 태그로 검사합니다. (단건 /api/control과 같은 규칙)
+ *  - 응답은 요청과 같은 순서의 항목별 결과 배열입니다.
+ */
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/audit"   // 감사 이벤트 기록
+	"generic-api-scaffold/internal/config"  // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/control" // 제어 명령 접수기
+	"generic-api-scaffold/internal/drops"   // 드롭/거절 기록
+)
+
+// batchControlReq : 일괄 제어 요청 본문
+type batchControlReq struct {
+	Commands []control.Request `json:"commands" validate:"required,min=1,dive"`
+}
+
+/*
+ * batchItemResult : 항목 하나의 처리 결과
+ *  - 성공 : ID, Status
+ *  - 실패 : Error (거절된 배치에서 문제없는 항목은 Error 없이 Status "not_queued")
+ */
+type batchItemResult struct {
+	Index    int          `json:"index"`
+	DeviceID string       `json:"device"`
+	ID       string       `json:"id,omitempty"`
+	Status   string       `json:"status"`
+	Error    *errorDetail `json:"error,omitempty"`
+}
+
+// batchControlResp : 일괄 제어 응답
+type batchControlResp struct {
+	Accepted bool              `json:"accepted"`
+	Results  []batchItemResult `json:"results"`
+}
+
+/*
+ * batchMax : 한 번에 접수할 수 있는 최대 명령 수
+ *  - APP_CONTROL_BATCH_MAX : 기본 100
+ */
+func batchMax(log *zap.Logger) int {
+	n := config.Int(log, "APP_CONTROL_BATCH_MAX", 100)
+	if n < 1 {
+		log.Fatal("APP_CONTROL_BATCH_MAX must be positive", zap.Int("value", n))
+	}
+	return n
+}
+
+/*
+ * handleControlBatch : 일괄 제어 명령 접수
+ *  - 202 : 모든 항목 접수
+ *  - 400 : 형식 오류 또는 같은 장치 중복, validation_failed와 필드별 에러 (예: commands[1].action)
+ *  - 429 : 하나 이상의 장치가 속도 제한에 걸림 (아무것도 접수하지 않음, Retry-After는 가장 긴 대기 시간)
+ *  - 500 : 속도 제한이 아닌 내부 오류 (아무것도 접수하지 않음)
+ *  - 429/500 응답 본문은 항목별 결과 배열
+ */
+func (s *Server) handleControlBatch(w http.ResponseWriter, r *http.Request) {
+	// ① 형식 검사 (항목별 validate 태그) + 최대 개수 + 중복 장치 검사
+	var req batchControlReq
+	if !s.decodeValid(w, r, &req) {
+		return
+	}
+	if len(req.Commands) > s.batchMax {
+		respondValidation(w, r, []fieldError{{Field: "commands", Rule: "max", Param: strconv.Itoa(s.batchMax),
+			Message: fmt.Sprintf("at most %d commands per batch", s.batchMax)}})
+		return
+	}
+	if fields := duplicateDevices(req.Commands); len(fields) > 0 {
+		respondValidation(w, r, fields)
+		return
+	}
+
+	results := make([]batchItemResult, len(req.Commands))
+	for i, c := range req.Commands {
+		results[i] = batchItemResult{Index: i, DeviceID: c.DeviceID, Status: "not_queued"}
+	}
+
+	// ② 속도 제한 검사 + 접수 (전부 또는 전무)
+	cmds, errs := s.dispatcher.SubmitBatch(r.Context(), req.Commands)
+	if errs != nil {
+		var retry time.Duration
+		internal := false
+		for i, err := range errs {
+			if err == nil {
+				continue
+			}
+			results[i].Status = "rejected"
+			var le *control.LimitError
+			if errors.As(err, &le) {
+				s.drops.Record("control", drops.ReasonQuota, le.DeviceID, le.Error())
+				results[i].Error = &errorDetail{Code: "rate_limited", Message: le.Error()}
+				if le.RetryAfter > retry {
+					retry = le.RetryAfter
+				}
+				continue
+			}
+			results[i].Error = &errorDetail{Code: "internal", Message: err.Error()}
+			internal = true
+		}
+		if internal {
+			s.auditEvent(r, audit.CategoryControl, "control.batch", "", fmt.Sprintf("%d commands", len(req.Commands)), errors.New("internal error"))
+			respond(w, r, http.StatusInternalServerError, batchControlResp{Results: results})
+			return
+		}
+		s.auditEvent(r, audit.CategoryControl, "control.batch", "", fmt.Sprintf("%d commands", len(req.Commands)), errors.New("rejected by rate limit"))
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+		respond(w, r, http.StatusTooManyRequests, batchControlResp{Results: results})
+		return
+	}
+
+	for i, cmd := range cmds {
+		results[i].ID = cmd.ID
+		results[i].Status = string(cmd.Status)
+		detail := fmt.Sprintf("id=%s action=%s kw10=%d batch", cmd.ID, cmd.Action, cmd.KW10)
+		if cmd.Override != "" {
+			detail += " override=" + cmd.Override
+		}
+		s.auditEvent(r, audit.CategoryControl, "control.submit", cmd.DeviceID, detail, nil)
+	}
+	s.log.Info("control batch queued", zap.Int("count", len(cmds)))
+	respond(w, r, http.StatusAccepted, batchControlResp{Accepted: true, Results: results})
+}
+
+// duplicateDevices : 같은 장치가 두 번 이상 나오면 뒤쪽 항목마다 필드 에러 (commands[i].device)
+func duplicateDevices(cmds []control.Request) []fieldError {
+	var out []fieldError
+	seen := make(map[string]int, len(cmds))
+	for i, c := range cmds {
+		if first, dup := seen[c.DeviceID]; dup {
+			out = append(out, fieldError{
+				Field:   fmt.Sprintf("commands[%d].device", i),
+				Rule:    "unique",
+				Message: fmt.Sprintf("device %s already appears at index %d", c.DeviceID, first),
+			})
+			continue
+		}
+		seen[c.DeviceID] = i
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+/*
+ * 명령 완료 대기(long-poll) API 핸들러
+ *  - GET /api/control/{id}/wait?timeout=30s
+ *  - 명령이 종료 상태(succeeded/failed)가 되거나 timeout이 지날 때까지 응답을 보류합니다.
+ *    간단한 스크립트 클라이언트가 자체 폴링 루프나 WebSocket 없이 결과를 기다릴 수 있습니다.
+ *  - 200 : 종료 상태의 명령
+ *  - 202 : timeout까지 끝나지 않음 (현재 상태의 명령, 다시 호출하면 이어서 대기)
+ *  - 404 : 없는 명령
+ *  - Dispatcher를 직접 호출하지 않고 버스 요청-응답(control.TopicCommandWait)으로 결과를 물음
+ */
+package infra
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux" // 경로 변수({id}) 추출
+	"go.uber.org/zap"        // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"     // 이벤트 버스 (요청-응답)
+	"generic-api-scaffold/internal/config"  // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/control" // 제어 명령 접수기
+)
+
+// 대기 시간을 지정하지 않았을 때의 기본값
+const defaultControlWait = 30 * time.Second
+
+/*
+ * controlWaitMax : 요청 하나가 대기할 수 있는 최대 시간
+ *  - APP_CONTROL_WAIT_MAX : 기본 60s (라우트 타임아웃 control.wait 기본 2m보다 짧아야 504 대신 202로 끝남)
+ */
+func controlWaitMax(log *zap.Logger) time.Duration {
+	d := config.Duration(log, "APP_CONTROL_WAIT_MAX", time.Minute)
+	if d <= 0 {
+		log.Fatal("APP_CONTROL_WAIT_MAX must be positive", zap.Duration("value", d))
+	}
+	return d
+}
+
+func (s *Server) handleControlWait(w http.ResponseWriter, r *http.Request) {
+	timeout := defaultControlWait
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			respondValidation(w, r, []fieldError{{Field: "timeout", Rule: "duration", Message: "timeout must be a non-negative duration such as 30s"}})
+			return
+		}
+		timeout = d
+	}
+	if timeout > s.waitMax {
+		timeout = s.waitMax
+	}
+
+	// 대기 시간은 응답자가 지킴 (요청 ctx는 클라이언트 연결 끊김만 전달)
+	reply, err := s.bus.Request(r.Context(), control.TopicCommandWait, control.WaitRequest{ID: mux.Vars(r)["id"], Timeout: timeout})
+	cmd, _ := reply.(control.Command)
+	switch {
+	case errors.Is(err, control.ErrNotFound):
+		respondError(w, r, http.StatusNotFound, "not_found", "command not found")
+	case errors.Is(err, context.DeadlineExceeded):
+		respond(w, r, http.StatusAccepted, cmd)
+	case errors.Is(err, bus.ErrNoResponder):
+		respondError(w, r, http.StatusServiceUnavailable, "unavailable", "command dispatcher is not available")
+	case err != nil:
+		// 클라이언트가 연결을 끊음 (응답을 받을 곳이 없음)
+		return
+	default:
+		respond(w, r, http.StatusOK, cmd)
+	}
+}
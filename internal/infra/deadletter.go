@@ -0,0 +1,54 @@
+/*
+ * 데드레터 관리 API (관리 서버 전용)
+ *  - GET    /deadletters             : 재시도 후에도 처리하지 못해 보관 중인 버스 메시지 목록 (오래된 것부터)
+ *  - POST   /deadletters/{id}/replay : 실패했던 구독자에게 한 번 다시 전달, 성공하면 목록에서 제거
+ *  - DELETE /deadletters/{id}        : 재전송하지 않고 삭제
+ */
+package infra
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux" // 경로 변수
+
+	"generic-api-scaffold/internal/bus" // 데드레터 큐
+)
+
+/*
+ * handleDeadLetters : 데드레터 목록 조회
+ */
+func (a *AdminServer) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, http.StatusOK, a.deadLetters.List())
+}
+
+/*
+ * handleDeadLetterReplay : 데드레터 재전송
+ *  - 성공 200 (재전송된 항목), 없으면 404, 구독자가 해지되었으면 409,
+ *    구독자가 다시 실패하면 502 (항목은 시도 횟수를 늘려 보관)
+ */
+func (a *AdminServer) handleDeadLetterReplay(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	e, err := a.deadLetters.Replay(r.Context(), id)
+	switch {
+	case errors.Is(err, bus.ErrDeadLetterNotFound):
+		respondError(w, r, http.StatusNotFound, "not_found", "dead letter not found")
+	case errors.Is(err, bus.ErrUnsubscribed):
+		respondError(w, r, http.StatusConflict, "unsubscribed", err.Error())
+	case err != nil:
+		respondError(w, r, http.StatusBadGateway, "replay_failed", err.Error())
+	default:
+		respond(w, r, http.StatusOK, e)
+	}
+}
+
+/*
+ * handleDeadLetterDiscard : 데드레터 삭제
+ */
+func (a *AdminServer) handleDeadLetterDiscard(w http.ResponseWriter, r *http.Request) {
+	if err := a.deadLetters.Discard(mux.Vars(r)["id"]); err != nil {
+		respondError(w, r, http.StatusNotFound, "not_found", "dead letter not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,46 @@
+/*
+ * 장치 API 핸들러
+ *  - GET /api/devices : 이벤트를 보낸 적이 있는 장치 목록과 각 장치의 마지막 수집 값
+ *    (EventBus가 보관하는 장치별 마지막 이벤트를 사용하므로 저장소를 조회하지 않음)
+ *  - GET /api/devices/{id}/latest : 장치 하나의 필드별 최신값과 수신 시각
+ *    (EventBus로 채워지는 최신값 저장소에서 응답, Influx 조회 없음)
+ */
+package infra
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux" // 경로 변수({id}) 추출
+)
+
+// deviceView : 장치 목록 응답 항목
+type deviceView struct {
+	DeviceID string             `json:"device"`
+	Values   map[string]float64 `json:"values"`
+}
+
+/*
+ * handleDevices : 장치 목록 조회 (장치 ID 순)
+ */
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request) {
+	events := s.bus.CatchUp()
+	out := make([]deviceView, 0, len(events))
+	for _, e := range events {
+		out = append(out, deviceView{DeviceID: e.DeviceID, Values: e.Values})
+	}
+	respond(w, r, http.StatusOK, out)
+}
+
+/*
+ * handleDeviceLatest : 장치 하나의 최신값 조회
+ *  - 한 번도 보고하지 않은 장치면 404
+ */
+func (s *Server) handleDeviceLatest(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	snap, ok := s.latest.Get(id)
+	if !ok {
+		respondError(w, r, http.StatusNotFound, "not_found", "no data received from device "+id)
+		return
+	}
+	respond(w, r, http.StatusOK, snap)
+}
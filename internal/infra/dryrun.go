@@ -0,0 +1,159 @@
+/*
+ * DryRunStore : 기록하지 않고 로그만 남기는 저장소 (APP_STORE_DRY_RUN=true)
+ *  - 개발 환경이나 파이프라인 변경 확인용이며, 운영 DB를 건드리지 않고 어떤 포인트가 기록될지 확인합니다.
+ *  - 쓰기 : Influx 저장소와 같은 스키마·필드 타입 검사·라우팅을 거쳐 라인 프로토콜로 직렬화한 뒤 보내지 않고 로그로 남김
+ *      검사에 걸린 값은 실제 저장소와 같이 /drops에 기록 (source="dryrun")
+ *  - 조회·내보내기·시뮬레이터 : 저장한 데이터가 없으므로 항상 빈 결과
+ *  - 저장소 연결을 만들지 않으므로 APP_STORE_BACKEND와 그 연결 설정은 쓰지 않음 (테이블 생성·마이그레이션도 하지 않음)
+ */
+package infra
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+// lpStringEscaper : 라인 프로토콜 문자열 필드 값 이스케이프
+var lpStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// DryRunStore : 직렬화 결과를 로그로 남기는 저장소
+type DryRunStore struct {
+	log    *zap.Logger
+	schema influxSchema
+	drops  *drops.Recorder
+}
+
+/*
+ * NewDryRunStore : DryRunStore 생성자 (NewInfluxStore가 APP_STORE_DRY_RUN=true일 때 APP_STORE_BACKEND 대신 호출)
+ *  - 스키마 설정(APP_INFLUX_MEASUREMENT, APP_INFLUX_STATIC_TAGS, APP_INFLUX_TAG_FIELDS, APP_INFLUX_ROUTES, 필드 타입)을 그대로 읽으므로
+ *    설정 오류는 실제 저장소와 같이 시작 시 Fatal
+ */
+func NewDryRunStore(log *zap.Logger, dr *drops.Recorder) *DryRunStore {
+	log.Warn("store dry run enabled, samples are logged as line protocol and not written")
+	return &DryRunStore{log: log, schema: loadInfluxSchema(log), drops: dr}
+}
+
+/*
+ * WritePoint : 샘플 하나를 직렬화해 로그로 남김 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *DryRunStore) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 기록 위치(데이터베이스)별 라인 프로토콜로 직렬화해 로그 한 줄씩 남김
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용 (나노초 정밀도)
+ *  - 필드가 없는 샘플과 필드 타입 검사에 걸린 샘플은 드롭 기록 후 건너뜀
+ */
+func (r *DryRunStore) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	lines := make(map[string]*bytes.Buffer) // 데이터베이스 → 라인 프로토콜 (비어 있으면 기본값)
+	points := make(map[string]int)
+	for _, s := range ss {
+		tags, fields := r.schema.point(s)
+		if len(fields) == 0 {
+			r.drops.Record("dryrun", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+			continue
+		}
+		target := r.schema.target(tags)
+		fields, ok := r.schema.checkFields(r.drops, target.measurement, s.DeviceID, fields)
+		if !ok || len(fields) == 0 {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		b := lines[target.database]
+		if b == nil {
+			b = new(bytes.Buffer)
+			lines[target.database] = b
+		}
+		appendLine(b, target.measurement, tags, fields, at)
+		points[target.database]++
+	}
+	for _, database := range sortedKeys(lines) {
+		r.log.Info("store dry run", zap.String("database", database), zap.Int("points", points[database]),
+			zap.String("line_protocol", strings.TrimSuffix(lines[database].String(), "\n")))
+	}
+	return nil
+}
+
+// appendLine : 라인 프로토콜 한 줄 (태그·필드는 이름순, 정수는 i 접미사, 문자열은 큰따옴표)
+func appendLine(b *bytes.Buffer, measurement string, tags map[string]string, fields map[string]interface{}, at time.Time) {
+	b.WriteString(lpMeasurementEscaper.Replace(measurement))
+	for _, k := range sortedKeys(tags) {
+		b.WriteString("," + lpTagEscaper.Replace(k) + "=" + lpTagEscaper.Replace(tags[k]))
+	}
+	for i, k := range sortedKeys(fields) {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(lpTagEscaper.Replace(k) + "=")
+		switch v := fields[k].(type) {
+		case int64:
+			b.WriteString(strconv.FormatInt(v, 10) + "i")
+		case bool:
+			b.WriteString(strconv.FormatBool(v))
+		case string:
+			b.WriteString(`"` + lpStringEscaper.Replace(v) + `"`)
+		case float64:
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	}
+	b.WriteString(" " + strconv.FormatInt(at.UnixNano(), 10) + "\n")
+}
+
+/*
+ * Ping : 항상 준비됨 (연결할 저장소가 없음)
+ */
+func (r *DryRunStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+/*
+ * Close : 정리할 연결 없음
+ */
+func (r *DryRunStore) Close() error {
+	return nil
+}
+
+/*
+ * Query : 항상 빈 결과 (조회 조건 검사는 다른 저장소와 같음)
+ */
+func (r *DryRunStore) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+/*
+ * Window : 항상 빈 구간 (sim.Source)
+ */
+func (r *DryRunStore) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	return nil, nil
+}
+
+/*
+ * Stream : 호출할 샘플 없음 (Exporter)
+ */
+func (r *DryRunStore) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	return nil
+}
+
+/*
+ * FieldKeys : 빈 필드 목록 (Exporter)
+ */
+func (r *DryRunStore) FieldKeys(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * 태그 보강 : 저장하기 전에 장치 메타데이터(사이트, 모델, 펌웨어, 위치 등)를 포인트 태그로 붙입니다.
+ *  - Grafana 대시보드가 조인 없이 site 등으로 묶어 볼 수 있도록, 기록 시점의 메타데이터를 태그로 남김
+ *  - TagEnricher를 구현한 값을 fx 값 그룹(group:"tag_enrichers")으로 제공하면 모든 저장 경로(기본·보조 저장소)에 적용됩니다.
+ *      기본 등록 : 장치 레지스트리 (device.Registry, APP_DEVICE_REGISTRY_FILE)
+ *  - 태그 우선순위 : 고정 태그(APP_INFLUX_STATIC_TAGS) < 보강 태그(등록 순서대로, 뒤가 우선) < 태그로 올린 값(APP_INFLUX_TAG_FIELDS)
+ *    device 태그는 항상 장치 ID이며 보강으로 바꿀 수 없음
+ *  - 태그를 저장하는 저장소(InfluxDB, TimescaleDB, VictoriaMetrics, Kafka)에만 반영되며, 조회의 기록 위치(라우팅)는 보강 태그를 보지 않음
+ */
+package infra
+
+import (
+	"go.uber.org/fx" // 값 그룹 주입
+
+	"generic-api-scaffold/internal/telemetry" // 기록 단위 샘플
+)
+
+// TagEnricher : 장치 ID → 붙일 태그 (없으면 nil, 저장 경로에서 샘플마다 호출되므로 빨라야 함)
+type TagEnricher interface {
+	EnrichTags(deviceID string) map[string]string
+}
+
+/*
+ * AsTagEnricher : 생성자의 결과를 group:"tag_enrichers"에 제공하도록 감쌈
+ *  - 예) fx.Provide(infra.AsTagEnricher(func(r *device.Registry) infra.TagEnricher { return r }))
+ */
+func AsTagEnricher(f interface{}) interface{} {
+	return fx.Annotate(f, fx.ResultTags(`group:"tag_enrichers"`))
+}
+
+// TagEnrichmentParams : NewTagEnrichment가 fx로부터 주입받는 보강기 목록
+type TagEnrichmentParams struct {
+	fx.In
+
+	Enrichers []TagEnricher `group:"tag_enrichers"`
+}
+
+// TagEnrichment : 등록된 보강기 묶음
+type TagEnrichment struct {
+	enrichers []TagEnricher
+}
+
+// NewTagEnrichment : fx가 호출하는 보강기 묶음 생성자
+func NewTagEnrichment(p TagEnrichmentParams) *TagEnrichment {
+	return &TagEnrichment{enrichers: p.Enrichers}
+}
+
+// apply : 샘플마다 보강 태그를 채움 (보강기가 없으면 아무것도 하지 않음)
+func (en *TagEnrichment) apply(ss []telemetry.Sample) {
+	if en == nil || len(en.enrichers) == 0 {
+		return
+	}
+	for i := range ss {
+		var tags map[string]string
+		for _, e := range en.enrichers {
+			for k, v := range e.EnrichTags(ss[i].DeviceID) {
+				if k == deviceTag {
+					continue
+				}
+				if tags == nil {
+					tags = make(map[string]string)
+				}
+				tags[k] = v
+			}
+		}
+		ss[i].Tags = tags
+	}
+}
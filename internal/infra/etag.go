@@ -0,0 +1,107 @@
+/*
+ * ETag / 조건부 GET 미들웨어
+ *  - GET 응답(200)의 본문 해시로 ETag를 만들고, If-None-Match가 일치하면 본문 없이 304로 응답합니다.
+ *  - 폴링하는 클라이언트(대시보드 등)가 바뀌지 않은 응답을 다시 내려받지 않도록 합니다.
+ *  - 응답을 끝까지 모아야 해시를 알 수 있으므로, 스트리밍 라우트(export, ui)는 대상에서 제외합니다.
+ *    (ui 정적 파일은 http.FileServer가 Last-Modified 기반 조건부 요청을 자체 처리)
+ *  - 본문 인코딩(JSON/CBOR/MessagePack)이 다르면 해시도 달라지므로 Accept별로 자연히 다른 ETag가 됩니다.
+ */
+package infra
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux" // 현재 라우트 이름 조회
+)
+
+// etagSkipRoutes : ETag를 붙이지 않는 스트리밍 라우트
+var etagSkipRoutes = map[string]bool{"export": true, "ui": true}
+
+/*
+ * etagFor : 본문 SHA-256 앞 16바이트로 강한(strong) ETag 생성
+ */
+func etagFor(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+/*
+ * etagMatches : If-None-Match 헤더 값이 etag와 일치하는지 (약한 비교, RFC 9110 13.1.2)
+ *  - "*"는 어떤 표현과도 일치
+ *  - W/ 접두어는 무시하고 비교
+ */
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * conditionalGet : ETag 미들웨어
+ *  - 내부 동작 :
+ *     ① GET 요청이고 스트리밍 라우트가 아니면 응답을 버퍼에 모음
+ *     ② 200이면 ETag 헤더를 붙이고, If-None-Match가 일치하면 304 (본문 없음)
+ *     ③ 그 밖의 상태 코드는 모은 응답을 그대로 전달
+ */
+func (s *Server) conditionalGet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if r.Method != http.MethodGet || route == nil || etagSkipRoutes[route.GetName()] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedWriter{header: w.Header()}
+		next.ServeHTTP(bw, r)
+		if bw.status == 0 {
+			bw.status = http.StatusOK
+		}
+
+		if bw.status == http.StatusOK {
+			etag := etagFor(bw.buf.Bytes())
+			w.Header().Set("ETag", etag)
+			if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+				// 304에는 본문 관련 헤더를 보내지 않음
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.WriteHeader(bw.status)
+		w.Write(bw.buf.Bytes())
+	})
+}
+
+/*
+ * bufferedWriter : 상태 코드와 본문을 모아 두는 ResponseWriter
+ *  - 헤더는 원래 ResponseWriter의 헤더 맵을 그대로 공유
+ */
+type bufferedWriter struct {
+	header http.Header
+	status int
+	buf    bytes.Buffer
+}
+
+func (bw *bufferedWriter) Header() http.Header { return bw.header }
+
+func (bw *bufferedWriter) WriteHeader(code int) {
+	if bw.status == 0 {
+		bw.status = code
+	}
+}
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	if bw.status == 0 {
+		bw.status = http.StatusOK
+	}
+	return bw.buf.Write(b)
+}
@@ -0,0 +1,28 @@
+package infra
+
+import "testing"
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `"abc"`
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"exact", `"abc"`, true},
+		{"weak", `W/"abc"`, true},
+		{"wildcard", `*`, true},
+		{"list", `"x", "abc"`, true},
+		{"list with weak", `"x",W/"abc"`, true},
+		{"different", `"abd"`, false},
+		{"unquoted", `abc`, false},
+		{"empty", ``, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.ifNoneMatch, etag); got != tt.want {
+				t.Fatalf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * 구간 내보내기 API 핸들러
+ *  - GET /api/export?device=A1&from=2025-01-01T00:00:00Z&to=2025-01-02T00:00:00Z&format=csv|ndjson
+ *  - 저장소에서 청크 단위로 읽으면서 바로 응답에 쓰므로(chunked transfer) 전체 결과를 메모리에 올리지 않습니다.
+ *  - 분석 담당자가 Influx에 직접 접근하지 않고도 데이터를 가져갈 수 있게 하기 위한 용도입니다.
+ *  - 응답을 쓰기 시작한 뒤 저장소 에러가 나면 상태 코드를 바꿀 수 없으므로, 로그만 남기고 응답을 끝냅니다.
+ */
+package infra
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/telemetry" // 내보낼 샘플
+)
+
+/*
+ * Exporter : 구간 데이터를 스트리밍으로 읽을 수 있는 저장소
+ *  - InfluxRepo가 구현
+ */
+type Exporter interface {
+	FieldKeys(ctx context.Context) ([]string, error)
+	Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error
+}
+
+// 내보내기 형식별 Content-Type
+const (
+	mediaCSV    = "text/csv; charset=utf-8"
+	mediaNDJSON = "application/x-ndjson"
+)
+
+// 이 행 수마다 버퍼를 비우고 Flush (청크 전송)
+const exportFlushEvery = 500
+
+/*
+ * exportMaxRange : 한 번에 내보낼 수 있는 최대 구간
+ *  - APP_EXPORT_MAX_RANGE : 기본 744h (31일)
+ */
+func exportMaxRange(log *zap.Logger) time.Duration {
+	return config.Duration(log, "APP_EXPORT_MAX_RANGE", 31*24*time.Hour)
+}
+
+// exportRow : NDJSON 한 줄
+type exportRow struct {
+	Time   time.Time          `json:"time"`
+	Device string             `json:"device"`
+	Values map[string]float64 `json:"values"`
+}
+
+/*
+ * handleExport : 구간 데이터를 CSV 또는 NDJSON으로 스트리밍
+ *  - device, from 필수 / to 생략 시 현재 시각 / format 생략 시 csv
+ *  - CSV 열 : time(RFC3339Nano), device, 필드들(저장소의 필드 이름 순, 값이 없으면 빈 칸)
+ */
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	device := q.Get("device")
+	if device == "" {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", "device is required")
+		return
+	}
+	from, err := time.Parse(time.RFC3339, q.Get("from"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", "from must be an RFC3339 time")
+		return
+	}
+	to := time.Now()
+	if v := q.Get("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "to must be an RFC3339 time")
+			return
+		}
+	}
+	if !to.After(from) {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", "to must be after from")
+		return
+	}
+	if to.Sub(from) > s.exportMaxRange {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", fmt.Sprintf("range must not exceed %s", s.exportMaxRange))
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format == "json" {
+		format = "ndjson"
+	}
+	if format != "csv" && format != "ndjson" {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", "format must be csv or ndjson")
+		return
+	}
+
+	// CSV는 열 머리글이 필요하므로 응답을 쓰기 전에 필드 목록 조회 (실패하면 아직 에러 응답 가능)
+	var fields []string
+	if format == "csv" {
+		if fields, err = s.exporter.FieldKeys(r.Context()); err != nil {
+			respondError(w, r, http.StatusBadGateway, "storage_error", err.Error())
+			return
+		}
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.%s", device, from.UTC().Format("20060102T150405Z"), to.UTC().Format("20060102T150405Z"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "csv" {
+		w.Header().Set("Content-Type", mediaCSV)
+	} else {
+		w.Header().Set("Content-Type", mediaNDJSON)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	bw := bufio.NewWriter(w)
+	flusher, _ := w.(http.Flusher)
+	var write func(telemetry.Sample) error
+
+	if format == "csv" {
+		cw := csv.NewWriter(bw)
+		header := append([]string{"time", "device"}, fields...)
+		if err := cw.Write(header); err != nil {
+			return
+		}
+		record := make([]string, len(header))
+		write = func(smp telemetry.Sample) error {
+			record[0] = smp.Time.UTC().Format(time.RFC3339Nano)
+			record[1] = smp.DeviceID
+			for i, f := range fields {
+				record[i+2] = ""
+				if v, ok := smp.Values[f]; ok {
+					record[i+2] = strconv.FormatFloat(v, 'g', -1, 64)
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		enc := json.NewEncoder(bw)
+		write = func(smp telemetry.Sample) error {
+			return enc.Encode(exportRow{Time: smp.Time.UTC(), Device: smp.DeviceID, Values: smp.Values})
+		}
+	}
+
+	rows := 0
+	err = s.exporter.Stream(r.Context(), device, from, to, func(smp telemetry.Sample) error {
+		if err := write(smp); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if ferr := bw.Flush(); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		s.log.Warn("export aborted", zap.String("device", device), zap.Int("rows", rows), zap.Error(err))
+		return
+	}
+	s.log.Info("export completed", zap.String("device", device), zap.String("format", format), zap.Int("rows", rows))
+}
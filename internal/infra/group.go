@@ -0,0 +1,69 @@
+/*
+ * 장치 그룹 / 그룹 경보 API 핸들러
+ *  - GET /api/groups         : 그룹 목록과 각 그룹의 가상 장치 집계 값
+ *  - GET /api/groups/{name}  : 그룹 하나의 구성 장치와 집계 값
+ *  - GET /api/alerts         : 발생 중인 그룹 경보와 설정된 규칙
+ */
+package infra
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux" // 경로 변수({name}) 추출
+
+	"generic-api-scaffold/internal/group" // 장치 그룹 집계 및 경보
+)
+
+// groupView : 그룹 응답 항목
+type groupView struct {
+	Name      string             `json:"name"`
+	DeviceID  string             `json:"device"`    // 가상 장치 ID (group:<name>)
+	Members   []string           `json:"members"`   // 구성 장치
+	Reporting int                `json:"reporting"` // 값을 보고한 적 있는 구성 장치 수
+	Values    map[string]float64 `json:"values"`    // "<필드>.<집계>" → 값
+}
+
+// groupViewOf : 그룹 하나의 응답 항목 생성
+func (s *Server) groupViewOf(name string) (groupView, error) {
+	sample, n, err := s.groups.Aggregate(name)
+	if err != nil {
+		return groupView{}, err
+	}
+	return groupView{Name: name, DeviceID: sample.DeviceID, Members: s.groups.Members(name), Reporting: n, Values: sample.Values}, nil
+}
+
+/*
+ * handleGroups : 그룹 목록과 집계 값 (이름 순)
+ */
+func (s *Server) handleGroups(w http.ResponseWriter, r *http.Request) {
+	names := s.groups.Names()
+	out := make([]groupView, 0, len(names))
+	for _, name := range names {
+		if v, err := s.groupViewOf(name); err == nil {
+			out = append(out, v)
+		}
+	}
+	respond(w, r, http.StatusOK, out)
+}
+
+/*
+ * handleGroup : 그룹 하나 조회
+ */
+func (s *Server) handleGroup(w http.ResponseWriter, r *http.Request) {
+	v, err := s.groupViewOf(mux.Vars(r)["name"])
+	if err != nil {
+		respondError(w, r, http.StatusNotFound, "not_found", "group not found")
+		return
+	}
+	respond(w, r, http.StatusOK, v)
+}
+
+/*
+ * handleAlerts : 발생 중인 그룹 경보와 설정된 규칙
+ */
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, http.StatusOK, struct {
+		Active []group.Alert     `json:"active"`
+		Rules  []group.AlertRule `json:"rules"`
+	}{Active: s.alerts.Active(), Rules: s.alerts.Rules()})
+}
@@ -0,0 +1,79 @@
+/*
+ * 헬스 체크 : Kubernetes liveness / readiness 프로브용 엔드포인트
+ *  - /livez  : 프로세스가 살아 있는지 확인 (의존성 검사 없음, 항상 200)
+ *  - /readyz : 등록된 모든 의존성(Influx, EventBus, Collector 등)을 검사하고
+ *              하나라도 실패하면 503을 반환하여 트래픽 라우팅에서 제외되도록 합니다.
+ */
+package infra
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+// 개별 의존성 검사에 허용되는 최대 시간
+const readinessCheckTimeout = 2 * time.Second
+
+/*
+ * ReadinessCheck : 준비 상태 검사 하나
+ *  - Name  : 응답 JSON에 표시될 의존성 이름 (예: "influx")
+ *  - Check : nil 반환 시 정상, 에러 반환 시 준비되지 않음
+ *  - fx 값 그룹(group:"readiness")으로 등록하면 Server가 자동으로 수집합니다.
+ */
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// checkResult : 의존성 하나의 검사 결과
+type checkResult struct {
+	Status string `json:"status"`          // "ok" | "fail"
+	Error  string `json:"error,omitempty"` // 실패 사유
+}
+
+// readinessResponse : /readyz 응답 본문
+type readinessResponse struct {
+	Status string                 `json:"status"` // "ready" | "not_ready"
+	Checks map[string]checkResult `json:"checks"`
+}
+
+/*
+ * handleLive : liveness 프로브
+ *  - 요청을 처리할 수 있다는 것 자체가 프로세스가 살아 있다는 의미이므로 항상 200 OK
+ */
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK) // HTTP 상태 코드 200 OK 반환
+	_, _ = w.Write([]byte("ok")) // 응답 본문에 "ok" 메시지 반환
+}
+
+/*
+ * handleReady : readiness 프로브
+ *  - 등록된 검사를 각각 타임아웃(2초)과 함께 실행
+ *  - 모두 통과하면 200, 하나라도 실패하면 503 Service Unavailable
+ */
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	resp := readinessResponse{Status: "ready", Checks: make(map[string]checkResult, len(s.checks))}
+
+	for _, c := range s.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+		err := c.Check(ctx)
+		cancel()
+
+		if err != nil {
+			resp.Status = "not_ready"
+			resp.Checks[c.Name] = checkResult{Status: "fail", Error: err.Error()}
+			s.log.Warn("readiness check failed", zap.String("check", c.Name), zap.Error(err))
+			continue
+		}
+		resp.Checks[c.Name] = checkResult{Status: "ok"}
+	}
+
+	status := http.StatusOK
+	if resp.Status != "ready" {
+		status = http.StatusServiceUnavailable
+	}
+	respond(w, r, status, resp)
+}
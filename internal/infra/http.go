@@ -8,16 +8,65 @@ package infra
 import (
 	"os"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 	"strconv"
 	
-	"github.com/gorilla/mux" // HTTP 라우팅을 위한 Gorilla Mux
-	"go.uber.org/fx"         // Fx 프레임워크를 통한 라이프사이클 관리
-	"go.uber.org/zap"        // 로깅 도구
+	"github.com/gorilla/mux"                         // HTTP 라우팅을 위한 Gorilla Mux
+	"github.com/prometheus/client_golang/prometheus" // HTTP 요청 메트릭 등록
+	"go.opentelemetry.io/otel/trace"                 // 요청 추적 스팬
+	"go.uber.org/fx"                                 // Fx 프레임워크를 통한 라이프사이클 관리
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/audit"   // 감사/접근 이벤트 기록 (SIEM 전송)
+	"generic-api-scaffold/internal/auth"    // API 토큰 인증 및 역할 확인
+	"generic-api-scaffold/internal/bus"     // 이벤트 버스 (장치별 마지막 이벤트)
+	"generic-api-scaffold/internal/control" // 제어 명령 속도 제한
+	"generic-api-scaffold/internal/drops"   // 드롭/거절 기록
+	"generic-api-scaffold/internal/group"   // 장치 그룹 집계 및 그룹 경보
+	"generic-api-scaffold/internal/latest"  // 장치별 최신값 저장소
+	"generic-api-scaffold/internal/price"   // 전력 가격 피드
+	"generic-api-scaffold/internal/search"  // 장치/명령/경보 검색
+	"generic-api-scaffold/internal/sim"     // what-if 시뮬레이터
+	"generic-api-scaffold/internal/webhook" // 웹훅 구독 관리
 )
 
+/*
+ * ServerParams : NewHTTPServer가 fx로부터 주입받는 의존성 묶음
+ *  - Checks : group:"readiness"로 등록된 모든 준비 상태 검사 (/readyz에서 사용)
+ *  - Search : group:"search"로 등록된 모든 검색 문서 공급원 (/api/search에서 사용)
+ *  - Routes : group:"routes"로 등록된 모든 라우트 등록자 (선택 모듈, 다른 패키지의 엔드포인트)
+ */
+type ServerParams struct {
+	fx.In
+
+	Log        *zap.Logger
+	Bus        *bus.EventBus
+	Latest     *latest.Store
+	Dispatcher *control.Dispatcher
+	Rollouts   *control.RolloutManager
+	Overrides  *control.OverrideManager
+	Auth       *auth.Authenticator
+	Sim        *sim.Runner
+	Prices     price.Feed
+	Drops      *drops.Recorder
+	Collector  ManualCollector
+	Groups     *group.Registry
+	Alerts     *group.Alerter
+	Webhooks   *webhook.Manager
+	Audit      *audit.Recorder
+	Exporter   Exporter
+	Metrics    *prometheus.Registry
+	Tracing    trace.TracerProvider
+	Checks     []ReadinessCheck `group:"readiness"`
+	Search     []search.Source  `group:"search"`
+	Routes     []RouteRegistrar `group:"routes"`
+}
+
 // Server : HTTP 서버 컨테이너
 //  - HTTP 서버, 라우터, 서버 설정을 관리하는 구조체
 type Server struct {
@@ -25,6 +74,35 @@ type Server struct {
 	router *mux.Router    // HTTP 라우터 (요청을 라우팅할 때 사용)
 	srv    *http.Server   // 실제 HTTP 서버
 	port   int            // 서버가 리스닝할 포트 번호
+	listen []listenSpec   // 리스닝 대상 목록 (TCP 주소, TLS, 유닉스 소켓)
+	tls    *tls.Config    // https 리스너용 인증서 설정 (없으면 nil)
+
+	bus        *bus.EventBus            // 이벤트 버스 (장치 목록/최신 값)
+	latest     *latest.Store            // 장치별 최신값 저장소 (필드별 시각)
+	dispatcher *control.Dispatcher      // 제어 명령 접수기 (속도 제한 포함)
+	rollouts   *control.RolloutManager  // 단계적 명령 배포 관리자
+	overrides  *control.OverrideManager // 운영자 오버라이드 세션
+	auth       *auth.Authenticator      // API 토큰 인증
+	sim        *sim.Runner              // what-if 시뮬레이터
+	prices     price.Feed               // 전력 가격 피드
+	drops      *drops.Recorder          // 드롭/거절 기록기
+	collector  ManualCollector          // 즉시 수집 (/api/collect)
+	groups     *group.Registry          // 장치 그룹 (가상 장치 집계)
+	alerts     *group.Alerter           // 그룹 경보
+	webhooks   *webhook.Manager         // 웹훅 구독 관리
+	audit      *audit.Recorder          // 감사/접근 이벤트 기록
+	exporter   Exporter                 // 구간 내보내기 저장소
+	checks     []ReadinessCheck         // 준비 상태(readiness) 검사 목록
+
+	batchMax       int            // 일괄 제어 최대 명령 수
+	waitMax        time.Duration  // 명령 완료 대기 최대 시간
+	search         *search.Index  // 장치/명령/경보 검색
+	exportMaxRange time.Duration  // 내보내기 최대 구간
+	bodyLimits     bodyLimits     // 요청 본문 크기 제한 (전역 + 라우트별)
+	timeouts       routeTimeouts  // 라우트별 요청 타임아웃
+	metrics        httpMetrics    // HTTP 요청 메트릭
+	cache          *responseCache // 읽기 API 응답 캐시
+	tracer         trace.Tracer   // 요청 추적 (OpenTelemetry)
 }
 
 /*
@@ -33,7 +111,8 @@ type Server struct {
  *  - HTTP 라우터를 초기화하고, 각 엔드포인트를 등록합니다.
  *  - 반환값 : *Server (HTTP 서버 객체)
  */
-func NewHTTPServer(log *zap.Logger) *Server {
+func NewHTTPServer(p ServerParams) *Server {
+	log := p.Log
 	portStr := os.Getenv("APP_PORT")
 	if portStr == "" {
 		portStr = "8080" // 기본값 8080
@@ -50,17 +129,66 @@ func NewHTTPServer(log *zap.Logger) *Server {
 		log:    log,    // 로깅 도구
 		router: r,      // 라우터
 		port:   port,   // 기본 포트 8080
+		listen: listenSpecs(log, fmt.Sprintf(":%d", port)), // APP_LISTEN 목록, 없으면 APP_SOCKET 또는 APP_PORT
+
+		bus:        p.Bus,        // 이벤트 버스
+		latest:     p.Latest,     // 최신값 저장소
+		dispatcher: p.Dispatcher, // 제어 명령 접수기
+		rollouts:   p.Rollouts,   // 단계적 배포 관리자
+		overrides:  p.Overrides,  // 운영자 오버라이드 세션
+		auth:       p.Auth,       // API 토큰 인증
+		sim:        p.Sim,        // what-if 시뮬레이터
+		prices:     p.Prices,     // 전력 가격 피드
+		drops:      p.Drops,      // 드롭/거절 기록기
+		collector:  p.Collector,  // 즉시 수집
+		groups:     p.Groups,     // 장치 그룹
+		alerts:     p.Alerts,     // 그룹 경보
+		webhooks:   p.Webhooks,   // 웹훅 구독 관리
+		audit:      p.Audit,      // 감사/접근 이벤트 기록
+		exporter:   p.Exporter,   // 구간 내보내기 저장소
+		checks:     p.Checks,     // 준비 상태 검사 목록
+
+		batchMax:       batchMax(log),                // 일괄 제어 최대 명령 수
+		waitMax:        controlWaitMax(log),          // 명령 완료 대기 최대 시간
+		search:         search.NewIndex(p.Search),    // 검색 공급원
+		exportMaxRange: exportMaxRange(log),          // 내보내기 최대 구간
+		bodyLimits:     newBodyLimits(log),           // 요청 본문 크기 제한
+		timeouts:       newRouteTimeouts(log),        // 라우트별 타임아웃
+		metrics:        newHTTPMetrics(p.Metrics),    // HTTP 요청 메트릭
+		cache:          newResponseCache(log, p.Bus), // 읽기 API 응답 캐시
+		tracer:         p.Tracing.Tracer(tracerName), // 요청 추적
 	}
 
+	// === 미들웨어 등록 ===
+	// 요청 추적: traceparent를 이어받아 서버 스팬 생성 (이후 미들웨어·핸들러·버스 구독자가 같은 트레이스)
+	r.Use(s.traceRequests)
+	// 접근 로그: 모든 요청을 감사 이벤트로 기록 (SIEM 전송 대상)
+	r.Use(s.accessLog)
+	// 요청 메트릭: 라우트/메서드/상태 코드별 요청 수와 처리 시간
+	r.Use(s.instrument)
+	// 요청 본문 크기 제한: 라우트 이름(.Name)으로 APP_HTTP_MAX_BODY_ROUTES 재정의 적용
+	r.Use(s.bodyLimit)
+	// 조건부 GET: 응답 본문 해시로 ETag를 붙이고 If-None-Match가 일치하면 304
+	r.Use(s.conditionalGet)
+	// 읽기 API 응답 캐시: 라우트별 TTL 동안 같은 GET 요청에 저장된 응답 반환 (APP_HTTP_CACHE_TTLS)
+	r.Use(s.responseCacheMW)
+	// 라우트별 타임아웃: 마감 시간 초과 시 504 (APP_HTTP_ROUTE_TIMEOUTS로 재정의)
+	r.Use(s.routeTimeout)
+
 	// === 라우팅 등록 ===
-	// 헬스 체크 API: 서버 상태 확인용
-	r.HandleFunc("/healthz", s.handleHealth).Methods(http.MethodGet)
+	// 기본 핸들러 묶음(builtinRoutes)을 먼저, 다른 모듈이 등록한 라우트(group:"routes", 내장 웹 대시보드 등)를 그다음에 등록
+	for _, reg := range s.builtinRoutes() {
+		reg.RegisterRoutes(r)
+	}
+	for _, reg := range p.Routes {
+		reg.RegisterRoutes(r)
+	}
+	log.Info("route registrars mounted", zap.Int("count", len(p.Routes)))
 
-	// 간단한 Ping API: 응답에 "pong"을 반환
-	r.HandleFunc("/api/ping", s.handlePing).Methods(http.MethodGet)
+	// 404/405: 표준 에러 봉투로 응답 (접근 로그·메트릭 미들웨어 포함)
+	s.registerFallbackHandlers()
 
-	// 제어 명령 API: /api/control?action=charge&kw10=50와 같은 형태로 제어 명령을 처리
-	r.HandleFunc("/api/control", s.handleControl).Methods(http.MethodPost)
+	s.tls = tlsConfig(log, s.listen) // https 리스너가 있을 때만 인증서 로드
 
 	// 생성된 Server 객체 반환
 	return s
@@ -75,27 +203,40 @@ func RegisterHooks(lc fx.Lifecycle, s *Server) {
 	lc.Append(fx.Hook{
 		// 애플리케이션 시작 시 서버 시작
 		OnStart: func(ctx context.Context) error {
-			// 서버 주소 구성
-			addr := fmt.Sprintf(":%d", s.port)
+			// 리스너를 모두 먼저 열어 주소 충돌/권한 문제를 시작 단계에서 에러로 돌려줌
+			lns, err := openListeners(s.listen, s.tls)
+			if err != nil {
+				return fmt.Errorf("http %w", err)
+			}
+
+			// 응답 쓰기 타임아웃은 가장 긴 라우트 타임아웃보다 길어야 504 응답을 보낼 수 있음
+			writeTimeout := 10 * time.Second
+			if m := s.timeouts.max() + 5*time.Second; m > writeTimeout {
+				writeTimeout = m
+			}
 
 			// HTTP 서버 설정
 			s.srv = &http.Server{
-				Addr:              addr,             // 서버 주소
+				Addr:              s.listen[0].Addr,  // 대표 주소 (진단용, 실제 리스너는 lns)
 				Handler:           s.router,          // 요청을 처리할 라우터
 				ReadHeaderTimeout: 5 * time.Second,   // HTTP 헤더 읽기 타임아웃
 				ReadTimeout:       10 * time.Second,  // HTTP 요청 읽기 타임아웃
-				WriteTimeout:      10 * time.Second,  // HTTP 응답 쓰기 타임아웃
+				WriteTimeout:      writeTimeout,      // HTTP 응답 쓰기 타임아웃 (라우트 타임아웃 + 여유)
 				IdleTimeout:       60 * time.Second,  // 유휴 상태의 타임아웃
 			}
 
-			// 서버를 고루틴에서 실행 (비동기 실행)
-			go func() {
-				s.log.Info("http server starting", zap.String("addr", addr))
-				// 서버 실행 (서버가 종료되면 에러 로그 출력)
-				if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-					s.log.Error("http server error", zap.Error(err))
-				}
-			}()
+			// 리스너마다 고루틴에서 실행 (하나의 http.Server를 공유하므로 Shutdown 한 번으로 모두 종료)
+			for i, ln := range lns {
+				l := s.listen[i]
+				go func() {
+					s.log.Info("http server starting", zap.Stringer("listen", l))
+					// 서버 실행 (서버가 종료되면 에러 로그 출력)
+					if err := s.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+						s.log.Error("http server error", zap.Stringer("listen", l), zap.Error(err))
+					}
+					s.log.Info("http listener closed", zap.Stringer("listen", l))
+				}()
+			}
 			return nil
 		},
 		// 애플리케이션 종료 시 서버 종료
@@ -111,49 +252,66 @@ func RegisterHooks(lc fx.Lifecycle, s *Server) {
 
 // ===== Handlers =====
 
-/*
- * handleHealth : 헬스 체크 엔드포인트
- *  - 서버 상태를 확인하는 간단한 핑 요청을 처리합니다.
- */
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)          // HTTP 상태 코드 200 OK 반환
-	_, _ = w.Write([]byte("ok"))         // 응답 본문에 "ok" 메시지 반환
-}
-
 /*
  * handlePing : 간단한 Ping 엔드포인트
  *  - 서버가 정상적으로 작동하는지 확인하는 데 사용됩니다.
  */
 func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)          // HTTP 상태 코드 200 OK 반환
-	_, _ = w.Write([]byte(`{"pong":true}`)) // JSON 응답: {"pong": true}
-}
-
-/*
- * controlReq : 제어 명령 요청을 처리하기 위한 구조체
- *  - Action : 수행할 액션 (예: "charge", "discharge", "on", "off" 등)
- *  - KW10 : kW 단위로 10배수로 지정된 값 (예: 50은 5.0kW)
- */
-type controlReq struct {
-	Action string `json:"action"` // 예: charge|discharge|on|off
-	KW10   int    `json:"kw10"`   // kW*10 (예: 50 => 5.0kW)
+	respond(w, r, http.StatusOK, map[string]bool{"pong": true}) // 응답: {"pong": true} (Accept에 따라 JSON/CBOR/MessagePack)
 }
 
 /*
  * handleControl : 제어 명령을 처리하는 엔드포인트
- *  - 요청: /api/control?action=charge&kw10=50 형태의 쿼리 파라미터로 전달
+ *  - 요청: /api/control?device=A1&action=charge&kw10=50 형태의 쿼리 파라미터로 전달
+ *  - 장치별 속도 제한(최소 간격, 시간당 방향 전환 횟수)을 통과해야 접수됩니다.
+ *    제한에 걸리면 429 Too Many Requests와 Retry-After 헤더로 응답합니다.
  *  - 실제 제어는 나중에 연결될 수 있음 (현재는 단순한 응답을 보냄)
  */
 func (s *Server) handleControl(w http.ResponseWriter, r *http.Request) {
 	// 요청에서 쿼리 파라미터 받기
 	q := r.URL.Query()
+	device := q.Get("device") // device: 제어 대상 장치 ID
 	action := q.Get("action") // action: charge|discharge|ready|on|off
 	kw10 := q.Get("kw10")     // kw10: kW 단위 (예: 50 => 5.0kW)
 
 	// 요청 로그 출력
-	s.log.Info("control request received", zap.String("action", action), zap.String("kw10", kw10))
+	s.log.Info("control request received", zap.String("device", device), zap.String("action", action), zap.String("kw10", kw10))
+
+	req := control.Request{DeviceID: device, Action: action}
+	if kw10 != "" {
+		kw, err := strconv.Atoi(kw10)
+		if err != nil {
+			respondValidation(w, r, []fieldError{{Field: "kw10", Rule: "numeric", Message: "kw10 must be an integer"}})
+			return
+		}
+		req.KW10 = kw
+	}
+	// control.Request의 구조체 태그 검증 (장치 필수, 알려진 액션, kw10 범위) → 실패 시 필드별 400
+	if !checkValid(w, r, &req) {
+		return
+	}
+
+	// 명령 접수 (장치별 속도 제한 검사 포함)
+	cmd, err := s.dispatcher.Submit(r.Context(), req.DeviceID, req.Action, req.KW10)
+	if err != nil {
+		var le *control.LimitError
+		s.auditEvent(r, audit.CategoryControl, "control.submit", device, "action="+action+" kw10="+kw10, err)
+		if errors.As(err, &le) {
+			s.drops.Record("control", drops.ReasonQuota, device, le.Error())
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(le.RetryAfter.Seconds()))))
+			respondError(w, r, http.StatusTooManyRequests, "rate_limited", le.Error())
+			return
+		}
+		respondError(w, r, http.StatusInternalServerError, "internal", err.Error())
+		return
+	}
+
+	detail := "id=" + cmd.ID + " action=" + action + " kw10=" + kw10
+	if cmd.Override != "" {
+		detail += " override=" + cmd.Override
+	}
+	s.auditEvent(r, audit.CategoryControl, "control.submit", device, detail, nil)
 
 	// 응답 반환: 명령이 큐에 추가되었음을 나타내는 상태 코드 202 (Accepted)
-	w.WriteHeader(http.StatusAccepted)
-	_, _ = w.Write([]byte(`{"status":"queued"}`)) // {"status": "queued"} 메시지 응답
+	respond(w, r, http.StatusAccepted, map[string]string{"status": string(cmd.Status), "id": cmd.ID})
 }
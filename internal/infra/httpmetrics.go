@@ -0,0 +1,76 @@
+/*
+ * HTTP 요청 메트릭 미들웨어
+ *  - scaffold_http_requests_total{route,method,code}        : 요청 수
+ *  - scaffold_http_request_duration_seconds{route,method}   : 처리 시간 분포
+ *  - route 라벨은 라우트 이름(.Name) → 경로 템플릿 순으로 정하고,
+ *    매칭된 라우트가 없는 요청(404/405)은 "unmatched"로 묶어 라벨 수가 늘어나지 않게 합니다.
+ */
+package infra
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"                         // 현재 라우트 조회
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 타입
+)
+
+// httpMetrics : HTTP 요청 메트릭 묶음
+type httpMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// newHTTPMetrics : HTTP 메트릭 생성 및 레지스트리 등록
+func newHTTPMetrics(reg *prometheus.Registry) httpMetrics {
+	m := httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled by the public API, by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request handling time, by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+	}
+	reg.MustRegister(m.requests, m.duration)
+	return m
+}
+
+// routeLabel : 메트릭 라벨로 쓸 라우트 이름
+func routeLabel(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unmatched"
+	}
+	if name := route.GetName(); name != "" {
+		return name
+	}
+	if tpl, err := route.GetPathTemplate(); err == nil {
+		return tpl
+	}
+	return "unmatched"
+}
+
+/*
+ * instrument : 요청 수와 처리 시간을 기록하는 미들웨어
+ */
+func (s *Server) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sr := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(sr, r)
+
+		status := sr.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		route := routeLabel(r)
+		s.metrics.requests.WithLabelValues(route, r.Method, strconv.Itoa(status)).Inc()
+		s.metrics.duration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	})
+}
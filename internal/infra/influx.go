@@ -6,19 +6,27 @@
  *      - cfg : InfluxDB 연결 및 설정 정보 (Config)
  *      - client : InfluxDB 클라이언트 (client.Client)
  *  - 기능 :
- *      - 데이터 수집 이벤트를 EventBus에서 구독
- *      - 이벤트가 발생하면 데이터를 InfluxDB에 저장
+ *      - 수집 데이터를 InfluxDB에 저장 (TimeSeriesStore 구현)
+ *      - 데이터 수집 이벤트의 EventBus 구독/저널 소비는 NewInfluxStore가 연결 (store.go)
  *      - 이벤트 구독은 비동기로 처리
  */
 package infra
 
 import (
 	"context"
-	"generic-api-scaffold/internal/bus"  // 이벤트 처리 (DataCollectedEvent)
+	"encoding/json"
+	"fmt"
+	"io"
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops" // 쓰기 실패/거절 기록
+	"generic-api-scaffold/internal/telemetry" // 조회 결과 샘플
 	
 	"time"
 	"os"
 	"github.com/influxdata/influxdb1-client/v2" // InfluxDB 1.x 클라이언트
+	"go.opentelemetry.io/otel/attribute" // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"     // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"     // 쓰기 스팬 (발행 트레이스의 하위)
 	"go.uber.org/fx"  // Fx 프레임워크
 	"go.uber.org/zap" // 로깅 도구
 )
@@ -27,16 +35,27 @@ import (
 type InfluxRepo struct {
 	log    *zap.Logger      // 로깅 도구
 	
-	client client.Client    // InfluxDB 클라이언트
+	client    client.Client   // InfluxDB 클라이언트
+	udp       client.Client   // UDP 라인 프로토콜 클라이언트 (UDP 라우팅 규칙이 있을 때만, influx_udp.go)
+	database  string          // 사용할 데이터베이스
+	precision string          // 시간 정밀도
+	schema    influxSchema    // 측정값 이름·태그 구성 (influx_schema.go)
+	drops     *drops.Recorder // 다시 시도해도 소용없는 쓰기 거절 기록
+	tracer    trace.Tracer    // 쓰기 스팬
 }
 
 /*
  * NewInfluxRepo : InfluxRepo 생성자
  *  - fx 프레임워크에 의해 호출되는 생성자 함수
- *  - InfluxDB 클라이언트 설정, EventBus 구독 등록, OnStop 시 client.Close 호출을 설정
+ *  - InfluxDB 클라이언트 설정, OnStop 시 client.Close 호출을 설정
+ *  - APP_INFLUX_GZIP=true면 라인 프로토콜 쓰기 본문을 gzip으로 압축 (기본 false)
+ *  - OnStart 시 APP_INFLUX_AUTO_CREATE=true면 데이터베이스를 만듦 (influx_bootstrap.go)
+ *    APP_INFLUX_RETENTION_POLICIES의 보존 정책을 만들거나 맞춤 (influx_retention.go)
+ *    이어서 APP_INFLUX_DOWNSAMPLE의 연속 질의를 만듦 (influx_downsample.go, 보존 정책이 먼저 있어야 함)
+ *  - 수집 이벤트 기록(EventBus 구독 또는 저널 소비자) 연결은 NewInfluxStore가 담당 (store.go)
  *  - 반환값 : *InfluxRepo (InfluxRepo 객체)
  */
-func NewInfluxRepo(lc fx.Lifecycle, log *zap.Logger, eb *bus.EventBus) *InfluxRepo {
+func NewInfluxRepo(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) *InfluxRepo {
 	// 환경변수로부터 읽은 InfluxDB 관련 값들
 	influxURL := os.Getenv("APP_INFLUX_URL")       // InfluxDB URL
 	influxUsername := os.Getenv("APP_INFLUX_USERNAME") // InfluxDB 사용자 이름
@@ -71,12 +90,19 @@ func NewInfluxRepo(lc fx.Lifecycle, log *zap.Logger, eb *bus.EventBus) *InfluxRe
 		log.Fatal("failed to parse influx timeout", zap.Error(err)) // 변환 실패 시 애플리케이션 종료
 	}
 
+	// 쓰기 본문 gzip 압축 (셀룰러 등 대역폭이 비싼 엣지 환경용)
+	encoding := client.DefaultEncoding
+	if config.Bool(log, "APP_INFLUX_GZIP", false) {
+		encoding = client.GzipEncoding
+	}
+
 	// InfluxDB 클라이언트 생성
 	c, err := client.NewHTTPClient(client.HTTPConfig{
 		Addr:     influxURL,  // InfluxDB 서버 URL
 		Username: influxUsername, // 사용자 이름
 		Password: influxPassword, // 비밀번호
 		Timeout:  timeout,  // 연결 타임아웃
+		WriteEncoding: encoding, // 쓰기 본문 압축
 	})
 	if err != nil {
 		log.Fatal("failed to connect influxdb", zap.Error(err)) // 연결 실패 시 애플리케이션 종료
@@ -86,57 +112,357 @@ func NewInfluxRepo(lc fx.Lifecycle, log *zap.Logger, eb *bus.EventBus) *InfluxRe
 	repo := &InfluxRepo{
 		log:    log,
 		
-		client: c,
+		client:    c,
+		database:  influxDatabase,
+		precision: influxPrecision,
+		schema:    loadInfluxSchema(log),
+		drops:     dr,
+		tracer:    tp.Tracer(tracerName),
+	}
+
+	// UDP로 기록하는 라우팅 규칙이 있으면 UDP 클라이언트 생성
+	if repo.schema.udpRoutes() {
+		repo.udp = newInfluxUDPClient(log)
+	}
+
+	// 데이터베이스 자동 생성, 보존 정책·다운샘플링 설정 (형식 오류는 시작 전에 Fatal)
+	autoCreate := config.Bool(log, "APP_INFLUX_AUTO_CREATE", false)
+	policies := loadRetentionPolicies(log)
+	rules := loadDownsampleRules(log)
+
+	// 시작 시 보존 정책·연속 질의 적용, 애플리케이션 종료 시 클라이언트 연결을 종료하는 후크 등록
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if autoCreate {
+				if err := repo.ensureDatabases(ctx); err != nil {
+					log.Error("influx databases not fully created", zap.Error(err)) // 시작은 막지 않음
+				}
+			}
+			if len(policies) > 0 {
+				if err := repo.ensureRetentionPolicies(ctx, policies); err != nil {
+					log.Error("influx retention policies not fully applied", zap.Error(err)) // 시작은 막지 않음
+				}
+			}
+			if len(rules) > 0 {
+				if err := repo.ensureContinuousQueries(ctx, rules); err != nil {
+					log.Error("influx continuous queries not fully applied", zap.Error(err))
+				}
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return repo.Close()  // InfluxDB 클라이언트 연결 종료
+		},
+	})
+
+	// 생성된 InfluxRepo 객체 반환
+	return repo
+}
+
+/*
+ * WritePoint : 샘플 하나를 InfluxDB에 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *InfluxRepo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 HTTP 쓰기 한 번으로 InfluxDB에 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 쓰기 실패는 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도)
+ *  - 다시 시도해도 소용없는 실패(잘못된 정밀도, 포인트 생성 실패)는 그 샘플만 드롭 기록 후 나머지를 기록
+ *  - 라우팅 규칙(APP_INFLUX_ROUTES)에 따라 데이터베이스마다 HTTP 쓰기 한 번 (:udp 규칙은 UDP로 보냄)
+ */
+func (r *InfluxRepo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
 	}
+	// 쓰기 스팬 (버스로 전달되었으면 묶음 첫 이벤트를 발행한 요청의 트레이스 아래에 기록됨)
+	_, span := r.tracer.Start(ctx, "influx write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "influxdb"),
+			attribute.String("db.namespace", r.database),
+			attribute.Int("influx.points", len(ss)),
+		))
+	defer span.End()
 
-	// EventBus의 구독자 함수 등록
-	// 수집된 데이터 이벤트가 발생하면 InfluxDB에 데이터를 기록
-	eb.Subscribe(func(e bus.DataCollectedEvent) {
-		// 배치 포인트 생성 (InfluxDB에 데이터를 한 번에 전송)
-		bp, _ := client.NewBatchPoints(client.BatchPointsConfig{
-			Database:  influxDatabase,  // 사용할 데이터베이스
-			Precision: influxPrecision, // 시간 정밀도
-		})
+	// 기록할 데이터베이스·전송 방식(라우팅 규칙, influx_schema.go)마다 배치 포인트 생성 (InfluxDB에 데이터를 한 번에 전송)
+	type batchKey struct {
+		database string
+		udp      bool
+	}
+	batches := make(map[batchKey]client.BatchPoints)
+	points := 0
+	for _, s := range ss {
+		// 태그(고정 태그, 장치 ID, 태그로 올린 값)와 필드(예: temperature, humidity) 구성
+		tags, fields := r.schema.point(s)
+		target := r.schema.target(tags)
 
-		// 데이터 포인트에 태그 추가 (예: 장치 ID)
-		tags := map[string]string{
-			"device": e.DeviceID,
+		// 측정값의 필드 타입 선언에 맞춰 검사·변환 (influx_fields.go, 버린 샘플은 드롭 기록 완료)
+		fields, ok := r.schema.checkFields(r.drops, target.measurement, s.DeviceID, fields)
+		if !ok {
+			continue
 		}
+		database := r.databaseOf(target)
+		key := batchKey{database: database, udp: target.udp}
 
-		// 수집된 데이터를 필드에 추가 (예: temperature, humidity)
-		fields := make(map[string]interface{}, len(e.Values))
-		for k, v := range e.Values {
-			fields[k] = v
+		bp, found := batches[key]
+		if !found {
+			var err error
+			bp, err = client.NewBatchPoints(client.BatchPointsConfig{
+				Database:  database,    // 사용할 데이터베이스
+				Precision: r.precision, // 시간 정밀도
+			})
+			if err != nil {
+				r.drops.Record("influx", drops.ReasonValidation, s.DeviceID, err.Error()) // 잘못된 정밀도 설정 등
+				continue
+			}
+			batches[key] = bp
 		}
 
-		// 데이터 포인트 생성
-		pt, err := client.NewPoint("device_data", tags, fields, time.Now())
+		// 데이터 포인트 생성 (시각이 없으면 지금)
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		pt, err := client.NewPoint(target.measurement, tags, fields, at)
 		if err != nil {
-			repo.log.Error("influx point create failed", zap.Error(err)) // 포인트 생성 실패 시 로그
-			return
+			r.log.Error("influx point create failed", zap.Error(err)) // 포인트 생성 실패 시 로그
+			r.drops.Record("influx", drops.ReasonValidation, s.DeviceID, err.Error())
+			continue
 		}
 
 		// 배치 포인트에 데이터 포인트 추가
 		bp.AddPoint(pt)
+		points++
+	}
+	if points == 0 {
+		return nil
+	}
 
-		// 배치 포인트를 InfluxDB에 기록
-		if err := repo.client.Write(bp); err != nil {
-			repo.log.Error("influx write failed", zap.Error(err)) // 쓰기 실패 시 로그
-			return
+	// 배치 포인트를 데이터베이스마다 InfluxDB에 기록 (하나라도 실패하면 묶음 전체를 다시 시도, 같은 포인트는 덮어써짐)
+	// UDP 묶음은 최선 노력으로 보내고 실패해도 다시 시도하지 않음
+	for key, bp := range batches {
+		if len(bp.Points()) == 0 {
+			continue
+		}
+		if key.udp {
+			r.writeUDP(bp)
+			continue
+		}
+		if err := r.client.Write(bp); err != nil {
+			r.log.Error("influx write failed", zap.String("database", key.database), zap.Error(err)) // 쓰기 실패 시 로그
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("influx write: %w", err)
 		}
+	}
 
-		// 성공적인 데이터 기록 로그
-		repo.log.Info("influx write success", zap.String("device", e.DeviceID))
-	})
+	// 성공적인 데이터 기록 로그
+	r.log.Debug("influx write success", zap.Int("points", points))
+	return nil
+}
 
-	// 애플리케이션 종료 시 클라이언트 연결을 종료하는 후크 등록
-	lc.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-			repo.client.Close()  // InfluxDB 클라이언트 연결 종료
+/*
+ * Ping : InfluxDB 서버 도달 가능 여부 확인
+ *  - ctx에 마감 시간이 있으면 그 남은 시간을 Ping 타임아웃으로 사용
+ *  - readiness 검사에서 사용됩니다.
+ */
+func (r *InfluxRepo) Ping(ctx context.Context) error {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	_, _, err := r.client.Ping(timeout)
+	return err
+}
+
+/*
+ * Close : InfluxDB 클라이언트 연결 종료
+ */
+func (r *InfluxRepo) Close() error {
+	if r.udp != nil {
+		r.udp.Close() // UDP 소켓 정리 (보낸 뒤 기다릴 응답이 없음)
+	}
+	return r.client.Close()
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회
+ *  - InfluxQL : SELECT * FROM <측정값> WHERE device = '<id>' AND time >= from AND time < to
+ *  - 숫자 필드만 Values에 담고, 태그 컬럼(device, 고정 태그 등)과 숫자가 아닌 값은 건너뜁니다.
+ *  - 시뮬레이션(sim.Source) 등 과거 데이터가 필요한 곳에서 사용됩니다.
+ */
+func (r *InfluxRepo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	target := r.schema.deviceTarget(deviceID) // 라우팅 규칙에 따른 측정값/데이터베이스
+	cmd := fmt.Sprintf(
+		"SELECT * FROM %s WHERE device = '%s' AND time >= '%s' AND time < '%s' ORDER BY time ASC",
+		influxQLIdent(target.measurement), escapeInfluxString(deviceID), from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano),
+	)
+	resp, err := r.client.Query(client.NewQuery(cmd, r.databaseOf(target), "ns"))
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Error(); err != nil {
+		return nil, err
+	}
+
+	var out []telemetry.Sample
+	for _, res := range resp.Results {
+		for _, row := range res.Series {
+			for _, vals := range row.Values {
+				out = append(out, rowSample(deviceID, row.Columns, vals))
+			}
+		}
+	}
+	return out, nil
+}
+
+/*
+ * rowSample : Influx 응답 행 하나 → Sample
+ *  - "time" 열은 epoch 나노초 정수 (정밀도 "ns"로 조회, float64 변환 시 정밀도 손실 주의)
+ *  - "device" 태그 열과 값이 없는(nil) 열은 건너뜀
+ */
+func rowSample(deviceID string, columns []string, vals []interface{}) telemetry.Sample {
+	s := telemetry.Sample{DeviceID: deviceID, Values: make(map[string]float64)}
+	for i, col := range columns {
+		if i >= len(vals) || vals[i] == nil {
+			continue
+		}
+		if col == "time" {
+			if n, ok := vals[i].(json.Number); ok {
+				if ns, err := n.Int64(); err == nil {
+					s.Time = time.Unix(0, ns)
+				}
+			}
+			continue
+		}
+		if col == "device" {
+			continue
+		}
+		if f, ok := toFloat(vals[i]); ok {
+			s.Values[col] = f
+		}
+	}
+	return s
+}
+
+// 스트리밍 조회 시 Influx가 한 번에 보내는 행 수
+const streamChunkSize = 1000
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 청크 단위로 조회하며 샘플마다 fn 호출
+ *  - Window와 달리 전체 결과를 메모리에 올리지 않으므로 긴 구간 내보내기에 사용
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 조회를 중단하고 그 에러를 반환
+ */
+func (r *InfluxRepo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	target := r.schema.deviceTarget(deviceID) // 라우팅 규칙에 따른 측정값/데이터베이스
+	cmd := fmt.Sprintf(
+		"SELECT * FROM %s WHERE device = '%s' AND time >= '%s' AND time < '%s' ORDER BY time ASC",
+		influxQLIdent(target.measurement), escapeInfluxString(deviceID), from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano),
+	)
+	q := client.NewQuery(cmd, r.databaseOf(target), "ns")
+	q.Chunked = true
+	q.ChunkSize = streamChunkSize
+
+	cr, err := r.client.QueryAsChunk(q)
+	if err != nil {
+		return err
+	}
+	defer cr.Close()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := cr.NextResponse()
+		if err == io.EOF {
 			return nil
-		},
-	})
+		}
+		if err != nil {
+			return err
+		}
+		if err := resp.Error(); err != nil {
+			return err
+		}
+		for _, res := range resp.Results {
+			for _, row := range res.Series {
+				for _, vals := range row.Values {
+					if err := fn(rowSample(deviceID, row.Columns, vals)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+}
 
-	// 생성된 InfluxRepo 객체 반환
-	return repo
+/*
+ * FieldKeys : 측정값(APP_INFLUX_MEASUREMENT)과 라우팅 규칙의 측정값에 저장된 필드 이름 목록 (합집합, 정렬)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *InfluxRepo) FieldKeys(ctx context.Context) ([]string, error) {
+	keys := make(map[string]bool)
+	for _, t := range r.schema.queryTargets() {
+		resp, err := r.client.Query(client.NewQuery("SHOW FIELD KEYS FROM "+influxQLIdent(t.measurement), r.databaseOf(t), ""))
+		if err != nil {
+			return nil, err
+		}
+		if err := resp.Error(); err != nil {
+			return nil, err
+		}
+		for _, res := range resp.Results {
+			for _, row := range res.Series {
+				for _, vals := range row.Values {
+					if len(vals) > 0 {
+						if k, ok := vals[0].(string); ok {
+							keys[k] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return sortedKeys(keys), nil
+}
+
+// databaseOf : 기록 위치의 데이터베이스 (라우팅 규칙에 없으면 APP_INFLUX_DATABASE)
+func (r *InfluxRepo) databaseOf(t influxTarget) string {
+	if t.database == "" {
+		return r.database
+	}
+	return t.database
+}
+
+// escapeInfluxString : InfluxQL 문자열 리터럴용 작은따옴표/역슬래시 이스케이프
+func escapeInfluxString(v string) string {
+	out := make([]rune, 0, len(v))
+	for _, c := range v {
+		if c == '\\' || c == '\'' {
+			out = append(out, '\\')
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+// toFloat : Influx 응답 값(json.Number, float64 등)을 float64로 변환
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case bool: // 필드 타입을 bool로 선언한 값 (influx_fields.go)
+		if n {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
 }
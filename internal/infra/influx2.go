@@ -0,0 +1,329 @@
+/*
+ * Influx2Repo : InfluxDB 2.x 저장소 (APP_INFLUX_VERSION=2)
+ *  - 1.x의 사용자 이름/비밀번호 + 데이터베이스 대신 토큰 + org + bucket으로 접속합니다.
+ *  - 기록은 InfluxRepo와 같은 스키마(influx_schema.go)로 하므로, 버전을 바꿔도 조회/내보내기 결과가 같습니다.
+ *  - 조회는 Flux로 하며, 필드를 열로 펼쳐(pivot) 시각마다 샘플 하나로 만듭니다.
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2" // InfluxDB 2.x 클라이언트
+	"github.com/influxdata/influxdb-client-go/v2/api"       // 쓰기/조회 API
+	"github.com/influxdata/influxdb-client-go/v2/api/query" // Flux 조회 결과 레코드
+	"github.com/influxdata/influxdb-client-go/v2/api/write" // 쓰기 포인트
+	"go.opentelemetry.io/otel/attribute"                    // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"                        // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"                        // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                                        // Fx 프레임워크
+	"go.uber.org/zap"                                       // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+// FieldKeys 조회 범위 (Flux schema 함수는 시작 시각이 필요)
+const fieldKeysLookback = "-30d"
+
+// Influx2Repo : InfluxDB 2.x에 데이터를 쓰고 읽는 저장소
+type Influx2Repo struct {
+	log    *zap.Logger
+	client influxdb2.Client
+	writer api.WriteAPIBlocking
+	reader api.QueryAPI
+	bucket string
+	org    string
+	schema influxSchema
+	drops  *drops.Recorder
+	tracer trace.Tracer
+}
+
+/*
+ * NewInflux2Repo : Influx2Repo 생성자 (NewInfluxStore가 APP_INFLUX_VERSION=2일 때 호출)
+ *  - APP_INFLUX_URL       : 서버 URL (기본 http://localhost:8086)
+ *  - APP_INFLUX_TOKEN     : API 토큰
+ *  - APP_INFLUX_ORG       : 조직 이름 (필수)
+ *  - APP_INFLUX_BUCKET    : 버킷 이름 (필수)
+ *  - APP_INFLUX_PRECISION : 시간 정밀도 ns | us | ms | s (기본 s, 1.x와 같음)
+ *  - APP_INFLUX_TIMEOUT   : 요청 타임아웃 (기본 5s)
+ *  - APP_INFLUX_GZIP      : 쓰기 본문 gzip 압축 (기본 false, 1.x와 같음)
+ *  - OnStart 시 APP_INFLUX_AUTO_CREATE=true면 없는 버킷을 만듦 (influx_bootstrap.go)
+ *    이어서 APP_INFLUX_DOWNSAMPLE의 다운샘플링 태스크를 만듦 (influx_downsample.go)
+ *  - OnStop 시 클라이언트 종료
+ */
+func NewInflux2Repo(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) *Influx2Repo {
+	url := config.String("APP_INFLUX_URL", "http://localhost:8086")
+	org := config.String("APP_INFLUX_ORG", "")
+	bucket := config.String("APP_INFLUX_BUCKET", "")
+	if org == "" || bucket == "" {
+		log.Fatal("APP_INFLUX_ORG and APP_INFLUX_BUCKET are required for APP_INFLUX_VERSION=2")
+	}
+	precision, ok := influx2Precision(config.String("APP_INFLUX_PRECISION", "s"))
+	if !ok {
+		log.Fatal("invalid APP_INFLUX_PRECISION, expected ns|us|ms|s", zap.String("value", config.String("APP_INFLUX_PRECISION", "")))
+	}
+	timeout := config.Duration(log, "APP_INFLUX_TIMEOUT", 5*time.Second)
+
+	c := influxdb2.NewClientWithOptions(url, config.String("APP_INFLUX_TOKEN", ""),
+		influxdb2.DefaultOptions().SetHTTPRequestTimeout(uint(timeout.Seconds())).SetPrecision(precision).
+			SetUseGZip(config.Bool(log, "APP_INFLUX_GZIP", false)))
+	repo := &Influx2Repo{
+		log:    log,
+		client: c,
+		writer: c.WriteAPIBlocking(org, bucket),
+		reader: c.QueryAPI(org),
+		bucket: bucket,
+		org:    org,
+		schema: loadInfluxSchema(log),
+		drops:  dr,
+		tracer: tp.Tracer(tracerName),
+	}
+	autoCreate := config.Bool(log, "APP_INFLUX_AUTO_CREATE", false)
+	rules := loadDownsampleRules(log)
+	if repo.schema.udpRoutes() {
+		log.Warn("udp routes in APP_INFLUX_ROUTES apply to InfluxDB 1.x only, writing them over HTTP")
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if autoCreate {
+				if err := repo.ensureBuckets(ctx); err != nil {
+					log.Error("influx buckets not fully created", zap.Error(err)) // 시작은 막지 않음
+				}
+			}
+			if len(rules) > 0 {
+				if err := repo.ensureTasks(ctx, rules); err != nil {
+					log.Error("influx downsampling tasks not fully applied", zap.Error(err)) // 시작은 막지 않음
+				}
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return repo.Close()
+		},
+	})
+	log.Info("influxdb 2.x store", zap.String("url", url), zap.String("org", org), zap.String("bucket", bucket))
+	return repo
+}
+
+// influx2Precision : 1.x 정밀도 표기 → 2.x 클라이언트의 시간 단위
+func influx2Precision(p string) (time.Duration, bool) {
+	switch p {
+	case "ns":
+		return time.Nanosecond, true
+	case "us", "u":
+		return time.Microsecond, true
+	case "ms":
+		return time.Millisecond, true
+	case "s":
+		return time.Second, true
+	}
+	return 0, false
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *Influx2Repo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 요청 한 번으로 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 필드가 없는 샘플은 기록할 수 없으므로 드롭 기록 후 건너뜀
+ *  - 라우팅 규칙(APP_INFLUX_ROUTES)에 따라 버킷마다 요청 한 번
+ *  - 쓰기 실패는 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도)
+ */
+func (r *Influx2Repo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := r.tracer.Start(ctx, "influx write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "influxdb"),
+			attribute.String("db.namespace", r.bucket),
+			attribute.Int("influx.points", len(ss)),
+		))
+	defer span.End()
+
+	pts := make(map[string][]*write.Point) // 버킷(라우팅 규칙, influx_schema.go) → 포인트
+	points := 0
+	for _, s := range ss {
+		tags, fields := r.schema.point(s)
+		if len(fields) == 0 {
+			r.drops.Record("influx", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+			continue
+		}
+		target := r.schema.target(tags)
+		fields, ok := r.schema.checkFields(r.drops, target.measurement, s.DeviceID, fields) // 필드 타입 선언 검사 (influx_fields.go)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		bucket := r.bucketOf(target)
+		pts[bucket] = append(pts[bucket], influxdb2.NewPoint(target.measurement, tags, fields, at))
+		points++
+	}
+	if points == 0 {
+		return nil
+	}
+
+	for bucket, bp := range pts {
+		writer := r.writer
+		if bucket != r.bucket {
+			writer = r.client.WriteAPIBlocking(r.org, bucket)
+		}
+		if err := writer.WritePoint(ctx, bp...); err != nil {
+			r.log.Error("influx write failed", zap.String("bucket", bucket), zap.Error(err))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("influx write: %w", err)
+		}
+	}
+	r.log.Debug("influx write success", zap.Int("points", points))
+	return nil
+}
+
+/*
+ * Ping : InfluxDB 서버 도달 가능 여부 확인 (readiness 검사)
+ */
+func (r *Influx2Repo) Ping(ctx context.Context) error {
+	ok, err := r.client.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("influxdb is not ready")
+	}
+	return nil
+}
+
+/*
+ * Close : 클라이언트 종료
+ */
+func (r *Influx2Repo) Close() error {
+	r.client.Close()
+	return nil
+}
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (TimeSeriesStore)
+ *  - 장치별 원시 구간(Window)을 읽어 앱에서 집계 (query.go)
+ */
+func (r *Influx2Repo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	return windowQuery(ctx, spec, r.Window)
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회 (sim.Source)
+ */
+func (r *Influx2Repo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	var out []telemetry.Sample
+	err := r.Stream(ctx, deviceID, from, to, func(s telemetry.Sample) error {
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 읽으며 샘플마다 fn 호출 (Exporter)
+ *  - 결과를 레코드 단위로 읽으므로 전체 결과를 메모리에 올리지 않음
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 조회를 중단하고 그 에러를 반환
+ */
+func (r *Influx2Repo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	target := r.schema.deviceTarget(deviceID) // 라우팅 규칙에 따른 측정값/버킷
+	flux := fmt.Sprintf(`from(bucket: "%s")
+  |> range(start: %s, stop: %s)
+  |> filter(fn: (r) => r._measurement == "%s" and r.device == "%s")
+  |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+  |> sort(columns: ["_time"])`,
+		escapeFluxString(r.bucketOf(target)), from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano),
+		escapeFluxString(target.measurement), escapeFluxString(deviceID))
+	res, err := r.reader.Query(ctx, flux)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(recordSample(deviceID, res.Record())); err != nil {
+			return err
+		}
+	}
+	return res.Err()
+}
+
+/*
+ * recordSample : 펼친(pivot) Flux 레코드 하나 → Sample
+ *  - "_"로 시작하는 열(_time, _measurement 등), result/table 열, 태그 열(문자열)처럼 숫자가 아닌 값은 건너뜀
+ */
+func recordSample(deviceID string, rec *query.FluxRecord) telemetry.Sample {
+	s := telemetry.Sample{Time: rec.Time(), DeviceID: deviceID, Values: make(map[string]float64)}
+	for k, v := range rec.Values() {
+		if strings.HasPrefix(k, "_") || k == "result" || k == "table" || k == deviceTag || v == nil {
+			continue
+		}
+		if f, ok := toFloat(v); ok {
+			s.Values[k] = f
+		}
+	}
+	return s
+}
+
+/*
+ * FieldKeys : 측정값(APP_INFLUX_MEASUREMENT)과 라우팅 규칙의 측정값에 저장된 필드 이름 목록 (합집합, 정렬, 최근 30일 기준)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *Influx2Repo) FieldKeys(ctx context.Context) ([]string, error) {
+	keys := make(map[string]bool)
+	for _, t := range r.schema.queryTargets() {
+		if err := r.fieldKeys(ctx, t, keys); err != nil {
+			return nil, err
+		}
+	}
+	return sortedKeys(keys), nil
+}
+
+// fieldKeys : 기록 위치 하나의 필드 이름을 keys에 추가
+func (r *Influx2Repo) fieldKeys(ctx context.Context, t influxTarget, keys map[string]bool) error {
+	flux := fmt.Sprintf(`import "influxdata/influxdb/schema"
+schema.measurementFieldKeys(bucket: "%s", measurement: "%s", start: %s)`,
+		escapeFluxString(r.bucketOf(t)), escapeFluxString(t.measurement), fieldKeysLookback)
+	res, err := r.reader.Query(ctx, flux)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		if k, ok := res.Record().Value().(string); ok {
+			keys[k] = true
+		}
+	}
+	return res.Err()
+}
+
+// bucketOf : 기록 위치의 버킷 (라우팅 규칙에 없으면 APP_INFLUX_BUCKET)
+func (r *Influx2Repo) bucketOf(t influxTarget) string {
+	if t.database == "" {
+		return r.bucket
+	}
+	return t.database
+}
+
+// escapeFluxString : Flux 문자열 리터럴용 큰따옴표/역슬래시 이스케이프
+func escapeFluxString(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v)
+}
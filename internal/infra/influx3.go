@@ -0,0 +1,276 @@
+//go:build !edge
+
+/*
+ * Influx3Repo : InfluxDB 3 저장소 (APP_INFLUX_VERSION=3, Cloud Dedicated/Clustered 등, 기본 빌드 전용)
+ *  - 기록은 v3 쓰기 API(라인 프로토콜), 조회는 FlightSQL(SQL)로 합니다. (influxdb3-go 클라이언트)
+ *  - 토큰 + 데이터베이스로 접속하며, 1.x/2.x와 같은 스키마(influx_schema.go)로 기록합니다.
+ *  - FlightSQL 결과의 time 열이 샘플 시각, 나머지 숫자 열이 필드 값이 됩니다.
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/InfluxCommunity/influxdb3-go/v2/influxdb3" // InfluxDB 3 클라이언트 (쓰기 API + FlightSQL)
+	"go.opentelemetry.io/otel/attribute"                   // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"                       // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"                       // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                                       // Fx 프레임워크
+	"go.uber.org/zap"                                      // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+var _ InfluxStore = (*Influx3Repo)(nil)
+
+// Influx3Repo : InfluxDB 3에 데이터를 쓰고 FlightSQL로 읽는 저장소
+type Influx3Repo struct {
+	log      *zap.Logger
+	client   *influxdb3.Client
+	database string
+	schema   influxSchema
+	drops    *drops.Recorder
+	tracer   trace.Tracer
+}
+
+// newInflux3Store : newInfluxBackend가 APP_INFLUX_VERSION=3일 때 호출 (edge 빌드는 influx3_edge.go)
+func newInflux3Store(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	return NewInflux3Repo(lc, log, dr, tp)
+}
+
+/*
+ * NewInflux3Repo : Influx3Repo 생성자 (NewInfluxStore가 APP_INFLUX_VERSION=3일 때 호출)
+ *  - APP_INFLUX_URL      : 서버 URL (기본 http://localhost:8086, FlightSQL도 같은 호스트)
+ *  - APP_INFLUX_TOKEN    : 데이터베이스 토큰
+ *  - APP_INFLUX_DATABASE : 데이터베이스 이름 (기본 resort, 1.x와 같음)
+ *  - APP_INFLUX_TIMEOUT  : 쓰기 요청 타임아웃 (기본 5s, 조회는 ctx 마감을 따름)
+ *  - APP_INFLUX_GZIP은 쓰지 않음 (클라이언트가 1KB 넘는 쓰기 본문을 항상 gzip으로 압축)
+ *  - OnStop 시 클라이언트 종료
+ */
+func NewInflux3Repo(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) *Influx3Repo {
+	url := config.String("APP_INFLUX_URL", "http://localhost:8086")
+	database := config.String("APP_INFLUX_DATABASE", "resort")
+	c, err := influxdb3.New(influxdb3.ClientConfig{
+		Host:       url,
+		Token:      config.String("APP_INFLUX_TOKEN", ""),
+		Database:   database,
+		HTTPClient: &http.Client{Timeout: config.Duration(log, "APP_INFLUX_TIMEOUT", 5*time.Second)},
+	})
+	if err != nil {
+		log.Fatal("failed to create influxdb 3 client", zap.Error(err))
+	}
+	repo := &Influx3Repo{
+		log:      log,
+		client:   c,
+		database: database,
+		schema:   loadInfluxSchema(log),
+		drops:    dr,
+		tracer:   tp.Tracer(tracerName),
+	}
+	if repo.schema.udpRoutes() {
+		log.Warn("udp routes in APP_INFLUX_ROUTES apply to InfluxDB 1.x only, writing them over HTTP")
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return repo.Close()
+		},
+	})
+	log.Info("influxdb 3 store", zap.String("url", url), zap.String("database", database))
+	return repo
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *Influx3Repo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 요청 한 번으로 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 필드가 없는 샘플은 기록할 수 없으므로 드롭 기록 후 건너뜀
+ *  - 라우팅 규칙(APP_INFLUX_ROUTES)에 따라 데이터베이스마다 요청 한 번
+ *  - 쓰기 실패는 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도)
+ */
+func (r *Influx3Repo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := r.tracer.Start(ctx, "influx write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "influxdb"),
+			attribute.String("db.namespace", r.database),
+			attribute.Int("influx.points", len(ss)),
+		))
+	defer span.End()
+
+	pts := make(map[string][]*influxdb3.Point) // 데이터베이스(라우팅 규칙, influx_schema.go) → 포인트
+	points := 0
+	for _, s := range ss {
+		tags, fields := r.schema.point(s)
+		if len(fields) == 0 {
+			r.drops.Record("influx", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+			continue
+		}
+		target := r.schema.target(tags)
+		fields, ok := r.schema.checkFields(r.drops, target.measurement, s.DeviceID, fields) // 필드 타입 선언 검사 (influx_fields.go)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		database := r.databaseOf(target)
+		pts[database] = append(pts[database], influxdb3.NewPoint(target.measurement, tags, fields, at))
+		points++
+	}
+	if points == 0 {
+		return nil
+	}
+
+	for database, bp := range pts {
+		if err := r.client.WritePoints(ctx, bp, influxdb3.WithDatabase(database)); err != nil {
+			r.log.Error("influx write failed", zap.String("database", database), zap.Error(err))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("influx write: %w", err)
+		}
+	}
+	r.log.Debug("influx write success", zap.Int("points", points))
+	return nil
+}
+
+/*
+ * Ping : FlightSQL로 가벼운 질의를 보내 도달 가능 여부 확인 (readiness 검사)
+ *  - InfluxDB 3에는 1.x/2.x의 ping 엔드포인트에 해당하는 공통 API가 없으므로 조회 경로 자체를 확인
+ */
+func (r *Influx3Repo) Ping(ctx context.Context) error {
+	it, err := r.client.Query(ctx, "SELECT 1")
+	if err != nil {
+		return err
+	}
+	for it.Next() { // 결과 값은 쓰지 않고 끝까지 읽기만 함
+	}
+	return it.Err()
+}
+
+/*
+ * Close : 클라이언트 종료 (FlightSQL gRPC 연결 포함)
+ */
+func (r *Influx3Repo) Close() error {
+	return r.client.Close()
+}
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (TimeSeriesStore)
+ *  - 장치별 원시 구간(Window)을 읽어 앱에서 집계 (query.go)
+ */
+func (r *Influx3Repo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	return windowQuery(ctx, spec, r.Window)
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회 (sim.Source)
+ */
+func (r *Influx3Repo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	var out []telemetry.Sample
+	err := r.Stream(ctx, deviceID, from, to, func(s telemetry.Sample) error {
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 읽으며 샘플마다 fn 호출 (Exporter)
+ *  - FlightSQL 결과를 행 단위로 읽으므로 전체 결과를 메모리에 올리지 않음
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 조회를 중단하고 그 에러를 반환
+ */
+func (r *Influx3Repo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	target := r.schema.deviceTarget(deviceID) // 라우팅 규칙에 따른 측정값/데이터베이스
+	q := fmt.Sprintf(`SELECT * FROM %s WHERE device = '%s' AND time >= '%s' AND time < '%s' ORDER BY time`,
+		sqlIdent(target.measurement), escapeSQLString(deviceID), from.UTC().Format(time.RFC3339Nano), to.UTC().Format(time.RFC3339Nano))
+	it, err := r.client.Query(ctx, q, influxdb3.WithDatabase(r.databaseOf(target)))
+	if err != nil {
+		return err
+	}
+
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(sqlRowSample(deviceID, it.Value())); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+/*
+ * sqlRowSample : FlightSQL 결과 행 하나 → Sample
+ *  - time 열이 시각, device 태그 열과 숫자가 아닌 값(다른 태그 등)은 건너뜀
+ */
+func sqlRowSample(deviceID string, row map[string]any) telemetry.Sample {
+	s := telemetry.Sample{DeviceID: deviceID, Values: make(map[string]float64)}
+	for k, v := range row {
+		switch {
+		case k == "time":
+			if t, ok := v.(time.Time); ok {
+				s.Time = t
+			}
+		case k == deviceTag || v == nil:
+		default:
+			if f, ok := toFloat(v); ok {
+				s.Values[k] = f
+			}
+		}
+	}
+	return s
+}
+
+/*
+ * FieldKeys : 측정값(APP_INFLUX_MEASUREMENT)과 라우팅 규칙의 측정값에 저장된 필드 이름 목록 (합집합, 정렬)
+ *  - information_schema에서 테이블의 숫자 열만 읽음 (time과 태그 열 제외)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *Influx3Repo) FieldKeys(ctx context.Context) ([]string, error) {
+	keys := make(map[string]bool)
+	for _, t := range r.schema.queryTargets() {
+		it, err := r.client.Query(ctx, fmt.Sprintf(`SELECT column_name FROM information_schema.columns
+WHERE table_name = '%s' AND data_type IN ('Float64', 'Int64', 'UInt64')`, escapeSQLString(t.measurement)),
+			influxdb3.WithDatabase(r.databaseOf(t)))
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			if k, ok := it.Value()["column_name"].(string); ok {
+				keys[k] = true
+			}
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return sortedKeys(keys), nil
+}
+
+// databaseOf : 기록 위치의 데이터베이스 (라우팅 규칙에 없으면 APP_INFLUX_DATABASE)
+func (r *Influx3Repo) databaseOf(t influxTarget) string {
+	if t.database == "" {
+		return r.database
+	}
+	return t.database
+}
+
+// escapeSQLString : SQL 문자열 리터럴용 작은따옴표 이스케이프
+func escapeSQLString(v string) string {
+	return strings.ReplaceAll(v, `'`, `''`)
+}
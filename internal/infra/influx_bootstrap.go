@@ -0,0 +1,65 @@
+/*
+ * InfluxDB 자동 생성 : 새 환경에서 수동 준비 없이 시작할 수 있도록 OnStart에서 기록 위치를 만듭니다.
+ *  - APP_INFLUX_AUTO_CREATE=true일 때만 (기본 false, 운영 환경은 보통 권한 있는 관리자가 미리 만듦)
+ *  - 1.x : APP_INFLUX_DATABASE와 라우팅 규칙(APP_INFLUX_ROUTES)의 데이터베이스를 CREATE DATABASE (이미 있으면 아무 일도 하지 않음)
+ *  - 2.x : APP_INFLUX_BUCKET과 라우팅 규칙의 버킷이 없으면 APP_INFLUX_ORG에 만듦 (보존 기간 무제한, 토큰에 버킷 생성 권한 필요)
+ *  - 3   : 첫 쓰기에서 데이터베이스가 만들어지므로 할 일 없음
+ *  - 이어서 기존 OnStart 단계가 보존 정책·연속 질의(1.x), 다운샘플링 태스크(2.x)를 멱등하게 맞춤 (influx_retention.go, influx_downsample.go)
+ *    다른 백엔드(TimescaleDB, PostgreSQL, SQLite, ClickHouse)는 테이블·하이퍼테이블·마이그레이션을 원래 스스로 만듦
+ *  - 실패해도 시작은 막지 않고 에러 로그만 남김 (보존 정책과 같음)
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+/*
+ * ensureDatabases : 기록할 데이터베이스를 만듦 (1.x)
+ *  - 데이터베이스마다 실패해도 나머지는 계속 진행하고, 마지막 에러를 반환
+ */
+func (r *InfluxRepo) ensureDatabases(ctx context.Context) error {
+	var lastErr error
+	for _, db := range append([]string{r.database}, r.schema.routeDatabases()...) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := r.exec("CREATE DATABASE " + influxQLIdent(db)); err != nil {
+			r.log.Error("influx database create failed", zap.String("database", db), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		r.log.Info("influx database ensured", zap.String("database", db))
+	}
+	return lastErr
+}
+
+/*
+ * ensureBuckets : 기록할 버킷이 없으면 만듦 (2.x)
+ *  - 이름으로 찾지 못하면(없음 또는 조회 실패) 만들기를 시도하고, 그것도 실패하면 에러
+ */
+func (r *Influx2Repo) ensureBuckets(ctx context.Context) error {
+	org, err := r.client.OrganizationsAPI().FindOrganizationByName(ctx, r.org)
+	if err != nil {
+		return err
+	}
+	buckets := r.client.BucketsAPI()
+
+	var lastErr error
+	for _, name := range append([]string{r.bucket}, r.schema.routeDatabases()...) {
+		if b, err := buckets.FindBucketByName(ctx, name); err == nil && b != nil {
+			r.log.Debug("influx bucket exists", zap.String("bucket", name))
+			continue
+		}
+		if _, err := buckets.CreateBucketWithName(ctx, org, name); err != nil {
+			r.log.Error("influx bucket create failed", zap.String("bucket", name), zap.Error(err))
+			lastErr = fmt.Errorf("bucket %q: %w", name, err)
+			continue
+		}
+		r.log.Info("influx bucket created", zap.String("bucket", name), zap.String("org", r.org))
+	}
+	return lastErr
+}
@@ -0,0 +1,165 @@
+/*
+ * Influx 다운샘플링 규칙 : 장기 보관용 평균 측정값을 시작 시 선언적으로 만듭니다.
+ *  - APP_INFLUX_DOWNSAMPLE : 간격:대상 측정값[:보관 위치] 목록 (예: 1m:device_data_1m:long,1h:device_data_1h:long)
+ *      간격마다 원본 측정값(APP_INFLUX_MEASUREMENT)의 필드 평균을 장치별로 대상 측정값에 기록
+ *      보관 위치는 1.x면 보존 정책(APP_INFLUX_RETENTION_POLICIES로 만든 long 등, 생략 시 기본 정책), 2.x면 버킷(생략 시 같은 버킷)
+ *  - 1.x : 연속 질의(continuous query) "downsample_<대상>" 생성 (필드 이름은 mean_<필드>)
+ *  - 2.x : 태스크 "downsample_<대상>" 생성 (aggregateWindow + to, 필드 이름은 그대로)
+ *  - 같은 이름이 이미 있으면 건드리지 않습니다. (규칙을 바꾸려면 기존 질의/태스크를 지운 뒤 재시작)
+ *  - InfluxDB에 닿지 않아도 앱 시작은 막지 않습니다. (에러 로그만 남김) 3은 지원하지 않아 무시합니다.
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/influxdb-client-go/v2/api" // 2.x 태스크 조회
+	"github.com/influxdata/influxdb1-client/v2"       // InfluxDB 1.x 클라이언트
+	"go.uber.org/zap"                                 // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// downsampleRule : 다운샘플링 규칙 하나
+type downsampleRule struct {
+	every  string // 집계 간격 (InfluxQL/Flux 기간 리터럴)
+	target string // 대상 측정값
+	dest   string // 1.x 보존 정책 / 2.x 버킷 (비어 있으면 기본)
+}
+
+// name : 연속 질의/태스크 이름
+func (d downsampleRule) name() string {
+	return "downsample_" + d.target
+}
+
+// loadDownsampleRules : APP_INFLUX_DOWNSAMPLE 읽기 (형식 오류는 Fatal)
+func loadDownsampleRules(log *zap.Logger) []downsampleRule {
+	var out []downsampleRule
+	for _, entry := range config.List("APP_INFLUX_DOWNSAMPLE", nil) {
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[1] == "" {
+			log.Fatal("invalid APP_INFLUX_DOWNSAMPLE entry, expected every:target[:destination]", zap.String("entry", entry))
+		}
+		if span, err := parseInfluxDuration(parts[0]); err != nil || span <= 0 {
+			log.Fatal("invalid APP_INFLUX_DOWNSAMPLE interval", zap.String("entry", entry))
+		}
+		d := downsampleRule{every: parts[0], target: parts[1]}
+		if len(parts) == 3 {
+			d.dest = parts[2]
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+/*
+ * ensureContinuousQueries : 규칙마다 연속 질의가 없으면 생성 (1.x)
+ *  - 규칙마다 실패해도 나머지는 계속 진행하고, 마지막 에러를 반환
+ */
+func (r *InfluxRepo) ensureContinuousQueries(ctx context.Context, rules []downsampleRule) error {
+	existing, err := r.continuousQueries()
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, d := range rules {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if existing[d.name()] {
+			r.log.Debug("influx continuous query exists", zap.String("query", d.name()))
+			continue
+		}
+		into := influxQLIdent(r.database) + "." + influxQLIdent(d.dest) + "." + influxQLIdent(d.target)
+		if d.dest == "" {
+			into = influxQLIdent(r.database) + ".." + influxQLIdent(d.target) // 기본 보존 정책
+		}
+		cmd := fmt.Sprintf("CREATE CONTINUOUS QUERY %s ON %s BEGIN SELECT mean(*) INTO %s FROM %s GROUP BY time(%s), * END",
+			influxQLIdent(d.name()), influxQLIdent(r.database), into, influxQLIdent(r.schema.measurement), d.every)
+		if err := r.exec(cmd); err != nil {
+			r.log.Error("influx continuous query create failed", zap.String("query", d.name()), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		r.log.Info("influx continuous query created", zap.String("query", d.name()), zap.String("every", d.every), zap.String("into", into))
+	}
+	return lastErr
+}
+
+// continuousQueries : 데이터베이스의 연속 질의 이름 (SHOW CONTINUOUS QUERIES, 시리즈 이름이 데이터베이스)
+func (r *InfluxRepo) continuousQueries() (map[string]bool, error) {
+	resp, err := r.client.Query(client.NewQuery("SHOW CONTINUOUS QUERIES", r.database, ""))
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Error(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]bool)
+	for _, res := range resp.Results {
+		for _, row := range res.Series {
+			if row.Name != r.database {
+				continue
+			}
+			for _, vals := range row.Values {
+				if len(vals) > 0 {
+					if name, ok := vals[0].(string); ok {
+						out[name] = true
+					}
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+/*
+ * ensureTasks : 규칙마다 다운샘플링 태스크가 없으면 생성 (2.x)
+ *  - 규칙마다 실패해도 나머지는 계속 진행하고, 마지막 에러를 반환
+ */
+func (r *Influx2Repo) ensureTasks(ctx context.Context, rules []downsampleRule) error {
+	org, err := r.client.OrganizationsAPI().FindOrganizationByName(ctx, r.org)
+	if err != nil {
+		return err
+	}
+	if org.Id == nil {
+		return fmt.Errorf("influx organization %q has no id", r.org)
+	}
+	tasks := r.client.TasksAPI()
+
+	var lastErr error
+	for _, d := range rules {
+		found, err := tasks.FindTasks(ctx, &api.TaskFilter{Name: d.name(), OrgID: *org.Id})
+		if err != nil {
+			return err
+		}
+		if len(found) > 0 {
+			r.log.Debug("influx task exists", zap.String("task", d.name()))
+			continue
+		}
+
+		dest := d.dest
+		if dest == "" {
+			dest = r.bucket
+		}
+		flux := fmt.Sprintf(`from(bucket: "%s")
+  |> range(start: -task.every)
+  |> filter(fn: (r) => r._measurement == "%s")
+  |> aggregateWindow(every: task.every, fn: mean, createEmpty: false)
+  |> set(key: "_measurement", value: "%s")
+  |> to(bucket: "%s", org: "%s")`,
+			escapeFluxString(r.bucket), escapeFluxString(r.schema.measurement), escapeFluxString(d.target),
+			escapeFluxString(dest), escapeFluxString(r.org))
+		if _, err := tasks.CreateTaskWithEvery(ctx, d.name(), flux, d.every, *org.Id); err != nil {
+			r.log.Error("influx task create failed", zap.String("task", d.name()), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		r.log.Info("influx task created", zap.String("task", d.name()), zap.String("every", d.every), zap.String("bucket", dest))
+	}
+	return lastErr
+}
@@ -0,0 +1,133 @@
+/*
+ * Influx 필드 타입 검사 : 기록 전에 필드 이름과 타입을 측정값별 선언과 맞춰, 타입 충돌로 샤드 쓰기가 막히지 않게 합니다.
+ *  - InfluxDB는 샤드 안에서 필드 타입이 처음 기록된 값으로 정해지므로, 다른 타입 값이 섞이면 그 묶음 전체가 거절됩니다.
+ *    (예: 정수로 만든 mode 필드에 1.5가 오면 field type conflict → 버스/저널이 같은 묶음을 계속 다시 시도)
+ *  - APP_INFLUX_FIELD_TYPES : 측정값:필드=타입|필드=타입 목록 (예: device_data:temperature=float|mode=int|on=bool)
+ *      타입은 float | int | bool | string, 목록에 없는 측정값은 검사하지 않음
+ *  - APP_INFLUX_FIELD_MODE  : 선언과 맞지 않을 때 처리 (기본 coerce)
+ *      coerce : 값을 선언한 타입으로 바꿈 (int는 반올림, bool은 0이 아니면 true), 선언에 없는 필드와 바꿀 수 없는 값은 빼고 기록
+ *      reject : 선언에 없는 필드나 정확히 바꿀 수 없는 값이 하나라도 있으면 샘플 전체를 버림
+ *  - NaN/±Inf는 라인 프로토콜로 기록할 수 없으므로 선언과 관계없이 그 필드를 뺌
+ *  - 빼거나 버린 내용은 드롭 기록기(source="influx", reason="validation")에 남깁니다.
+ */
+package infra
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 뺀 필드/버린 샘플 기록
+)
+
+// 선언할 수 있는 필드 타입
+var influxFieldTypes = map[string]bool{"float": true, "int": true, "bool": true, "string": true}
+
+// loadFieldTypes : APP_INFLUX_FIELD_TYPES, APP_INFLUX_FIELD_MODE 읽기 (형식 오류는 Fatal)
+func loadFieldTypes(log *zap.Logger) (map[string]map[string]string, bool) {
+	out := make(map[string]map[string]string)
+	for _, entry := range config.List("APP_INFLUX_FIELD_TYPES", nil) {
+		measurement, decls, ok := strings.Cut(entry, ":")
+		if !ok || measurement == "" || decls == "" {
+			log.Fatal("invalid APP_INFLUX_FIELD_TYPES entry, expected measurement:field=type|field=type", zap.String("entry", entry))
+		}
+		types := out[measurement]
+		if types == nil {
+			types = make(map[string]string)
+			out[measurement] = types
+		}
+		for _, d := range strings.Split(decls, "|") {
+			field, typ, ok := strings.Cut(d, "=")
+			if !ok || field == "" || !influxFieldTypes[typ] {
+				log.Fatal("invalid APP_INFLUX_FIELD_TYPES declaration, expected field=float|int|bool|string",
+					zap.String("entry", entry), zap.String("declaration", d))
+			}
+			types[field] = typ
+		}
+	}
+
+	switch mode := config.String("APP_INFLUX_FIELD_MODE", "coerce"); mode {
+	case "coerce":
+		return out, true
+	case "reject":
+		return out, false
+	default:
+		log.Fatal("invalid APP_INFLUX_FIELD_MODE, expected coerce|reject", zap.String("value", mode))
+		return nil, false
+	}
+}
+
+/*
+ * checkFields : 측정값의 필드 선언에 맞춰 필드를 검사·변환
+ *  - 반환 false : 샘플을 기록하지 않음 (reject 모드의 불일치, 또는 남은 필드가 없음 - 드롭 기록 완료)
+ *  - 원래 필드가 없는 샘플은 그대로 반환 (호출자가 처리)
+ */
+func (sc influxSchema) checkFields(dr *drops.Recorder, measurement, deviceID string, fields map[string]interface{}) (map[string]interface{}, bool) {
+	if len(fields) == 0 {
+		return fields, true
+	}
+	types := sc.fieldTypes[measurement]
+	out := make(map[string]interface{}, len(fields))
+	var problems []string
+	rejected := false
+	for k, v := range fields {
+		f, _ := v.(float64) // point()는 숫자 값만 필드로 넣음
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			problems = append(problems, k+": non-finite value dropped")
+			continue
+		}
+		if types == nil {
+			out[k] = f
+			continue
+		}
+		typ, ok := types[k]
+		if !ok {
+			problems = append(problems, k+": undeclared field")
+			rejected = rejected || !sc.coerce
+			continue
+		}
+		cv, exact, ok := convertField(typ, f)
+		if !ok || (!exact && !sc.coerce) {
+			problems = append(problems, fmt.Sprintf("%s: %v is not a valid %s", k, f, typ))
+			rejected = rejected || !sc.coerce
+			continue
+		}
+		out[k] = cv // coerce 모드에서 바뀐 값은 드롭이 아니므로 기록하지 않음
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		detail := strings.Join(problems, "; ")
+		if rejected || len(out) == 0 {
+			detail = "sample rejected: " + detail
+		}
+		dr.Record("influx", drops.ReasonValidation, deviceID, detail)
+	}
+	return out, !rejected && len(out) > 0
+}
+
+/*
+ * convertField : 숫자 값을 선언한 타입으로 변환
+ *  - exact : 값 손실 없이 바뀌었는지
+ *  - ok    : 바꿀 수 있는지 (정수 범위를 넘으면 false)
+ */
+func convertField(typ string, f float64) (v interface{}, exact, ok bool) {
+	switch typ {
+	case "int":
+		r := math.Round(f)
+		if r < math.MinInt64 || r >= math.MaxInt64 {
+			return nil, false, false
+		}
+		return int64(r), r == f, true
+	case "bool":
+		return f != 0, f == 0 || f == 1, true
+	case "string":
+		return strconv.FormatFloat(f, 'f', -1, 64), true, true
+	}
+	return f, true, true
+}
@@ -0,0 +1,192 @@
+/*
+ * Influx 보존 정책(retention policy) 관리 : 시작 시 설정된 보존 정책을 만들거나 설정과 맞춥니다. (InfluxDB 1.x)
+ *  - APP_INFLUX_RETENTION_POLICIES : 이름:기간:복제수[:default] 목록 (예: raw:30d:1:default,long:104w:1)
+ *      기간은 InfluxQL 기간 리터럴 (ns, u, ms, s, m, h, d, w 조합) 또는 INF (무기한)
+ *  - 없는 정책은 CREATE RETENTION POLICY, 기간/복제수/기본 여부가 다른 정책은 ALTER RETENTION POLICY로 맞춥니다.
+ *    설정에 없는 정책은 건드리지 않습니다.
+ *  - 새 환경이 influx CLI 작업 없이 올바른 데이터 수명 주기로 시작되도록 하기 위한 것이며,
+ *    InfluxDB에 닿지 않아도 앱 시작은 막지 않습니다. (에러 로그만 남김)
+ *  - 2.x/3은 버킷/데이터베이스 단위 보존 기간을 쓰므로 이 설정을 무시합니다.
+ */
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb1-client/v2" // InfluxDB 1.x 클라이언트
+	"go.uber.org/zap"                           // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// retentionPolicy : 설정된 보존 정책 하나
+type retentionPolicy struct {
+	name        string
+	duration    string        // InfluxQL 기간 리터럴 (그대로 질의에 사용)
+	span        time.Duration // 비교용 기간 (INF면 0)
+	replication int
+	isDefault   bool
+}
+
+// InfluxQL 기간 리터럴 (예: 30d, 1w2d, 12h)
+var (
+	influxDurationRe     = regexp.MustCompile(`^(\d+(ns|u|µ|ms|s|m|h|d|w))+$`)
+	influxDurationPartRe = regexp.MustCompile(`(\d+)(ns|u|µ|ms|s|m|h|d|w)`)
+	influxDurationUnits  = map[string]time.Duration{
+		"ns": time.Nanosecond, "u": time.Microsecond, "µ": time.Microsecond, "ms": time.Millisecond,
+		"s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour, // d, w는 time.ParseDuration이 모름
+	}
+)
+
+// loadRetentionPolicies : APP_INFLUX_RETENTION_POLICIES 읽기 (형식 오류는 Fatal)
+func loadRetentionPolicies(log *zap.Logger) []retentionPolicy {
+	var out []retentionPolicy
+	defaults := 0
+	for _, entry := range config.List("APP_INFLUX_RETENTION_POLICIES", nil) {
+		p, err := parseRetentionPolicy(entry)
+		if err != nil {
+			log.Fatal("invalid APP_INFLUX_RETENTION_POLICIES entry, expected name:duration:replication[:default]",
+				zap.String("entry", entry), zap.Error(err))
+		}
+		if p.isDefault {
+			defaults++
+		}
+		out = append(out, p)
+	}
+	if defaults > 1 {
+		log.Fatal("APP_INFLUX_RETENTION_POLICIES may mark only one policy as default")
+	}
+	return out
+}
+
+// parseRetentionPolicy : "이름:기간:복제수[:default]" 한 항목
+func parseRetentionPolicy(entry string) (retentionPolicy, error) {
+	parts := strings.Split(entry, ":")
+	if len(parts) < 3 || len(parts) > 4 || parts[0] == "" {
+		return retentionPolicy{}, fmt.Errorf("expected 3 or 4 fields")
+	}
+	p := retentionPolicy{name: parts[0], duration: parts[1]}
+	if len(parts) == 4 {
+		if parts[3] != "default" {
+			return p, fmt.Errorf("unknown flag %q", parts[3])
+		}
+		p.isDefault = true
+	}
+	var err error
+	if p.span, err = parseInfluxDuration(p.duration); err != nil {
+		return p, err
+	}
+	if p.replication, err = strconv.Atoi(parts[2]); err != nil || p.replication < 1 {
+		return p, fmt.Errorf("replication must be a positive integer")
+	}
+	return p, nil
+}
+
+// parseInfluxDuration : InfluxQL 기간 리터럴 → time.Duration (INF는 0, 서버도 무기한을 0s로 표시)
+func parseInfluxDuration(v string) (time.Duration, error) {
+	if strings.EqualFold(v, "INF") {
+		return 0, nil
+	}
+	if !influxDurationRe.MatchString(v) {
+		return 0, fmt.Errorf("invalid duration %q", v)
+	}
+	var total time.Duration
+	for _, m := range influxDurationPartRe.FindAllStringSubmatch(v, -1) {
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		total += time.Duration(n) * influxDurationUnits[m[2]]
+	}
+	return total, nil
+}
+
+/*
+ * ensureRetentionPolicies : 설정된 보존 정책을 만들거나 설정과 맞춤
+ *  - 정책마다 실패해도 나머지는 계속 진행하고, 마지막 에러를 반환
+ */
+func (r *InfluxRepo) ensureRetentionPolicies(ctx context.Context, policies []retentionPolicy) error {
+	existing, err := r.retentionPolicies()
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, p := range policies {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		cur, ok := existing[p.name]
+		verb := "CREATE"
+		if ok {
+			if cur.span == p.span && cur.replication == p.replication && (cur.isDefault || !p.isDefault) {
+				r.log.Debug("influx retention policy up to date", zap.String("policy", p.name))
+				continue
+			}
+			verb = "ALTER"
+		}
+		cmd := fmt.Sprintf("%s RETENTION POLICY %s ON %s DURATION %s REPLICATION %d",
+			verb, influxQLIdent(p.name), influxQLIdent(r.database), p.duration, p.replication)
+		if p.isDefault {
+			cmd += " DEFAULT"
+		}
+		if err := r.exec(cmd); err != nil {
+			r.log.Error("influx retention policy update failed", zap.String("policy", p.name), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		r.log.Info("influx retention policy applied", zap.String("policy", p.name), zap.String("action", strings.ToLower(verb)),
+			zap.String("duration", p.duration), zap.Int("replication", p.replication), zap.Bool("default", p.isDefault))
+	}
+	return lastErr
+}
+
+// retentionPolicies : 데이터베이스의 현재 보존 정책 (SHOW RETENTION POLICIES)
+func (r *InfluxRepo) retentionPolicies() (map[string]retentionPolicy, error) {
+	resp, err := r.client.Query(client.NewQuery("SHOW RETENTION POLICIES ON "+influxQLIdent(r.database), r.database, ""))
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Error(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]retentionPolicy)
+	for _, res := range resp.Results {
+		for _, row := range res.Series {
+			col := make(map[string]int, len(row.Columns))
+			for i, c := range row.Columns {
+				col[c] = i
+			}
+			for _, vals := range row.Values {
+				var p retentionPolicy
+				p.name, _ = vals[col["name"]].(string)
+				if d, ok := vals[col["duration"]].(string); ok {
+					p.span, _ = time.ParseDuration(d)
+				}
+				if n, ok := vals[col["replicaN"]].(json.Number); ok {
+					v, _ := n.Int64()
+					p.replication = int(v)
+				}
+				p.isDefault, _ = vals[col["default"]].(bool)
+				out[p.name] = p
+			}
+		}
+	}
+	return out, nil
+}
+
+// exec : 결과가 없는 InfluxQL 명령 실행
+func (r *InfluxRepo) exec(cmd string) error {
+	resp, err := r.client.Query(client.NewQuery(cmd, r.database, ""))
+	if err != nil {
+		return err
+	}
+	return resp.Error()
+}
@@ -0,0 +1,219 @@
+/*
+ * Influx 기록 스키마 : 측정값 이름, 고정 태그, 태그로 올릴 필드를 설정으로 정해 기존 대시보드의 스키마에 맞춥니다.
+ *  - APP_INFLUX_MEASUREMENT : 측정값 이름 (기본 device_data)
+ *  - APP_INFLUX_STATIC_TAGS : 모든 포인트에 붙이는 고정 태그 (예: site=resort-a,environment=prod,host=edge-01)
+ *  - APP_INFLUX_TAG_FIELDS  : 필드 대신 태그로 기록할 값 이름 (예: mode,zone, 값은 숫자 문자열로 기록)
+ *  - APP_INFLUX_ROUTES      : 태그 값에 따라 다른 측정값/데이터베이스로 기록하는 규칙 (예: device=inv-*:inverters,site=meter-*:meters:metering)
+ *      태그=패턴:측정값[:데이터베이스] 목록, 패턴은 path.Match 글롭이며 위에서부터 처음 맞는 규칙을 씀
+ *      데이터베이스는 1.x/3이면 데이터베이스, 2.x면 버킷 (생략하면 기본값), 맞는 규칙이 없으면 APP_INFLUX_MEASUREMENT
+ *      끝에 :udp를 붙이면 HTTP 대신 UDP 라인 프로토콜로 기록 (1.x 전용, 유실 허용, 예: device=vib-*:vibration:vibration:udp)
+ *  - device 태그(장치 ID)는 항상 붙으며, 위 설정으로 덮어쓸 수 없습니다.
+ *  - 1.x/2.x/3 저장소가 같은 스키마를 쓰며, 조회는 숫자 필드만 돌려주므로 태그로 올린 값은 조회 결과에 포함되지 않습니다.
+ *  - 조회는 장치 ID와 고정 태그만으로 기록 위치를 정하므로, 태그로 올린 값·보강 태그에 건 라우팅 규칙은 조회에 반영되지 않습니다.
+ *    (보존 정책·다운샘플링·필드 목록은 기본 측정값/데이터베이스 기준)
+ */
+package infra
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/telemetry" // 기록 단위 샘플
+)
+
+// 장치 ID 태그 이름 (조회 필터에 사용하므로 설정으로 바꾸지 않음)
+const deviceTag = "device"
+
+// influxSchema : 포인트 구성 설정
+type influxSchema struct {
+	measurement string
+	tags        map[string]string            // 고정 태그
+	promote     map[string]bool              // 태그로 올릴 필드 이름
+	routes      []influxRoute                // 기록 위치 라우팅 규칙 (순서대로 검사)
+	fieldTypes  map[string]map[string]string // 측정값 → 필드 → 선언한 타입 (influx_fields.go)
+	coerce      bool                         // 선언과 다른 값을 바꿔서 기록 (false면 샘플을 버림)
+}
+
+// influxRoute : 라우팅 규칙 하나 (tag 값이 pattern과 맞으면 target으로 기록)
+type influxRoute struct {
+	tag     string
+	pattern string
+	target  influxTarget
+}
+
+// influxTarget : 포인트를 기록할 위치 (database가 비어 있으면 저장소 기본값)
+type influxTarget struct {
+	measurement string
+	database    string
+	udp         bool // UDP 라인 프로토콜로 기록 (1.x, 데이터베이스는 서버의 UDP 리스너 설정을 따름)
+}
+
+// loadInfluxSchema : 환경변수에서 스키마 설정을 읽음 (형식 오류는 Fatal)
+func loadInfluxSchema(log *zap.Logger) influxSchema {
+	sc := influxSchema{
+		measurement: config.String("APP_INFLUX_MEASUREMENT", "device_data"),
+		tags:        make(map[string]string),
+		promote:     make(map[string]bool),
+	}
+	for _, kv := range config.List("APP_INFLUX_STATIC_TAGS", nil) {
+		k, v, ok := strings.Cut(kv, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" || k == deviceTag {
+			log.Fatal("invalid APP_INFLUX_STATIC_TAGS entry, expected key=value (device is reserved)", zap.String("entry", kv))
+		}
+		sc.tags[k] = v
+	}
+	for _, f := range config.List("APP_INFLUX_TAG_FIELDS", nil) {
+		if f == deviceTag {
+			log.Fatal("APP_INFLUX_TAG_FIELDS must not contain device")
+		}
+		sc.promote[f] = true
+	}
+	for _, entry := range config.List("APP_INFLUX_ROUTES", nil) {
+		rt, err := parseInfluxRoute(entry)
+		if err != nil {
+			log.Fatal("invalid APP_INFLUX_ROUTES entry, expected tag=pattern:measurement[:database][:udp]", zap.String("entry", entry), zap.Error(err))
+		}
+		sc.routes = append(sc.routes, rt)
+	}
+	sc.fieldTypes, sc.coerce = loadFieldTypes(log)
+	return sc
+}
+
+// udpRoutes : UDP로 기록하는 라우팅 규칙이 있는지
+func (sc influxSchema) udpRoutes() bool {
+	for _, rt := range sc.routes {
+		if rt.target.udp {
+			return true
+		}
+	}
+	return false
+}
+
+// routeDatabases : 라우팅 규칙이 지정한 데이터베이스(2.x는 버킷) 목록 (UDP 규칙 제외, 중복 없이 규칙 순서대로)
+func (sc influxSchema) routeDatabases() []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, rt := range sc.routes {
+		if db := rt.target.database; db != "" && !rt.target.udp && !seen[db] {
+			seen[db] = true
+			out = append(out, db)
+		}
+	}
+	return out
+}
+
+// queryTargets : 조회할 기록 위치 전체 (기본 측정값 + 라우팅 규칙, 측정값·데이터베이스가 같으면 한 번만)
+func (sc influxSchema) queryTargets() []influxTarget {
+	out := []influxTarget{{measurement: sc.measurement}}
+	for _, rt := range sc.routes {
+		t := influxTarget{measurement: rt.target.measurement, database: rt.target.database}
+		dup := false
+		for _, o := range out {
+			dup = dup || o == t
+		}
+		if !dup {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// sortedKeys : 맵 키 정렬 (필드 목록 합집합, Kafka 레코드·VictoriaMetrics 라인처럼 같은 샘플을 항상 같은 바이트로 만들 때)
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseInfluxRoute : "태그=패턴:측정값[:데이터베이스][:udp]" 한 항목
+func parseInfluxRoute(entry string) (influxRoute, error) {
+	cond, dest, ok := strings.Cut(entry, ":")
+	tag, pattern, ok2 := strings.Cut(cond, "=")
+	if !ok || !ok2 || tag == "" || pattern == "" {
+		return influxRoute{}, fmt.Errorf("expected tag=pattern before the first colon")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return influxRoute{}, err
+	}
+	parts := strings.Split(dest, ":")
+	udp := len(parts) > 1 && parts[len(parts)-1] == "udp"
+	if udp {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 2 || parts[0] == "" {
+		return influxRoute{}, fmt.Errorf("expected measurement[:database][:udp] after the first colon")
+	}
+	t := influxTarget{measurement: parts[0], udp: udp}
+	if len(parts) == 2 {
+		t.database = parts[1]
+	}
+	return influxRoute{tag: tag, pattern: pattern, target: t}, nil
+}
+
+// target : 포인트 태그에 맞는 기록 위치 (맞는 규칙이 없으면 기본 측정값)
+func (sc influxSchema) target(tags map[string]string) influxTarget {
+	for _, rt := range sc.routes {
+		if v, ok := tags[rt.tag]; ok {
+			if m, _ := path.Match(rt.pattern, v); m {
+				return rt.target
+			}
+		}
+	}
+	return influxTarget{measurement: sc.measurement}
+}
+
+// deviceTarget : 장치 하나의 조회 위치 (고정 태그 + device 태그로 라우팅 규칙 검사)
+func (sc influxSchema) deviceTarget(deviceID string) influxTarget {
+	tags := make(map[string]string, len(sc.tags)+1)
+	for k, v := range sc.tags {
+		tags[k] = v
+	}
+	tags[deviceTag] = deviceID
+	return sc.target(tags)
+}
+
+/*
+ * point : 샘플 하나의 태그와 필드
+ *  - 태그 : 고정 태그 + 보강 태그(s.Tags, enrich.go) + device + 태그로 올린 필드
+ *  - 필드 : 나머지 값 (비어 있을 수 있음 → 호출자가 드롭 기록)
+ */
+func (sc influxSchema) point(s telemetry.Sample) (map[string]string, map[string]interface{}) {
+	tags := make(map[string]string, len(sc.tags)+len(s.Tags)+1+len(sc.promote))
+	for k, v := range sc.tags {
+		tags[k] = v
+	}
+	for k, v := range s.Tags {
+		tags[k] = v
+	}
+	tags[deviceTag] = s.DeviceID
+
+	fields := make(map[string]interface{}, len(s.Values))
+	for k, v := range s.Values {
+		if sc.promote[k] {
+			tags[k] = strconv.FormatFloat(v, 'f', -1, 64)
+			continue
+		}
+		fields[k] = v
+	}
+	return tags, fields
+}
+
+// influxQLIdent : InfluxQL 큰따옴표 식별자 (측정값 이름)
+func influxQLIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+// sqlIdent : SQL 큰따옴표 식별자 (측정값 = 테이블 이름)
+func sqlIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
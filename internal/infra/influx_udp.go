@@ -0,0 +1,49 @@
+/*
+ * Influx UDP 기록 : 유실을 허용하는 고빈도 텔레메트리를 HTTP 대신 UDP 라인 프로토콜로 보냅니다. (InfluxDB 1.x)
+ *  - 라우팅 규칙(APP_INFLUX_ROUTES) 끝에 :udp를 붙인 장치 종류만 UDP로 기록하고, 나머지는 그대로 HTTP로 기록합니다.
+ *  - APP_INFLUX_UDP_ADDR          : 서버의 UDP 리스너 host:port (UDP 규칙이 있으면 필수, 예: localhost:8089)
+ *  - APP_INFLUX_UDP_PAYLOAD_BYTES : 데이터그램 하나의 최대 크기 (기본 512, 경로 MTU보다 작게)
+ *  - 기록할 데이터베이스와 보존 정책은 서버의 UDP 리스너 설정이 정하므로, 조회가 맞는 곳을 읽도록
+ *    규칙의 데이터베이스를 리스너의 데이터베이스와 같게 지정하세요.
+ *  - 응답이 없으므로 서버가 받았는지 알 수 없고, 보내기 실패는 재시도하지 않고 드롭 기록(source="influx_udp")만 남깁니다.
+ */
+package infra
+
+import (
+	"github.com/influxdata/influxdb1-client/v2" // InfluxDB 1.x 클라이언트 (UDP)
+	"go.uber.org/zap"                           // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 보내지 못한 포인트 기록
+)
+
+// newInfluxUDPClient : UDP 라인 프로토콜 클라이언트 (설정 오류는 Fatal)
+func newInfluxUDPClient(log *zap.Logger) client.Client {
+	addr := config.String("APP_INFLUX_UDP_ADDR", "")
+	if addr == "" {
+		log.Fatal("APP_INFLUX_UDP_ADDR is required when APP_INFLUX_ROUTES has udp routes")
+	}
+	payload := config.Bytes(log, "APP_INFLUX_UDP_PAYLOAD_BYTES", 512)
+	if payload <= 0 {
+		log.Fatal("APP_INFLUX_UDP_PAYLOAD_BYTES must be positive", zap.Int64("value", payload))
+	}
+	c, err := client.NewUDPClient(client.UDPConfig{Addr: addr, PayloadSize: int(payload)})
+	if err != nil {
+		log.Fatal("failed to create influx udp client", zap.String("addr", addr), zap.Error(err))
+	}
+	log.Info("influx udp writer enabled", zap.String("addr", addr), zap.Int64("payload", payload))
+	return c
+}
+
+/*
+ * writeUDP : 배치 포인트를 UDP로 보냄 (최선 노력)
+ *  - 실패해도 에러를 반환하지 않음 (HTTP 묶음까지 다시 시도하게 만들지 않도록)
+ */
+func (r *InfluxRepo) writeUDP(bp client.BatchPoints) {
+	if err := r.udp.Write(bp); err != nil {
+		r.log.Warn("influx udp write failed", zap.Int("points", len(bp.Points())), zap.Error(err))
+		for _, pt := range bp.Points() {
+			r.drops.Record("influx_udp", drops.ReasonWriteFailed, pt.Tags()[deviceTag], err.Error())
+		}
+	}
+}
@@ -0,0 +1,282 @@
+//go:build !edge
+
+/*
+ * KafkaStore : 시계열 DB 대신 Kafka 토픽에 기록하는 저장소 (APP_STORE_BACKEND=kafka, 기본 빌드 전용)
+ *  - 데이터 웨어하우스가 Kafka에서 데이터를 가져가는 배포용이며, 샘플마다 레코드 하나를 장치 ID 키로 씁니다.
+ *    (같은 장치의 레코드는 같은 파티션에 순서대로 쌓임)
+ *  - 레코드 형식 (APP_KAFKA_STORE_FORMAT)
+ *      json : {"device":"A1","time":"RFC3339Nano","values":{...},"tags":{...}}
+ *      avro : kafkaAvroSchema 스키마의 Avro 바이너리 (APP_KAFKA_STORE_SCHEMA_ID를 지정하면 Confluent 형식 - 매직 바이트 0 + 스키마 ID)
+ *    tags는 APP_INFLUX_STATIC_TAGS의 고정 태그 (예: site)와 보강 태그 (enrich.go), NaN/±Inf 값은 JSON/웨어하우스가 다룰 수 없어 빼고 드롭 기록
+ *  - 쓰기는 동기식 : 브로커 확인(APP_KAFKA_STORE_ACKS)까지 기다려 실패를 에러로 반환 → 버스 재시도/저널/WAL이 다시 시도 (at-least-once)
+ *  - 쓰기 전용 : 조회·내보내기·시뮬레이터는 errKafkaStoreWriteOnly를 반환 (웨어하우스에서 조회)
+ *  - edge 빌드에서는 kafka_store_edge.go가 사용되어 Kafka 클라이언트가 링크되지 않습니다.
+ */
+package infra
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/segmentio/kafka-go"      // Kafka 클라이언트
+	"go.opentelemetry.io/otel/attribute" // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"     // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"     // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                     // Fx 프레임워크
+	"go.uber.org/zap"                    // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록 단위 샘플
+)
+
+// 조회 요청에 돌려주는 에러 (Kafka 저장소는 읽을 수 없음)
+var errKafkaStoreWriteOnly = errors.New("kafka store is write-only, query the data warehouse instead")
+
+// kafkaAvroSchema : avro 형식 레코드의 스키마 (웨어하우스/스키마 레지스트리에 등록할 값, 시작 로그에도 남김)
+const kafkaAvroSchema = `{"type":"record","name":"Telemetry","namespace":"scaffold","fields":[` +
+	`{"name":"device","type":"string"},` +
+	`{"name":"time","type":{"type":"long","logicalType":"timestamp-millis"}},` +
+	`{"name":"values","type":{"type":"map","values":"double"}},` +
+	`{"name":"tags","type":{"type":"map","values":"string"}}]}`
+
+var _ InfluxStore = (*KafkaStore)(nil)
+
+// KafkaStore : 샘플을 Kafka 레코드로 쓰는 쓰기 전용 저장소
+type KafkaStore struct {
+	log      *zap.Logger
+	w        *kafka.Writer
+	brokers  []string
+	tags     map[string]string // 고정 태그
+	avro     bool
+	schemaID int // Confluent 스키마 ID (0이면 헤더 없는 Avro)
+	drops    *drops.Recorder
+	tracer   trace.Tracer
+}
+
+/*
+ * newKafkaStore : KafkaStore 생성자 (NewInfluxStore가 APP_STORE_BACKEND=kafka일 때 호출)
+ *  - APP_KAFKA_STORE_BROKERS   : 브로커 주소, 쉼표 구분 (기본 APP_KAFKA_BROKERS, 둘 다 비어 있으면 Fatal)
+ *  - APP_KAFKA_STORE_TOPIC     : 쓸 토픽 (기본 scaffold.telemetry)
+ *  - APP_KAFKA_STORE_FORMAT    : json | avro (기본 json)
+ *  - APP_KAFKA_STORE_SCHEMA_ID : avro 레코드 앞에 붙일 Confluent 스키마 레지스트리 ID (기본 0, 붙이지 않음)
+ *  - APP_KAFKA_STORE_ACKS      : all | one (기본 all, 확인 없는 쓰기는 실패를 알 수 없어 지원하지 않음)
+ *  - APP_KAFKA_STORE_TIMEOUT   : 쓰기 제한 시간 (기본 10s)
+ *  - OnStop 시 쓰기 종료
+ */
+func newKafkaStore(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	brokers := config.List("APP_KAFKA_STORE_BROKERS", config.List("APP_KAFKA_BROKERS", nil))
+	if len(brokers) == 0 {
+		log.Fatal("APP_KAFKA_STORE_BROKERS (or APP_KAFKA_BROKERS) is required for APP_STORE_BACKEND=kafka")
+	}
+	acks, ok := map[string]kafka.RequiredAcks{
+		"all": kafka.RequireAll,
+		"one": kafka.RequireOne,
+	}[config.String("APP_KAFKA_STORE_ACKS", "all")]
+	if !ok {
+		log.Fatal("invalid APP_KAFKA_STORE_ACKS, expected all|one")
+	}
+	s := &KafkaStore{
+		log:      log,
+		brokers:  brokers,
+		tags:     loadInfluxSchema(log).tags,
+		schemaID: config.Int(log, "APP_KAFKA_STORE_SCHEMA_ID", 0),
+		drops:    dr,
+		tracer:   tp.Tracer(tracerName),
+	}
+	switch f := config.String("APP_KAFKA_STORE_FORMAT", "json"); f {
+	case "json":
+	case "avro":
+		s.avro = true
+	default:
+		log.Fatal("invalid APP_KAFKA_STORE_FORMAT, expected json|avro", zap.String("value", f))
+	}
+	timeout := config.Duration(log, "APP_KAFKA_STORE_TIMEOUT", 10*time.Second)
+	s.w = &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        config.String("APP_KAFKA_STORE_TOPIC", "scaffold.telemetry"),
+		Balancer:     &kafka.Hash{},                                 // 키(장치 ID) 해시로 파티션 선택
+		BatchSize:    config.Int(log, "APP_INFLUX_BATCH_SIZE", 500), // 저장소 묶음 하나를 요청 하나로
+		BatchTimeout: 10 * time.Millisecond,                         // 묶음은 이미 버스/저널에서 모였으므로 기다리지 않음
+		WriteTimeout: timeout,
+		RequiredAcks: acks,
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return s.Close()
+		},
+	})
+	fields := []zap.Field{zap.Strings("brokers", brokers), zap.String("topic", s.w.Topic), zap.Bool("avro", s.avro)}
+	if s.avro {
+		fields = append(fields, zap.String("schema", kafkaAvroSchema), zap.Int("schema_id", s.schemaID))
+	}
+	log.Info("kafka store", fields...)
+	return s
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (s *KafkaStore) WritePoint(ctx context.Context, p telemetry.Sample) error {
+	return s.WriteBatch(ctx, []telemetry.Sample{p})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 레코드로 바꿔 한 번에 씀 (모든 레코드가 확인될 때까지 기다림)
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 값이 하나도 남지 않은 샘플은 드롭 기록 후 건너뜀
+ */
+func (s *KafkaStore) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := s.tracer.Start(ctx, "kafka store write", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", s.w.Topic),
+			attribute.Int("kafka.samples", len(ss)),
+		))
+	defer span.End()
+
+	now := time.Now()
+	msgs := make([]kafka.Message, 0, len(ss))
+	for _, p := range ss {
+		values := make(map[string]float64, len(p.Values))
+		for k, v := range p.Values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				s.drops.Record("kafka_store", drops.ReasonValidation, p.DeviceID, k+": non-finite value dropped")
+				continue
+			}
+			values[k] = v
+		}
+		if len(values) == 0 {
+			s.drops.Record("kafka_store", drops.ReasonValidation, p.DeviceID, "sample has no fields")
+			continue
+		}
+		at := p.Time
+		if at.IsZero() {
+			at = now
+		}
+		tags := s.tags
+		if len(p.Tags) > 0 {
+			tags = make(map[string]string, len(s.tags)+len(p.Tags))
+			for k, v := range s.tags {
+				tags[k] = v
+			}
+			for k, v := range p.Tags {
+				tags[k] = v
+			}
+		}
+		value, err := s.encode(p.DeviceID, at, values, tags)
+		if err != nil {
+			s.drops.Record("kafka_store", drops.ReasonValidation, p.DeviceID, err.Error())
+			continue
+		}
+		msgs = append(msgs, kafka.Message{Key: []byte(p.DeviceID), Value: value, Time: at})
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if err := s.w.WriteMessages(ctx, msgs...); err != nil {
+		s.log.Error("kafka store write failed", zap.Int("records", len(msgs)), zap.Error(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("kafka store write: %w", err)
+	}
+	s.log.Debug("kafka store write success", zap.Int("records", len(msgs)))
+	return nil
+}
+
+// encode : 레코드 값 (json 또는 avro)
+func (s *KafkaStore) encode(device string, at time.Time, values map[string]float64, tags map[string]string) ([]byte, error) {
+	if !s.avro {
+		return json.Marshal(struct {
+			Device string             `json:"device"`
+			Time   time.Time          `json:"time"`
+			Values map[string]float64 `json:"values"`
+			Tags   map[string]string  `json:"tags,omitempty"`
+		}{device, at.UTC(), values, tags})
+	}
+	var b []byte
+	if s.schemaID > 0 {
+		b = append(b, 0) // Confluent 매직 바이트
+		b = binary.BigEndian.AppendUint32(b, uint32(s.schemaID))
+	}
+	b = avroString(b, device)
+	b = avroLong(b, at.UnixMilli())
+	b = avroLong(b, int64(len(values)))
+	for _, k := range sortedKeys(values) {
+		b = avroString(b, k)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(values[k]))
+	}
+	if len(values) > 0 {
+		b = avroLong(b, 0) // map 블록 끝
+	}
+	b = avroLong(b, int64(len(tags)))
+	for _, k := range sortedKeys(tags) {
+		b = avroString(b, k)
+		b = avroString(b, tags[k])
+	}
+	if len(tags) > 0 {
+		b = avroLong(b, 0)
+	}
+	return b, nil
+}
+
+// avroLong : Avro long (지그재그 가변 길이)
+func avroLong(b []byte, n int64) []byte {
+	return binary.AppendUvarint(b, uint64((n<<1)^(n>>63)))
+}
+
+// avroString : Avro string (길이 + UTF-8 바이트)
+func avroString(b []byte, s string) []byte {
+	return append(avroLong(b, int64(len(s))), s...)
+}
+
+/*
+ * Ping : 브로커 하나에라도 연결되는지 확인 (readiness 검사)
+ */
+func (s *KafkaStore) Ping(ctx context.Context) error {
+	var err error
+	for _, b := range s.brokers {
+		var conn *kafka.Conn
+		if conn, err = kafka.DialContext(ctx, "tcp", b); err == nil {
+			return conn.Close()
+		}
+	}
+	return err
+}
+
+/*
+ * Close : 남은 쓰기를 마치고 종료
+ */
+func (s *KafkaStore) Close() error {
+	return s.w.Close()
+}
+
+// Query : 지원하지 않음 (errKafkaStoreWriteOnly)
+func (s *KafkaStore) Query(context.Context, telemetry.QuerySpec) ([]telemetry.Series, error) {
+	return nil, errKafkaStoreWriteOnly
+}
+
+// Window : 지원하지 않음 (errKafkaStoreWriteOnly)
+func (s *KafkaStore) Window(context.Context, string, time.Time, time.Time) ([]telemetry.Sample, error) {
+	return nil, errKafkaStoreWriteOnly
+}
+
+// Stream : 지원하지 않음 (errKafkaStoreWriteOnly)
+func (s *KafkaStore) Stream(context.Context, string, time.Time, time.Time, func(telemetry.Sample) error) error {
+	return errKafkaStoreWriteOnly
+}
+
+// FieldKeys : 지원하지 않음 (errKafkaStoreWriteOnly)
+func (s *KafkaStore) FieldKeys(context.Context) ([]string, error) {
+	return nil, errKafkaStoreWriteOnly
+}
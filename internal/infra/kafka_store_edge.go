@@ -0,0 +1,20 @@
+//go:build edge
+
+/*
+ * Kafka 저장소 (edge 빌드용)
+ *  - edge 빌드에는 Kafka 클라이언트가 포함되지 않으므로 APP_STORE_BACKEND=kafka를 쓸 수 없습니다.
+ */
+package infra
+
+import (
+	"go.opentelemetry.io/otel/trace" // 쓰기 스팬
+	"go.uber.org/fx"                 // Fx 프레임워크
+	"go.uber.org/zap"                // 로깅 도구
+
+	"generic-api-scaffold/internal/drops" // 쓰기 거절 기록
+)
+
+func newKafkaStore(_ fx.Lifecycle, log *zap.Logger, _ *drops.Recorder, _ trace.TracerProvider) InfluxStore {
+	log.Fatal("APP_STORE_BACKEND=kafka is not available in the edge build")
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+ * 리스너 구성 : 서버가 어디에서 요청을 받을지 정합니다.
+ *  - 유닉스 도메인 소켓 : 사이드카/리버스 프록시 배포에서 TCP 포트를 전혀 노출하지 않으려는 경우
+ *    (이전 실행이 남긴 소켓 파일은 열기 전에 지우고, 종료 시에는 net 패키지가 소켓 파일을 지움)
+ *  - 다중 리스너 : APP_LISTEN 목록으로 여러 주소를 동시에 열기 (듀얼 스택, HTTP + HTTPS 등)
+ *      예) APP_LISTEN=:8080,[::1]:8081,https://:8443,unix:///run/app/api.sock
+ *      - 접두어 없음 또는 http:// : 평문 HTTP
+ *      - https://                : TLS (인증서 APP_TLS_CERT / 키 APP_TLS_KEY 필요)
+ *      - unix://                 : 유닉스 소켓 (권한 APP_SOCKET_MODE)
+ */
+package infra
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// 소켓 파일 기본 권한 (소유자/그룹 읽기·쓰기 — 프록시를 같은 그룹에 두는 배포 기준)
+const defaultSocketMode = 0o660
+
+/*
+ * listenSpec : 서버 하나의 리스닝 대상
+ *  - Network : "tcp" 또는 "unix"
+ *  - Addr    : TCP 주소(예: ":8080") 또는 소켓 파일 경로
+ *  - Mode    : 유닉스 소켓 파일 권한 (tcp에서는 사용하지 않음)
+ *  - TLS     : TLS로 감쌀지 여부 (https://)
+ */
+type listenSpec struct {
+	Network string
+	Addr    string
+	Mode    fs.FileMode
+	TLS     bool
+}
+
+/*
+ * socketSpec : <prefix>_SOCKET / <prefix>_SOCKET_MODE 환경변수로 유닉스 소켓 구성을 읽음
+ *  - 소켓 경로가 비어 있으면 tcpAddr로 TCP 리스닝
+ *  - 권한은 8진수 문자열 (예: 660, 0600), 잘못된 값이면 Fatal
+ */
+func socketSpec(log *zap.Logger, prefix, tcpAddr string) listenSpec {
+	path := config.String(prefix+"_SOCKET", "")
+	if path == "" {
+		return listenSpec{Network: "tcp", Addr: tcpAddr}
+	}
+	return listenSpec{Network: "unix", Addr: path, Mode: socketMode(log, prefix)}
+}
+
+// socketMode : <prefix>_SOCKET_MODE (8진수, 기본 660)
+func socketMode(log *zap.Logger, prefix string) fs.FileMode {
+	v := config.String(prefix+"_SOCKET_MODE", "")
+	if v == "" {
+		return defaultSocketMode
+	}
+	m, err := strconv.ParseUint(v, 8, 32)
+	if err != nil || m > 0o777 {
+		log.Fatal("invalid socket mode, expected octal permission bits",
+			zap.String("key", prefix+"_SOCKET_MODE"), zap.String("value", v))
+	}
+	return fs.FileMode(m)
+}
+
+/*
+ * listenSpecs : API 서버의 리스닝 대상 목록
+ *  - APP_LISTEN이 있으면 목록의 모든 주소 (APP_PORT / APP_SOCKET은 무시)
+ *  - 없으면 기존대로 APP_SOCKET 또는 tcpAddr(APP_PORT) 하나
+ *  - 같은 대상이 중복되거나 형식이 잘못되면 Fatal
+ */
+func listenSpecs(log *zap.Logger, tcpAddr string) []listenSpec {
+	entries := config.List("APP_LISTEN", nil)
+	if len(entries) == 0 {
+		return []listenSpec{socketSpec(log, "APP", tcpAddr)}
+	}
+	seen := make(map[string]bool, len(entries))
+	specs := make([]listenSpec, 0, len(entries))
+	for _, e := range entries {
+		var l listenSpec
+		switch {
+		case strings.HasPrefix(e, "unix://"):
+			l = listenSpec{Network: "unix", Addr: strings.TrimPrefix(e, "unix://"), Mode: socketMode(log, "APP")}
+		case strings.HasPrefix(e, "https://"):
+			l = listenSpec{Network: "tcp", Addr: strings.TrimPrefix(e, "https://"), TLS: true}
+		default:
+			l = listenSpec{Network: "tcp", Addr: strings.TrimPrefix(e, "http://")}
+		}
+		if l.Network == "tcp" {
+			if _, _, err := net.SplitHostPort(l.Addr); err != nil {
+				log.Fatal("invalid APP_LISTEN entry", zap.String("entry", e), zap.Error(err))
+			}
+		}
+		if l.Addr == "" || seen[l.String()] {
+			log.Fatal("invalid or duplicate APP_LISTEN entry", zap.String("entry", e))
+		}
+		seen[l.String()] = true
+		specs = append(specs, l)
+	}
+	return specs
+}
+
+/*
+ * tlsConfig : https 리스너가 하나라도 있으면 APP_TLS_CERT / APP_TLS_KEY로 인증서를 읽음
+ *  - https 리스너가 없으면 nil
+ *  - 인증서가 없거나 읽을 수 없으면 Fatal
+ */
+func tlsConfig(log *zap.Logger, specs []listenSpec) *tls.Config {
+	need := false
+	for _, l := range specs {
+		need = need || l.TLS
+	}
+	if !need {
+		return nil
+	}
+	certFile := config.String("APP_TLS_CERT", "")
+	keyFile := config.String("APP_TLS_KEY", "")
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		log.Fatal("https listener requires APP_TLS_CERT and APP_TLS_KEY", zap.Error(err))
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+}
+
+// String : 로그용 리스닝 대상 표시 (예: tcp :8080, tls :8443, unix /run/app.sock)
+func (l listenSpec) String() string {
+	if l.TLS {
+		return "tls " + l.Addr
+	}
+	return l.Network + " " + l.Addr
+}
+
+/*
+ * openListeners : 목록의 리스너를 모두 열기
+ *  - 하나라도 실패하면 이미 연 리스너를 닫고 에러 (일부만 열린 채로 시작하지 않음)
+ */
+func openListeners(specs []listenSpec, tc *tls.Config) ([]net.Listener, error) {
+	lns := make([]net.Listener, 0, len(specs))
+	for _, l := range specs {
+		ln, err := l.listen()
+		if err != nil {
+			for _, opened := range lns {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("listen on %s: %w", l, err)
+		}
+		if l.TLS {
+			ln = tls.NewListener(ln, tc)
+		}
+		lns = append(lns, ln)
+	}
+	return lns, nil
+}
+
+/*
+ * listen : 리스너 열기
+ *  - unix : 남아 있는 소켓 파일 제거 → 리스닝 → 권한 적용
+ *           (경로에 소켓이 아닌 파일이 있으면 지우지 않고 에러)
+ */
+func (l listenSpec) listen() (net.Listener, error) {
+	if l.Network != "unix" {
+		return net.Listen(l.Network, l.Addr)
+	}
+	if fi, err := os.Lstat(l.Addr); err == nil {
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("socket path %s exists and is not a socket", l.Addr)
+		}
+		if err := os.Remove(l.Addr); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", l.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(l.Addr, l.Mode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("chmod socket: %w", err)
+	}
+	return ln, nil
+}
@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestListenSpecs(t *testing.T) {
+	tests := []struct {
+		name   string
+		listen string
+		socket string
+		mode   string
+		want   []listenSpec
+		fatal  bool
+	}{
+		{name: "default tcp", want: []listenSpec{{Network: "tcp", Addr: ":8080"}}},
+		{name: "legacy socket", socket: "/run/app.sock", mode: "600",
+			want: []listenSpec{{Network: "unix", Addr: "/run/app.sock", Mode: 0o600}}},
+		{name: "legacy socket default mode", socket: "/run/app.sock",
+			want: []listenSpec{{Network: "unix", Addr: "/run/app.sock", Mode: defaultSocketMode}}},
+		{name: "listen list overrides port and socket", listen: "http://:9090, https://0.0.0.0:8443,unix:///run/app.sock", socket: "/ignored.sock",
+			want: []listenSpec{
+				{Network: "tcp", Addr: ":9090"},
+				{Network: "tcp", Addr: "0.0.0.0:8443", TLS: true},
+				{Network: "unix", Addr: "/run/app.sock", Mode: defaultSocketMode},
+			}},
+		{name: "bare address is http", listen: "127.0.0.1:8080",
+			want: []listenSpec{{Network: "tcp", Addr: "127.0.0.1:8080"}}},
+		{name: "same port plain and tls", listen: "http://:8443,https://:8443",
+			want: []listenSpec{{Network: "tcp", Addr: ":8443"}, {Network: "tcp", Addr: ":8443", TLS: true}}},
+		{name: "missing port", listen: "http://localhost", fatal: true},
+		{name: "duplicate", listen: "http://:9090,:9090", fatal: true},
+		{name: "empty socket path", listen: "unix://", fatal: true},
+		{name: "bad socket mode", socket: "/run/app.sock", mode: "999", fatal: true},
+		{name: "socket mode out of range", socket: "/run/app.sock", mode: "1777", fatal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_LISTEN", tt.listen)
+			t.Setenv("APP_SOCKET", tt.socket)
+			t.Setenv("APP_SOCKET_MODE", tt.mode)
+			// Fatal은 프로세스를 끝내므로 panic으로 바꿔 확인
+			log := zap.New(zapcore.NewNopCore(), zap.WithFatalHook(zapcore.WriteThenPanic))
+
+			var got []listenSpec
+			fatal := func() (fatal bool) {
+				defer func() { fatal = recover() != nil }()
+				got = listenSpecs(log, ":8080")
+				return false
+			}()
+			if fatal != tt.fatal {
+				t.Fatalf("fatal=%v, want %v (specs %v)", fatal, tt.fatal, got)
+			}
+			if !tt.fatal && !slices.Equal(got, tt.want) {
+				t.Fatalf("specs %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
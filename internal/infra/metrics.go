@@ -0,0 +1,43 @@
+/*
+ * Prometheus 메트릭 레지스트리
+ *  - 애플리케이션 전용 레지스트리를 fx로 제공하여, HTTP·버스·수집기·Influx 등
+ *    각 구성요소가 자신의 메트릭을 같은 레지스트리에 등록하도록 합니다.
+ *  - 전역 prometheus.DefaultRegisterer를 쓰지 않으므로, 노출되는 메트릭이 명시적으로 관리됩니다.
+ *  - 관리 서버(AdminServer)의 /metrics 엔드포인트가 이 레지스트리의 내용을 Prometheus 텍스트 형식으로 노출합니다.
+ */
+package infra
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"            // 메트릭 타입 및 레지스트리
+	"github.com/prometheus/client_golang/prometheus/collectors" // Go 런타임/프로세스 기본 수집기
+	"github.com/prometheus/client_golang/prometheus/promhttp"   // /metrics HTTP 핸들러
+)
+
+// 애플리케이션 메트릭 이름 앞에 붙는 공통 접두사 (예: scaffold_http_requests_total)
+const metricsNamespace = "scaffold"
+
+/*
+ * NewMetricsRegistry : fx가 호출하는 메트릭 레지스트리 생성자
+ *  - 기본 수집기 등록 :
+ *     ① Go 런타임 (고루틴 수, GC, 메모리 등)
+ *     ② 프로세스 (CPU 시간, RSS, 열린 파일 디스크립터 등)
+ *  - 반환 : *prometheus.Registry (다른 구성요소는 MustRegister로 메트릭 추가)
+ */
+func NewMetricsRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+/*
+ * metricsHandler : 레지스트리 내용을 노출하는 /metrics 핸들러
+ *  - 수집 중 발생한 에러도 같은 레지스트리의 promhttp 메트릭으로 기록됩니다.
+ */
+func metricsHandler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
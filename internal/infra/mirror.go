@@ -0,0 +1,50 @@
+/*
+ * 보조 저장소 미러링 : 기본 저장소와 함께 두 번째 저장소에도 같은 데이터를 기록합니다.
+ *  - Influx 1.x → 2.x, Influx → TimescaleDB 같은 이전을 한 번에 바꾸지 않고, 두 저장소에 함께 기록하며 검증 기간을 둔 뒤 전환하기 위한 기능
+ *  - 조회·내보내기·시뮬레이터·readiness 검사는 기본 저장소만 씀 (보조 저장소는 기록 전용)
+ *  - 보조 저장소 설정은 APP_MIRROR_ 접두사 환경변수로 지정 (config.Overlay)
+ *      APP_MIRROR_STORE_BACKEND=influx, APP_MIRROR_INFLUX_VERSION=2, APP_MIRROR_INFLUX_URL=... 처럼 APP_ 뒤를 그대로 붙임
+ *      지정하지 않은 키는 기본 저장소 값을 함께 씀 (측정값 이름, 고정 태그, 필드 타입 등 스키마 설정을 두 번 적지 않아도 됨)
+ *  - 실패 처리는 기본 저장소와 따로
+ *      저널이 켜져 있으면 저널 소비자 "mirror"가 자기 위치에서 다시 시도하고, 없으면 버스 구독자 "mirror"의 재시도/데드레터 큐를 씀
+ *      보조 저장소가 느리거나 멈춰도 기본 저장소 기록은 영향을 받지 않음
+ *      디스크 스풀(WAL)과 서킷 브레이커는 기본 저장소에만 적용
+ *  - 메트릭은 scaffold_mirror_points_written_total, scaffold_mirror_batches_total{result}, scaffold_mirror_write_duration_seconds,
+ *    scaffold_mirror_write_retries_total, scaffold_mirror_journal_backlog (store_metrics.go와 같은 뜻)
+ */
+package infra
+
+import (
+	"github.com/prometheus/client_golang/prometheus" // 보조 저장소 메트릭
+	"go.opentelemetry.io/otel/trace"                 // 쓰기 스팬
+	"go.uber.org/fx"                                 // Fx 프레임워크
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"     // 수집 이벤트 구독
+	"generic-api-scaffold/internal/config"  // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"   // 쓰기 거절 기록
+	"generic-api-scaffold/internal/journal" // 영속 저널 소비
+)
+
+/*
+ * attachMirror : APP_MIRROR_STORE_BACKEND가 있으면 보조 저장소를 만들어 수집 이벤트 기록에 연결
+ *  - 묶음 크기·재시도·버퍼 설정(APP_INFLUX_BATCH_SIZE 등)도 APP_MIRROR_INFLUX_BATCH_SIZE처럼 따로 지정할 수 있음
+ *  - 보조 저장소의 로그에는 store="mirror"가 붙음
+ */
+func attachMirror(lc fx.Lifecycle, log *zap.Logger, eb *bus.EventBus, j *journal.Journal, dr *drops.Recorder, tp trace.TracerProvider,
+	reg *prometheus.Registry, en *TagEnrichment) {
+	backend := config.String("APP_MIRROR_STORE_BACKEND", "")
+	if backend == "" {
+		return
+	}
+	mlog := log.With(zap.String("store", "mirror"))
+	m := newStoreMetrics(reg, "mirror")
+	if j.Enabled() {
+		m.watchJournal(reg, j, "mirror")
+	}
+	config.Overlay("APP_MIRROR_", func() {
+		s := newStoreBackend(lc, mlog, dr, tp)
+		attachStore(mlog, eb, j, m, en, "mirror", withMetrics(m, s))
+	})
+	log.Info("store mirroring enabled", zap.String("primary", config.String("APP_STORE_BACKEND", "influx")), zap.String("mirror", backend))
+}
@@ -0,0 +1,68 @@
+/*
+ * 404 / 405 핸들러
+ *  - gorilla/mux의 기본 응답(평문 "404 page not found")을 표준 에러 봉투로 바꿉니다.
+ *  - 405 응답에는 해당 경로에서 허용되는 메서드를 Allow 헤더와 메시지로 알려 줍니다.
+ *  - 라우터 미들웨어(r.Use)는 매칭된 라우트에만 적용되므로, 접근 로그·메트릭 미들웨어를 직접 감쌉니다.
+ */
+package infra
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux" // 라우트 순회 및 매칭
+)
+
+// registerFallbackHandlers : 라우터에 404/405 핸들러 등록 (모든 라우트 등록 후 호출)
+func (s *Server) registerFallbackHandlers() {
+	s.router.NotFoundHandler = s.accessLog(s.instrument(http.HandlerFunc(s.handleNotFound)))
+	s.router.MethodNotAllowedHandler = s.accessLog(s.instrument(http.HandlerFunc(s.handleMethodNotAllowed)))
+}
+
+// handleNotFound : 일치하는 경로 없음
+func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	respondError(w, r, http.StatusNotFound, "not_found", "no route for "+r.URL.Path)
+}
+
+/*
+ * handleMethodNotAllowed : 경로는 있지만 메서드가 다름
+ *  - Allow 헤더 : 이 경로에서 허용되는 메서드 목록 (정렬)
+ */
+func (s *Server) handleMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	allowed := s.allowedMethods(r)
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+	}
+	respondError(w, r, http.StatusMethodNotAllowed, "method_not_allowed",
+		r.Method+" not allowed for "+r.URL.Path+"; allowed: "+strings.Join(allowed, ", "))
+}
+
+/*
+ * allowedMethods : 요청 경로와 일치하는 라우트들이 허용하는 메서드 목록
+ *  - 라우트마다 선언된 메서드로 요청을 바꿔 매칭해 보고, 일치하면 허용 목록에 추가
+ */
+func (s *Server) allowedMethods(r *http.Request) []string {
+	set := make(map[string]bool)
+	_ = s.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil // 메서드 제한이 없는 라우트 (PathPrefix 등)
+		}
+		for _, m := range methods {
+			probe := r.Clone(r.Context())
+			probe.Method = m
+			if route.Match(probe, &mux.RouteMatch{}) {
+				set[m] = true
+			}
+		}
+		return nil
+	})
+
+	out := make([]string, 0, len(set))
+	for m := range set {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,140 @@
+/*
+ * 운영자 오버라이드 API 핸들러
+ *  - POST   /api/overrides       : 세션 시작 {"device":"A1","reason":"...","ttl":"15m"} (override 역할 필요)
+ *      형식 오류(TTL 해석 불가, 0 이하, 상한 초과 포함)는 400 validation_failed, 이미 진행 중인 세션이 있으면 409
+ *  - GET    /api/overrides       : 진행 중인 세션 목록
+ *  - DELETE /api/overrides/{id}  : 세션 조기 종료 (override 역할 필요)
+ *  - 시작/종료는 심각도 8의 감사 이벤트로 남기고, 만료는 OverrideManager 리스너가 기록합니다.
+ */
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux" // 경로 변수({id}) 추출
+
+	"generic-api-scaffold/internal/audit"   // 감사 이벤트 기록
+	"generic-api-scaffold/internal/auth"    // 호출자 식별 및 역할 확인
+	"generic-api-scaffold/internal/control" // 오버라이드 세션 관리
+)
+
+// overrideSeverity : 오버라이드 관련 감사 이벤트 심각도 (SIEM에서 눈에 띄도록 높게)
+const overrideSeverity = 8
+
+// overrideReq : 세션 시작 요청 본문
+type overrideReq struct {
+	Device string `json:"device" validate:"required"`
+	Reason string `json:"reason" validate:"required"`
+	TTL    string `json:"ttl" validate:"required"` // Go duration 문자열 (예: "15m")
+}
+
+/*
+ * requireRole : 호출자가 role을 가졌는지 확인
+ *  - 토큰이 없거나 틀리면 401, 역할이 없으면 403 (응답을 이미 썼으면 false)
+ */
+func (s *Server) requireRole(w http.ResponseWriter, r *http.Request, role string) (auth.Principal, bool) {
+	p, err := s.auth.Identify(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="scaffold"`)
+		respondError(w, r, http.StatusUnauthorized, "unauthorized", err.Error())
+		return auth.Principal{}, false
+	}
+	if !p.HasRole(role) {
+		respondError(w, r, http.StatusForbidden, "forbidden", fmt.Sprintf("role %q required", role))
+		return auth.Principal{}, false
+	}
+	return p, true
+}
+
+func (s *Server) handleOverrideStart(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.requireRole(w, r, auth.RoleOverride)
+	if !ok {
+		return
+	}
+	var req overrideReq
+	if !s.decodeValid(w, r, &req) {
+		return
+	}
+	ttl, err := time.ParseDuration(req.TTL)
+	switch {
+	case err != nil:
+		respondValidation(w, r, []fieldError{{Field: "ttl", Rule: "duration", Message: "ttl must be a duration such as 15m"}})
+		return
+	case ttl <= 0:
+		respondValidation(w, r, []fieldError{{Field: "ttl", Rule: "gt", Param: "0", Message: "ttl must be positive"}})
+		return
+	case ttl > s.overrides.MaxTTL():
+		limit := s.overrides.MaxTTL().String()
+		respondValidation(w, r, []fieldError{{Field: "ttl", Rule: "max", Param: limit, Message: "ttl must be at most " + limit}})
+		return
+	}
+
+	o, err := s.overrides.Start(req.Device, p.Name, req.Reason, ttl)
+	s.audit.Record(audit.Event{
+		Category: audit.CategoryControl,
+		Action:   "override.start",
+		Outcome:  outcomeOf(err),
+		Severity: overrideSeverity,
+		Source:   r.RemoteAddr,
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		DeviceID: req.Device,
+		Detail:   fmt.Sprintf("operator=%s ttl=%s reason=%s%s", p.Name, req.TTL, req.Reason, errSuffix(err)),
+	})
+	if errors.Is(err, control.ErrOverrideActive) {
+		respondError(w, r, http.StatusConflict, "override_rejected", err.Error())
+		return
+	}
+	if err != nil {
+		respondValidation(w, r, []fieldError{{Rule: "invalid", Message: err.Error()}})
+		return
+	}
+	respond(w, r, http.StatusCreated, o)
+}
+
+func (s *Server) handleOverrideList(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, http.StatusOK, s.overrides.List())
+}
+
+func (s *Server) handleOverrideEnd(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.requireRole(w, r, auth.RoleOverride)
+	if !ok {
+		return
+	}
+	o, err := s.overrides.End(mux.Vars(r)["id"], p.Name)
+	if errors.Is(err, control.ErrNotFound) {
+		respondError(w, r, http.StatusNotFound, "not_found", "override not found or already ended")
+		return
+	}
+	s.audit.Record(audit.Event{
+		Category: audit.CategoryControl,
+		Action:   "override.end",
+		Outcome:  audit.OutcomeSuccess,
+		Severity: overrideSeverity,
+		Source:   r.RemoteAddr,
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		DeviceID: o.DeviceID,
+		Detail:   fmt.Sprintf("id=%s operator=%s", o.ID, p.Name),
+	})
+	respond(w, r, http.StatusOK, o)
+}
+
+// outcomeOf : 에러 여부 → 감사 결과
+func outcomeOf(err error) string {
+	if err != nil {
+		return audit.OutcomeFailure
+	}
+	return audit.OutcomeSuccess
+}
+
+// errSuffix : 감사 상세 메시지 뒤에 붙일 에러 설명
+func errSuffix(err error) string {
+	if err != nil {
+		return ": " + err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,407 @@
+//go:build !edge
+
+/*
+ * PostgresRepo : 일반 PostgreSQL 저장소 (APP_STORE_BACKEND=postgres, 기본 빌드 전용)
+ *  - TimescaleDB 확장을 설치할 수 없는 소규모 설치용이며, 같은 저장소 인터페이스(InfluxStore)를 구현합니다.
+ *  - 시각 범위로 월마다 나눈 파티션 테이블 하나 (APP_POSTGRES_TABLE, 기본 telemetry)
+ *      device_id text, time timestamptz, field text, value double precision
+ *      기본 키 (device_id, field, time) - 같은 포인트를 다시 기록하면 덮어씀 (버스/저널/WAL 재시도에 멱등)
+ *    월 파티션(<테이블>_YYYY_MM, UTC 기준)은 시작 시 이번 달·다음 달을, 그 밖의 달은 처음 기록할 때 만듭니다.
+ *    오래된 파티션은 DROP TABLE로 통째로 지우면 됩니다.
+ *  - 테이블 구조는 시작 시 스키마 마이그레이션(postgresMigrations)으로 맞춥니다.
+ *      적용한 버전은 scaffold_schema_migrations에 남고, 여러 인스턴스가 동시에 시작해도 advisory 잠금으로 한 번만 적용
+ *  - 묶음 쓰기는 여러 행 INSERT ... ON CONFLICT를 postgresInsertRows행씩 트랜잭션 하나로 실행합니다.
+ *  - 태그(고정 태그, 태그로 올린 값)는 저장하지 않으며, 라우팅 규칙과 관계없이 모든 장치를 같은 테이블에 기록합니다.
+ *    필드 타입 선언은 APP_INFLUX_MEASUREMENT 측정값 기준 (bool은 1/0, string 필드는 드롭 기록)
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"    // 연결 풀
+	"go.opentelemetry.io/otel/attribute" // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"     // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"     // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                     // Fx 프레임워크
+	"go.uber.org/zap"                    // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+var _ InfluxStore = (*PostgresRepo)(nil)
+
+// INSERT 문 하나의 최대 행 수 (행마다 인자 4개, Postgres 인자 한도 65535 아래)
+const postgresInsertRows = 1000
+
+/*
+ * postgresMigrations : 스키마 마이그레이션 (순서대로 한 번씩 적용, 버전 = 순번 + 1)
+ *  - sql은 테이블 이름(APP_POSTGRES_TABLE)으로 실행할 문장을 만듦
+ *  - 이미 배포한 항목은 바꾸지 말고 새 항목을 뒤에 추가
+ */
+var postgresMigrations = []struct {
+	name string
+	sql  func(table string) string
+}{
+	{"create partitioned table", func(table string) string {
+		return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  device_id text             NOT NULL,
+  time      timestamptz      NOT NULL,
+  field     text             NOT NULL,
+  value     double precision NOT NULL,
+  PRIMARY KEY (device_id, field, time)
+) PARTITION BY RANGE (time)`, sqlIdent(table))
+	}},
+	{"time index", func(table string) string { // 필드 목록 조회(최근 30일)용
+		return fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (time)`, sqlIdent(table+"_time_idx"), sqlIdent(table))
+	}},
+}
+
+// PostgresRepo : 파티션 테이블에 데이터를 쓰고 SQL로 읽는 저장소
+type PostgresRepo struct {
+	log    *zap.Logger
+	pool   *pgxpool.Pool
+	table  string
+	schema influxSchema
+	drops  *drops.Recorder
+	tracer trace.Tracer
+
+	mu         sync.Mutex
+	migrated   bool            // 이번 실행에서 마이그레이션을 확인했는지
+	partitions map[string]bool // 있는 것을 확인한 월 파티션 이름
+}
+
+// newPostgresStore : newStoreBackend가 APP_STORE_BACKEND=postgres일 때 호출 (edge 빌드는 postgres_edge.go)
+func newPostgresStore(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	return NewPostgresRepo(lc, log, dr, tp)
+}
+
+/*
+ * NewPostgresRepo : PostgresRepo 생성자 (NewInfluxStore가 APP_STORE_BACKEND=postgres일 때 호출)
+ *  - APP_POSTGRES_DSN   : 연결 문자열 (필수, 예: postgres://scaffold:secret@db:5432/telemetry)
+ *  - APP_POSTGRES_TABLE : 테이블 이름 (기본 telemetry)
+ *  - OnStart 시 마이그레이션과 이번 달·다음 달 파티션 준비 (DB에 닿지 않아도 시작은 막지 않고 첫 쓰기에서 다시 시도)
+ *  - OnStop 시 연결 풀 종료
+ */
+func NewPostgresRepo(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) *PostgresRepo {
+	dsn := config.String("APP_POSTGRES_DSN", "")
+	if dsn == "" {
+		log.Fatal("APP_POSTGRES_DSN is required for APP_STORE_BACKEND=postgres")
+	}
+	pool, err := pgxpool.New(context.Background(), dsn) // 연결은 처음 쓸 때 맺음
+	if err != nil {
+		log.Fatal("invalid APP_POSTGRES_DSN", zap.Error(err)) // 연결 문자열에 비밀번호가 있으므로 값은 남기지 않음
+	}
+	repo := &PostgresRepo{
+		log:        log,
+		pool:       pool,
+		table:      config.String("APP_POSTGRES_TABLE", "telemetry"),
+		schema:     loadInfluxSchema(log),
+		drops:      dr,
+		tracer:     tp.Tracer(tracerName),
+		partitions: make(map[string]bool),
+	}
+	if len(repo.schema.routes) > 0 {
+		log.Warn("APP_INFLUX_ROUTES is ignored by the postgres store, all devices are written to one table", zap.String("table", repo.table))
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			now := time.Now().UTC()
+			if err := repo.ensurePartitions(ctx, []time.Time{now, now.AddDate(0, 1, 0)}); err != nil {
+				log.Error("postgres schema not ready", zap.String("table", repo.table), zap.Error(err))
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return repo.Close()
+		},
+	})
+	log.Info("postgres store", zap.String("table", repo.table))
+	return repo
+}
+
+/*
+ * migrate : 적용하지 않은 마이그레이션을 트랜잭션 하나로 적용 (이번 실행에서 한 번만 확인)
+ */
+func (r *PostgresRepo) migrate(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.migrated {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) // Commit 뒤에는 아무것도 하지 않음
+
+	// 여러 인스턴스가 동시에 시작해도 하나씩 적용 (트랜잭션이 끝나면 풀림)
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('scaffold_schema_migrations'))`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS scaffold_schema_migrations (
+  table_name text        NOT NULL,
+  version    int         NOT NULL,
+  name       text        NOT NULL,
+  applied_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (table_name, version)
+)`); err != nil {
+		return err
+	}
+	var current int
+	if err := tx.QueryRow(ctx, `SELECT coalesce(max(version), 0) FROM scaffold_schema_migrations WHERE table_name = $1`, r.table).Scan(&current); err != nil {
+		return err
+	}
+	if current > len(postgresMigrations) {
+		return fmt.Errorf("postgres schema version %d is newer than this build (%d)", current, len(postgresMigrations))
+	}
+	for i, m := range postgresMigrations[current:] {
+		version := current + i + 1
+		if _, err := tx.Exec(ctx, m.sql(r.table)); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", version, m.name, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO scaffold_schema_migrations (table_name, version, name) VALUES ($1, $2, $3)`,
+			r.table, version, m.name); err != nil {
+			return err
+		}
+		r.log.Info("postgres migration applied", zap.String("table", r.table), zap.Int("version", version), zap.String("name", m.name))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	r.migrated = true
+	return nil
+}
+
+/*
+ * ensurePartitions : 주어진 시각들이 속한 월 파티션이 없으면 만듦 (마이그레이션 먼저 확인)
+ */
+func (r *PostgresRepo) ensurePartitions(ctx context.Context, times []time.Time) error {
+	if err := r.migrate(ctx); err != nil {
+		return err
+	}
+	for _, t := range times {
+		start := time.Date(t.UTC().Year(), t.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+		name := r.table + "_" + start.Format("2006_01")
+
+		r.mu.Lock()
+		ok := r.partitions[name]
+		r.mu.Unlock()
+		if ok {
+			continue
+		}
+		if _, err := r.pool.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+			sqlIdent(name), sqlIdent(r.table), start.Format(time.RFC3339), start.AddDate(0, 1, 0).Format(time.RFC3339))); err != nil {
+			return fmt.Errorf("partition %s: %w", name, err)
+		}
+		r.mu.Lock()
+		r.partitions[name] = true
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *PostgresRepo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+// postgresRow : 기록할 행 하나
+type postgresRow struct {
+	device string
+	at     time.Time
+	field  string
+	value  float64
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 트랜잭션 한 번으로 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 필드가 없는 샘플, 숫자로 저장할 수 없는 필드는 드롭 기록 후 건너뜀
+ *  - 같은 묶음 안에 같은 키(장치, 필드, 시각)가 여러 번 있으면 마지막 값만 기록 (ON CONFLICT가 한 행을 두 번 바꿀 수 없음)
+ *  - 쓰기 실패는 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도)
+ */
+func (r *PostgresRepo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := r.tracer.Start(ctx, "postgres write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.Int("postgres.samples", len(ss)),
+		))
+	defer span.End()
+
+	type key struct {
+		device, field string
+		at            int64
+	}
+	index := make(map[key]int)
+	var rows []postgresRow
+	var times []time.Time
+	for _, s := range ss {
+		_, fields := r.schema.point(s)
+		if len(fields) == 0 {
+			r.drops.Record("postgres", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+			continue
+		}
+		fields, ok := r.schema.checkFields(r.drops, r.schema.measurement, s.DeviceID, fields)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		times = append(times, at)
+		for k, v := range fields {
+			f, ok := toFloat(v)
+			if !ok {
+				r.drops.Record("postgres", drops.ReasonValidation, s.DeviceID, k+": non-numeric field cannot be stored")
+				continue
+			}
+			row := postgresRow{device: s.DeviceID, at: at, field: k, value: f}
+			id := key{s.DeviceID, k, at.UnixMicro()} // timestamptz 정밀도
+			if i, ok := index[id]; ok {
+				rows[i] = row
+				continue
+			}
+			index[id] = len(rows)
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := r.insertRows(ctx, times, rows); err != nil {
+		r.log.Error("postgres write failed", zap.String("table", r.table), zap.Error(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("postgres write: %w", err)
+	}
+	r.log.Debug("postgres write success", zap.Int("points", len(rows)))
+	return nil
+}
+
+// insertRows : 파티션을 준비한 뒤 여러 행 INSERT를 트랜잭션 하나로 실행
+func (r *PostgresRepo) insertRows(ctx context.Context, times []time.Time, rows []postgresRow) error {
+	if err := r.ensurePartitions(ctx, times); err != nil {
+		return err
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].at.Before(rows[j].at) }) // 파티션별로 모아 씀
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) // Commit 뒤에는 아무것도 하지 않음
+
+	for start := 0; start < len(rows); start += postgresInsertRows {
+		end := start + postgresInsertRows
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := rows[start:end]
+		values := make([]string, len(chunk))
+		args := make([]any, 0, 4*len(chunk))
+		for i, row := range chunk {
+			values[i] = fmt.Sprintf("($%d, $%d, $%d, $%d)", 4*i+1, 4*i+2, 4*i+3, 4*i+4)
+			args = append(args, row.device, row.at, row.field, row.value)
+		}
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`INSERT INTO %s (device_id, time, field, value) VALUES %s
+ON CONFLICT (device_id, field, time) DO UPDATE SET value = EXCLUDED.value`, sqlIdent(r.table), strings.Join(values, ", ")), args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+/*
+ * Ping : DB 도달 가능 여부 확인 (readiness 검사)
+ */
+func (r *PostgresRepo) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
+/*
+ * Close : 연결 풀 종료
+ */
+func (r *PostgresRepo) Close() error {
+	r.pool.Close()
+	return nil
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회 (sim.Source)
+ */
+func (r *PostgresRepo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	var out []telemetry.Sample
+	err := r.Stream(ctx, deviceID, from, to, func(s telemetry.Sample) error {
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 읽으며 샘플마다 fn 호출 (Exporter)
+ *  - 필드별 행을 시각마다 샘플 하나로 모음
+ */
+func (r *PostgresRepo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	q := postgresDialect.selectWindow(sqlIdent(r.table))
+	return streamSamples(ctx, r.pool, q, []any{deviceID, from, to}, deviceID, fn)
+}
+
+/*
+ * FieldKeys : 저장된 필드 이름 목록 (정렬, 최근 30일 기준 - 2.x와 같음)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *PostgresRepo) FieldKeys(ctx context.Context) ([]string, error) {
+	return fieldKeys(ctx, r.pool, sqlIdent(r.table))
+}
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (TimeSeriesStore, SQL)
+ *  - 집계 구간은 From부터 Every 폭으로 나눔 (Every가 0이면 전체 구간을 From 시각 하나로)
+ *  - Limit은 시계열마다 앞에서부터 적용
+ */
+func (r *PostgresRepo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	acc := newSeriesSet()
+	q, args := postgresDialect.selectSeries(sqlIdent(r.table), spec.Devices, spec)
+	if err := collectSeries(ctx, r.pool, acc, q, args); err != nil {
+		return nil, err
+	}
+	return limitSeries(acc.list(), spec.Limit), nil
+}
+
+// postgresDialect : 확장 없이 표준 함수만 쓰는 PostgreSQL SQL (first/last는 시각순 array_agg)
+var postgresDialect = sqlDialect{
+	device: "device_id",
+	aggregates: map[telemetry.Aggregate]string{
+		telemetry.AggMean:  "avg(value)",
+		telemetry.AggMin:   "min(value)",
+		telemetry.AggMax:   "max(value)",
+		telemetry.AggSum:   "sum(value)",
+		telemetry.AggCount: "count(value)::double precision",
+		telemetry.AggFirst: "(array_agg(value ORDER BY time))[1]",
+		telemetry.AggLast:  "(array_agg(value ORDER BY time DESC))[1]",
+	},
+	bucket: func(every time.Duration) string {
+		us := every.Microseconds()
+		return fmt.Sprintf("$2::timestamptz + floor(extract(epoch FROM time - $2::timestamptz) * 1000000 / %d)::double precision * INTERVAL '%d microseconds'", us, us)
+	},
+}
@@ -0,0 +1,26 @@
+//go:build !edge
+
+/*
+ * pprof 핸들러 마운트 (기본 빌드 전용)
+ *  - edge 빌드에서는 pprof_edge.go의 no-op이 사용되어 net/http/pprof가 링크되지 않습니다.
+ */
+package infra
+
+import (
+	"net/http/pprof" // 런타임 프로파일링 핸들러 (고루틴 덤프, 힙, CPU 프로파일 등)
+
+	"github.com/gorilla/mux" // HTTP 라우팅을 위한 Gorilla Mux
+)
+
+// pprofAvailable : 이 빌드에 pprof가 포함되었는지 여부
+const pprofAvailable = true
+
+// mountPprof : /debug/pprof/ 아래에 pprof 핸들러 등록
+func mountPprof(r *mux.Router) {
+	// /debug/pprof/ 아래의 이름 있는 프로파일(goroutine, heap, allocs ...)은 Index가 처리
+	r.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	r.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	r.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	r.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	r.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
+}
@@ -0,0 +1,13 @@
+//go:build edge
+
+/*
+ * pprof 핸들러 마운트 (edge 빌드용 no-op)
+ */
+package infra
+
+import "github.com/gorilla/mux" // HTTP 라우팅을 위한 Gorilla Mux
+
+// pprofAvailable : edge 빌드에는 pprof가 포함되지 않음
+const pprofAvailable = false
+
+func mountPprof(*mux.Router) {}
@@ -0,0 +1,60 @@
+/*
+ * 전력 가격 API 핸들러
+ *  - GET /api/price/hints?from=&to=&step=1h&quantile=0.25
+ *      구간별 단가와 비용 최적화 힌트(charge | discharge | hold) 반환
+ *      from/to를 생략하면 지금부터 24시간
+ */
+package infra
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"generic-api-scaffold/internal/price" // 가격 피드 및 힌트 계산
+)
+
+/*
+ * handlePriceHints : 가격 기반 충전/방전 힌트 조회
+ */
+func (s *Server) handlePriceHints(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from := time.Now().Truncate(time.Hour)
+	to := from.Add(24 * time.Hour)
+	step := time.Hour
+	quantile := 0.25
+
+	var err error
+	if v := q.Get("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "invalid from: "+err.Error())
+			return
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "invalid to: "+err.Error())
+			return
+		}
+	}
+	if v := q.Get("step"); v != "" {
+		if step, err = time.ParseDuration(v); err != nil {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "invalid step: "+err.Error())
+			return
+		}
+	}
+	if v := q.Get("quantile"); v != "" {
+		if quantile, err = strconv.ParseFloat(v, 64); err != nil {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "invalid quantile: "+err.Error())
+			return
+		}
+	}
+
+	hints, err := price.Hints(r.Context(), s.prices, from, to, step, quantile)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	respond(w, r, http.StatusOK, hints)
+}
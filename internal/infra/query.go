@@ -0,0 +1,262 @@
+/*
+ * 시계열 조회 : TimeSeriesStore.Query 구현 (원시 구간 조회 + 기본 집계)
+ *  - 1.x : InfluxQL로 조회·집계 (GROUP BY time(Every), device)
+ *  - 2.x/3 : 장치별 원시 구간(Window)을 읽어 앱에서 같은 규칙으로 집계 (aggregateSamples)
+ *  - 집계 구간은 From에 맞춰 나뉘며, 값이 없는 구간은 결과에 넣지 않습니다.
+ *  - 결과는 장치 → 필드 순으로 정렬된 시계열 목록이고, 각 시계열의 포인트는 시간순입니다.
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb1-client/v2" // InfluxDB 1.x 클라이언트
+
+	"generic-api-scaffold/internal/telemetry" // 조회 조건/결과
+)
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (InfluxQL)
+ *  - 필드를 지정하지 않으면 숫자 필드 전부 (집계 시 <함수>_<필드> 열 이름에서 접두어를 떼어 냄)
+ *  - Limit은 장치별 행 수 제한 (LIMIT, GROUP BY device와 함께 시계열마다 적용)
+ *  - 장치들이 라우팅 규칙으로 서로 다른 측정값/데이터베이스에 있으면 위치마다 나누어 질의
+ */
+func (r *InfluxRepo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	prefix := ""
+	if spec.Aggregate != telemetry.AggNone && len(spec.Fields) == 0 {
+		prefix = string(spec.Aggregate) + "_" // mean(*) → mean_<필드>
+	}
+
+	// 라우팅 규칙(APP_INFLUX_ROUTES)에 따라 같은 측정값/데이터베이스의 장치끼리 묶어 질의 한 번씩
+	groups := make(map[influxTarget][]string)
+	var order []influxTarget
+	for _, d := range spec.Devices {
+		t := r.schema.deviceTarget(d)
+		if _, ok := groups[t]; !ok {
+			order = append(order, t)
+		}
+		groups[t] = append(groups[t], d)
+	}
+
+	acc := newSeriesSet()
+	for _, t := range order {
+		part := spec
+		part.Devices = groups[t]
+		resp, err := r.client.Query(client.NewQuery(influxQLSelect(t.measurement, part), r.databaseOf(t), "ns"))
+		if err != nil {
+			return nil, err
+		}
+		if err := resp.Error(); err != nil {
+			return nil, err
+		}
+		for _, res := range resp.Results {
+			for _, row := range res.Series {
+				device := row.Tags[deviceTag]
+				for _, vals := range row.Values {
+					s := rowSample(device, row.Columns, vals)
+					for k, v := range s.Values {
+						acc.add(device, strings.TrimPrefix(k, prefix), s.Time, v)
+					}
+				}
+			}
+		}
+	}
+	return acc.list(), nil
+}
+
+// influxQLSelect : 조회 조건 → InfluxQL SELECT 문
+func influxQLSelect(measurement string, spec telemetry.QuerySpec) string {
+	var cols string
+	switch {
+	case spec.Aggregate == telemetry.AggNone && len(spec.Fields) == 0:
+		cols = "*"
+	case spec.Aggregate == telemetry.AggNone:
+		idents := make([]string, len(spec.Fields))
+		for i, f := range spec.Fields {
+			idents[i] = influxQLIdent(f)
+		}
+		cols = strings.Join(idents, ", ")
+	case len(spec.Fields) == 0:
+		cols = string(spec.Aggregate) + "(*)"
+	default:
+		exprs := make([]string, len(spec.Fields))
+		for i, f := range spec.Fields {
+			exprs[i] = fmt.Sprintf("%s(%s) AS %s", spec.Aggregate, influxQLIdent(f), influxQLIdent(f))
+		}
+		cols = strings.Join(exprs, ", ")
+	}
+
+	devices := make([]string, len(spec.Devices))
+	for i, d := range spec.Devices {
+		devices[i] = fmt.Sprintf("%s = '%s'", deviceTag, escapeInfluxString(d))
+	}
+	cmd := fmt.Sprintf("SELECT %s FROM %s WHERE (%s) AND time >= '%s' AND time < '%s' GROUP BY ",
+		cols, influxQLIdent(measurement), strings.Join(devices, " OR "),
+		spec.From.UTC().Format(time.RFC3339Nano), spec.To.UTC().Format(time.RFC3339Nano))
+
+	if spec.Aggregate != telemetry.AggNone && spec.Every > 0 {
+		offset := time.Duration(spec.From.UnixNano() % int64(spec.Every)) // 구간 경계를 From에 맞춤
+		if offset < 0 {
+			offset += spec.Every
+		}
+		cmd += fmt.Sprintf("time(%dns, %dns), %s fill(none)", spec.Every.Nanoseconds(), offset.Nanoseconds(), deviceTag)
+	} else {
+		cmd += deviceTag
+	}
+	cmd += " ORDER BY time ASC"
+	if spec.Limit > 0 {
+		cmd += fmt.Sprintf(" LIMIT %d", spec.Limit)
+	}
+	return cmd
+}
+
+/*
+ * windowQuery : 장치별 원시 구간을 읽어 앱에서 집계하는 Query (2.x/3 공통)
+ *  - window : 장치 하나의 [from, to) 구간 샘플 (각 저장소의 Window)
+ */
+func windowQuery(ctx context.Context, spec telemetry.QuerySpec,
+	window func(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error)) ([]telemetry.Series, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	var out []telemetry.Series
+	for _, device := range spec.Devices {
+		ss, err := window(ctx, device, spec.From, spec.To)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, aggregateSamples(device, ss, spec)...)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].DeviceID < out[j].DeviceID })
+	return out, nil
+}
+
+/*
+ * aggregateSamples : 장치 하나의 시간순 샘플 → 필드별 시계열 (조회 조건의 필드/집계/Limit 적용)
+ *  - 집계 구간 시각은 From + n×Every (Every가 0이면 From)
+ */
+func aggregateSamples(device string, ss []telemetry.Sample, spec telemetry.QuerySpec) []telemetry.Series {
+	want := make(map[string]bool, len(spec.Fields))
+	for _, f := range spec.Fields {
+		want[f] = true
+	}
+
+	type bucket struct {
+		at               time.Time
+		first, last, sum float64
+		min, max         float64
+		count            int
+	}
+	buckets := make(map[string][]*bucket) // 필드 → 시간순 구간
+	acc := newSeriesSet()
+	for _, s := range ss {
+		for k, v := range s.Values {
+			if len(want) > 0 && !want[k] {
+				continue
+			}
+			if spec.Aggregate == telemetry.AggNone {
+				acc.add(device, k, s.Time, v)
+				continue
+			}
+			at := spec.From
+			if spec.Every > 0 {
+				at = spec.From.Add(s.Time.Sub(spec.From) / spec.Every * spec.Every)
+			}
+			bs := buckets[k]
+			if n := len(bs); n == 0 || !bs[n-1].at.Equal(at) {
+				bs = append(bs, &bucket{at: at, first: v, min: v, max: v})
+				buckets[k] = bs
+			}
+			b := bs[len(bs)-1]
+			b.last = v
+			b.sum += v
+			b.count++
+			if v < b.min {
+				b.min = v
+			}
+			if v > b.max {
+				b.max = v
+			}
+		}
+	}
+	for field, bs := range buckets {
+		for _, b := range bs {
+			var v float64
+			switch spec.Aggregate {
+			case telemetry.AggMean:
+				v = b.sum / float64(b.count)
+			case telemetry.AggMin:
+				v = b.min
+			case telemetry.AggMax:
+				v = b.max
+			case telemetry.AggSum:
+				v = b.sum
+			case telemetry.AggCount:
+				v = float64(b.count)
+			case telemetry.AggFirst:
+				v = b.first
+			case telemetry.AggLast:
+				v = b.last
+			}
+			acc.add(device, field, b.at, v)
+		}
+	}
+
+	return limitSeries(acc.list(), spec.Limit)
+}
+
+// limitSeries : 시계열마다 앞에서부터 limit개만 남김 (0이면 제한 없음)
+func limitSeries(out []telemetry.Series, limit int) []telemetry.Series {
+	if limit > 0 {
+		for i := range out {
+			if len(out[i].Points) > limit {
+				out[i].Points = out[i].Points[:limit]
+			}
+		}
+	}
+	return out
+}
+
+// seriesSet : 장치 × 필드별로 포인트를 모으는 도우미
+type seriesSet map[[2]string]*telemetry.Series
+
+func newSeriesSet() seriesSet {
+	return make(seriesSet)
+}
+
+// add : 장치·필드 시계열에 포인트 추가
+func (a seriesSet) add(device, field string, at time.Time, v float64) {
+	key := [2]string{device, field}
+	s, ok := a[key]
+	if !ok {
+		s = &telemetry.Series{DeviceID: device, Field: field}
+		a[key] = s
+	}
+	s.Points = append(s.Points, telemetry.Point{Time: at, Value: v})
+}
+
+// list : 장치 → 필드 순으로 정렬한 시계열 목록 (포인트는 시간순)
+func (a seriesSet) list() []telemetry.Series {
+	out := make([]telemetry.Series, 0, len(a))
+	for _, s := range a {
+		sort.SliceStable(s.Points, func(i, j int) bool { return s.Points[i].Time.Before(s.Points[j].Time) })
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].DeviceID != out[j].DeviceID {
+			return out[i].DeviceID < out[j].DeviceID
+		}
+		return out[i].Field < out[j].Field
+	})
+	return out
+}
@@ -0,0 +1,279 @@
+/*
+ * 시계열 저장소 쓰기 큐 : 버스 전달과 저장소 쓰기(HTTP 요청)를 떼어 놓는 크기 제한 메모리 큐입니다.
+ *  - APP_INFLUX_QUEUE_MAX_MB를 지정하면 켜짐 (기본 0, 비활성)
+ *  - 쓰기 흐름 :
+ *      구독자(attachStore)의 묶음은 큐에 넣는 즉시 성공으로 반환되고, 전용 쓰기 고루틴(APP_INFLUX_QUEUE_WRITERS개)이 꺼내어 기록
+ *      큐에 든 샘플의 추정 메모리 크기 합이 상한을 넘으면 자리가 날 때까지 구독자가 대기
+ *        → 이벤트는 버스 구독자 큐(APP_INFLUX_BUFFER, APP_INFLUX_OVERFLOW)에 머물러, 순간적인 폭주에도 프로세스 메모리가 상한을 넘지 않음
+ *      상한보다 큰 묶음 하나는 큐가 비어 있을 때 받음 (영원히 대기하지 않도록)
+ *  - 쓰기 고루틴이 여럿이면 묶음 사이의 기록 순서는 보장하지 않음 (샘플 시각은 큐에 넣을 때 채우므로 시각은 그대로)
+ *  - 쓰기 실패는 APP_INFLUX_RETRY_ATTEMPTS번까지 APP_INFLUX_RETRY_BACKOFF부터 두 배씩 다시 시도 (scaffold_influx_write_retries_total{path="queue"})
+ *    끝내 실패하면 드롭 기록기(source="influx_queue", reason="write_failed")에 남김 (디스크 스풀을 켜면 실패한 묶음은 스풀이 받음)
+ *  - 저널(APP_JOURNAL_PATH)과 함께 켤 수 없음 : 큐에 넣는 즉시 성공을 반환하므로 저널 항목이 기록 전에 확인(ack)되어 at-least-once가 깨짐
+ *  - 종료(OnStop) 시 버스 비우기가 먼저 끝난 뒤(bus.DrainBefore, NewInfluxStore) 큐를 닫고,
+ *    남은 묶음을 종료 제한 시간 안에 모두 기록하며, 남은 것은 드롭 기록(reason="shutdown")
+ *  - 큐 적체는 scaffold_influx_queue_bytes, scaffold_influx_queue_batches
+ */
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 큐 적체 메트릭
+	"go.uber.org/fx"                                 // 쓰기 고루틴 라이프사이클
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 기록하지 못한 묶음 기록
+	"generic-api-scaffold/internal/journal"   // 저널과 함께 켜졌는지 확인
+	"generic-api-scaffold/internal/telemetry" // 기록 단위 샘플
+)
+
+// errQueueClosed : 종료 중이라 큐에 넣지 못함 (구독자의 재시도 경로를 따름)
+var errQueueClosed = errors.New("store write queue closed")
+
+// 샘플 하나의 추정 메모리 크기 (고정 부분, 값·태그마다 더하는 부분)
+const (
+	queueSampleBytes = 96 // 시각, 장치 ID·맵 헤더
+	queueEntryBytes  = 48 // 맵 항목 하나 (키 문자열 헤더 + 값 + 버킷 몫)
+)
+
+// queuedBatch : 큐에 든 묶음과 그 추정 크기
+type queuedBatch struct {
+	ss    []telemetry.Sample
+	bytes int64
+}
+
+// queueStore : 쓰기를 크기 제한 큐에 넣고 전용 고루틴이 기록하는 TimeSeriesStore
+type queueStore struct {
+	TimeSeriesStore
+	log      *zap.Logger
+	drops    *drops.Recorder
+	m        *storeMetrics
+	max      int64 // 큐 최대 크기 (바이트)
+	attempts int
+	backoff  time.Duration
+	source   string // 드롭 기록 출처 (influx_queue)
+
+	mu      sync.Mutex
+	items   []queuedBatch
+	bytes   int64
+	closed  bool
+	ready   *sync.Cond    // 쓰기 고루틴 깨우기 (새 묶음 또는 닫힘)
+	freed   chan struct{} // 자리가 날 때마다 닫고 새로 만듦 (대기 중인 구독자 깨우기)
+	size    prometheus.Gauge
+	batches prometheus.Gauge
+}
+
+/*
+ * withQueue : 저장소 쓰기에 크기 제한 큐 적용
+ *  - APP_INFLUX_QUEUE_MAX_MB  : 큐 최대 크기 (MB, 기본 0이면 큐 없이 구독자가 직접 기록)
+ *  - APP_INFLUX_QUEUE_WRITERS : 쓰기 고루틴 수 (기본 2)
+ *  - 저널이 켜져 있으면 시작하지 않음 (Fatal)
+ *  - OnStart : 쓰기 고루틴 시작 / OnStop : 큐를 닫고 남은 묶음을 기록한 뒤 종료 (제한 시간이 지나면 나머지는 드롭 기록)
+ */
+func withQueue(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, dr *drops.Recorder, m *storeMetrics, j *journal.Journal, s TimeSeriesStore) TimeSeriesStore {
+	mb := config.Int(log, "APP_INFLUX_QUEUE_MAX_MB", 0)
+	if mb < 0 {
+		log.Fatal("APP_INFLUX_QUEUE_MAX_MB must not be negative", zap.Int("value", mb))
+	}
+	if mb == 0 {
+		return s
+	}
+	if j.Enabled() {
+		log.Fatal("APP_INFLUX_QUEUE_MAX_MB cannot be combined with APP_JOURNAL_PATH, queued batches would be acknowledged before they are written")
+	}
+	writers := config.Int(log, "APP_INFLUX_QUEUE_WRITERS", 2)
+	if writers <= 0 {
+		log.Fatal("APP_INFLUX_QUEUE_WRITERS must be positive", zap.Int("value", writers))
+	}
+
+	q := &queueStore{
+		TimeSeriesStore: s, log: log, drops: dr, m: m, max: int64(mb) << 20,
+		attempts: config.Int(log, "APP_INFLUX_RETRY_ATTEMPTS", 5),
+		backoff:  config.Duration(log, "APP_INFLUX_RETRY_BACKOFF", 500*time.Millisecond),
+		source:   m.name + "_queue",
+		freed:    make(chan struct{}),
+		size: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      m.name + "_queue_bytes",
+			Help:      "Estimated memory of time-series batches waiting in the write queue.",
+		}),
+		batches: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      m.name + "_queue_batches",
+			Help:      "Time-series batches waiting in the write queue.",
+		}),
+	}
+	q.ready = sync.NewCond(&q.mu)
+	reg.MustRegister(q.size, q.batches)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			for i := 0; i < writers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					q.run(ctx)
+				}()
+			}
+			log.Info("store write queue enabled", zap.Int("max_mb", mb), zap.Int("writers", writers))
+			return nil
+		},
+		OnStop: func(stop context.Context) error {
+			q.close()
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-stop.Done():
+				cancel() // 기록 중인 쓰기를 멈추고 남은 묶음은 드롭 기록
+				<-done
+			}
+			cancel()
+			return nil
+		},
+	})
+	return q
+}
+
+// WritePoint : 큐를 거쳐 샘플 하나 기록
+func (q *queueStore) WritePoint(ctx context.Context, p telemetry.Sample) error {
+	return q.WriteBatch(ctx, []telemetry.Sample{p})
+}
+
+/*
+ * WriteBatch : 묶음을 큐에 넣고 바로 반환 (자리가 없으면 ctx가 끝날 때까지 대기)
+ *  - 샘플 시각이 비어 있으면 큐에 넣을 때의 시각으로 채움
+ */
+func (q *queueStore) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	now := time.Now()
+	b := queuedBatch{ss: make([]telemetry.Sample, len(ss))}
+	for i, p := range ss {
+		if p.Time.IsZero() {
+			p.Time = now
+		}
+		b.ss[i] = p
+		b.bytes += sampleBytes(p)
+	}
+
+	q.mu.Lock()
+	for !q.closed && q.bytes > 0 && q.bytes+b.bytes > q.max {
+		freed := q.freed
+		q.mu.Unlock()
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		q.mu.Lock()
+	}
+	defer q.mu.Unlock()
+	if q.closed {
+		return errQueueClosed
+	}
+	q.items = append(q.items, b)
+	q.bytes += b.bytes
+	q.size.Set(float64(q.bytes))
+	q.batches.Set(float64(len(q.items)))
+	q.ready.Signal()
+	return nil
+}
+
+// sampleBytes : 샘플 하나의 추정 메모리 크기
+func sampleBytes(p telemetry.Sample) int64 {
+	n := queueSampleBytes + len(p.DeviceID)
+	for k := range p.Values {
+		n += queueEntryBytes + len(k)
+	}
+	for k, v := range p.Tags {
+		n += queueEntryBytes + len(k) + len(v)
+	}
+	return int64(n)
+}
+
+// pop : 맨 앞 묶음을 꺼냄 (비어 있으면 대기, 닫혔고 비어 있으면 false)
+func (q *queueStore) pop() (queuedBatch, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if len(q.items) == 0 {
+		return queuedBatch{}, false
+	}
+	b := q.items[0]
+	q.items[0] = queuedBatch{} // 참조 해제
+	q.items = q.items[1:]
+	q.bytes -= b.bytes
+	q.size.Set(float64(q.bytes))
+	q.batches.Set(float64(len(q.items)))
+	close(q.freed)
+	q.freed = make(chan struct{})
+	return b, true
+}
+
+// close : 새 묶음을 더 받지 않음 (쓰기 고루틴은 남은 묶음을 모두 기록한 뒤 끝남)
+func (q *queueStore) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.ready.Broadcast()
+	close(q.freed)
+	q.freed = make(chan struct{})
+}
+
+/*
+ * run : 쓰기 고루틴 (큐가 닫히고 빌 때까지)
+ *  - ctx가 끝난 뒤(종료 제한 시간 초과) 꺼낸 묶음은 기록하지 않고 드롭 기록
+ */
+func (q *queueStore) run(ctx context.Context) {
+	for {
+		b, ok := q.pop()
+		if !ok {
+			return
+		}
+		if ctx.Err() != nil {
+			q.drops.Record(q.source, drops.ReasonShutdown, "", fmt.Sprintf("%d queued points not written before shutdown", len(b.ss)))
+			continue
+		}
+		q.write(ctx, b.ss)
+	}
+}
+
+// write : 묶음 하나를 기록 (실패하면 백오프를 두 배씩 늘리며 다시 시도, 끝내 실패하면 드롭 기록)
+func (q *queueStore) write(ctx context.Context, ss []telemetry.Sample) {
+	backoff := q.backoff
+	for attempt := 1; ; attempt++ {
+		err := q.TimeSeriesStore.WriteBatch(ctx, ss)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			q.drops.Record(q.source, drops.ReasonShutdown, "", fmt.Sprintf("%d queued points not written before shutdown", len(ss)))
+			return
+		}
+		if attempt >= q.attempts {
+			q.log.Error("queued store write failed, dropping batch", zap.Int("points", len(ss)), zap.Int("attempts", attempt), zap.Error(err))
+			q.drops.Record(q.source, drops.ReasonWriteFailed, "", fmt.Sprintf("%d points: %v", len(ss), err))
+			return
+		}
+		q.m.retries.WithLabelValues("queue").Inc()
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+		}
+		backoff *= 2
+	}
+}
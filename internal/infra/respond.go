@@ -0,0 +1,115 @@
+/*
+ * 응답 도우미 : 핸들러들이 공통으로 사용하는 응답/에러 응답 작성 함수
+ *  - 모든 에러는 동일한 형태의 에러 봉투(envelope)로 응답합니다.
+ *      {"error": {"code": "rate_limited", "message": "..."}}
+ *  - 요청의 Accept 헤더에 따라 응답 인코딩을 고릅니다. (콘텐츠 협상)
+ *      application/json (기본) | application/cbor | application/msgpack
+ *    대역폭이 제한된 현장 클라이언트는 CBOR/MessagePack으로 응답 크기를 줄일 수 있습니다.
+ */
+package infra
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"      // CBOR 인코딩 (json 태그를 그대로 사용)
+	"github.com/vmihailenco/msgpack/v5" // MessagePack 인코딩
+)
+
+// 지원하는 응답 미디어 타입
+const (
+	mediaJSON    = "application/json"
+	mediaCBOR    = "application/cbor"
+	mediaMsgpack = "application/msgpack"
+)
+
+// errorDetail : 에러 봉투 내부의 상세 정보
+type errorDetail struct {
+	Code    string       `json:"code"`             // 기계가 판별할 수 있는 에러 코드
+	Message string       `json:"message"`          // 사람이 읽을 수 있는 설명
+	Fields  []fieldError `json:"fields,omitempty"` // 요청 검증 실패 시 필드별 에러
+}
+
+// errorEnvelope : 표준 에러 응답 형태
+type errorEnvelope struct {
+	Error errorDetail `json:"error"`
+}
+
+/*
+ * respond : 상태 코드와 함께 값을 협상된 인코딩으로 직렬화하여 응답
+ *  - Vary: Accept 헤더를 붙여 캐시가 인코딩별로 응답을 구분하도록 함
+ */
+func respond(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	media := negotiate(r.Header.Get("Accept"))
+	w.Header().Set("Content-Type", media)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+
+	switch media {
+	case mediaCBOR:
+		_ = cbor.NewEncoder(w).Encode(v)
+	case mediaMsgpack:
+		enc := msgpack.NewEncoder(w)
+		enc.SetCustomStructTag("json") // JSON과 같은 필드 이름 사용
+		_ = enc.Encode(v)
+	default:
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+/*
+ * respondError : 표준 에러 봉투로 응답
+ */
+func respondError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	respond(w, r, status, errorEnvelope{Error: errorDetail{Code: code, Message: message}})
+}
+
+// mediaRange : Accept 헤더의 항목 하나 (미디어 타입 + 품질 값 q)
+type mediaRange struct {
+	typ string
+	q   float64
+}
+
+/*
+ * negotiate : Accept 헤더를 해석하여 응답 미디어 타입 결정
+ *  - q 값이 높은 순서대로 지원하는 타입을 찾고, 없으면 JSON
+ *  - application/x-msgpack, application/vnd.msgpack 도 MessagePack으로 취급
+ */
+func negotiate(accept string) string {
+	if accept == "" {
+		return mediaJSON
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mr := mediaRange{typ: strings.ToLower(strings.TrimSpace(fields[0])), q: 1}
+		for _, param := range fields[1:] {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && k == "q" {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					mr.q = q
+				}
+			}
+		}
+		if mr.q > 0 {
+			ranges = append(ranges, mr)
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, mr := range ranges {
+		switch mr.typ {
+		case mediaJSON, "*/*", "application/*":
+			return mediaJSON
+		case mediaCBOR:
+			return mediaCBOR
+		case mediaMsgpack, "application/x-msgpack", "application/vnd.msgpack":
+			return mediaMsgpack
+		}
+	}
+	return mediaJSON
+}
@@ -0,0 +1,95 @@
+/*
+ * 단계적 배포(rollout) API 핸들러
+ *  - POST /api/rollouts             : 배포 시작
+ *  - GET  /api/rollouts/{id}        : 진행 상황 조회
+ *  - POST /api/rollouts/{id}/abort  : 배포 중단
+ */
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux" // 경로 변수({id}) 추출
+
+	"generic-api-scaffold/internal/audit"   // 감사 이벤트 기록
+	"generic-api-scaffold/internal/control" // 배포 관리자
+)
+
+/*
+ * rolloutReq : 배포 시작 요청 본문
+ *  - StageWait : "30s", "2m" 같은 Go duration 문자열 (비어 있으면 기본값)
+ */
+type rolloutReq struct {
+	Action       string   `json:"action" validate:"required,oneof=charge discharge ready on off"`
+	KW10         int      `json:"kw10" validate:"gte=0,lte=1000"`
+	Devices      []string `json:"devices" validate:"required,min=1,dive,required"`
+	StagePercent int      `json:"stage_percent" validate:"gte=0,lte=100"` // 0이면 기본값
+	StageWait    string   `json:"stage_wait"`
+	MaxErrorRate *float64 `json:"max_error_rate" validate:"omitempty,gte=0,lte=1"` // 없으면 기본값, 0이면 실패 허용 안 함
+}
+
+/*
+ * handleRolloutStart : 배포 시작
+ *  - 성공 시 202 Accepted와 함께 배포 상태 반환 (진행은 백그라운드)
+ */
+func (s *Server) handleRolloutStart(w http.ResponseWriter, r *http.Request) {
+	var req rolloutReq
+	if !s.decodeValid(w, r, &req) {
+		return
+	}
+
+	spec := control.RolloutSpec{
+		Action:       req.Action,
+		KW10:         req.KW10,
+		Devices:      req.Devices,
+		StagePercent: req.StagePercent,
+		MaxErrorRate: req.MaxErrorRate,
+	}
+	if req.StageWait != "" {
+		d, err := time.ParseDuration(req.StageWait)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "invalid stage_wait: "+err.Error())
+			return
+		}
+		spec.StageWait = d
+	}
+
+	ro, err := s.rollouts.Start(spec)
+	s.auditEvent(r, audit.CategoryControl, "rollout.start", "",
+		fmt.Sprintf("action=%s kw10=%d devices=%d", spec.Action, spec.KW10, len(spec.Devices)), err)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	respond(w, r, http.StatusAccepted, ro)
+}
+
+/*
+ * handleRolloutGet : 배포 진행 상황 조회
+ */
+func (s *Server) handleRolloutGet(w http.ResponseWriter, r *http.Request) {
+	ro, err := s.rollouts.Get(mux.Vars(r)["id"])
+	if errors.Is(err, control.ErrNotFound) {
+		respondError(w, r, http.StatusNotFound, "not_found", "rollout not found")
+		return
+	}
+	respond(w, r, http.StatusOK, ro)
+}
+
+/*
+ * handleRolloutAbort : 배포 중단 요청
+ *  - 중단은 비동기로 반영되므로 202 Accepted로 응답
+ */
+func (s *Server) handleRolloutAbort(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	err := s.rollouts.Abort(id)
+	s.auditEvent(r, audit.CategoryControl, "rollout.abort", "", "id="+id, err)
+	if errors.Is(err, control.ErrNotFound) {
+		respondError(w, r, http.StatusNotFound, "not_found", "rollout not found")
+		return
+	}
+	respond(w, r, http.StatusAccepted, map[string]string{"status": "aborting"})
+}
@@ -0,0 +1,139 @@
+/*
+ * 라우트 등록 확장점 : 다른 패키지가 infra/http.go를 고치지 않고 API 서버에 엔드포인트를 추가할 수 있게 합니다.
+ *  - RouteRegistrar를 구현한 값을 fx 값 그룹(group:"routes")으로 제공하면
+ *    Server가 기본 라우트를 등록한 뒤 모든 등록자를 차례로 호출합니다.
+ *  - 등록자는 Server와 같은 라우터를 받으므로 접근 로그, 메트릭, 본문 크기 제한, 캐시, 타임아웃 미들웨어가 그대로 적용됩니다.
+ *    (라우트별 설정을 받으려면 .Name("...")으로 이름을 붙임, 이름은 라우트마다 달라야 함)
+ *  - Server 자신의 핸들러도 기능별 RouteFunc 묶음(builtinRoutes)으로 같은 방식으로 등록합니다.
+ *    헬스 체크를 제외한 모든 라우트에 고유한 이름이 있어 라우트별 타임아웃, 본문 크기 제한, 캐시 TTL 설정을 받을 수 있습니다.
+ *    경로가 여럿인 기능은 "기능.동작" 형식 (예: devices.list, devices.latest, control.wait)
+ */
+package infra
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux" // HTTP 라우팅
+	"go.uber.org/fx"         // 값 그룹 등록
+)
+
+/*
+ * RouteRegistrar : API 서버에 라우트를 추가하는 모듈
+ *  - RegisterRoutes : 라우터에 핸들러 등록 (NewHTTPServer 안에서 한 번 호출)
+ */
+type RouteRegistrar interface {
+	RegisterRoutes(r *mux.Router)
+}
+
+// RouteFunc : 함수 하나로 RouteRegistrar 구현
+type RouteFunc func(r *mux.Router)
+
+// RegisterRoutes : RouteRegistrar 구현
+func (f RouteFunc) RegisterRoutes(r *mux.Router) { f(r) }
+
+/*
+ * AsRouteRegistrar : 생성자의 결과를 RouteRegistrar로서 group:"routes"에 제공하도록 감쌈
+ *  - 예) fx.Provide(infra.AsRouteRegistrar(mymodule.NewHandlers))
+ *  - 생성자의 결과 타입은 RouteRegistrar를 구현해야 함
+ */
+func AsRouteRegistrar(f interface{}) interface{} {
+	return fx.Annotate(f, fx.As(new(RouteRegistrar)), fx.ResultTags(`group:"routes"`))
+}
+
+/*
+ * builtinRoutes : Server가 직접 제공하는 핸들러 묶음
+ *  - NewHTTPServer가 group:"routes" 등록자보다 먼저 차례로 등록
+ */
+func (s *Server) builtinRoutes() []RouteRegistrar {
+	return []RouteRegistrar{
+		RouteFunc(s.healthRoutes),
+		RouteFunc(s.devicesRoutes),
+		RouteFunc(s.groupRoutes),
+		RouteFunc(s.searchRoutes),
+		RouteFunc(s.webhookRoutes),
+		RouteFunc(s.exportRoutes),
+		RouteFunc(s.controlRoutes),
+		RouteFunc(s.overrideRoutes),
+		RouteFunc(s.rolloutRoutes),
+		RouteFunc(s.simulationRoutes),
+		RouteFunc(s.priceRoutes),
+	}
+}
+
+// healthRoutes : 헬스 체크(liveness/readiness)와 Ping
+func (s *Server) healthRoutes(r *mux.Router) {
+	r.HandleFunc("/livez", s.handleLive).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", s.handleReady).Methods(http.MethodGet)
+	r.HandleFunc("/healthz", s.handleLive).Methods(http.MethodGet) // 하위 호환용 (/livez와 동일)
+
+	// 간단한 Ping API: 응답에 "pong"을 반환
+	r.HandleFunc("/api/ping", s.handlePing).Methods(http.MethodGet).Name("ping")
+}
+
+// devicesRoutes : 장치 목록, 장치 최신값, 수동 수집
+func (s *Server) devicesRoutes(r *mux.Router) {
+	// 장치 목록 API: 장치별 마지막 수집 값
+	r.HandleFunc("/api/devices", s.handleDevices).Methods(http.MethodGet).Name("devices.list")
+	// 장치 최신값 API: 필드별 마지막 값과 수신 시각 (메모리 최신값 저장소)
+	r.HandleFunc("/api/devices/{id}/latest", s.handleDeviceLatest).Methods(http.MethodGet).Name("devices.latest")
+
+	// 수동 수집 API: 다음 주기를 기다리지 않고 즉시 수집·발행 (센서 디버깅용)
+	r.HandleFunc("/api/collect", s.handleCollect).Methods(http.MethodPost).Name("collect")
+}
+
+// groupRoutes : 장치 그룹(사이트, 랙 등)을 가상 장치로 집계한 값과 그룹 경보
+func (s *Server) groupRoutes(r *mux.Router) {
+	r.HandleFunc("/api/groups", s.handleGroups).Methods(http.MethodGet).Name("groups.list")
+	r.HandleFunc("/api/groups/{name}", s.handleGroup).Methods(http.MethodGet).Name("groups.get")
+	r.HandleFunc("/api/alerts", s.handleAlerts).Methods(http.MethodGet).Name("alerts.list")
+}
+
+// searchRoutes : 장치, 제어 명령, 경보 전문 검색
+func (s *Server) searchRoutes(r *mux.Router) {
+	r.HandleFunc("/api/search", s.handleSearch).Methods(http.MethodGet).Name("search")
+}
+
+// webhookRoutes : 웹훅 구독 CRUD (operator 역할 필요)
+func (s *Server) webhookRoutes(r *mux.Router) {
+	r.HandleFunc("/api/webhooks", s.handleWebhookList).Methods(http.MethodGet).Name("webhooks.list")
+	r.HandleFunc("/api/webhooks", s.handleWebhookCreate).Methods(http.MethodPost).Name("webhooks.create")
+	r.HandleFunc("/api/webhooks/{id}", s.handleWebhookGet).Methods(http.MethodGet).Name("webhooks.get")
+	r.HandleFunc("/api/webhooks/{id}", s.handleWebhookUpdate).Methods(http.MethodPut).Name("webhooks.update")
+	r.HandleFunc("/api/webhooks/{id}", s.handleWebhookDelete).Methods(http.MethodDelete).Name("webhooks.delete")
+}
+
+// exportRoutes : 구간 데이터를 CSV/NDJSON으로 스트리밍 (Influx 직접 접근 없이 분석용 추출)
+func (s *Server) exportRoutes(r *mux.Router) {
+	r.HandleFunc("/api/export", s.handleExport).Methods(http.MethodGet).Name("export")
+}
+
+// controlRoutes : 제어 명령 접수, 일괄 접수, 완료 대기(long-poll)
+func (s *Server) controlRoutes(r *mux.Router) {
+	r.HandleFunc("/api/control", s.handleControl).Methods(http.MethodPost).Name("control.submit")
+	r.HandleFunc("/api/control/batch", s.handleControlBatch).Methods(http.MethodPost).Name("control.batch")
+	r.HandleFunc("/api/control/{id}/wait", s.handleControlWait).Methods(http.MethodGet).Name("control.wait")
+}
+
+// overrideRoutes : 운영자 오버라이드 세션 (시작/종료는 override 역할 필요)
+func (s *Server) overrideRoutes(r *mux.Router) {
+	r.HandleFunc("/api/overrides", s.handleOverrideList).Methods(http.MethodGet).Name("overrides.list")
+	r.HandleFunc("/api/overrides", s.handleOverrideStart).Methods(http.MethodPost).Name("overrides.start")
+	r.HandleFunc("/api/overrides/{id}", s.handleOverrideEnd).Methods(http.MethodDelete).Name("overrides.end")
+}
+
+// rolloutRoutes : 단계적 배포 시작, 진행 조회, 중단
+func (s *Server) rolloutRoutes(r *mux.Router) {
+	r.HandleFunc("/api/rollouts", s.handleRolloutStart).Methods(http.MethodPost).Name("rollouts.start")
+	r.HandleFunc("/api/rollouts/{id}", s.handleRolloutGet).Methods(http.MethodGet).Name("rollouts.get")
+	r.HandleFunc("/api/rollouts/{id}/abort", s.handleRolloutAbort).Methods(http.MethodPost).Name("rollouts.abort")
+}
+
+// simulationRoutes : 과거 텔레메트리에 후보 정책을 적용한 what-if 결과 (실제 장치 제어 없음)
+func (s *Server) simulationRoutes(r *mux.Router) {
+	r.HandleFunc("/api/simulations", s.handleSimulation).Methods(http.MethodPost).Name("simulations")
+}
+
+// priceRoutes : 전력 가격 구간별 단가와 충전/방전 권장 동작
+func (s *Server) priceRoutes(r *mux.Router) {
+	r.HandleFunc("/api/price/hints", s.handlePriceHints).Methods(http.MethodGet).Name("price")
+}
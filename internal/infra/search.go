@@ -0,0 +1,57 @@
+/*
+ * 검색 API 핸들러
+ *  - GET /api/search?q=검색어[&kind=device,command,alert][&limit=20]
+ *    장치, 제어 명령, 경보를 가로질러 검색하고 점수 순으로 반환합니다. (대시보드 검색창에서 사용)
+ */
+package infra
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"generic-api-scaffold/internal/search" // 경량 전문 검색
+)
+
+// 검색 결과 수 기본값 / 상한
+const (
+	searchDefaultLimit = 20
+	searchMaxLimit     = 200
+)
+
+/*
+ * handleSearch : 전문 검색
+ *  - q가 비어 있으면 400
+ */
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", "q is required")
+		return
+	}
+
+	limit := searchDefaultLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+	if limit > searchMaxLimit {
+		limit = searchMaxLimit
+	}
+
+	var kinds []string
+	if v := q.Get("kind"); v != "" {
+		kinds = strings.Split(v, ",")
+	}
+
+	hits := s.search.Search(query, kinds, limit)
+	if hits == nil {
+		hits = []search.Hit{}
+	}
+	respond(w, r, http.StatusOK, map[string]interface{}{"query": query, "hits": hits})
+}
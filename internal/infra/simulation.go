@@ -0,0 +1,62 @@
+/*
+ * what-if 시뮬레이션 API 핸들러
+ *  - POST /api/simulations : 과거 텔레메트리 구간에 후보 정책을 적용한 결과 보고서 반환
+ *  - 실제 장치에는 명령을 보내지 않습니다.
+ */
+package infra
+
+import (
+	"net/http"
+	"time"
+
+	"generic-api-scaffold/internal/rules" // 후보 정책
+	"generic-api-scaffold/internal/sim"   // 시뮬레이터
+)
+
+/*
+ * simulationReq : 시뮬레이션 요청 본문
+ *  - From / To   : RFC3339 시각 (예: "2025-01-01T00:00:00Z")
+ *  - MinInterval : 정책의 명령 최소 간격 (Go duration 문자열, 선택)
+ */
+type simulationReq struct {
+	Device      string       `json:"device" validate:"required"`
+	From        time.Time    `json:"from" validate:"required"`
+	To          time.Time    `json:"to" validate:"required,gtfield=From"`
+	Rules       []rules.Rule `json:"rules"`
+	MinInterval string       `json:"min_interval"`
+	PricePerKWh float64      `json:"price_per_kwh"`
+}
+
+/*
+ * handleSimulation : 시뮬레이션 실행
+ *  - 구간 조회와 재생을 요청 처리 중에 동기로 수행하고 보고서를 200으로 반환
+ */
+func (s *Server) handleSimulation(w http.ResponseWriter, r *http.Request) {
+	var req simulationReq
+	if !s.decodeValid(w, r, &req) {
+		return
+	}
+
+	policy := rules.Policy{Rules: req.Rules}
+	if req.MinInterval != "" {
+		d, err := time.ParseDuration(req.MinInterval)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, "invalid_request", "invalid min_interval: "+err.Error())
+			return
+		}
+		policy.MinInterval = d
+	}
+
+	rep, err := s.sim.Run(r.Context(), sim.Request{
+		DeviceID:    req.Device,
+		From:        req.From,
+		To:          req.To,
+		Policy:      policy,
+		PricePerKWh: req.PricePerKWh,
+	})
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "simulation_failed", err.Error())
+		return
+	}
+	respond(w, r, http.StatusOK, rep)
+}
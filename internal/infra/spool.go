@@ -0,0 +1,198 @@
+/*
+ * 시계열 저장소 디스크 스풀 : InfluxDB에 닿지 않는 동안 묶음을 디스크 WAL(wal 패키지)에 쌓았다가 회복되면 순서대로 다시 기록합니다.
+ *  - APP_INFLUX_WAL_DIR을 지정하면 켜짐 (엣지 장비에서 네트워크가 몇 시간 끊겨도 데이터를 잃지 않도록)
+ *  - 쓰기 흐름 :
+ *      WAL이 비어 있으면 저장소에 바로 기록하고, 실패하면 그 묶음을 WAL에 덧붙인 뒤 성공으로 반환
+ *      WAL에 밀린 묶음이 있으면 순서를 지키기 위해 새 묶음도 WAL 뒤에 덧붙임
+ *  - 재생 고루틴 하나가 WAL의 가장 오래된 묶음부터 기록하고, 실패하면 APP_INFLUX_WAL_RETRY 뒤 같은 묶음부터 다시 시도합니다.
+ *    (scaffold_influx_write_retries_total{path="wal"})
+ *    (서킷 브레이커가 열려 있으면 탐침이 성공할 때까지 브레이커에서 대기)
+ *  - 샘플 시각이 비어 있으면 WAL에 넣을 때의 시각으로 채워, 나중에 기록해도 원래 시각으로 남습니다.
+ *  - WAL이 APP_INFLUX_WAL_MAX_BYTES를 넘으면 가장 오래된 세그먼트부터 지우고 드롭 기록기(source="influx_wal")에 남깁니다.
+ *  - 재시작 후 같은 묶음이 다시 기록될 수 있으나, 같은 시각·태그의 포인트는 덮어써지므로 결과는 같습니다.
+ */
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // WAL 크기 메트릭
+	"go.uber.org/fx"                                 // 재생 고루틴 라이프사이클
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 용량 초과로 지운 세그먼트 기록
+	"generic-api-scaffold/internal/telemetry" // 기록 단위 샘플
+	"generic-api-scaffold/internal/wal"       // 디스크 세그먼트 로그
+)
+
+// spoolStore : 쓰기 실패 시 디스크 WAL에 쌓는 TimeSeriesStore
+type spoolStore struct {
+	TimeSeriesStore
+	log   *zap.Logger
+	wal   *wal.WAL
+	drops *drops.Recorder
+	m     *storeMetrics
+	retry time.Duration
+	wake  chan struct{} // 새 묶음 알림 (크기 1)
+
+	mu sync.Mutex // 바로 기록할지 WAL에 넣을지 판단 + 덧붙이기를 한 번에 (순서 유지)
+}
+
+/*
+ * withSpool : 저장소 쓰기에 디스크 스풀 적용
+ *  - APP_INFLUX_WAL_DIR           : WAL 디렉터리 (비어 있으면 스풀 없음, 기본 비활성)
+ *  - APP_INFLUX_WAL_MAX_BYTES     : WAL 최대 크기 (기본 1GB)
+ *  - APP_INFLUX_WAL_SEGMENT_BYTES : 세그먼트 파일 하나의 최대 크기 (기본 16MB)
+ *  - APP_INFLUX_WAL_RETRY         : 재생 실패 후 재시도 간격 (기본 5s)
+ *  - OnStart : 재생 고루틴 시작 (이전 실행에서 남은 묶음부터) / OnStop : 재생을 멈추고 WAL 닫기
+ */
+func withSpool(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry, dr *drops.Recorder, m *storeMetrics, s TimeSeriesStore) TimeSeriesStore {
+	dir := config.String("APP_INFLUX_WAL_DIR", "")
+	if dir == "" {
+		return s
+	}
+	retry := config.Duration(log, "APP_INFLUX_WAL_RETRY", 5*time.Second)
+	if retry <= 0 {
+		log.Fatal("APP_INFLUX_WAL_RETRY must be positive", zap.Duration("value", retry))
+	}
+	w, err := wal.Open(dir, config.Bytes(log, "APP_INFLUX_WAL_SEGMENT_BYTES", 16<<20), config.Bytes(log, "APP_INFLUX_WAL_MAX_BYTES", 1<<30))
+	if err != nil {
+		log.Fatal("failed to open influx wal", zap.String("dir", dir), zap.Error(err))
+	}
+
+	sp := &spoolStore{TimeSeriesStore: s, log: log, wal: w, drops: dr, m: m, retry: retry, wake: make(chan struct{}, 1)}
+	reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "influx_wal_bytes",
+		Help:      "Bytes of spooled time-series batches waiting on disk for replay.",
+	}, func() float64 { return float64(w.Size()) }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				sp.replay(ctx)
+			}()
+			log.Info("influx wal enabled", zap.String("dir", dir), zap.Int64("bytes", w.Size()))
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return w.Close()
+		},
+	})
+	return sp
+}
+
+// WritePoint : 스풀을 거쳐 샘플 하나 기록
+func (s *spoolStore) WritePoint(ctx context.Context, p telemetry.Sample) error {
+	return s.WriteBatch(ctx, []telemetry.Sample{p})
+}
+
+/*
+ * WriteBatch : WAL이 비어 있으면 바로 기록, 실패했거나 밀린 묶음이 있으면 WAL 뒤에 덧붙임
+ *  - ctx가 끝나서 실패한 경우는 스풀하지 않고 에러 반환 (호출한 쪽의 종료 경로를 따름)
+ *  - WAL 덧붙이기까지 실패하면 에러 반환 (버스 재시도/DLQ 또는 저널 재시도)
+ */
+func (s *spoolStore) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	now := time.Now()
+	stamped := make([]telemetry.Sample, len(ss))
+	for i, p := range ss {
+		if p.Time.IsZero() {
+			p.Time = now
+		}
+		stamped[i] = p
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.wal.Empty() {
+		err := s.TimeSeriesStore.WriteBatch(ctx, stamped)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		s.log.Warn("influx write failed, spooling to wal", zap.Int("points", len(stamped)), zap.Error(err))
+	}
+	return s.spool(stamped)
+}
+
+// spool : 묶음을 WAL에 덧붙이고 재생 고루틴을 깨움 (호출자가 s.mu를 잡고 있어야 함)
+func (s *spoolStore) spool(ss []telemetry.Sample) error {
+	data, err := json.Marshal(ss)
+	if err != nil {
+		return err
+	}
+	evicted, err := s.wal.Append(data)
+	if evicted > 0 {
+		s.drops.Record("influx_wal", drops.ReasonBackpressure, "",
+			fmt.Sprintf("%d bytes of oldest spooled batches evicted (APP_INFLUX_WAL_MAX_BYTES)", evicted))
+	}
+	if err != nil {
+		return fmt.Errorf("influx wal append: %w", err)
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default: // 이미 깨울 예정
+	}
+	return nil
+}
+
+/*
+ * replay : WAL의 가장 오래된 묶음부터 저장소에 기록 (ctx가 끝날 때까지)
+ *  - 읽을 수 없는 레코드는 다시 시도해도 같으므로 드롭 기록 후 건너뜀
+ */
+func (s *spoolStore) replay(ctx context.Context) {
+	for ctx.Err() == nil {
+		data, ok, err := s.wal.Next()
+		switch {
+		case errors.Is(err, wal.ErrCorrupt):
+			s.drops.Record("influx_wal", drops.ReasonValidation, "", err.Error())
+			continue
+		case err != nil:
+			s.log.Error("influx wal read failed", zap.Error(err))
+			s.sleep(ctx, s.retry)
+			continue
+		case !ok:
+			select {
+			case <-s.wake:
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		var ss []telemetry.Sample
+		if err := json.Unmarshal(data, &ss); err != nil {
+			s.drops.Record("influx_wal", drops.ReasonValidation, "", fmt.Sprintf("undecodable wal record: %v", err))
+			s.wal.Commit()
+			continue
+		}
+		if err := s.TimeSeriesStore.WriteBatch(ctx, ss); err != nil {
+			if ctx.Err() == nil {
+				s.m.retries.WithLabelValues("wal").Inc()
+				s.log.Warn("influx wal replay failed, will retry", zap.Duration("retry", s.retry), zap.Error(err))
+				s.sleep(ctx, s.retry)
+			}
+			continue
+		}
+		s.wal.Commit()
+	}
+}
+
+// sleep : d만큼 대기 (ctx가 끝나면 바로 반환)
+func (s *spoolStore) sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,370 @@
+//go:build !edge
+
+/*
+ * SQLiteRepo : 임베디드 SQLite 저장소 (APP_STORE_BACKEND=sqlite, 기본 빌드 전용)
+ *  - 외부 DB 없이 바이너리 하나로 동작하는 단일 노드 배포용이며, 같은 저장소 인터페이스(InfluxStore)를 구현합니다.
+ *    드라이버는 cgo가 필요 없는 modernc.org/sqlite (바이너리를 크게 늘리므로 edge 빌드에는 포함하지 않음)
+ *  - 테이블 하나 : samples (time 유닉스 나노초, device, field, value), 기본 키 (device, field, time)
+ *    같은 포인트를 다시 기록하면 덮어씀 (버스/저널/WAL 재시도에 멱등)
+ *  - 크기 상한 : 사용 중인 페이지 크기가 APP_SQLITE_MAX_BYTES를 넘으면 가장 오래된 데이터부터 지워 최근 구간만 유지 (롤링 윈도)
+ *    지운 공간은 파일을 줄이지 않고 다음 기록에 다시 씀 (VACUUM 하지 않음)
+ *  - 조회 집계는 2.x/3과 같이 원시 구간을 읽어 앱에서 계산 (windowQuery)
+ *  - 태그(고정 태그, 태그로 올린 값)는 저장하지 않으며, 라우팅 규칙과 관계없이 모든 장치를 같은 테이블에 기록합니다.
+ *    필드 타입 선언은 APP_INFLUX_MEASUREMENT 측정값 기준 (bool은 1/0, string 필드는 드롭 기록)
+ */
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute" // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"     // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"     // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                     // Fx 프레임워크
+	"go.uber.org/zap"                    // 로깅 도구
+	_ "modernc.org/sqlite"               // SQLite 드라이버 (순수 Go, database/sql 이름 "sqlite")
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+// 한 번 정리할 때 지우는 비율 (상한을 넘을 때마다 조금씩 지우지 않도록 여유를 둠)
+const sqlitePruneFraction = 10
+
+// sqliteSchema : 시작 시 실행하는 스키마 (이미 있으면 그대로)
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS samples (
+  time   INTEGER NOT NULL,
+  device TEXT    NOT NULL,
+  field  TEXT    NOT NULL,
+  value  REAL    NOT NULL,
+  PRIMARY KEY (device, field, time)
+) WITHOUT ROWID`,
+	`CREATE INDEX IF NOT EXISTS samples_time_idx ON samples (time)`,
+}
+
+var _ InfluxStore = (*SQLiteRepo)(nil)
+
+// SQLiteRepo : 로컬 SQLite 파일에 데이터를 쓰고 읽는 저장소
+type SQLiteRepo struct {
+	log      *zap.Logger
+	db       *sql.DB
+	path     string
+	schema   influxSchema
+	drops    *drops.Recorder
+	tracer   trace.Tracer
+	maxBytes int64
+}
+
+// newSQLiteStore : newStoreBackend가 APP_STORE_BACKEND=sqlite일 때 호출 (edge 빌드는 sqlite_edge.go)
+func newSQLiteStore(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	return NewSQLiteRepo(lc, log, dr, tp)
+}
+
+/*
+ * NewSQLiteRepo : SQLiteRepo 생성자 (NewInfluxStore가 APP_STORE_BACKEND=sqlite일 때 호출)
+ *  - APP_SQLITE_PATH        : DB 파일 경로 (기본 data/telemetry.db, 디렉터리가 없으면 만듦)
+ *  - APP_SQLITE_MAX_BYTES   : 데이터 크기 상한 (기본 256MB, 0이면 제한 없음)
+ *  - APP_SQLITE_PRUNE_EVERY : 크기 상한 검사 간격 (기본 1m)
+ *  - WAL 저널 모드로 열어 쓰는 동안에도 조회가 막히지 않음
+ *  - OnStart 시 크기 정리 고루틴 시작 / OnStop 시 고루틴을 멈추고 DB 닫기
+ */
+func NewSQLiteRepo(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) *SQLiteRepo {
+	path := config.String("APP_SQLITE_PATH", "data/telemetry.db")
+	maxBytes := config.Bytes(log, "APP_SQLITE_MAX_BYTES", 256<<20)
+	every := config.Duration(log, "APP_SQLITE_PRUNE_EVERY", time.Minute)
+	if maxBytes < 0 || every <= 0 {
+		log.Fatal("APP_SQLITE_MAX_BYTES must not be negative and APP_SQLITE_PRUNE_EVERY must be positive",
+			zap.Int64("max_bytes", maxBytes), zap.Duration("prune_every", every))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Fatal("failed to create sqlite directory", zap.String("path", path), zap.Error(err))
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		log.Fatal("failed to open sqlite", zap.String("path", path), zap.Error(err))
+	}
+	for _, stmt := range sqliteSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			log.Fatal("failed to prepare sqlite schema", zap.String("path", path), zap.Error(err))
+		}
+	}
+	repo := &SQLiteRepo{
+		log:      log,
+		db:       db,
+		path:     path,
+		schema:   loadInfluxSchema(log),
+		drops:    dr,
+		tracer:   tp.Tracer(tracerName),
+		maxBytes: maxBytes,
+	}
+	if len(repo.schema.routes) > 0 {
+		log.Warn("APP_INFLUX_ROUTES is ignored by the sqlite store, all devices are written to one table")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				repo.pruneLoop(ctx, every)
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return repo.Close()
+		},
+	})
+	log.Info("sqlite store", zap.String("path", path), zap.Int64("max_bytes", maxBytes))
+	return repo
+}
+
+// pruneLoop : every마다 크기 상한 검사 (ctx가 끝나면 반환)
+func (r *SQLiteRepo) pruneLoop(ctx context.Context, every time.Duration) {
+	if r.maxBytes == 0 {
+		<-ctx.Done()
+		return
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := r.prune(ctx); err != nil && ctx.Err() == nil {
+				r.log.Warn("sqlite prune failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+/*
+ * prune : 사용 중인 크기가 상한을 넘는 동안 가장 오래된 행을 1/sqlitePruneFraction씩 지움
+ */
+func (r *SQLiteRepo) prune(ctx context.Context) error {
+	for {
+		used, err := r.usedBytes(ctx)
+		if err != nil || used <= r.maxBytes {
+			return err
+		}
+		var cutoff sql.NullInt64 // 지울 행 중 가장 새로운 시각
+		if err := r.db.QueryRowContext(ctx, `SELECT time FROM samples ORDER BY time
+LIMIT 1 OFFSET (SELECT count(*) / ? FROM samples)`, sqlitePruneFraction).Scan(&cutoff); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if !cutoff.Valid {
+			return nil // 지울 데이터가 없음 (스키마·인덱스만으로 상한을 넘음)
+		}
+		res, err := r.db.ExecContext(ctx, `DELETE FROM samples WHERE time <= ?`, cutoff.Int64)
+		if err != nil {
+			return err
+		}
+		n, _ := res.RowsAffected()
+		r.log.Info("sqlite size cap reached, oldest data removed",
+			zap.Int64("used_bytes", used), zap.Int64("max_bytes", r.maxBytes),
+			zap.Int64("rows", n), zap.Time("until", time.Unix(0, cutoff.Int64)))
+	}
+}
+
+// usedBytes : 데이터가 차지한 크기 (빈 페이지 제외)
+func (r *SQLiteRepo) usedBytes(ctx context.Context) (int64, error) {
+	var pages, free, size int64
+	if err := r.db.QueryRowContext(ctx, `SELECT page_count, freelist_count, page_size FROM pragma_page_count(), pragma_freelist_count(), pragma_page_size()`).
+		Scan(&pages, &free, &size); err != nil {
+		return 0, err
+	}
+	return (pages - free) * size, nil
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *SQLiteRepo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 트랜잭션 한 번으로 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 필드가 없는 샘플, 숫자로 저장할 수 없는 필드는 드롭 기록 후 건너뜀
+ *  - 쓰기 실패는 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도)
+ */
+func (r *SQLiteRepo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := r.tracer.Start(ctx, "sqlite write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.Int("sqlite.samples", len(ss)),
+		))
+	defer span.End()
+
+	points, err := r.insert(ctx, ss)
+	if err != nil {
+		r.log.Error("sqlite write failed", zap.Error(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("sqlite write: %w", err)
+	}
+	if points > 0 {
+		r.log.Debug("sqlite write success", zap.Int("points", points))
+	}
+	return nil
+}
+
+// insert : 묶음의 필드마다 한 행씩 upsert (기록한 행 수 반환)
+func (r *SQLiteRepo) insert(ctx context.Context, ss []telemetry.Sample) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() // Commit 뒤에는 아무것도 하지 않음
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO samples (time, device, field, value) VALUES (?, ?, ?, ?)
+ON CONFLICT (device, field, time) DO UPDATE SET value = excluded.value`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	points := 0
+	for _, s := range ss {
+		_, fields := r.schema.point(s)
+		if len(fields) == 0 {
+			r.drops.Record("sqlite", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+			continue
+		}
+		fields, ok := r.schema.checkFields(r.drops, r.schema.measurement, s.DeviceID, fields)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		for k, v := range fields {
+			f, ok := toFloat(v)
+			if !ok {
+				r.drops.Record("sqlite", drops.ReasonValidation, s.DeviceID, k+": non-numeric field cannot be stored")
+				continue
+			}
+			if _, err := stmt.ExecContext(ctx, at.UnixNano(), s.DeviceID, k, f); err != nil {
+				return 0, err
+			}
+			points++
+		}
+	}
+	return points, tx.Commit()
+}
+
+/*
+ * Ping : DB 파일을 쓸 수 있는지 확인 (readiness 검사)
+ */
+func (r *SQLiteRepo) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+/*
+ * Close : DB 닫기
+ */
+func (r *SQLiteRepo) Close() error {
+	return r.db.Close()
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회 (sim.Source)
+ */
+func (r *SQLiteRepo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	var out []telemetry.Sample
+	err := r.Stream(ctx, deviceID, from, to, func(s telemetry.Sample) error {
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 읽으며 샘플마다 fn 호출 (Exporter)
+ *  - 필드별 행을 시각마다 샘플 하나로 모음
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 조회를 중단하고 그 에러를 반환
+ */
+func (r *SQLiteRepo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	rows, err := r.db.QueryContext(ctx, `SELECT time, field, value FROM samples
+WHERE device = ? AND time >= ? AND time < ? ORDER BY time`, deviceID, from.UnixNano(), to.UnixNano())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var cur *telemetry.Sample
+	for rows.Next() {
+		var (
+			ns    int64
+			field string
+			v     float64
+		)
+		if err := rows.Scan(&ns, &field, &v); err != nil {
+			return err
+		}
+		at := time.Unix(0, ns)
+		if cur != nil && !cur.Time.Equal(at) {
+			if err := fn(*cur); err != nil {
+				return err
+			}
+			cur = nil
+		}
+		if cur == nil {
+			cur = &telemetry.Sample{Time: at, DeviceID: deviceID, Values: make(map[string]float64)}
+		}
+		cur.Values[field] = v
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if cur != nil {
+		return fn(*cur)
+	}
+	return nil
+}
+
+/*
+ * FieldKeys : 저장된 필드 이름 목록 (정렬, 최근 30일 기준 - 2.x와 같음)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *SQLiteRepo) FieldKeys(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT field FROM samples WHERE time > ? ORDER BY field`,
+		time.Now().Add(-30*24*time.Hour).UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (TimeSeriesStore)
+ *  - 장치마다 원시 구간을 읽어 앱에서 집계 (query.go, 2.x/3과 같은 규칙)
+ */
+func (r *SQLiteRepo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	return windowQuery(ctx, spec, r.Window)
+}
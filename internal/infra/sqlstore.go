@@ -0,0 +1,134 @@
+//go:build !edge
+
+/*
+ * SQL 저장소 공통 : TimescaleDB(timescale.go)와 PostgreSQL(postgres.go) 저장소가 함께 쓰는 읽기 도우미 (기본 빌드 전용)
+ *  - 두 저장소 모두 (시각, 장치, 필드, 값) 한 행에 값 하나를 저장하는 좁은 테이블을 쓰며,
+ *    장치 열 이름과 집계 구간·집계 함수 표현만 다릅니다. (sqlDialect)
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool" // 연결 풀
+
+	"generic-api-scaffold/internal/telemetry" // 조회 단위 샘플/시계열
+)
+
+// sqlDialect : 저장소마다 다른 SQL 표현
+type sqlDialect struct {
+	device     string                           // 장치 ID 열 이름
+	aggregates map[telemetry.Aggregate]string   // 집계 함수 (모두 double precision으로 맞춤)
+	bucket     func(every time.Duration) string // 구간 시작 시각 식 ($2 = From)
+}
+
+/*
+ * selectSeries : 조회 조건 → SQL과 인자 (결과 열 : device, field, time, value)
+ *  - 집계는 Every 폭의 구간(From 기준)마다, Every가 0이면 전체 구간을 From 시각 하나로
+ */
+func (d sqlDialect) selectSeries(table string, devices []string, spec telemetry.QuerySpec) (string, []any) {
+	args := []any{devices, spec.From, spec.To}
+	where := d.device + " = ANY($1) AND time >= $2 AND time < $3"
+	if len(spec.Fields) > 0 {
+		args = append(args, spec.Fields)
+		where += " AND field = ANY($4)"
+	}
+
+	if spec.Aggregate == telemetry.AggNone {
+		return fmt.Sprintf("SELECT %s, field, time, value FROM %s WHERE %s ORDER BY %[1]s, field, time", d.device, table, where), args
+	}
+	bucket := "$2::timestamptz"
+	if spec.Every > 0 {
+		bucket = d.bucket(spec.Every)
+	}
+	return fmt.Sprintf("SELECT %s, field, %s AS bucket, %s FROM %s WHERE %s GROUP BY %[1]s, field, bucket ORDER BY %[1]s, field, bucket",
+		d.device, bucket, d.aggregates[spec.Aggregate], table, where), args
+}
+
+// selectWindow : 장치 하나의 [from, to) 구간 행 (결과 열 : time, field, value, 시각순)
+func (d sqlDialect) selectWindow(table string) string {
+	return fmt.Sprintf("SELECT time, field, value FROM %s WHERE %s = $1 AND time >= $2 AND time < $3 ORDER BY time", table, d.device)
+}
+
+/*
+ * streamSamples : (time, field, value) 행을 시각마다 샘플 하나로 모아 fn 호출
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 조회를 중단하고 그 에러를 반환
+ */
+func streamSamples(ctx context.Context, pool *pgxpool.Pool, q string, args []any, deviceID string, fn func(telemetry.Sample) error) error {
+	rows, err := pool.Query(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var cur *telemetry.Sample
+	for rows.Next() {
+		var (
+			at    time.Time
+			field string
+			v     float64
+		)
+		if err := rows.Scan(&at, &field, &v); err != nil {
+			return err
+		}
+		if cur != nil && !cur.Time.Equal(at) {
+			if err := fn(*cur); err != nil {
+				return err
+			}
+			cur = nil
+		}
+		if cur == nil {
+			cur = &telemetry.Sample{Time: at, DeviceID: deviceID, Values: make(map[string]float64)}
+		}
+		cur.Values[field] = v
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if cur != nil {
+		return fn(*cur)
+	}
+	return nil
+}
+
+// collectSeries : (device, field, time, value) 행을 읽어 시계열에 추가
+func collectSeries(ctx context.Context, pool *pgxpool.Pool, acc seriesSet, q string, args []any) error {
+	rows, err := pool.Query(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			device, field string
+			at            time.Time
+			v             float64
+		)
+		if err := rows.Scan(&device, &field, &at, &v); err != nil {
+			return err
+		}
+		acc.add(device, field, at, v)
+	}
+	return rows.Err()
+}
+
+// fieldKeys : 테이블의 최근 30일 필드 이름 목록 (정렬, 2.x와 같은 기준)
+func fieldKeys(ctx context.Context, pool *pgxpool.Pool, table string) ([]string, error) {
+	rows, err := pool.Query(ctx, fmt.Sprintf(`SELECT DISTINCT field FROM %s WHERE time > now() - interval '30 days' ORDER BY field`, table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,203 @@
+/*
+ * TimeSeriesStore : 시계열 저장소 추상화
+ *  - 다른 모듈은 구체 타입(*InfluxRepo) 대신 이 인터페이스에 의존하여, 다른 백엔드나 테스트용 가짜 저장소로 바꿔 끼울 수 있습니다.
+ *  - fx로 제공되며, 구현은 InfluxDB 버전에 따라 InfluxRepo(1.x), Influx2Repo(2.x), Influx3Repo(3, FlightSQL, edge 빌드 제외) 중 하나입니다. (NewInfluxStore)
+ *    APP_STORE_BACKEND=timescale이면 TimescaleRepo(TimescaleDB, timescale.go), postgres면 PostgresRepo(일반 PostgreSQL, postgres.go),
+ *    sqlite면 SQLiteRepo(임베디드, edge 빌드 제외, sqlite.go), kafka면 KafkaStore(쓰기 전용, kafka_store.go),
+ *    clickhouse면 ClickHouseRepo(대량 분석용, clickhouse.go), victoriametrics면 VictoriaMetricsRepo(HTTP API, victoriametrics.go)를 씁니다.
+ */
+package infra
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 서킷 브레이커 메트릭
+	"go.opentelemetry.io/otel/trace"                 // 쓰기 스팬
+	"go.uber.org/fx"                                 // Fx 프레임워크
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"       // 수집 이벤트 구독
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/journal"   // 영속 저널 소비 (at-least-once)
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+/*
+ * TimeSeriesStore 인터페이스
+ *  - WritePoint : 샘플 하나 기록 (Time이 비어 있으면 기록 시각)
+ *  - WriteBatch : 샘플 묶음을 한 번에 기록 (다시 시도해도 소용없는 샘플은 구현이 드롭 기록 후 건너뜀)
+ *  - Query      : 조건(장치, 필드, 구간, 집계)에 맞는 장치 × 필드별 시계열 조회 (HTTP 데이터 API, 알림 평가, query.go)
+ *  - Ping       : 저장소 도달 가능 여부 (readiness 검사)
+ *  - Close      : 연결 정리 (OnStop)
+ */
+type TimeSeriesStore interface {
+	WritePoint(ctx context.Context, s telemetry.Sample) error
+	WriteBatch(ctx context.Context, ss []telemetry.Sample) error
+	Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+/*
+ * InfluxStore : 저장소 구현(InfluxDB 1.x, 2.x, 3, TimescaleDB, PostgreSQL, SQLite, ClickHouse, VictoriaMetrics)이 공통으로 제공하는 기능
+ *  - 저장/조회(TimeSeriesStore), 내보내기(Exporter), 시뮬레이터의 과거 데이터 조회(Window, sim.Source)
+ */
+type InfluxStore interface {
+	TimeSeriesStore
+	Exporter
+	Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error)
+}
+
+// 각 구현이 InfluxStore를 구현하는지 컴파일 시 확인
+var (
+	_ InfluxStore = (*InfluxRepo)(nil)
+	_ InfluxStore = (*Influx2Repo)(nil)
+	_ InfluxStore = (*VictoriaMetricsRepo)(nil)
+	_ InfluxStore = (*DryRunStore)(nil)
+)
+
+/*
+ * NewInfluxStore : fx가 호출하는 시계열 저장소 생성자
+ *  - APP_STORE_BACKEND  : influx (기본) | timescale (APP_TIMESCALE_DSN) | postgres (APP_POSTGRES_DSN) | sqlite (APP_SQLITE_PATH, edge 빌드 제외)
+ *                         | kafka (APP_KAFKA_STORE_BROKERS, 쓰기 전용, edge 빌드 제외) | clickhouse (APP_CLICKHOUSE_ADDR, edge 빌드 제외)
+ *                         | victoriametrics (APP_VM_URL)
+ *  - APP_STORE_DRY_RUN  : true면 위 저장소 대신 DryRunStore (라인 프로토콜을 로그로만 남김, 기본 false, dryrun.go)
+ *                         influx 외에는 아래 APP_INFLUX_VERSION을 쓰지 않음
+ *  - APP_INFLUX_VERSION : 1 (기본, 사용자 이름/비밀번호 + 데이터베이스) | 2 (토큰 + org + bucket) | 3 (토큰 + 데이터베이스, FlightSQL 조회, edge 빌드 제외)
+ *  - 만든 저장소에 수집 이벤트 기록을 연결 (attachStore)
+ *    쓰기는 크기 제한 쓰기 큐(withQueue, APP_INFLUX_QUEUE_MAX_MB), 디스크 스풀(withSpool, APP_INFLUX_WAL_DIR)과 서킷 브레이커(withBreaker)를 차례로 거침
+ *    실제 저장소 쓰기마다 처리량·지연 메트릭을 남김 (withMetrics, store_metrics.go)
+ *  - APP_MIRROR_STORE_BACKEND를 지정하면 보조 저장소에도 같은 데이터를 따로 기록 (attachMirror, mirror.go)
+ *  - 종료 시 버스 비우기가 저장소·큐 훅보다 먼저 끝나도록 훅 등록 (bus.DrainBefore)
+ */
+func NewInfluxStore(lc fx.Lifecycle, log *zap.Logger, eb *bus.EventBus, j *journal.Journal, dr *drops.Recorder, tp trace.TracerProvider,
+	reg *prometheus.Registry, en *TagEnrichment) InfluxStore {
+	s := newStoreBackend(lc, log, dr, tp)
+	m := newStoreMetrics(reg, "influx")
+	if j.Enabled() {
+		m.watchJournal(reg, j, "influx")
+	}
+	attachStore(log, eb, j, m, en, "influx", withQueue(lc, log, reg, dr, m, j, withSpool(lc, log, reg, dr, m, withBreaker(log, reg, withMetrics(m, s)))))
+	attachMirror(lc, log, eb, j, dr, tp, reg, en)
+	eb.DrainBefore(lc) // 위에서 등록한 큐·스풀·저장소 훅이 닫기 전에 버스에 남은 이벤트를 기록
+	return s
+}
+
+// newStoreBackend : APP_STORE_BACKEND에 맞는 저장소 (보조 저장소도 같은 함수로 만듦, mirror.go)
+// 시작 시 Ping으로 연결을 확인하고 APP_INFLUX_STARTUP에 따라 시작 실패 또는 성능 저하 상태로 시작 (store_startup.go)
+func newStoreBackend(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	if config.Bool(log, "APP_STORE_DRY_RUN", false) {
+		return NewDryRunStore(log, dr)
+	}
+	var s InfluxStore
+	switch b := config.String("APP_STORE_BACKEND", "influx"); b {
+	case "influx":
+		s = newInfluxBackend(lc, log, dr, tp)
+	case "timescale":
+		s = newTimescaleStore(lc, log, dr, tp)
+	case "postgres":
+		s = newPostgresStore(lc, log, dr, tp)
+	case "sqlite":
+		s = newSQLiteStore(lc, log, dr, tp)
+	case "kafka":
+		s = newKafkaStore(lc, log, dr, tp)
+	case "clickhouse":
+		s = newClickHouseStore(lc, log, dr, tp)
+	case "victoriametrics":
+		s = NewVictoriaMetricsRepo(lc, log, dr, tp)
+	default:
+		log.Fatal("invalid APP_STORE_BACKEND, expected influx|timescale|postgres|sqlite|kafka|clickhouse|victoriametrics", zap.String("value", b))
+	}
+	if b := config.String("APP_STORE_BACKEND", "influx"); b != "influx" &&
+		(len(config.List("APP_INFLUX_RETENTION_POLICIES", nil)) > 0 || len(config.List("APP_INFLUX_DOWNSAMPLE", nil)) > 0) {
+		log.Warn("APP_INFLUX_RETENTION_POLICIES and APP_INFLUX_DOWNSAMPLE apply to InfluxDB only, ignored", zap.String("backend", b))
+	}
+	checkStartup(lc, log, s)
+	return s
+}
+
+// newInfluxBackend : APP_INFLUX_VERSION에 맞는 InfluxDB 저장소
+func newInfluxBackend(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	v := config.String("APP_INFLUX_VERSION", "1")
+	if v != "1" && len(config.List("APP_INFLUX_RETENTION_POLICIES", nil)) > 0 {
+		log.Warn("APP_INFLUX_RETENTION_POLICIES applies to InfluxDB 1.x only, ignored", zap.String("version", v))
+	}
+	if v == "3" && len(config.List("APP_INFLUX_DOWNSAMPLE", nil)) > 0 {
+		log.Warn("APP_INFLUX_DOWNSAMPLE is not supported on InfluxDB 3, ignored")
+	}
+	switch v {
+	case "1":
+		return NewInfluxRepo(lc, log, dr, tp)
+	case "2":
+		return NewInflux2Repo(lc, log, dr, tp)
+	case "3":
+		return newInflux3Store(lc, log, dr, tp)
+	}
+	log.Fatal("invalid APP_INFLUX_VERSION, expected 1|2|3", zap.String("value", v))
+	return nil
+}
+
+/*
+ * attachStore : 수집 이벤트를 저장소에 묶음 단위로 기록하도록 연결
+ *  - name은 저널 소비자·버스 구독자 이름 (기본 저장소 influx, 보조 저장소 mirror)
+ *  - 기록 전에 장치 메타데이터 태그를 붙임 (en, enrich.go)
+ *  - 이벤트마다가 아니라 APP_INFLUX_BATCH_SIZE개가 모이거나 APP_INFLUX_BATCH_LATENCY가 지나면 쓰기 한 번으로 기록
+ *  - 저널이 켜져 있으면 저널의 묶음 영속 소비자로 기록 (재시작해도 확인되지 않은 데이터를 이어서 기록, journal 패키지)
+ *    쓰기 실패는 에러로 반환 → 저널이 APP_JOURNAL_RETRY 간격으로 같은 묶음부터 다시 시도 (scaffold_influx_write_retries_total{path="journal"})
+ *  - 저널이 없으면 EventBus의 묶음 구독자로 기록
+ *    쓰기 실패는 에러로 반환 → 버스가 묶음을 지수 백오프 + 지터로 APP_INFLUX_RETRY_ATTEMPTS번까지 시도하고,
+ *    끝내 실패하면 데드레터 큐에 보관 (관리 서버 /deadletters에서 재전송)
+ *    쓰기가 밀려 버퍼(APP_INFLUX_BUFFER)가 가득 차면 APP_INFLUX_OVERFLOW 정책을 따름
+ *      block(발행자 대기) | drop_oldest | drop_newest, 비어 있으면 버스 기본값(APP_BUS_BACKPRESSURE)
+ *      넘침은 scaffold_bus_queue_overflows_total{subscriber="influx"}와 경고 로그, 버린 이벤트는 /drops(source="bus")
+ *    ctx는 발행자(수집 루프, POST /api/collect 요청)의 값을 유지하며, 앱 종료 시 취소됨
+ */
+func attachStore(log *zap.Logger, eb *bus.EventBus, j *journal.Journal, m *storeMetrics, en *TagEnrichment, name string, s TimeSeriesStore) {
+	size := config.Int(log, "APP_INFLUX_BATCH_SIZE", 500)                    // 묶음 하나의 최대 포인트 수
+	latency := config.Duration(log, "APP_INFLUX_BATCH_LATENCY", time.Second) // 묶음이 차지 않아도 이 시간이 지나면 기록
+	write := func(ctx context.Context, es []bus.DataCollectedEvent) error {
+		ss := make([]telemetry.Sample, len(es))
+		for i, e := range es {
+			ss[i] = sampleOf(e)
+		}
+		en.apply(ss)
+		return s.WriteBatch(ctx, ss)
+	}
+
+	if j.Enabled() {
+		failed := false // 저널은 실패한 묶음을 성공할 때까지 같은 위치부터 다시 전달 (소비자 고루틴 하나에서만 호출)
+		retried := func(ctx context.Context, es []bus.DataCollectedEvent) error {
+			if failed {
+				m.retries.WithLabelValues("journal").Inc()
+			}
+			err := write(ctx, es)
+			failed = err != nil
+			return err
+		}
+		if err := j.ConsumeBatch(name, size, latency, retried); err != nil {
+			log.Fatal("failed to register store journal consumer", zap.String("consumer", name), zap.Error(err))
+		}
+		return
+	}
+	opts := []bus.SubscribeOption{bus.WithBatch(size, latency),
+		bus.WithRetry(config.Int(log, "APP_INFLUX_RETRY_ATTEMPTS", 5), // 첫 쓰기 포함 최대 시도 횟수
+			config.Duration(log, "APP_INFLUX_RETRY_BACKOFF", 500*time.Millisecond)), // 첫 재시도 간격 (이후 두 배씩, APP_BUS_RETRY_MAX_BACKOFF까지)
+		bus.WithBufferSize(config.Int(log, "APP_INFLUX_BUFFER", 10000)), // 쓰기가 멈춘 동안 버퍼링 (다른 구독자에 영향 없음, 발행 순서대로 기록)
+		bus.WithName(name), bus.WithoutRetained()} // 이미 기록한 마지막 값을 다시 쓰지 않음
+	if policy := config.String("APP_INFLUX_OVERFLOW", ""); policy != "" { // 버퍼가 가득 찼을 때 (비어 있으면 버스 기본값)
+		switch p := bus.BackpressurePolicy(policy); p {
+		case bus.BackpressureBlock, bus.BackpressureDropOldest, bus.BackpressureDropNewest:
+			opts = append(opts, bus.WithBackpressure(p))
+		default:
+			log.Fatal("invalid APP_INFLUX_OVERFLOW, expected block|drop_oldest|drop_newest", zap.String("value", policy))
+		}
+	}
+	eb.SubscribeBatch(write, opts...)
+}
+
+// sampleOf : 수집 이벤트 → 저장 샘플 (시각은 측정 시각, 없으면 기록 시각)
+func sampleOf(e bus.DataCollectedEvent) telemetry.Sample {
+	return telemetry.Sample{Time: e.Time, DeviceID: e.DeviceID, Values: e.Values}
+}
@@ -0,0 +1,101 @@
+/*
+ * 시계열 저장소 쓰기 메트릭 : 쓰기 파이프라인의 처리량·지연·재시도·적체를 Prometheus로 노출합니다.
+ *  - scaffold_influx_points_written_total            : 저장소에 기록한 포인트 수 (성공한 묶음 기준)
+ *  - scaffold_influx_batches_total{result}           : 저장소로 보낸 묶음 수 (success | error)
+ *  - scaffold_influx_write_duration_seconds          : 묶음 하나의 저장소 쓰기 지연
+ *  - scaffold_influx_write_retries_total{path}       : 실패한 묶음을 다시 쓴 횟수 (journal | wal | queue)
+ *                                                      버스 경로의 재시도는 scaffold_bus_retries_total{subscriber="influx"}
+ *  - scaffold_influx_journal_backlog                 : 저널에서 Influx 소비자가 아직 확인하지 않은 항목 수
+ *                                                      버스 경로의 적체는 scaffold_bus_queue_depth{subscriber="influx"}
+ *  - WAL에 쌓인 크기는 scaffold_influx_wal_bytes (spool.go), 쓰기 큐 적체는 scaffold_influx_queue_bytes / _queue_batches (queue.go)
+ *  - 보조 저장소(mirror.go)는 같은 메트릭을 scaffold_mirror_* 이름으로 따로 남김
+ */
+package infra
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 메트릭
+
+	"generic-api-scaffold/internal/journal"   // 저널 적체
+	"generic-api-scaffold/internal/telemetry" // 기록 단위 샘플
+)
+
+// storeMetrics : 쓰기 파이프라인 메트릭 묶음
+type storeMetrics struct {
+	points  prometheus.Counter
+	batches *prometheus.CounterVec
+	latency prometheus.Histogram
+	retries *prometheus.CounterVec
+	name    string // 메트릭 이름 앞부분 (influx | mirror)
+}
+
+// newStoreMetrics : 메트릭 생성 및 등록 (이름은 scaffold_<name>_...)
+func newStoreMetrics(reg *prometheus.Registry, name string) *storeMetrics {
+	m := &storeMetrics{
+		name: name,
+		points: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      name + "_points_written_total",
+			Help:      "Points written to the time-series store in successful batches.",
+		}),
+		batches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      name + "_batches_total",
+			Help:      "Batches sent to the time-series store, by result.",
+		}, []string{"result"}),
+		latency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      name + "_write_duration_seconds",
+			Help:      "Time-series store batch write latency.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12), // 5ms ~ 10s
+		}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      name + "_write_retries_total",
+			Help:      "Retried time-series store batch writes, by buffering path.",
+		}, []string{"path"}),
+	}
+	reg.MustRegister(m.points, m.batches, m.latency, m.retries)
+	return m
+}
+
+// watchJournal : 저널 소비자 name의 적체를 게이지로 노출
+func (m *storeMetrics) watchJournal(reg *prometheus.Registry, j *journal.Journal, name string) {
+	reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      m.name + "_journal_backlog",
+		Help:      "Journal entries not yet acknowledged by the time-series store consumer.",
+	}, func() float64 { return float64(j.Backlog(name)) }))
+}
+
+// metricsStore : 쓰기마다 메트릭을 남기는 TimeSeriesStore (실제 저장소 바로 바깥)
+type metricsStore struct {
+	TimeSeriesStore
+	m *storeMetrics
+}
+
+// withMetrics : 저장소 쓰기에 메트릭 적용
+func withMetrics(m *storeMetrics, s TimeSeriesStore) TimeSeriesStore {
+	return &metricsStore{TimeSeriesStore: s, m: m}
+}
+
+// WritePoint : 메트릭을 남기며 샘플 하나 기록
+func (s *metricsStore) WritePoint(ctx context.Context, p telemetry.Sample) error {
+	return s.WriteBatch(ctx, []telemetry.Sample{p})
+}
+
+// WriteBatch : 메트릭을 남기며 묶음 기록
+func (s *metricsStore) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	start := time.Now()
+	err := s.TimeSeriesStore.WriteBatch(ctx, ss)
+	s.m.latency.Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.m.batches.WithLabelValues("error").Inc()
+		return err
+	}
+	s.m.batches.WithLabelValues("success").Inc()
+	s.m.points.Add(float64(len(ss)))
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+ * 시작 시 저장소 연결 확인 : 클라이언트 생성은 서버에 접속하지 않으므로(예: InfluxDB 1.x NewHTTPClient), OnStart에서 Ping으로 확인합니다.
+ *  - APP_INFLUX_STARTUP=fail : Ping이 실패하면 시작 실패 (설정 오류나 접속할 수 없는 주소를 배포 즉시 드러냄)
+ *  - APP_INFLUX_STARTUP=lazy (기본) : 실패해도 성능 저하 상태로 시작하고, 도달할 때까지 APP_INFLUX_STARTUP_RETRY 간격으로 백그라운드에서 다시 Ping
+ *      그동안의 쓰기는 버스 재시도·저널·WAL·서킷 브레이커가 받고, /readyz는 저장소 검사 실패로 503
+ *  - Ping 하나의 제한 시간은 APP_INFLUX_STARTUP_TIMEOUT (기본 10s, fx 시작 제한 시간이 더 짧으면 그쪽)
+ *  - 잘못된 설정 값(형식 오류, 필수 값 누락)은 이와 상관없이 생성자에서 Fatal
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"  // 시작 확인 라이프사이클
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// checkStartup : 저장소 s의 시작 시 연결 확인 후크 등록 (newStoreBackend, 보조 저장소 포함)
+func checkStartup(lc fx.Lifecycle, log *zap.Logger, s TimeSeriesStore) {
+	policy := config.String("APP_INFLUX_STARTUP", "lazy")
+	if policy != "fail" && policy != "lazy" {
+		log.Fatal("invalid APP_INFLUX_STARTUP, expected fail|lazy", zap.String("value", policy))
+	}
+	timeout := config.Duration(log, "APP_INFLUX_STARTUP_TIMEOUT", 10*time.Second)
+	retry := config.Duration(log, "APP_INFLUX_STARTUP_RETRY", 5*time.Second)
+	if timeout <= 0 || retry <= 0 {
+		log.Fatal("APP_INFLUX_STARTUP_TIMEOUT and APP_INFLUX_STARTUP_RETRY must be positive",
+			zap.Duration("timeout", timeout), zap.Duration("retry", retry))
+	}
+
+	ping := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return s.Ping(ctx)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(start context.Context) error {
+			err := ping(start)
+			switch {
+			case err == nil:
+				close(done)
+				return nil
+			case policy == "fail":
+				close(done)
+				return fmt.Errorf("time-series store unreachable at startup (APP_INFLUX_STARTUP=fail): %w", err)
+			}
+			log.Warn("time-series store unreachable at startup, starting degraded", zap.Duration("retry", retry), zap.Error(err))
+			go func() {
+				defer close(done)
+				reconnect(ctx, log, ping, retry)
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
+
+// reconnect : 도달할 때까지 retry 간격으로 Ping (ctx가 끝나면 중단)
+func reconnect(ctx context.Context, log *zap.Logger, ping func(context.Context) error, retry time.Duration) {
+	t := time.NewTicker(retry)
+	defer t.Stop()
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+		err := ping(ctx)
+		if err == nil {
+			log.Info("time-series store reachable", zap.Int("attempts", attempt))
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("time-series store still unreachable", zap.Int("attempts", attempt), zap.Error(err))
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ * 라우트별 타임아웃 미들웨어
+ *  - 서버 전체의 Read/Write 타임아웃과 별개로, 라우트마다 요청 컨텍스트에 마감 시간(deadline)을 붙입니다.
+ *      예) ping=2s, simulations=30s
+ *  - 마감 시간까지 핸들러가 응답을 시작하지 못하면 504 Gateway Timeout과 표준 에러 봉투로 응답합니다.
+ *  - 이미 응답을 쓰기 시작한(스트리밍 중인) 핸들러는 중간에 끊지 않고, 컨텍스트 취소로 스스로 정리하도록 둡니다.
+ */
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux" // 현재 라우트 이름 조회
+	"go.uber.org/zap"        // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+/*
+ * routeTimeouts : 라우트별 타임아웃 설정
+ *  - def    : 기본 타임아웃
+ *  - routes : 라우트 이름(Route.Name) → 타임아웃
+ */
+type routeTimeouts struct {
+	def    time.Duration
+	routes map[string]time.Duration
+}
+
+/*
+ * newRouteTimeouts : 환경변수로부터 라우트별 타임아웃 설정 생성
+ *  - APP_HTTP_ROUTE_TIMEOUT  : 기본 타임아웃 (기본 8s, 서버 WriteTimeout보다 짧아야 504를 보낼 수 있음)
+ *  - APP_HTTP_ROUTE_TIMEOUTS : 라우트별 재정의 (예: "ping=2s,simulations=30s")
+ */
+func newRouteTimeouts(log *zap.Logger) routeTimeouts {
+	t := routeTimeouts{
+		def:    config.Duration(log, "APP_HTTP_ROUTE_TIMEOUT", 8*time.Second),
+		routes: map[string]time.Duration{"ping": 2 * time.Second, "simulations": 30 * time.Second, "export": 2 * time.Minute, "control.wait": 2 * time.Minute},
+	}
+	for _, item := range config.List("APP_HTTP_ROUTE_TIMEOUTS", nil) {
+		name, v, ok := strings.Cut(item, "=")
+		if !ok {
+			log.Fatal("invalid APP_HTTP_ROUTE_TIMEOUTS entry", zap.String("entry", item))
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			log.Fatal("invalid APP_HTTP_ROUTE_TIMEOUTS duration", zap.String("entry", item), zap.Error(err))
+		}
+		t.routes[strings.TrimSpace(name)] = d
+	}
+	return t
+}
+
+// timeoutFor : 요청이 매칭된 라우트의 타임아웃
+func (t routeTimeouts) timeoutFor(r *http.Request) time.Duration {
+	if route := mux.CurrentRoute(r); route != nil {
+		if d, ok := t.routes[route.GetName()]; ok {
+			return d
+		}
+	}
+	return t.def
+}
+
+// max : 설정된 타임아웃 중 가장 긴 값 (서버 WriteTimeout 계산용)
+func (t routeTimeouts) max() time.Duration {
+	m := t.def
+	for _, d := range t.routes {
+		if d > m {
+			m = d
+		}
+	}
+	return m
+}
+
+/*
+ * routeTimeout : 라우트별 타임아웃 미들웨어
+ *  - 내부 동작 :
+ *     ① 요청 컨텍스트에 라우트 타임아웃을 붙이고 핸들러를 별도 고루틴에서 실행
+ *     ② 핸들러가 먼저 끝나면 그대로 반환
+ *     ③ 마감 시간이 먼저 오고 아직 아무것도 쓰지 않았다면 504 응답 후 반환
+ *        (이후 핸들러의 쓰기는 버려짐), 이미 쓰기 시작했다면 핸들러가 끝날 때까지 대기
+ */
+func (s *Server) routeTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := s.timeouts.timeoutFor(r)
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+
+		tw := &timeoutWriter{w: w, h: w.Header().Clone()}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return
+		case p := <-panicked:
+			panic(p) // 원래 고루틴에서 다시 panic하여 net/http의 복구 처리에 맡김
+		case <-ctx.Done():
+			tw.mu.Lock()
+			if !tw.wroteHeader {
+				tw.timedOut = true
+				tw.mu.Unlock()
+				s.log.Warn("request timed out", zap.String("path", r.URL.Path), zap.Duration("timeout", d))
+				respondError(w, r, http.StatusGatewayTimeout, "timeout", fmt.Sprintf("request exceeded %s", d))
+				return
+			}
+			tw.mu.Unlock()
+
+			// 스트리밍 중인 응답은 핸들러가 컨텍스트 취소를 보고 끝낼 때까지 대기
+			select {
+			case <-done:
+			case p := <-panicked:
+				panic(p)
+			}
+		}
+	})
+}
+
+/*
+ * timeoutWriter : 타임아웃 이후의 쓰기를 막는 ResponseWriter 래퍼
+ *  - 타임아웃 응답(504)을 보낸 뒤 핸들러가 쓰려고 하면 http.ErrHandlerTimeout 반환
+ *  - 핸들러는 자기 헤더 맵(h)에 쓰고, 첫 WriteHeader/Write 때 mu 안에서 실제 헤더로 옮김
+ *    (타임아웃 뒤 버려진 핸들러 고루틴과 504 응답이 같은 맵을 동시에 쓰지 않도록, http.TimeoutHandler와 같은 방식)
+ *  - Flush를 통과시켜 스트리밍 응답도 지원
+ */
+type timeoutWriter struct {
+	w http.ResponseWriter
+	h http.Header // 핸들러용 헤더 (앞선 미들웨어가 붙인 헤더의 사본으로 시작)
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header { return tw.h }
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.commitHeader()
+	tw.w.WriteHeader(code)
+}
+
+// commitHeader : 핸들러 헤더를 실제 헤더로 옮김 (mu를 잡은 채로, 처음 한 번만)
+func (tw *timeoutWriter) commitHeader() {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	dst := tw.w.Header()
+	for k := range dst {
+		if _, ok := tw.h[k]; !ok {
+			delete(dst, k)
+		}
+	}
+	for k, vv := range tw.h {
+		dst[k] = vv
+	}
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.commitHeader()
+	return tw.w.Write(b)
+}
+
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.commitHeader() // Flush는 아직 쓰지 않은 헤더를 200으로 내보내므로 먼저 옮김
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,71 @@
+package infra
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func TestRouteTimeout(t *testing.T) {
+	// stuck : 컨텍스트를 보지 않고 테스트가 끝날 때까지 붙잡혀 있다가 쓰는 핸들러 (이 쓰기는 버려져야 함)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	stuck := func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("late"))
+	}
+	// streaming : 마감 전에 쓰기 시작하고 컨텍스트가 취소되면 끝내는 핸들러
+	streaming := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("partial"))
+		<-r.Context().Done()
+	}
+	fast := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) }
+
+	tests := []struct {
+		name     string
+		route    string
+		handler  http.HandlerFunc
+		status   int
+		code     string // 504일 때 에러 코드
+		maxDelay time.Duration
+	}{
+		{name: "route override times out", route: "slow", handler: stuck, status: http.StatusGatewayTimeout, code: "timeout", maxDelay: time.Second},
+		{name: "default timeout for other routes", route: "other", handler: stuck, status: http.StatusGatewayTimeout, code: "timeout", maxDelay: 2 * time.Second},
+		{name: "fast handler unaffected", route: "slow", handler: fast, status: http.StatusOK},
+		{name: "streaming response keeps its status", route: "slow", handler: streaming, status: http.StatusOK, maxDelay: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{log: zap.NewNop(), timeouts: routeTimeouts{def: 100 * time.Millisecond, routes: map[string]time.Duration{"slow": 20 * time.Millisecond}}}
+			r := mux.NewRouter()
+			r.Use(s.routeTimeout)
+			r.Handle("/x", tt.handler).Name(tt.route)
+
+			rec := httptest.NewRecorder()
+			start := time.Now()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/x", nil))
+			if tt.maxDelay > 0 && time.Since(start) > tt.maxDelay {
+				t.Fatalf("took %s, want under %s", time.Since(start), tt.maxDelay)
+			}
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d (body %q)", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.code == "" {
+				return
+			}
+			var body errorEnvelope
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if body.Error.Code != tt.code {
+				t.Fatalf("error code %q, want %q", body.Error.Code, tt.code)
+			}
+		})
+	}
+}
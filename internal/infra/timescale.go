@@ -0,0 +1,332 @@
+//go:build !edge
+
+/*
+ * TimescaleRepo : TimescaleDB(PostgreSQL) 저장소 (APP_STORE_BACKEND=timescale, 기본 빌드 전용)
+ *  - Influx 대신 Postgres를 표준으로 쓰는 사이트용이며, 같은 저장소 인터페이스(InfluxStore)를 구현하므로
+ *    수집 기록·조회·내보내기·시뮬레이터가 그대로 동작합니다.
+ *  - 측정값(APP_INFLUX_MEASUREMENT, 라우팅 규칙의 측정값)마다 하이퍼테이블 하나
+ *      time timestamptz, device text, field text, value double precision, tags jsonb (device 외 태그)
+ *      기본 키 (device, field, time) - 같은 포인트를 다시 기록하면 덮어씀 (버스/저널/WAL 재시도에 멱등)
+ *    테이블은 시작 시(기본 측정값)와 처음 기록할 때(그 밖의 측정값) 없으면 만듭니다.
+ *  - 묶음 쓰기는 임시 테이블에 COPY한 뒤 INSERT ... ON CONFLICT로 옮깁니다. (트랜잭션 하나)
+ *  - 필드 타입 선언(influx_fields.go)의 bool은 1/0으로 저장하고, string 필드는 숫자 열에 넣을 수 없어 드롭 기록합니다.
+ *  - 라우팅 규칙의 데이터베이스와 :udp, 보존 정책·다운샘플링·gzip 설정은 쓰지 않습니다.
+ *    (보존 기간은 add_retention_policy, 다운샘플링은 연속 집계로 DB에서 설정)
+ */
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"            // COPY
+	"github.com/jackc/pgx/v5/pgxpool"    // 연결 풀
+	"go.opentelemetry.io/otel/attribute" // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"     // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"     // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                     // Fx 프레임워크
+	"go.uber.org/zap"                    // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+var _ InfluxStore = (*TimescaleRepo)(nil)
+
+// COPY 대상 열 (하이퍼테이블과 같은 순서)
+var timescaleColumns = []string{"time", "device", "field", "value", "tags"}
+
+// TimescaleRepo : TimescaleDB에 데이터를 쓰고 SQL로 읽는 저장소
+type TimescaleRepo struct {
+	log    *zap.Logger
+	pool   *pgxpool.Pool
+	schema influxSchema
+	drops  *drops.Recorder
+	tracer trace.Tracer
+
+	mu     sync.Mutex
+	tables map[string]bool // 있는 것을 확인한 하이퍼테이블 (측정값 이름)
+}
+
+// newTimescaleStore : newStoreBackend가 APP_STORE_BACKEND=timescale일 때 호출 (edge 빌드는 timescale_edge.go)
+func newTimescaleStore(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) InfluxStore {
+	return NewTimescaleRepo(lc, log, dr, tp)
+}
+
+/*
+ * NewTimescaleRepo : TimescaleRepo 생성자 (NewInfluxStore가 APP_STORE_BACKEND=timescale일 때 호출)
+ *  - APP_TIMESCALE_DSN : 연결 문자열 (필수, 예: postgres://scaffold:secret@db:5432/telemetry?pool_max_conns=8)
+ *  - 측정값·고정 태그·라우팅·필드 타입 설정은 Influx와 같은 APP_INFLUX_* 값을 씀 (influx_schema.go)
+ *  - OnStart 시 기본 측정값의 하이퍼테이블을 만듦 (DB에 닿지 않아도 시작은 막지 않고 첫 쓰기에서 다시 시도)
+ *  - OnStop 시 연결 풀 종료
+ */
+func NewTimescaleRepo(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) *TimescaleRepo {
+	dsn := config.String("APP_TIMESCALE_DSN", "")
+	if dsn == "" {
+		log.Fatal("APP_TIMESCALE_DSN is required for APP_STORE_BACKEND=timescale")
+	}
+	pool, err := pgxpool.New(context.Background(), dsn) // 연결은 처음 쓸 때 맺음
+	if err != nil {
+		log.Fatal("invalid APP_TIMESCALE_DSN", zap.Error(err)) // 연결 문자열에 비밀번호가 있으므로 값은 남기지 않음
+	}
+	repo := &TimescaleRepo{
+		log:    log,
+		pool:   pool,
+		schema: loadInfluxSchema(log),
+		drops:  dr,
+		tracer: tp.Tracer(tracerName),
+		tables: make(map[string]bool),
+	}
+	for _, rt := range repo.schema.routes {
+		if rt.target.database != "" || rt.target.udp {
+			log.Warn("database and udp parts of APP_INFLUX_ROUTES are ignored by the timescale store",
+				zap.String("measurement", rt.target.measurement))
+		}
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := repo.ensureTable(ctx, repo.schema.measurement); err != nil {
+				log.Error("timescale hypertable not ready", zap.String("measurement", repo.schema.measurement), zap.Error(err))
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return repo.Close()
+		},
+	})
+	log.Info("timescaledb store", zap.String("measurement", repo.schema.measurement))
+	return repo
+}
+
+/*
+ * ensureTable : 측정값의 하이퍼테이블이 없으면 만듦 (한 번 확인하면 다시 묻지 않음)
+ */
+func (r *TimescaleRepo) ensureTable(ctx context.Context, measurement string) error {
+	r.mu.Lock()
+	ok := r.tables[measurement]
+	r.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	table := sqlIdent(measurement)
+	if _, err := r.pool.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  time   timestamptz      NOT NULL,
+  device text             NOT NULL,
+  field  text             NOT NULL,
+  value  double precision NOT NULL,
+  tags   jsonb            NOT NULL DEFAULT '{}',
+  PRIMARY KEY (device, field, time)
+)`, table)); err != nil {
+		return err
+	}
+	if _, err := r.pool.Exec(ctx, `SELECT create_hypertable($1::regclass, 'time', if_not_exists => TRUE)`, table); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.tables[measurement] = true
+	r.mu.Unlock()
+	r.log.Info("timescale hypertable ready", zap.String("measurement", measurement))
+	return nil
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *TimescaleRepo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 측정값마다 트랜잭션 한 번으로 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 필드가 없는 샘플, 숫자로 저장할 수 없는 필드는 드롭 기록 후 건너뜀
+ *  - 쓰기 실패는 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도, 이미 기록한 측정값은 덮어써짐)
+ */
+func (r *TimescaleRepo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := r.tracer.Start(ctx, "timescale write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.Int("timescale.samples", len(ss)),
+		))
+	defer span.End()
+
+	rows := make(map[string][][]any) // 측정값 → COPY 행
+	for _, s := range ss {
+		tags, fields := r.schema.point(s)
+		if len(fields) == 0 {
+			r.drops.Record("timescale", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+			continue
+		}
+		target := r.schema.target(tags)
+		fields, ok := r.schema.checkFields(r.drops, target.measurement, s.DeviceID, fields)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		delete(tags, deviceTag) // device는 열로 저장
+		tagJSON, err := json.Marshal(tags)
+		if err != nil {
+			r.drops.Record("timescale", drops.ReasonValidation, s.DeviceID, err.Error())
+			continue
+		}
+		for k, v := range fields {
+			f, ok := toFloat(v)
+			if !ok {
+				r.drops.Record("timescale", drops.ReasonValidation, s.DeviceID, k+": non-numeric field cannot be stored")
+				continue
+			}
+			rows[target.measurement] = append(rows[target.measurement], []any{at, s.DeviceID, k, f, string(tagJSON)})
+		}
+	}
+
+	points := 0
+	for measurement, rs := range rows {
+		if err := r.copyRows(ctx, measurement, rs); err != nil {
+			r.log.Error("timescale write failed", zap.String("measurement", measurement), zap.Error(err))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("timescale write: %w", err)
+		}
+		points += len(rs)
+	}
+	if points > 0 {
+		r.log.Debug("timescale write success", zap.Int("points", points))
+	}
+	return nil
+}
+
+/*
+ * copyRows : 임시 테이블에 COPY한 뒤 하이퍼테이블로 옮김 (같은 키는 덮어씀)
+ *  - COPY는 ON CONFLICT를 지원하지 않으므로 임시 테이블을 거침
+ *  - 같은 묶음 안에 같은 키가 여러 번 있으면 하나만 남김 (ON CONFLICT가 한 행을 두 번 바꿀 수 없음)
+ */
+func (r *TimescaleRepo) copyRows(ctx context.Context, measurement string, rows [][]any) error {
+	if err := r.ensureTable(ctx, measurement); err != nil {
+		return err
+	}
+	table := sqlIdent(measurement)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) // Commit 뒤에는 아무것도 하지 않음
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE TEMP TABLE timescale_stage (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP`, table)); err != nil {
+		return err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"timescale_stage"}, timescaleColumns, pgx.CopyFromRows(rows)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`INSERT INTO %s (time, device, field, value, tags)
+SELECT DISTINCT ON (device, field, time) time, device, field, value, tags FROM timescale_stage
+ON CONFLICT (device, field, time) DO UPDATE SET value = EXCLUDED.value, tags = EXCLUDED.tags`, table)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+/*
+ * Ping : DB 도달 가능 여부 확인 (readiness 검사)
+ */
+func (r *TimescaleRepo) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
+/*
+ * Close : 연결 풀 종료
+ */
+func (r *TimescaleRepo) Close() error {
+	r.pool.Close()
+	return nil
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회 (sim.Source)
+ */
+func (r *TimescaleRepo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	var out []telemetry.Sample
+	err := r.Stream(ctx, deviceID, from, to, func(s telemetry.Sample) error {
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 읽으며 샘플마다 fn 호출 (Exporter)
+ *  - 필드별 행을 시각마다 샘플 하나로 모음
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 조회를 중단하고 그 에러를 반환
+ */
+func (r *TimescaleRepo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	target := r.schema.deviceTarget(deviceID) // 라우팅 규칙에 따른 측정값
+	q := timescaleDialect.selectWindow(sqlIdent(target.measurement))
+	return streamSamples(ctx, r.pool, q, []any{deviceID, from, to}, deviceID, fn)
+}
+
+/*
+ * FieldKeys : 기본 측정값에 저장된 필드 이름 목록 (정렬, 최근 30일 기준 - 2.x와 같음)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *TimescaleRepo) FieldKeys(ctx context.Context) ([]string, error) {
+	return fieldKeys(ctx, r.pool, sqlIdent(r.schema.measurement))
+}
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (TimeSeriesStore, SQL)
+ *  - 집계는 time_bucket(Every, time, From)으로 구간을 나눔 (Every가 0이면 전체 구간을 From 시각 하나로)
+ *  - Limit은 시계열마다 앞에서부터 적용
+ *  - 장치들이 라우팅 규칙으로 서로 다른 측정값에 있으면 측정값마다 나누어 질의
+ */
+func (r *TimescaleRepo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]string)
+	var order []string
+	for _, d := range spec.Devices {
+		m := r.schema.deviceTarget(d).measurement
+		if _, ok := groups[m]; !ok {
+			order = append(order, m)
+		}
+		groups[m] = append(groups[m], d)
+	}
+
+	acc := newSeriesSet()
+	for _, m := range order {
+		q, args := timescaleDialect.selectSeries(sqlIdent(m), groups[m], spec)
+		if err := collectSeries(ctx, r.pool, acc, q, args); err != nil {
+			return nil, err
+		}
+	}
+	return limitSeries(acc.list(), spec.Limit), nil
+}
+
+// timescaleDialect : time_bucket과 first/last 집계 함수를 쓰는 TimescaleDB SQL
+var timescaleDialect = sqlDialect{
+	device: "device",
+	aggregates: map[telemetry.Aggregate]string{
+		telemetry.AggMean:  "avg(value)",
+		telemetry.AggMin:   "min(value)",
+		telemetry.AggMax:   "max(value)",
+		telemetry.AggSum:   "sum(value)",
+		telemetry.AggCount: "count(value)::double precision",
+		telemetry.AggFirst: "first(value, time)",
+		telemetry.AggLast:  "last(value, time)",
+	},
+	bucket: func(every time.Duration) string {
+		return fmt.Sprintf("time_bucket(INTERVAL '%d microseconds', time, $2::timestamptz)", every.Microseconds())
+	},
+}
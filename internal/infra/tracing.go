@@ -0,0 +1,48 @@
+package infra
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"             // 전역 전파기 (W3C traceparent)
+	"go.opentelemetry.io/otel/attribute"   // 스팬 속성
+	"go.opentelemetry.io/otel/codes"       // 스팬 상태
+	"go.opentelemetry.io/otel/propagation" // HTTP 헤더 캐리어
+	"go.opentelemetry.io/otel/trace"       // 스팬 생성
+)
+
+// tracerName : 이 패키지가 만드는 스팬의 계측 범위 이름
+const tracerName = "generic-api-scaffold/internal/infra"
+
+/*
+ * traceRequests : 요청마다 서버 스팬을 만드는 미들웨어
+ *  - 요청의 traceparent 헤더가 있으면 그 트레이스를 이어받음 (호출한 서비스의 트레이스에 합류)
+ *  - 스팬 이름은 "메서드 라우트" (라우트 이름이 있으면 이름, 예: "POST collect")
+ *  - 요청 ctx에 스팬을 넣으므로, 핸들러가 발행한 버스 이벤트와 그 구독자 처리(Influx 쓰기)가 같은 트레이스에 기록됨
+ *  - 5xx 응답은 스팬을 에러로 표시
+ */
+func (s *Server) traceRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		route := routeLabel(r)
+		ctx, span := s.tracer.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", r.URL.Path),
+			))
+		defer span.End()
+
+		sr := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(sr, r.WithContext(ctx))
+
+		status := sr.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
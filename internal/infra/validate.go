@@ -0,0 +1,135 @@
+/*
+ * 요청 DTO 검증 : 구조체 태그(validate:"...")로 요청 형식을 선언하고 공통 도우미로 검사합니다.
+ *  - go-playground/validator 사용
+ *      예) Action string `json:"action" validate:"required,oneof=charge discharge on off"`
+ *          KW10   int    `json:"kw10" validate:"gte=0,lte=1000"`
+ *  - 검증 실패 시 400과 함께 필드별 에러를 에러 봉투의 fields에 담아 응답합니다.
+ *      {"error": {"code": "validation_failed", "message": "...", "fields": [{"field": "kw10", "rule": "lte", "param": "1000", ...}]}}
+ *  - 필드 이름은 json 태그 이름을 사용합니다. (클라이언트가 보낸 이름 그대로)
+ */
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10" // 구조체 태그 기반 검증
+)
+
+// validate : 공유 검증기 (구조체 메타데이터를 캐시하므로 하나만 사용)
+var validate = newValidator()
+
+// newValidator : 에러의 필드 이름으로 json 태그 이름을 쓰는 검증기
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// fieldError : 필드 하나의 검증 실패 정보
+type fieldError struct {
+	Field   string `json:"field"`           // 필드 경로 (예: devices[0])
+	Rule    string `json:"rule"`            // 실패한 규칙 (예: oneof, lte)
+	Param   string `json:"param,omitempty"` // 규칙 인자 (예: 1000)
+	Message string `json:"message"`         // 사람이 읽을 수 있는 설명
+}
+
+/*
+ * validateStruct : 구조체 검증
+ *  - 반환 : 실패한 필드 목록 (통과하면 nil)
+ */
+func validateStruct(v interface{}) []fieldError {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+	var ves validator.ValidationErrors
+	if !errors.As(err, &ves) {
+		// 구조체가 아닌 값을 넘긴 프로그래밍 오류
+		return []fieldError{{Field: "", Rule: "invalid", Message: err.Error()}}
+	}
+	out := make([]fieldError, 0, len(ves))
+	for _, fe := range ves {
+		out = append(out, fieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		})
+	}
+	return out
+}
+
+// fieldPath : "Request.kw10" → "kw10", "batchControlReq.commands[0].action" → "commands[0].action" (최상위 구조체 이름 제거)
+func fieldPath(ns string) string {
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return ns
+}
+
+// fieldMessage : 자주 쓰는 규칙의 설명 문구
+func fieldMessage(fe validator.FieldError) string {
+	name := fieldPath(fe.Namespace())
+	switch fe.Tag() {
+	case "required":
+		return name + " is required"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", name, fe.Param())
+	case "gte", "min":
+		return fmt.Sprintf("%s must be at least %s", name, fe.Param())
+	case "lte", "max":
+		return fmt.Sprintf("%s must be at most %s", name, fe.Param())
+	case "gtfield":
+		return fmt.Sprintf("%s must be after %s", name, fe.Param())
+	default:
+		return fmt.Sprintf("%s failed %s validation", name, fe.Tag())
+	}
+}
+
+/*
+ * respondValidation : 필드별 에러를 담아 400 응답
+ */
+func respondValidation(w http.ResponseWriter, r *http.Request, fields []fieldError) {
+	msgs := make([]string, len(fields))
+	for i, f := range fields {
+		msgs[i] = f.Message
+	}
+	respond(w, r, http.StatusBadRequest, errorEnvelope{Error: errorDetail{
+		Code:    "validation_failed",
+		Message: strings.Join(msgs, "; "),
+		Fields:  fields,
+	}})
+}
+
+/*
+ * checkValid : 이미 채워진 요청 구조체를 검증 (쿼리 파라미터로 만든 요청 등)
+ *  - 실패 시 400을 이미 썼으므로 false 반환 → 핸들러는 그대로 return
+ */
+func checkValid(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if fields := validateStruct(v); len(fields) > 0 {
+		respondValidation(w, r, fields)
+		return false
+	}
+	return true
+}
+
+/*
+ * decodeValid : decodeJSON + 구조체 태그 검증
+ *  - 디코딩 실패는 decodeJSON과 동일(413/400), 검증 실패는 400 validation_failed
+ */
+func (s *Server) decodeValid(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	return s.decodeJSON(w, r, v) && checkValid(w, r, v)
+}
@@ -0,0 +1,552 @@
+/*
+ * VictoriaMetricsRepo : VictoriaMetrics 저장소 (APP_STORE_BACKEND=victoriametrics)
+ *  - InfluxDB 대신 VictoriaMetrics(단일 노드 또는 클러스터)를 쓰는 설치용이며, 같은 저장소 인터페이스(InfluxStore)를 구현합니다.
+ *    클라이언트 라이브러리 없이 HTTP API만 쓰므로 edge 빌드에도 포함됩니다.
+ *  - 쓰기 API (APP_VM_WRITE_API)
+ *      influx : Influx 호환 라인 프로토콜 (/write, 기존 Influx 1.x 기록과 같은 메트릭)
+ *      import : VictoriaMetrics JSON 가져오기 (/api/v1/import, 묶음 안의 시계열마다 한 줄)
+ *    두 API 모두 메트릭 이름은 <측정값>_<필드>, 라벨은 device + 고정 태그 + 태그로 올린 값 (VM의 Influx 변환 규칙과 같음)
+ *    예) 장치 A1의 temperature=21.5 → device_data_temperature{device="A1",site="resort-a"}
+ *  - VM 특성에 맞춘 쓰기
+ *      큰 요청을 선호하므로 묶음을 APP_VM_MAX_REQUEST_BYTES까지 한 요청에 담아 gzip으로 보내고, 넘으면 여러 요청으로 나눔
+ *      중복 제거가 기본으로 꺼져 있어 같은 요청을 다시 보내면 샘플이 두 번 저장되므로, 실패한 요청만 그 자리에서 다시 시도
+ *      (APP_VM_RETRY_ATTEMPTS, 503/429/연결 실패) - 끝내 실패하면 에러를 반환해 버스/저널/WAL이 묶음 전체를 다시 보냄
+ *      재시도 중복을 없애려면 VM에 -dedup.minScrapeInterval=1ms를 권장
+ *      400 응답(형식 거절)은 다시 보내도 소용없으므로 /drops(source="victoriametrics", reason=write_failed)에 남기고 버림
+ *  - 시각 정밀도는 밀리초 (VM 저장 단위), NaN/±Inf와 숫자가 아닌 필드는 드롭 기록
+ *  - 조회는 /api/v1/export로 원시 구간을 읽어 앱에서 집계 (windowQuery, 2.x/3과 같은 규칙)
+ *    VM은 받은 데이터를 약 1초 뒤에 조회에 반영하므로 기록 직후의 조회에는 마지막 값이 빠질 수 있음
+ *  - 라우팅 규칙은 쓰지 않으며, 모든 장치를 APP_INFLUX_MEASUREMENT 측정값으로 기록합니다.
+ */
+package infra
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute" // 쓰기 스팬 속성
+	"go.opentelemetry.io/otel/codes"     // 쓰기 스팬 상태
+	"go.opentelemetry.io/otel/trace"     // 쓰기 스팬 (발행 트레이스의 하위)
+	"go.uber.org/fx"                     // Fx 프레임워크
+	"go.uber.org/zap"                    // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 쓰기 거절 기록
+	"generic-api-scaffold/internal/telemetry" // 기록/조회 단위 샘플
+)
+
+// 다시 보내도 소용없는 응답 (형식 거절) → 재시도하지 않고 드롭 기록
+var errVMRejected = errors.New("victoriametrics rejected the request")
+
+// 라인 프로토콜 이스케이프 (측정값 / 태그 키·값, 필드 키)
+var (
+	lpMeasurementEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, ` `, `\ `)
+	lpTagEscaper         = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `=`, `\=`, ` `, `\ `)
+)
+
+// vmSeries : 가져오기 API 한 줄 (시계열 하나의 샘플들)
+type vmSeries struct {
+	Metric     map[string]string `json:"metric"`
+	Values     []float64         `json:"values"`
+	Timestamps []int64           `json:"timestamps"` // 밀리초
+}
+
+// VictoriaMetricsRepo : VictoriaMetrics HTTP API로 데이터를 쓰고 읽는 저장소
+type VictoriaMetricsRepo struct {
+	log      *zap.Logger
+	client   *http.Client
+	insert   string // 쓰기 API 기준 URL (단일 노드는 APP_VM_URL, 클러스터는 .../insert/<테넌트>/...)
+	sel      string // 조회 API 기준 URL
+	health   string // readiness 검사 URL
+	format   string // influx | import
+	headers  map[string]string
+	maxBytes int
+	attempts int
+	backoff  time.Duration
+	schema   influxSchema
+	drops    *drops.Recorder
+	tracer   trace.Tracer
+}
+
+/*
+ * NewVictoriaMetricsRepo : VictoriaMetricsRepo 생성자 (NewInfluxStore가 APP_STORE_BACKEND=victoriametrics일 때 호출)
+ *  - APP_VM_URL               : 단일 노드 주소 (필수, 예: http://victoriametrics:8428)
+ *  - APP_VM_INSERT_URL / APP_VM_SELECT_URL : 클러스터의 vminsert / vmselect 주소 (기본 APP_VM_URL)
+ *  - APP_VM_TENANT            : 클러스터 테넌트 (예: 0 또는 0:0, 비어 있으면 단일 노드 경로)
+ *  - APP_VM_WRITE_API         : influx | import (기본 influx)
+ *  - APP_VM_USERNAME / APP_VM_PASSWORD : 기본 인증 (vmauth 등, 기본 없음)
+ *  - APP_VM_BEARER_TOKEN      : Bearer 토큰 (기본 없음, 기본 인증보다 우선)
+ *  - APP_VM_TIMEOUT           : 요청 제한 시간 (기본 30s)
+ *  - APP_VM_MAX_REQUEST_BYTES : 요청 하나의 압축 전 최대 크기 (기본 8MB, VM -maxInsertRequestSize 기본값 32MB 아래)
+ *  - APP_VM_RETRY_ATTEMPTS    : 요청마다 첫 전송 포함 최대 시도 횟수 (기본 3)
+ *  - APP_VM_RETRY_BACKOFF     : 첫 재시도 간격 (기본 1s, 이후 두 배씩)
+ */
+func NewVictoriaMetricsRepo(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder, tp trace.TracerProvider) *VictoriaMetricsRepo {
+	base := strings.TrimSuffix(config.String("APP_VM_URL", ""), "/")
+	insert := strings.TrimSuffix(config.String("APP_VM_INSERT_URL", base), "/")
+	sel := strings.TrimSuffix(config.String("APP_VM_SELECT_URL", base), "/")
+	if insert == "" || sel == "" {
+		log.Fatal("APP_VM_URL (or APP_VM_INSERT_URL and APP_VM_SELECT_URL) is required for APP_STORE_BACKEND=victoriametrics")
+	}
+	format := config.String("APP_VM_WRITE_API", "influx")
+	if format != "influx" && format != "import" {
+		log.Fatal("invalid APP_VM_WRITE_API, expected influx|import", zap.String("value", format))
+	}
+	repo := &VictoriaMetricsRepo{
+		log:      log,
+		client:   &http.Client{Timeout: config.Duration(log, "APP_VM_TIMEOUT", 30*time.Second)},
+		health:   insert + "/health",
+		format:   format,
+		headers:  make(map[string]string),
+		maxBytes: int(config.Bytes(log, "APP_VM_MAX_REQUEST_BYTES", 8<<20)),
+		attempts: config.Int(log, "APP_VM_RETRY_ATTEMPTS", 3),
+		backoff:  config.Duration(log, "APP_VM_RETRY_BACKOFF", time.Second),
+		schema:   loadInfluxSchema(log),
+		drops:    dr,
+		tracer:   tp.Tracer(tracerName),
+	}
+	if repo.maxBytes < 1 || repo.attempts < 1 || repo.backoff < 0 {
+		log.Fatal("APP_VM_MAX_REQUEST_BYTES and APP_VM_RETRY_ATTEMPTS must be positive and APP_VM_RETRY_BACKOFF must not be negative",
+			zap.Int("max_request_bytes", repo.maxBytes), zap.Int("retry_attempts", repo.attempts), zap.Duration("retry_backoff", repo.backoff))
+	}
+	if tenant := config.String("APP_VM_TENANT", ""); tenant != "" {
+		insert += "/insert/" + tenant
+		sel += "/select/" + tenant + "/prometheus"
+		if format == "influx" {
+			insert += "/influx"
+		} else {
+			insert += "/prometheus"
+		}
+	}
+	repo.insert, repo.sel = insert, sel
+	if tok := config.String("APP_VM_BEARER_TOKEN", ""); tok != "" {
+		repo.headers["Authorization"] = "Bearer " + tok
+	} else if user := config.String("APP_VM_USERNAME", ""); user != "" {
+		cred := user + ":" + config.String("APP_VM_PASSWORD", "")
+		repo.headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(cred))
+	}
+	if len(repo.schema.routes) > 0 {
+		log.Warn("APP_INFLUX_ROUTES is ignored by the victoriametrics store, all devices are written as one measurement",
+			zap.String("measurement", repo.schema.measurement))
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return repo.Close()
+		},
+	})
+	log.Info("victoriametrics store", zap.String("insert", insert), zap.String("select", sel), zap.String("write_api", format))
+	return repo
+}
+
+/*
+ * WritePoint : 샘플 하나를 기록 (WriteBatch의 한 개짜리 묶음)
+ */
+func (r *VictoriaMetricsRepo) WritePoint(ctx context.Context, s telemetry.Sample) error {
+	return r.WriteBatch(ctx, []telemetry.Sample{s})
+}
+
+/*
+ * WriteBatch : 샘플 묶음을 APP_VM_MAX_REQUEST_BYTES 크기 요청들로 나누어 기록
+ *  - 샘플 시각이 비어 있으면 기록 시각을 사용
+ *  - 필드가 없는 샘플, 숫자로 저장할 수 없는 필드, NaN/±Inf는 드롭 기록 후 건너뜀
+ *  - 요청마다 그 자리에서 재시도하고, 끝내 실패하면 에러로 반환 (호출한 쪽 - 버스 또는 저널 - 이 묶음 전체를 다시 시도)
+ */
+func (r *VictoriaMetricsRepo) WriteBatch(ctx context.Context, ss []telemetry.Sample) error {
+	if err := ctx.Err(); err != nil {
+		return err // 종료 중이면 쓰지 않고 남겨 둠 (데드레터 큐 또는 저널)
+	}
+	ctx, span := r.tracer.Start(ctx, "victoriametrics write", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "victoriametrics"),
+			attribute.String("victoriametrics.write_api", r.format),
+			attribute.Int("victoriametrics.samples", len(ss)),
+		))
+	defer span.End()
+
+	var lines [][]byte
+	if r.format == "influx" {
+		lines = r.influxLines(ss)
+	} else {
+		lines = r.importLines(ss)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	path := "/write"
+	if r.format == "import" {
+		path = "/api/v1/import"
+	}
+
+	bodies := vmChunks(lines, r.maxBytes)
+	for _, body := range bodies {
+		if err := r.send(ctx, r.insert+path, body); err != nil {
+			r.log.Error("victoriametrics write failed", zap.Error(err))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("victoriametrics write: %w", err)
+		}
+	}
+	r.log.Debug("victoriametrics write success", zap.Int("lines", len(lines)), zap.Int("requests", len(bodies)))
+	return nil
+}
+
+// vmChunks : 줄들을 maxBytes 이하의 요청 본문들로 묶음 (한 줄이 maxBytes보다 길면 그 줄만 따로)
+func vmChunks(lines [][]byte, maxBytes int) [][]byte {
+	var out [][]byte
+	var cur []byte
+	for _, line := range lines {
+		if len(cur) > 0 && len(cur)+len(line) > maxBytes {
+			out = append(out, cur)
+			cur = nil
+		}
+		cur = append(cur, line...)
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+// numericFields : 샘플의 태그와 기록할 숫자 필드 (건너뛸 샘플이면 ok=false, 드롭 기록 포함)
+func (r *VictoriaMetricsRepo) numericFields(s telemetry.Sample) (map[string]string, map[string]float64, bool) {
+	tags, fields := r.schema.point(s)
+	if len(fields) == 0 {
+		r.drops.Record("victoriametrics", drops.ReasonValidation, s.DeviceID, "sample has no fields")
+		return nil, nil, false
+	}
+	fields, ok := r.schema.checkFields(r.drops, r.schema.measurement, s.DeviceID, fields)
+	if !ok {
+		return nil, nil, false
+	}
+	out := make(map[string]float64, len(fields))
+	for k, v := range fields {
+		f, ok := toFloat(v)
+		if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+			r.drops.Record("victoriametrics", drops.ReasonValidation, s.DeviceID, k+": non-numeric or non-finite field cannot be stored")
+			continue
+		}
+		out[k] = f
+	}
+	return tags, out, len(out) > 0
+}
+
+// influxLines : 샘플마다 라인 프로토콜 한 줄 (시각은 나노초, VM이 밀리초로 저장)
+func (r *VictoriaMetricsRepo) influxLines(ss []telemetry.Sample) [][]byte {
+	var lines [][]byte
+	for _, s := range ss {
+		tags, fields, ok := r.numericFields(s)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		var b bytes.Buffer
+		b.WriteString(lpMeasurementEscaper.Replace(r.schema.measurement))
+		for _, k := range sortedKeys(tags) {
+			b.WriteString("," + lpTagEscaper.Replace(k) + "=" + lpTagEscaper.Replace(tags[k]))
+		}
+		for i, k := range sortedKeys(fields) {
+			if i == 0 {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte(',')
+			}
+			b.WriteString(lpTagEscaper.Replace(k) + "=" + strconv.FormatFloat(fields[k], 'g', -1, 64))
+		}
+		b.WriteString(" " + strconv.FormatInt(at.UnixNano(), 10) + "\n")
+		lines = append(lines, b.Bytes())
+	}
+	return lines
+}
+
+// importLines : 묶음 안의 시계열마다 JSON 한 줄 (같은 시계열·같은 밀리초 값이 여러 번 있으면 마지막 값)
+func (r *VictoriaMetricsRepo) importLines(ss []telemetry.Sample) [][]byte {
+	index := make(map[string]*vmSeries)
+	var order []*vmSeries
+	for _, s := range ss {
+		tags, fields, ok := r.numericFields(s)
+		if !ok {
+			continue
+		}
+		at := s.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		ms := at.UnixMilli()
+		for field, v := range fields {
+			name := r.schema.measurement + "_" + field
+			key := name + "\xff" + s.DeviceID
+			ts, ok := index[key]
+			if !ok {
+				metric := map[string]string{"__name__": name}
+				for k, v := range tags {
+					metric[k] = v
+				}
+				ts = &vmSeries{Metric: metric}
+				index[key] = ts
+				order = append(order, ts)
+			}
+			if n := len(ts.Timestamps); n > 0 && ts.Timestamps[n-1] == ms {
+				ts.Values[n-1] = v
+				continue
+			}
+			ts.Values = append(ts.Values, v)
+			ts.Timestamps = append(ts.Timestamps, ms)
+		}
+	}
+	lines := make([][]byte, 0, len(order))
+	for _, ts := range order {
+		b, _ := json.Marshal(ts) // 유한한 float64와 문자열만 있으므로 실패하지 않음
+		lines = append(lines, append(b, '\n'))
+	}
+	return lines
+}
+
+/*
+ * send : 요청 본문 하나를 gzip으로 보냄 (503/429/연결 실패는 APP_VM_RETRY_ATTEMPTS까지 다시 시도)
+ *  - 400 등 형식 거절은 드롭 기록 후 nil (다시 보내도 소용없음)
+ */
+func (r *VictoriaMetricsRepo) send(ctx context.Context, target string, body []byte) error {
+	var zb bytes.Buffer
+	zw := gzip.NewWriter(&zb)
+	zw.Write(body)
+	zw.Close()
+
+	backoff := r.backoff
+	var err error
+	for attempt := 1; attempt <= r.attempts; attempt++ {
+		if attempt > 1 {
+			r.log.Warn("victoriametrics write retry", zap.Int("attempt", attempt), zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		err = r.post(ctx, target, zb.Bytes())
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, errVMRejected) {
+			r.drops.Record("victoriametrics", drops.ReasonWriteFailed, "", fmt.Sprintf("%d bytes: %v", len(body), err))
+			r.log.Warn("victoriametrics write rejected", zap.Int("bytes", len(body)), zap.Error(err))
+			return nil
+		}
+	}
+	return err
+}
+
+// post : 압축한 본문 전송 (2xx 성공, 5xx/429는 재시도할 에러, 그 밖은 errVMRejected)
+func (r *VictoriaMetricsRepo) post(ctx context.Context, target string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := r.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) // VM이 알려 주는 거절 이유
+	switch {
+	case resp.StatusCode/100 == 2:
+		return nil
+	case resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return fmt.Errorf("%w: %s: %s", errVMRejected, resp.Status, bytes.TrimSpace(msg))
+}
+
+// do : 인증 헤더를 붙여 요청
+func (r *VictoriaMetricsRepo) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", "generic-api-scaffold")
+	for k, v := range r.headers {
+		req.Header.Set(k, v)
+	}
+	return r.client.Do(req)
+}
+
+/*
+ * Ping : /health 확인 (readiness 검사)
+ */
+func (r *VictoriaMetricsRepo) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.health, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("victoriametrics health: %s", resp.Status)
+	}
+	return nil
+}
+
+/*
+ * Close : 유휴 연결 정리
+ */
+func (r *VictoriaMetricsRepo) Close() error {
+	r.client.CloseIdleConnections()
+	return nil
+}
+
+/*
+ * Query : 조회 조건에 맞는 장치 × 필드별 시계열 (TimeSeriesStore)
+ *  - 장치별 원시 구간(Window)을 읽어 앱에서 집계 (query.go)
+ */
+func (r *VictoriaMetricsRepo) Query(ctx context.Context, spec telemetry.QuerySpec) ([]telemetry.Series, error) {
+	return windowQuery(ctx, spec, r.Window)
+}
+
+/*
+ * Window : 장치 하나의 [from, to) 구간 데이터를 시간순으로 조회 (sim.Source)
+ */
+func (r *VictoriaMetricsRepo) Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error) {
+	var out []telemetry.Sample
+	err := r.Stream(ctx, deviceID, from, to, func(s telemetry.Sample) error {
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * Stream : 장치 하나의 [from, to) 구간 데이터를 읽으며 샘플마다 fn 호출 (Exporter)
+ *  - /api/v1/export는 시계열(필드)마다 한 줄을 돌려주므로, 구간 전체를 읽어 시각마다 샘플 하나로 모은 뒤 시간순으로 호출
+ *  - fn이 에러를 반환하거나 ctx가 취소되면 중단하고 그 에러를 반환
+ */
+func (r *VictoriaMetricsRepo) Stream(ctx context.Context, deviceID string, from, to time.Time, fn func(telemetry.Sample) error) error {
+	match := fmt.Sprintf(`{__name__=~"%s_.+",device="%s"}`, promQuote(regexp.QuoteMeta(r.schema.measurement)), promQuote(deviceID))
+	form := url.Values{
+		"match[]": {match},
+		"start":   {vmTime(from)},
+		"end":     {vmTime(to)}, // export의 end는 포함이므로 아래에서 to를 뺌
+	}
+	samples := make(map[int64]map[string]float64)
+	err := r.postForm(ctx, "/api/v1/export", form, func(line []byte) error {
+		var ts vmSeries
+		if err := json.Unmarshal(line, &ts); err != nil {
+			return err
+		}
+		field := strings.TrimPrefix(ts.Metric["__name__"], r.schema.measurement+"_")
+		for i, ms := range ts.Timestamps {
+			if ms >= to.UnixMilli() || i >= len(ts.Values) {
+				continue
+			}
+			if samples[ms] == nil {
+				samples[ms] = make(map[string]float64)
+			}
+			samples[ms][field] = ts.Values[i]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	stamps := make([]int64, 0, len(samples))
+	for ms := range samples {
+		stamps = append(stamps, ms)
+	}
+	sort.Slice(stamps, func(i, j int) bool { return stamps[i] < stamps[j] })
+	for _, ms := range stamps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(telemetry.Sample{Time: time.UnixMilli(ms), DeviceID: deviceID, Values: samples[ms]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+ * FieldKeys : 저장된 필드 이름 목록 (정렬, 최근 30일 기준 - 2.x와 같음)
+ *  - CSV 내보내기의 열 머리글을 정할 때 사용
+ */
+func (r *VictoriaMetricsRepo) FieldKeys(ctx context.Context) ([]string, error) {
+	form := url.Values{
+		"match[]": {fmt.Sprintf(`{__name__=~"%s_.+"}`, promQuote(regexp.QuoteMeta(r.schema.measurement)))},
+		"start":   {vmTime(time.Now().Add(-30 * 24 * time.Hour))},
+	}
+	var body []byte
+	if err := r.postForm(ctx, "/api/v1/label/__name__/values", form, func(line []byte) error {
+		body = append(body, line...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(resp.Data))
+	for _, name := range resp.Data {
+		keys = append(keys, strings.TrimPrefix(name, r.schema.measurement+"_"))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// postForm : 조회 API에 폼 요청을 보내고 응답 본문을 줄마다 fn에 넘김
+func (r *VictoriaMetricsRepo) postForm(ctx context.Context, path string, form url.Values, fn func(line []byte) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.sel+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := r.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("victoriametrics %s: %s: %s", path, resp.Status, bytes.TrimSpace(msg))
+	}
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 64<<20) // 긴 구간의 시계열 한 줄
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		if err := fn(sc.Bytes()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// vmTime : 조회 API 시각 인자 (유닉스 초, 밀리초 소수)
+func vmTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', 3, 64)
+}
+
+// promQuote : 시계열 선택자의 큰따옴표 문자열 안에 넣을 값 이스케이프
+func promQuote(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v)
+}
@@ -0,0 +1,93 @@
+/*
+ * 웹훅 구독 API 핸들러
+ *  - GET    /api/webhooks       : 구독 목록
+ *  - POST   /api/webhooks       : 구독 등록 {"url","events":["data.collected",...],"secret"}
+ *  - GET    /api/webhooks/{id}  : 구독 조회
+ *  - PUT    /api/webhooks/{id}  : 구독 수정 (secret 생략 시 기존 값 유지)
+ *  - DELETE /api/webhooks/{id}  : 구독 삭제
+ *  - 모든 경로는 operator 역할 필요 (토큰이 없으면 401, 역할이 없으면 403)
+ *  - secret은 어떤 응답에도 포함하지 않습니다.
+ */
+package infra
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux" // 경로 변수({id}) 추출
+
+	"generic-api-scaffold/internal/audit"   // 감사 이벤트 기록
+	"generic-api-scaffold/internal/auth"    // 호출자 역할 확인
+	"generic-api-scaffold/internal/webhook" // 웹훅 구독 관리
+)
+
+func (s *Server) handleWebhookList(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.requireRole(w, r, auth.RoleOperator); !ok {
+		return
+	}
+	respond(w, r, http.StatusOK, s.webhooks.List())
+}
+
+func (s *Server) handleWebhookCreate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.requireRole(w, r, auth.RoleOperator); !ok {
+		return
+	}
+	var spec webhook.Spec
+	if !s.decodeJSON(w, r, &spec) {
+		return
+	}
+	sub, err := s.webhooks.Create(spec)
+	s.auditEvent(r, audit.CategoryConfig, "webhook.create", "", "id="+sub.ID+" url="+spec.URL, err)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	respond(w, r, http.StatusCreated, sub)
+}
+
+func (s *Server) handleWebhookGet(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.requireRole(w, r, auth.RoleOperator); !ok {
+		return
+	}
+	sub, err := s.webhooks.Get(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, r, http.StatusNotFound, "not_found", err.Error())
+		return
+	}
+	respond(w, r, http.StatusOK, sub)
+}
+
+func (s *Server) handleWebhookUpdate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.requireRole(w, r, auth.RoleOperator); !ok {
+		return
+	}
+	var spec webhook.Spec
+	if !s.decodeJSON(w, r, &spec) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	sub, err := s.webhooks.Update(id, spec)
+	s.auditEvent(r, audit.CategoryConfig, "webhook.update", "", "id="+id+" url="+spec.URL, err)
+	switch {
+	case errors.Is(err, webhook.ErrNotFound):
+		respondError(w, r, http.StatusNotFound, "not_found", err.Error())
+	case err != nil:
+		respondError(w, r, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		respond(w, r, http.StatusOK, sub)
+	}
+}
+
+func (s *Server) handleWebhookDelete(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.requireRole(w, r, auth.RoleOperator); !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	err := s.webhooks.Delete(id)
+	s.auditEvent(r, audit.CategoryConfig, "webhook.delete", "", "id="+id, err)
+	if err != nil {
+		respondError(w, r, http.StatusNotFound, "not_found", err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,418 @@
+/*
+ * Journal : 수집 이벤트의 로컬 영속 저널 (at-least-once 전달)
+ *  - APP_JOURNAL_PATH를 지정하면 켜지며, 발행된 DataCollectedEvent를 bbolt 파일에 먼저 기록합니다.
+ *    (버스의 sync 구독자로 붙으므로 Publish가 반환될 때는 이미 디스크에 있음)
+ *  - 영속 소비자(Consume)는 이름별 커서를 가지며, 처리에 성공한 항목만 확인(ack)하여 커서를 옮깁니다.
+ *    처리에 실패하면 APP_JOURNAL_RETRY 간격으로 같은 항목부터 다시 시도하고,
+ *    프로세스가 재시작되면 마지막으로 확인한 위치 다음부터 이어서 전달합니다.
+ *      → Influx가 내려가 있는 동안 수집된 데이터도 재시작 후 유실되지 않음
+ *  - 모든 소비자가 확인한 항목은 지웁니다. 확인되지 않은 항목이 APP_JOURNAL_MAX_ENTRIES를 넘으면
+ *    가장 오래된 항목부터 버리고 드롭 기록기(source="journal", reason="backpressure")에 남깁니다.
+ *  - 항목은 스키마 레지스트리의 Envelope(APP_EVENT_CODEC)로 직렬화하므로 스키마가 바뀌어도 마이그레이션하여 읽습니다.
+ *  - 같은 항목이 두 번 전달될 수 있으므로(확인 직전에 종료된 경우) 소비자는 멱등이어야 합니다.
+ *  - 묶음 소비자(ConsumeBatch)는 항목을 최대 size개씩 모아 한 번에 처리하고 묶음 단위로 확인합니다.
+ *    (묶음이 차지 않아도 가장 오래 기다린 항목이 latency를 넘으면 처리)
+ */
+package journal
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt" // 임베디드 키-값 저장소
+	"go.uber.org/fx"        // 라이프사이클 훅
+	"go.uber.org/zap"       // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"    // 기록 대상 이벤트
+	"generic-api-scaffold/internal/codec"  // 이벤트 스키마 레지스트리
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"  // 용량 초과로 버린 항목 기록
+)
+
+// bbolt 버킷 이름
+var (
+	bucketEvents  = []byte("events")  // 순번(8바이트 빅엔디언) → 직렬화된 이벤트
+	bucketCursors = []byte("cursors") // 소비자 이름 → 마지막으로 확인한 순번
+)
+
+// Handler : 영속 소비자의 처리 함수 (nil을 반환해야 확인됨)
+type Handler func(ctx context.Context, e bus.DataCollectedEvent) error
+
+// BatchHandler : 묶음 소비자의 처리 함수 (nil을 반환해야 묶음 전체가 확인됨)
+type BatchHandler func(ctx context.Context, es []bus.DataCollectedEvent) error
+
+// consumer : 영속 소비자 하나 (단건 소비자는 크기 1짜리 묶음 소비자)
+type consumer struct {
+	name    string
+	fn      BatchHandler
+	size    int           // 묶음 최대 항목 수
+	latency time.Duration // 묶음이 차지 않았을 때 최대 대기 시간
+	since   time.Time     // 차지 않은 묶음을 처음 본 시각 (없으면 0)
+	acked   uint64        // 마지막으로 확인한 순번
+	wake    chan struct{} // 새 항목 알림 (크기 1)
+}
+
+// entry : 저널 항목 하나 (순번 + 직렬화된 이벤트)
+type entry struct {
+	seq  uint64
+	data []byte
+}
+
+/*
+ * Journal 구조체
+ *  - db가 nil이면 비활성 (Enabled() == false)
+ */
+type Journal struct {
+	log     *zap.Logger
+	db      *bolt.DB
+	schemas *codec.Registry
+	drops   *drops.Recorder
+
+	maxEntries uint64
+	retry      time.Duration
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+
+	mu        sync.Mutex
+	consumers map[string]*consumer
+}
+
+/*
+ * NewJournal : fx가 호출하는 Journal 생성자
+ *  - APP_JOURNAL_PATH        : 저널 파일 경로 (비어 있으면 비활성, 기본 비활성)
+ *  - APP_JOURNAL_MAX_ENTRIES : 확인되지 않은 항목 최대 수 (기본 100000)
+ *  - APP_JOURNAL_RETRY       : 소비자 처리 실패 후 재시도 간격 (기본 5s)
+ *  - OnStart : 등록된 소비자의 전달 고루틴 시작 / OnStop : 고루틴 종료를 기다린 뒤 파일 닫기
+ */
+func NewJournal(lc fx.Lifecycle, log *zap.Logger, b *bus.EventBus, schemas *codec.Registry, dr *drops.Recorder) *Journal {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &Journal{
+		log:        log,
+		schemas:    schemas,
+		drops:      dr,
+		maxEntries: uint64(config.Int(log, "APP_JOURNAL_MAX_ENTRIES", 100000)),
+		retry:      config.Duration(log, "APP_JOURNAL_RETRY", 5*time.Second),
+		ctx:        ctx,
+		cancel:     cancel,
+		consumers:  make(map[string]*consumer),
+	}
+	path := config.String("APP_JOURNAL_PATH", "")
+	if path == "" {
+		return j
+	}
+	if j.maxEntries < 1 || j.retry <= 0 {
+		log.Fatal("APP_JOURNAL_MAX_ENTRIES and APP_JOURNAL_RETRY must be positive")
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		log.Fatal("failed to open journal", zap.String("path", path), zap.Error(err))
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(bucketEvents); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(bucketCursors)
+		return err
+	})
+	if err != nil {
+		log.Fatal("failed to initialize journal", zap.String("path", path), zap.Error(err))
+	}
+	j.db = db
+
+	// 발행 시점에 디스크에 남도록 sync 구독 (실패하면 버스의 재시도/DLQ 경로를 탐)
+	bus.SubscribeErr(b, j.append, bus.WithDelivery(bus.DeliverySync), bus.WithName("journal"), bus.WithoutRetained(),
+		bus.WithPriority(bus.PriorityCritical)) // 다른 구독자보다 먼저 디스크에 기록
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			j.mu.Lock()
+			j.started = true
+			for _, c := range j.consumers {
+				j.start(c)
+			}
+			j.mu.Unlock()
+			log.Info("journal started", zap.String("path", path), zap.Int("consumers", len(j.consumers)))
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			j.cancel()
+			j.wg.Wait()
+			return j.db.Close()
+		},
+	})
+	return j
+}
+
+/*
+ * Enabled : 저널이 켜져 있는지 (APP_JOURNAL_PATH 지정 여부)
+ */
+func (j *Journal) Enabled() bool {
+	return j.db != nil
+}
+
+/*
+ * Backlog : 소비자 name이 아직 확인하지 않은 항목 수 (비활성이거나 없는 소비자면 0)
+ *  - 용량 초과로 버린 항목은 세지 않음
+ */
+func (j *Journal) Backlog(name string) uint64 {
+	if j.db == nil {
+		return 0
+	}
+	j.mu.Lock()
+	c, ok := j.consumers[name]
+	var acked uint64
+	if ok {
+		acked = c.acked
+	}
+	j.mu.Unlock()
+	if !ok {
+		return 0
+	}
+
+	var n uint64
+	_ = j.db.View(func(tx *bolt.Tx) error {
+		events := tx.Bucket(bucketEvents)
+		k, _ := events.Cursor().First()
+		if k == nil {
+			return nil
+		}
+		from := acked + 1
+		if first := binary.BigEndian.Uint64(k); first > from {
+			from = first
+		}
+		if last := events.Sequence(); last >= from {
+			n = last - from + 1
+		}
+		return nil
+	})
+	return n
+}
+
+/*
+ * Consume : 영속 소비자 등록
+ *  - 저장된 커서(없으면 처음) 다음 항목부터 순서대로 fn에 전달하고, 성공한 항목만 확인
+ *  - 같은 이름은 재시작 후에도 같은 커서를 이어서 사용하므로, 이름을 바꾸면 처음부터 다시 받음
+ */
+func (j *Journal) Consume(name string, fn Handler) error {
+	return j.ConsumeBatch(name, 1, 0, func(ctx context.Context, es []bus.DataCollectedEvent) error {
+		return fn(ctx, es[0])
+	})
+}
+
+/*
+ * ConsumeBatch : 묶음 영속 소비자 등록
+ *  - 항목을 최대 size개씩 fn에 전달하고, 성공하면 묶음의 마지막 항목까지 확인
+ *  - 밀린 항목이 size개보다 적으면 가장 오래 기다린 항목이 latency를 넘을 때까지 더 모음
+ *  - 실패하면 같은 묶음부터 다시 시도 (Consume과 같음)
+ */
+func (j *Journal) ConsumeBatch(name string, size int, latency time.Duration, fn BatchHandler) error {
+	if !j.Enabled() {
+		return fmt.Errorf("journal is disabled")
+	}
+	if size < 1 || latency < 0 {
+		return fmt.Errorf("journal consumer %q: batch size must be positive and latency non-negative", name)
+	}
+	c := &consumer{name: name, fn: fn, size: size, latency: latency, wake: make(chan struct{}, 1)}
+	err := j.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(bucketCursors).Get([]byte(name)); v != nil {
+			c.acked = binary.BigEndian.Uint64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.consumers[name]; ok {
+		return fmt.Errorf("journal consumer %q already registered", name)
+	}
+	j.consumers[name] = c
+	if j.started {
+		j.start(c)
+	}
+	return nil
+}
+
+// start : 소비자의 전달 고루틴 시작 (호출자가 j.mu를 잡고 있어야 함)
+func (j *Journal) start(c *consumer) {
+	j.wg.Add(1)
+	go j.run(c)
+}
+
+/*
+ * append : 이벤트를 저널 끝에 기록하고 소비자를 깨움
+ *  - 확인되지 않은 항목이 상한을 넘으면 가장 오래된 항목을 버림
+ */
+func (j *Journal) append(_ context.Context, e bus.DataCollectedEvent) error {
+	data, err := j.schemas.Encode(e)
+	if err != nil {
+		return err
+	}
+
+	var evicted int
+	err = j.db.Update(func(tx *bolt.Tx) error {
+		events := tx.Bucket(bucketEvents)
+		seq, err := events.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := events.Put(key(seq), data); err != nil {
+			return err
+		}
+		// 순번은 연속이므로 (마지막 - 처음 + 1)이 남은 항목 수
+		cur := events.Cursor()
+		for k, _ := cur.First(); k != nil && seq-binary.BigEndian.Uint64(k)+1 > j.maxEntries; k, _ = cur.Next() {
+			if err := cur.Delete(); err != nil {
+				return err
+			}
+			evicted++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if evicted > 0 {
+		j.drops.Record("journal", drops.ReasonBackpressure, e.DeviceID,
+			fmt.Sprintf("%d oldest unacknowledged entries evicted (APP_JOURNAL_MAX_ENTRIES=%d)", evicted, j.maxEntries))
+	}
+
+	j.mu.Lock()
+	for _, c := range j.consumers {
+		select {
+		case c.wake <- struct{}{}:
+		default: // 이미 깨울 예정
+		}
+	}
+	j.mu.Unlock()
+	return nil
+}
+
+/*
+ * run : 소비자 하나의 전달 루프
+ *  - 밀린 항목을 모두 처리한 뒤 새 항목 알림을 기다림
+ *  - 처리에 실패하면 retry 간격 뒤 같은 항목부터 다시 시도
+ *  - 묶음이 차지 않았으면 남은 대기 시간 뒤(또는 새 항목 알림 시) 다시 확인
+ */
+func (j *Journal) run(c *consumer) {
+	defer j.wg.Done()
+	for {
+		wait := (<-chan time.Time)(nil)
+		if delay, err := j.drain(c); err != nil {
+			j.log.Warn("journal consumer failed, will retry", zap.String("consumer", c.name),
+				zap.Duration("retry", j.retry), zap.Error(err))
+			wait = time.After(j.retry)
+		} else if delay > 0 {
+			wait = time.After(delay)
+		}
+		select {
+		case <-j.ctx.Done():
+			return
+		case <-c.wake:
+		case <-wait:
+		}
+	}
+}
+
+/*
+ * drain : 커서 다음 항목부터 끝까지 묶음 단위로 처리 (실패한 묶음에서 멈춤)
+ *  - 차지 않은 묶음이 아직 latency를 넘지 않았으면 처리하지 않고 남은 대기 시간을 반환
+ */
+func (j *Journal) drain(c *consumer) (time.Duration, error) {
+	for j.ctx.Err() == nil {
+		entries, err := j.next(c.acked, c.size)
+		if err != nil || len(entries) == 0 {
+			return 0, err
+		}
+		if len(entries) < c.size {
+			if c.since.IsZero() {
+				c.since = time.Now()
+			}
+			if left := c.latency - time.Since(c.since); left > 0 {
+				return left, nil
+			}
+		}
+
+		es := make([]bus.DataCollectedEvent, 0, len(entries))
+		for _, en := range entries {
+			v, _, err := j.schemas.Decode(en.data)
+			e, ok := v.(bus.DataCollectedEvent)
+			if err == nil && !ok {
+				err = fmt.Errorf("unexpected event %T", v)
+			}
+			if err != nil {
+				// 읽을 수 없는 항목은 다시 시도해도 같으므로 기록하고 건너뜀
+				j.drops.Record("journal", drops.ReasonValidation, "", fmt.Sprintf("entry %d: %v", en.seq, err))
+				continue
+			}
+			es = append(es, e)
+		}
+		if len(es) > 0 {
+			if err := c.fn(j.ctx, es); err != nil {
+				return 0, err // since는 그대로 두어 재시도 때 바로 처리
+			}
+		}
+		if err := j.ack(c, entries[len(entries)-1].seq); err != nil {
+			return 0, err
+		}
+		c.since = time.Time{}
+	}
+	return 0, nil
+}
+
+// next : after 다음부터 최대 limit개 항목
+func (j *Journal) next(after uint64, limit int) ([]entry, error) {
+	var out []entry
+	err := j.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket(bucketEvents).Cursor()
+		for k, v := cur.Seek(key(after + 1)); k != nil && len(out) < limit; k, v = cur.Next() {
+			out = append(out, entry{seq: binary.BigEndian.Uint64(k), data: append([]byte(nil), v...)})
+		}
+		return nil
+	})
+	return out, err
+}
+
+/*
+ * ack : 소비자의 커서를 seq로 옮기고, 모든 소비자가 확인한 항목을 지움
+ */
+func (j *Journal) ack(c *consumer, seq uint64) error {
+	j.mu.Lock()
+	c.acked = seq
+	low := seq
+	for _, other := range j.consumers {
+		if other.acked < low {
+			low = other.acked
+		}
+	}
+	j.mu.Unlock()
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(bucketCursors).Put([]byte(c.name), key(seq)); err != nil {
+			return err
+		}
+		cur := tx.Bucket(bucketEvents).Cursor()
+		for k, _ := cur.First(); k != nil && binary.BigEndian.Uint64(k) <= low; k, _ = cur.Next() {
+			if err := cur.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// key : 순번을 정렬 가능한 8바이트 키로
+func key(seq uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, seq)
+	return b
+}
@@ -0,0 +1,156 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+
+	"generic-api-scaffold/internal/bus"
+	"generic-api-scaffold/internal/codec"
+	"generic-api-scaffold/internal/drops"
+)
+
+// openJournal : 같은 파일로 버스와 저널을 새로 만듦 (프로세스 재시작 흉내)
+func openJournal(t *testing.T, path string) (*Journal, *bus.EventBus, *fxtest.Lifecycle) {
+	t.Helper()
+	t.Setenv("APP_JOURNAL_PATH", path)
+	t.Setenv("APP_JOURNAL_RETRY", "10ms")
+	log := zap.NewNop()
+	reg := prometheus.NewRegistry()
+	dr := drops.NewRecorder(log, reg)
+	lc := fxtest.NewLifecycle(t)
+	b := bus.NewEventBus(bus.EventBusParams{Lifecycle: lc, Log: log, Metrics: reg, Drops: dr})
+	j := NewJournal(lc, log, b, codec.NewRegistry(codec.NewCodec(log)), dr)
+	return j, b, lc
+}
+
+// recorder : 소비자가 받은 값 (n 필드) 기록, 호출 번호가 fail에 맞으면 실패
+type recorder struct {
+	mu    sync.Mutex
+	calls int
+	got   []float64
+	fail  func(call int) bool
+}
+
+func (r *recorder) handle(_ context.Context, e bus.DataCollectedEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.fail != nil && r.fail(r.calls) {
+		return errors.New("store unavailable")
+	}
+	r.got = append(r.got, e.Values["n"])
+	return nil
+}
+
+func (r *recorder) snapshot() (int, []float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls, append([]float64(nil), r.got...)
+}
+
+// eventually : 조건이 참이 될 때까지 최대 2초 대기
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func publish(b *bus.EventBus, values ...float64) {
+	for _, v := range values {
+		b.Publish(context.Background(), bus.DataCollectedEvent{DeviceID: "A1", Values: map[string]float64{"n": v}, Time: time.Now()})
+	}
+}
+
+func TestRetryUntilAcknowledged(t *testing.T) {
+	j, b, lc := openJournal(t, filepath.Join(t.TempDir(), "journal.db"))
+	rec := &recorder{fail: func(call int) bool { return call <= 2 }} // 처음 두 번은 저장소 장애
+	if err := j.Consume("influx", rec.handle); err != nil {
+		t.Fatal(err)
+	}
+	lc.RequireStart()
+	publish(b, 1, 2, 3)
+
+	eventually(t, "all events delivered", func() bool { _, got := rec.snapshot(); return len(got) == 3 })
+	if _, got := rec.snapshot(); fmt.Sprint(got) != "[1 2 3]" {
+		t.Fatalf("delivered %v, want [1 2 3] in order", got)
+	}
+	eventually(t, "backlog cleared", func() bool { return j.Backlog("influx") == 0 })
+	lc.RequireStop()
+}
+
+func TestReplayAfterRestart(t *testing.T) {
+	tests := []struct {
+		name     string
+		ackFirst int       // 재시작 전 확인(ack)되는 항목 수, 그 뒤로는 계속 실패
+		want     []float64 // 재시작 후 전달되는 값
+	}{
+		{name: "nothing acknowledged", ackFirst: 0, want: []float64{1, 2, 3}},
+		{name: "resume after last ack", ackFirst: 2, want: []float64{3}},
+		{name: "everything acknowledged", ackFirst: 3, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal.db")
+
+			j, b, lc := openJournal(t, path)
+			first := &recorder{fail: func(call int) bool { return call > tt.ackFirst }}
+			if err := j.Consume("influx", first.handle); err != nil {
+				t.Fatal(err)
+			}
+			lc.RequireStart()
+			publish(b, 1, 2, 3)
+			// 확인된 마지막 항목 다음 호출이 보이면 그 앞의 ack는 이미 디스크에 있음
+			eventually(t, "first run settled", func() bool {
+				calls, got := first.snapshot()
+				return len(got) == tt.ackFirst && (tt.ackFirst == 3 || calls > tt.ackFirst)
+			})
+			lc.RequireStop()
+
+			j, _, lc = openJournal(t, path)
+			second := &recorder{}
+			if err := j.Consume("influx", second.handle); err != nil {
+				t.Fatal(err)
+			}
+			if got := j.Backlog("influx"); got != uint64(len(tt.want)) {
+				t.Fatalf("backlog after restart = %d, want %d", got, len(tt.want))
+			}
+			lc.RequireStart()
+			eventually(t, "replay", func() bool { _, got := second.snapshot(); return len(got) >= len(tt.want) })
+			time.Sleep(50 * time.Millisecond) // 더 전달되지 않는지 확인
+			if _, got := second.snapshot(); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("replayed %v, want %v", got, tt.want)
+			}
+			lc.RequireStop()
+		})
+	}
+}
+
+func TestDisabledWithoutPath(t *testing.T) {
+	t.Setenv("APP_JOURNAL_PATH", "")
+	log := zap.NewNop()
+	reg := prometheus.NewRegistry()
+	dr := drops.NewRecorder(log, reg)
+	lc := fxtest.NewLifecycle(t)
+	b := bus.NewEventBus(bus.EventBusParams{Lifecycle: lc, Log: log, Metrics: reg, Drops: dr})
+	j := NewJournal(lc, log, b, codec.NewRegistry(codec.NewCodec(log)), dr)
+	if j.Enabled() {
+		t.Fatal("journal enabled without APP_JOURNAL_PATH")
+	}
+	if err := j.Consume("influx", (&recorder{}).handle); err == nil {
+		t.Fatal("Consume succeeded on a disabled journal")
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Store : 장치별 최신값 저장소 (last-value store)
+ *  - EventBus를 구독하여 장치별로 필드의 마지막 값과 수신 시각을 메모리에 보관합니다.
+ *  - GET /api/devices/{id}/latest 가 매번 Influx를 조회하지 않고 여기서 응답합니다.
+ *  - 장치가 일부 필드만 보고해도 이전에 받은 다른 필드 값은 유지합니다. (필드별 시각을 따로 보관)
+ *  - 시각은 이벤트의 측정 시각(없으면 받은 시각)이며, 재생 등으로 늦게 도착한 더 오래된 값은 최신값을 덮어쓰지 않습니다.
+ *  - bus.CatchUpSource를 구현하므로 늦게 시작한 구독자의 따라잡기 공급원으로도 쓸 수 있습니다.
+ */
+package latest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus" // 이벤트 구독
+)
+
+// Field : 필드 하나의 마지막 값
+type Field struct {
+	Value float64   `json:"value"`
+	Time  time.Time `json:"time"`
+}
+
+/*
+ * Snapshot : 장치 하나의 최신 상태
+ *  - Time   : 가장 최근 이벤트의 측정 시각
+ *  - Values : 필드 → 마지막 값 (필드별 측정 시각은 Fields)
+ */
+type Snapshot struct {
+	DeviceID string             `json:"device"`
+	Time     time.Time          `json:"time"`
+	Values   map[string]float64 `json:"values"`
+	Fields   map[string]Field   `json:"fields"`
+}
+
+// Store 구조체
+type Store struct {
+	log *zap.Logger
+
+	mu      sync.RWMutex
+	devices map[string]*Snapshot
+}
+
+/*
+ * NewStore : fx가 호출하는 최신값 저장소 생성자
+ *  - 생성 시 EventBus를 구독 (버스에 이미 있는 장치별 마지막 이벤트로 따라잡기)
+ */
+func NewStore(log *zap.Logger, b *bus.EventBus) *Store {
+	s := &Store{log: log, devices: make(map[string]*Snapshot)}
+	b.Subscribe(s.onEvent, bus.WithCatchUp(), bus.WithPriority(bus.PriorityCritical)) // 다른 구독자가 최신값을 읽기 전에 반영
+	return s
+}
+
+// onEvent : 이벤트의 필드 값을 장치 상태에 병합 (이미 더 최근 값이 있는 필드는 건너뜀)
+func (s *Store) onEvent(_ context.Context, e bus.DataCollectedEvent) {
+	at := e.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.devices[e.DeviceID]
+	if !ok {
+		snap = &Snapshot{DeviceID: e.DeviceID, Values: make(map[string]float64), Fields: make(map[string]Field)}
+		s.devices[e.DeviceID] = snap
+	}
+	if at.After(snap.Time) {
+		snap.Time = at
+	}
+	for k, v := range e.Values {
+		if f, ok := snap.Fields[k]; ok && f.Time.After(at) {
+			continue
+		}
+		snap.Values[k] = v
+		snap.Fields[k] = Field{Value: v, Time: at}
+	}
+}
+
+/*
+ * Get : 장치 하나의 최신 상태 (사본)
+ *  - 한 번도 보고하지 않은 장치면 false
+ */
+func (s *Store) Get(deviceID string) (Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap, ok := s.devices[deviceID]
+	if !ok {
+		return Snapshot{}, false
+	}
+	return snap.copy(), true
+}
+
+/*
+ * CatchUp : 장치별 최신 상태를 이벤트 형태로 반환 (장치 ID 순)
+ *  - bus.CatchUpSource 구현 (필드가 병합된 상태를 전달하므로 버스의 마지막 이벤트보다 완전함)
+ */
+func (s *Store) CatchUp() []bus.DataCollectedEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.devices))
+	for id := range s.devices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := make([]bus.DataCollectedEvent, 0, len(ids))
+	for _, id := range ids {
+		snap := s.devices[id].copy()
+		out = append(out, bus.DataCollectedEvent{DeviceID: id, Values: snap.Values, Time: snap.Time})
+	}
+	return out
+}
+
+// copy : 맵까지 복사한 사본 (호출자가 잠금을 잡고 있어야 함)
+func (snap *Snapshot) copy() Snapshot {
+	c := Snapshot{
+		DeviceID: snap.DeviceID,
+		Time:     snap.Time,
+		Values:   make(map[string]float64, len(snap.Values)),
+		Fields:   make(map[string]Field, len(snap.Fields)),
+	}
+	for k, v := range snap.Values {
+		c.Values[k] = v
+	}
+	for k, f := range snap.Fields {
+		c.Fields[k] = f
+	}
+	return c
+}
@@ -0,0 +1,115 @@
+/*
+ * dayAheadFeed : 하루 전(day-ahead) 시장 가격 피드
+ *  - 설정된 URL에서 가격 구간 목록을 JSON으로 받아 메모리에 캐시하고 주기적으로 갱신합니다.
+ *  - 응답 형식 : [{"start":"2025-01-01T00:00:00Z","end":"2025-01-01T01:00:00Z","price":0.123}, ...]
+ */
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+)
+
+// Interval : 가격이 적용되는 구간 [Start, End)
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Price float64   `json:"price"`
+}
+
+// dayAheadFeed 구조체
+type dayAheadFeed struct {
+	log     *zap.Logger
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	mu        sync.RWMutex
+	intervals []Interval // Start 기준 정렬
+
+	done chan struct{}
+}
+
+func newDayAheadFeed(log *zap.Logger, url string, refresh time.Duration) *dayAheadFeed {
+	return &dayAheadFeed{
+		log:     log,
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		done:    make(chan struct{}),
+	}
+}
+
+/*
+ * run : 시작 즉시 한 번, 이후 refresh 주기로 가격을 다시 받아옴
+ */
+func (f *dayAheadFeed) run() {
+	ticker := time.NewTicker(f.refresh)
+	defer ticker.Stop()
+
+	for {
+		if err := f.fetch(); err != nil {
+			f.log.Warn("price feed refresh failed", zap.String("url", f.url), zap.Error(err))
+		}
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *dayAheadFeed) stop() { close(f.done) }
+
+// fetch : 가격 목록을 받아 캐시 교체
+func (f *dayAheadFeed) fetch() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var intervals []Interval
+	if err := json.NewDecoder(resp.Body).Decode(&intervals); err != nil {
+		return err
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+
+	f.mu.Lock()
+	f.intervals = intervals
+	f.mu.Unlock()
+
+	f.log.Info("price feed refreshed", zap.Int("intervals", len(intervals)))
+	return nil
+}
+
+/*
+ * Price : 시각 t를 포함하는 구간의 가격 (없으면 ErrNoPrice)
+ */
+func (f *dayAheadFeed) Price(_ context.Context, t time.Time) (float64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	i := sort.Search(len(f.intervals), func(i int) bool { return f.intervals[i].End.After(t) })
+	if i < len(f.intervals) && !f.intervals[i].Start.After(t) {
+		return f.intervals[i].Price, nil
+	}
+	return 0, ErrNoPrice
+}
@@ -0,0 +1,136 @@
+/*
+ * 전력 가격 피드 : 특정 시각의 kWh당 전력 단가를 제공합니다.
+ *  - tariff   : 시간대별 요금표 (예: 심야 0.10, 주간 0.25) — 외부 연동 없이 동작
+ *  - dayahead : 하루 전(day-ahead) 시장 가격을 HTTP로 주기적으로 받아 캐시
+ *  - 시뮬레이션 보고서의 구간별 비용 계산과 규칙 엔진의 "price" 필드에 사용됩니다.
+ */
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/fx"  // 애플리케이션 생명주기(Lifecycle) 훅 제공
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// ErrNoPrice : 요청한 시각의 가격 정보가 없음
+var ErrNoPrice = errors.New("no price available for the requested time")
+
+/*
+ * Feed : 가격 피드 인터페이스
+ *  - Price : 시각 t에 적용되는 kWh당 단가
+ */
+type Feed interface {
+	Price(ctx context.Context, t time.Time) (float64, error)
+}
+
+/*
+ * NewFeed : fx가 호출하는 가격 피드 생성자
+ *  - APP_PRICE_SOURCE : tariff(기본) | dayahead
+ *  - tariff   : APP_PRICE_TARIFF (예: "00:00=0.10,07:00=0.25,22:00=0.10")
+ *  - dayahead : APP_PRICE_FEED_URL, APP_PRICE_REFRESH (기본 1h)
+ */
+func NewFeed(lc fx.Lifecycle, log *zap.Logger) Feed {
+	switch src := config.String("APP_PRICE_SOURCE", "tariff"); src {
+	case "tariff":
+		t, err := ParseTariff(config.String("APP_PRICE_TARIFF", "00:00=0"))
+		if err != nil {
+			log.Fatal("invalid price tariff", zap.Error(err))
+		}
+		return t
+	case "dayahead":
+		url := config.String("APP_PRICE_FEED_URL", "")
+		if url == "" {
+			log.Fatal("APP_PRICE_FEED_URL is required for dayahead price source")
+		}
+		f := newDayAheadFeed(log, url, config.Duration(log, "APP_PRICE_REFRESH", time.Hour))
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				go f.run()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				f.stop()
+				return nil
+			},
+		})
+		return f
+	default:
+		log.Fatal("unknown price source", zap.String("source", src))
+		return nil
+	}
+}
+
+// tariffEntry : 하루 중 시작 시각(자정 기준 경과 시간)과 단가
+type tariffEntry struct {
+	start time.Duration
+	price float64
+}
+
+/*
+ * Tariff : 시간대별 요금표
+ *  - 각 항목은 다음 항목의 시작 시각 전까지 적용되며, 마지막 항목은 자정을 넘어 첫 항목까지 이어집니다.
+ *  - 시각은 로컬 시간대(time.Local) 기준입니다.
+ */
+type Tariff struct {
+	entries []tariffEntry
+}
+
+/*
+ * ParseTariff : "HH:MM=단가,HH:MM=단가" 형식의 요금표 파싱
+ */
+func ParseTariff(spec string) (*Tariff, error) {
+	t := &Tariff{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid tariff entry %q", part)
+		}
+		clock, err := time.Parse("15:04", strings.TrimSpace(kv[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tariff time %q: %w", kv[0], err)
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tariff price %q: %w", kv[1], err)
+		}
+		start := time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute
+		t.entries = append(t.entries, tariffEntry{start: start, price: p})
+	}
+	if len(t.entries) == 0 {
+		return nil, errors.New("tariff has no entries")
+	}
+	sort.Slice(t.entries, func(i, j int) bool { return t.entries[i].start < t.entries[j].start })
+	return t, nil
+}
+
+/*
+ * Price : 시각 t가 속한 시간대의 단가
+ */
+func (t *Tariff) Price(_ context.Context, at time.Time) (float64, error) {
+	at = at.In(time.Local)
+	y, m, d := at.Date()
+	sinceMidnight := at.Sub(time.Date(y, m, d, 0, 0, 0, 0, time.Local))
+
+	// 자정 직후 첫 항목 이전 구간은 전날 마지막 항목이 적용됨
+	p := t.entries[len(t.entries)-1].price
+	for _, e := range t.entries {
+		if e.start > sinceMidnight {
+			break
+		}
+		p = e.price
+	}
+	return p, nil
+}
@@ -0,0 +1,75 @@
+/*
+ * 비용 최적화 제어 힌트 : 가격이 싼 구간에는 충전, 비싼 구간에는 방전을 권장합니다.
+ *  - 조회 구간을 일정 간격으로 나누어 가격을 구한 뒤, 하위/상위 비율(기본 25%) 구간에 힌트를 붙입니다.
+ */
+package price
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Hint : 구간 하나에 대한 권장 동작
+type Hint struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Price  float64   `json:"price"`
+	Action string    `json:"action"` // charge | discharge | hold
+}
+
+/*
+ * Hints : [from, to) 구간을 step 간격으로 나누어 구간별 권장 동작 계산
+ *  - quantile : 충전/방전을 권장할 하위/상위 비율 (0 < quantile <= 0.5)
+ *  - 가격 정보가 없는 구간은 건너뜁니다.
+ */
+func Hints(ctx context.Context, feed Feed, from, to time.Time, step time.Duration, quantile float64) ([]Hint, error) {
+	if step <= 0 || !to.After(from) {
+		return nil, errors.New("invalid range or step")
+	}
+	if quantile <= 0 || quantile > 0.5 {
+		return nil, errors.New("quantile must be in (0, 0.5]")
+	}
+
+	var hints []Hint
+	for t := from; t.Before(to); t = t.Add(step) {
+		p, err := feed.Price(ctx, t)
+		if errors.Is(err, ErrNoPrice) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		hints = append(hints, Hint{Start: t, End: t.Add(step), Price: p, Action: "hold"})
+	}
+	if len(hints) == 0 {
+		return hints, nil
+	}
+
+	// 가격 기준 하위/상위 임계값 계산
+	prices := make([]float64, len(hints))
+	for i, h := range hints {
+		prices[i] = h.Price
+	}
+	sort.Float64s(prices)
+	k := int(float64(len(prices)) * quantile)
+	if k < 1 {
+		k = 1
+	}
+	low, high := prices[k-1], prices[len(prices)-k]
+	if low >= high {
+		// 가격 변동이 없으면 모두 hold
+		return hints, nil
+	}
+
+	for i := range hints {
+		switch {
+		case hints[i].Price <= low:
+			hints[i].Action = "charge"
+		case hints[i].Price >= high:
+			hints[i].Action = "discharge"
+		}
+	}
+	return hints, nil
+}
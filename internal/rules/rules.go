@@ -0,0 +1,101 @@
+/*
+ * 규칙 엔진 : 텔레메트리 값을 조건과 비교하여 내려야 할 제어 명령을 결정합니다.
+ *  - Rule   : "필드 값이 임계치를 넘으면 이 명령을 내린다" 형태의 조건 하나
+ *  - Policy : 규칙 목록 + 명령 최소 간격
+ *  - 규칙은 선언된 순서대로 평가하며, 처음 일치한 규칙의 명령을 사용합니다.
+ *  - 평가 주체가 샘플에 "price" 필드(kWh당 단가)를 넣어 주면 가격 기반 규칙도 작성할 수 있습니다.
+ *      예) {"field":"price","op":"lt","threshold":0.1,"action":"charge","kw10":50}
+ */
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"generic-api-scaffold/internal/telemetry" // 평가 대상 샘플
+)
+
+/*
+ * Rule : 조건 하나
+ *  - Field     : 비교할 필드 이름 (예: "soc", "temp", "price")
+ *  - Op        : gt | gte | lt | lte | eq
+ *  - Threshold : 비교 기준 값
+ *  - Action    : 조건 일치 시 내릴 명령 (charge|discharge|on|off ...)
+ *  - KW10      : 명령 출력 (kW*10)
+ */
+type Rule struct {
+	Name      string  `json:"name"`
+	Field     string  `json:"field"`
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+	Action    string  `json:"action"`
+	KW10      int     `json:"kw10"`
+}
+
+/*
+ * Policy : 규칙 묶음
+ *  - MinInterval : 같은 장치에 명령을 다시 내리기까지의 최소 간격 (0이면 제한 없음)
+ */
+type Policy struct {
+	Rules       []Rule        `json:"rules"`
+	MinInterval time.Duration `json:"min_interval_ns"`
+}
+
+// Decision : 규칙 평가 결과로 내려질 명령
+type Decision struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	KW10   int    `json:"kw10"`
+}
+
+/*
+ * Validate : 정책의 규칙들이 올바른지 검사
+ */
+func (p Policy) Validate() error {
+	for i, r := range p.Rules {
+		if r.Field == "" || r.Action == "" {
+			return fmt.Errorf("rule %d: field and action are required", i)
+		}
+		switch r.Op {
+		case "gt", "gte", "lt", "lte", "eq":
+		default:
+			return fmt.Errorf("rule %d: unsupported op %q", i, r.Op)
+		}
+	}
+	return nil
+}
+
+/*
+ * Evaluate : 샘플 하나에 대해 규칙을 순서대로 평가
+ *  - 일치한 규칙이 있으면 (Decision, true), 없으면 (Decision{}, false)
+ *  - 샘플에 해당 필드가 없으면 그 규칙은 건너뜁니다.
+ */
+func (p Policy) Evaluate(s telemetry.Sample) (Decision, bool) {
+	for _, r := range p.Rules {
+		v, ok := s.Values[r.Field]
+		if !ok {
+			continue
+		}
+		if match(r.Op, v, r.Threshold) {
+			return Decision{Rule: r.Name, Action: r.Action, KW10: r.KW10}, true
+		}
+	}
+	return Decision{}, false
+}
+
+// match : 연산자에 따라 값 비교
+func match(op string, v, threshold float64) bool {
+	switch op {
+	case "gt":
+		return v > threshold
+	case "gte":
+		return v >= threshold
+	case "lt":
+		return v < threshold
+	case "lte":
+		return v <= threshold
+	case "eq":
+		return v == threshold
+	}
+	return false
+}
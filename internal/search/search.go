@@ -0,0 +1,131 @@
+/*
+ * 경량 전문 검색(full-text search)
+ *  - 장치, 제어 명령, 경보처럼 메모리에 있는 자산을 검색어로 찾기 위한 작은 검색기입니다.
+ *  - 별도 인덱스를 유지하지 않고, 질의할 때마다 각 Source가 제공하는 문서를 훑어 점수를 매깁니다.
+ *    (자산 수백~수천 개 규모에서는 충분히 빠르며, 저장소/외부 검색 엔진 의존이 없습니다)
+ *  - 검색어를 공백으로 나눈 모든 단어가 문서(제목 또는 본문)에 포함되어야 일치로 봅니다. (대소문자 무시)
+ */
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+ * Document : 검색 대상 문서 하나
+ *  - Kind  : 문서 종류 ("device" | "command" | "alert" ...)
+ *  - ID    : 종류 안에서의 식별자 (장치 ID, 명령 ID, 규칙 이름 등)
+ *  - Title : 결과 목록에 보여 줄 짧은 제목 (일치 시 가중치가 더 높음)
+ *  - Text  : 검색 대상 본문 (메타데이터, 명령 내용, 경보 메시지 등)
+ */
+type Document struct {
+	Kind  string `json:"kind"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+/*
+ * Source : 검색 문서 공급원
+ *  - 각 구성요소가 fx 값 그룹(group:"search")으로 등록
+ */
+type Source struct {
+	Kind      string
+	Documents func() []Document
+}
+
+// Hit : 검색 결과 하나
+type Hit struct {
+	Document
+	Score int `json:"score"`
+}
+
+/*
+ * Index : 등록된 Source들을 대상으로 검색
+ */
+type Index struct {
+	sources []Source
+}
+
+// NewIndex : Source 목록으로 Index 생성
+func NewIndex(sources []Source) *Index {
+	return &Index{sources: sources}
+}
+
+// Kinds : 검색 가능한 문서 종류 목록
+func (x *Index) Kinds() []string {
+	out := make([]string, 0, len(x.sources))
+	for _, s := range x.sources {
+		out = append(out, s.Kind)
+	}
+	sort.Strings(out)
+	return out
+}
+
+/*
+ * Search : 검색어와 일치하는 문서를 점수 순으로 최대 limit개 반환
+ *  - kinds가 비어 있지 않으면 해당 종류의 Source만 검색
+ *  - 점수 : 단어마다 제목 일치 3점 + 본문 등장 횟수 (ID와 정확히 같으면 10점 추가)
+ *  - 점수가 같으면 종류, ID 순
+ */
+func (x *Index) Search(query string, kinds []string, limit int) []Hit {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	want := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		want[k] = true
+	}
+
+	var hits []Hit
+	for _, src := range x.sources {
+		if len(want) > 0 && !want[src.Kind] {
+			continue
+		}
+		for _, doc := range src.Documents() {
+			if score, ok := scoreDoc(doc, terms); ok {
+				hits = append(hits, Hit{Document: doc, Score: score})
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Kind != hits[j].Kind {
+			return hits[i].Kind < hits[j].Kind
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// scoreDoc : 모든 단어가 포함되면 (점수, true)
+func scoreDoc(doc Document, terms []string) (int, bool) {
+	id := strings.ToLower(doc.ID)
+	title := strings.ToLower(doc.Title)
+	text := strings.ToLower(doc.Text)
+
+	score := 0
+	for _, t := range terms {
+		inTitle := strings.Contains(title, t) || strings.Contains(id, t)
+		n := strings.Count(text, t)
+		if !inTitle && n == 0 {
+			return 0, false
+		}
+		if inTitle {
+			score += 3
+		}
+		if t == id {
+			score += 10
+		}
+		score += n
+	}
+	return score, true
+}
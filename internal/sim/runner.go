@@ -0,0 +1,190 @@
+/*
+ * Runner : 과거 텔레메트리를 이용한 what-if 시뮬레이터
+ *  - 저장소에 기록된 시간 구간의 샘플을 순서대로 재생하며 후보 정책(rules.Policy)을 평가합니다.
+ *  - 정책이 내렸을 명령 목록과, 그 명령으로 인한 충전/방전 에너지 및 비용 추정치를 보고합니다.
+ *  - 비용은 가격 피드(price.Feed)의 구간별 단가로 계산하며, 규칙에서는 "price" 필드로 단가를 참조할 수 있습니다.
+ *  - 실제 장치에는 어떤 명령도 보내지 않습니다. (Dispatcher를 사용하지 않음)
+ */
+package sim
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/price"     // 구간별 전력 단가
+	"generic-api-scaffold/internal/rules"     // 후보 정책
+	"generic-api-scaffold/internal/telemetry" // 재생할 샘플
+)
+
+/*
+ * Source : 과거 텔레메트리 조회 인터페이스
+ *  - Window : 장치 하나의 [from, to) 구간 샘플을 시간순으로 반환
+ *  - 구현 : *infra.InfluxRepo (1.x), *infra.Influx2Repo (2.x), *infra.Influx3Repo (3)
+ */
+type Source interface {
+	Window(ctx context.Context, deviceID string, from, to time.Time) ([]telemetry.Sample, error)
+}
+
+/*
+ * Request : 시뮬레이션 요청
+ *  - PricePerKWh : 고정 단가 (0보다 크면 가격 피드 대신 사용, 충전은 비용·방전은 수익으로 계산)
+ */
+type Request struct {
+	DeviceID    string       `json:"device"`
+	From        time.Time    `json:"from"`
+	To          time.Time    `json:"to"`
+	Policy      rules.Policy `json:"policy"`
+	PricePerKWh float64      `json:"price_per_kwh"`
+}
+
+// SimCommand : 정책이 해당 시각에 내렸을 명령
+type SimCommand struct {
+	Time time.Time `json:"time"`
+	rules.Decision
+}
+
+/*
+ * IntervalCost : 샘플 사이 구간 하나의 에너지/비용
+ *  - KWh  : 충전이면 양수, 방전이면 음수
+ *  - Cost : KWh * Price (방전 구간은 음수 = 수익)
+ */
+type IntervalCost struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Action string    `json:"action,omitempty"`
+	KWh    float64   `json:"kwh"`
+	Price  float64   `json:"price"`
+	Cost   float64   `json:"cost"`
+}
+
+/*
+ * Report : 시뮬레이션 결과
+ *  - ChargedKWh / DischargedKWh : 명령에 따라 충전/방전되었을 에너지 추정치
+ *  - NetKWh                     : 충전 - 방전
+ *  - EstimatedCost              : 충전 비용 - 방전 수익 (구간별 단가 합계)
+ *  - Intervals                  : 구간별 에너지/단가/비용
+ */
+type Report struct {
+	DeviceID      string         `json:"device"`
+	Samples       int            `json:"samples"`
+	Commands      []SimCommand   `json:"commands"`
+	ChargedKWh    float64        `json:"charged_kwh"`
+	DischargedKWh float64        `json:"discharged_kwh"`
+	NetKWh        float64        `json:"net_kwh"`
+	EstimatedCost float64        `json:"estimated_cost"`
+	Intervals     []IntervalCost `json:"intervals"`
+}
+
+// Runner 구조체
+type Runner struct {
+	log  *zap.Logger
+	src  Source
+	feed price.Feed
+}
+
+/*
+ * NewRunner : fx가 호출하는 Runner 생성자
+ */
+func NewRunner(log *zap.Logger, src Source, feed price.Feed) *Runner {
+	return &Runner{log: log, src: src, feed: feed}
+}
+
+/*
+ * Run : 시뮬레이션 실행
+ *  - 내부 동작 :
+ *     ① 저장소에서 [From, To) 구간 샘플 조회
+ *     ② 샘플 시각의 단가를 "price" 필드로 추가하여 정책을 평가,
+ *        직전 명령과 다르고 최소 간격이 지났으면 명령 발행으로 기록
+ *     ③ 다음 샘플까지 현재 명령의 출력이 유지된다고 보고 에너지(kWh)와 비용을 적산
+ */
+func (r *Runner) Run(ctx context.Context, req Request) (Report, error) {
+	if req.DeviceID == "" {
+		return Report{}, errors.New("device is required")
+	}
+	if !req.To.After(req.From) {
+		return Report{}, errors.New("to must be after from")
+	}
+	if err := req.Policy.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	samples, err := r.src.Window(ctx, req.DeviceID, req.From, req.To)
+	if err != nil {
+		return Report{}, err
+	}
+
+	rep := Report{DeviceID: req.DeviceID, Samples: len(samples), Commands: []SimCommand{}, Intervals: []IntervalCost{}}
+	var current rules.Decision
+	var lastIssued time.Time
+
+	for i, s := range samples {
+		unit, err := r.priceAt(ctx, req, s.Time)
+		if err != nil {
+			return Report{}, err
+		}
+
+		// ② 정책 평가 및 명령 발행 여부 판단 (원본 샘플을 바꾸지 않도록 복사 후 price 추가)
+		values := make(map[string]float64, len(s.Values)+1)
+		for k, v := range s.Values {
+			values[k] = v
+		}
+		values["price"] = unit
+		s.Values = values
+
+		if d, ok := req.Policy.Evaluate(s); ok && d != current {
+			cooled := lastIssued.IsZero() || s.Time.Sub(lastIssued) >= req.Policy.MinInterval
+			if cooled {
+				rep.Commands = append(rep.Commands, SimCommand{Time: s.Time, Decision: d})
+				current, lastIssued = d, s.Time
+			}
+		}
+
+		// ③ 다음 샘플(마지막이면 구간 끝)까지 에너지 적산
+		end := req.To
+		if i+1 < len(samples) {
+			end = samples[i+1].Time
+		}
+		kwh := float64(current.KW10) / 10 * end.Sub(s.Time).Hours()
+		switch current.Action {
+		case "charge":
+			rep.ChargedKWh += kwh
+		case "discharge":
+			rep.DischargedKWh += kwh
+			kwh = -kwh
+		default:
+			kwh = 0
+		}
+		rep.Intervals = append(rep.Intervals, IntervalCost{
+			Start: s.Time, End: end, Action: current.Action,
+			KWh: kwh, Price: unit, Cost: kwh * unit,
+		})
+		rep.EstimatedCost += kwh * unit
+	}
+
+	rep.NetKWh = rep.ChargedKWh - rep.DischargedKWh
+
+	r.log.Info("simulation finished",
+		zap.String("device", req.DeviceID),
+		zap.Int("samples", rep.Samples),
+		zap.Int("commands", len(rep.Commands)))
+	return rep, nil
+}
+
+/*
+ * priceAt : 시각 t의 단가
+ *  - 요청에 고정 단가가 있으면 그 값을, 없으면 가격 피드 값을 사용
+ *  - 가격 피드에 해당 시각 정보가 없으면 0으로 간주
+ */
+func (r *Runner) priceAt(ctx context.Context, req Request, t time.Time) (float64, error) {
+	if req.PricePerKWh > 0 {
+		return req.PricePerKWh, nil
+	}
+	p, err := r.feed.Price(ctx, t)
+	if errors.Is(err, price.ErrNoPrice) {
+		return 0, nil
+	}
+	return p, err
+}
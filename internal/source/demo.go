@@ -0,0 +1,30 @@
+/*
+ * Demo : 고정 샘플(temp=23.5)을 돌려주는 예제 공급원
+ *  - 실제 공급원이 없는 개발 환경에서도 수집 → 버스 → 저장 파이프라인이 돌도록 기본으로 등록됩니다.
+ *  - 실제 공급원을 등록했다면 APP_COLLECTOR_SOURCES에서 빼서 끕니다. (예: APP_COLLECTOR_SOURCES=modbus)
+ */
+package source
+
+import (
+	"context"
+
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
+)
+
+// 예제 공급원이 보고하는 장치
+const demoDeviceID = "A1"
+
+// Demo : 예제 공급원
+type Demo struct{}
+
+// NewDemo : fx가 호출하는 예제 공급원 생성자
+func NewDemo() Source {
+	return Demo{}
+}
+
+func (Demo) Name() string { return "demo" }
+
+// Collect : 장치 A1의 샘플 데이터 하나
+func (Demo) Collect(ctx context.Context) ([]telemetry.Sample, error) {
+	return []telemetry.Sample{{DeviceID: demoDeviceID, Values: map[string]float64{"temp": 23.5}}}, nil
+}
@@ -0,0 +1,253 @@
+/*
+ * HTTP 폴링 : 제조사 클라우드 API나 장치 내장 웹 서버의 JSON 응답에서 측정값을 꺼내는 수집 공급원
+ *  - APP_HTTP_SOURCE_CONFIG_FILE : 엔드포인트 목록 JSON 파일 (비어 있으면 엔드포인트 없음, 수집 결과 없음)
+ *      {"endpoints": [
+ *        {"name": "vendor", "url": "https://api.vendor.example/v1/sites/{device}/live", "devices": ["INV1", "INV2"],
+ *         "headers": {"Authorization": "Bearer ${VENDOR_TOKEN}"}, "interval": "1m", "timeout": "10s",
+ *         "fields": {"ac_power": "data.ac.power", "soc": "battery.soc"}, "time": "data.timestamp"},
+ *        {"name": "meter", "url": "http://192.168.1.20/status.json", "devices": ["MTR1"],
+ *         "username": "admin", "password": "${METER_PASSWORD}", "fields": {"grid_w": "emeters.0.power"}}
+ *      ]}
+ *  - 엔드포인트 항목 :
+ *      url      : 요청 URL 템플릿 ({device}는 장치 ID로 치환, 경로 이스케이프)
+ *      devices  : 이 엔드포인트로 읽는 장치 ID 목록 (장치마다 요청 하나, URL에 {device}가 없으면 장치는 하나만)
+ *      method   : 요청 메서드 (기본 GET), body : 요청 본문 ({device} 치환)
+ *      headers, username/password : 인증 헤더, 기본 인증 (값의 ${VAR}는 환경변수로 치환하여 비밀 값을 파일에 두지 않음)
+ *      fields   : 필드 이름 → gjson 경로 (github.com/tidwall/gjson 문법, 숫자·불리언 0/1, 없는 경로는 건너뜀)
+ *      time     : 측정 시각 gjson 경로 (RFC3339 문자열 또는 유닉스 초, 비어 있으면 받은 시각)
+ *      interval : 폴링 간격 (기본 0 = 수집 주기마다, 수집 주기보다 짧게 줄 수는 없음)
+ *      timeout  : 요청 하나의 제한 시간 (기본 APP_HTTP_SOURCE_TIMEOUT, 10s)
+ *  - 2xx가 아닌 응답, JSON이 아닌 본문, 꺼낸 필드가 하나도 없는 응답은 그 장치의 수집 실패 (나머지 장치는 그대로)
+ */
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson" // JSON 경로로 값 꺼내기
+	"go.uber.org/zap"          // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
+)
+
+// 응답 본문 최대 크기 (잘못된 URL이 큰 파일을 돌려줘도 메모리를 지킴)
+const httpPollMaxBody = 4 << 20
+
+// httpEndpoint : 엔드포인트 하나의 설정과 폴링 상태
+type httpEndpoint struct {
+	Name     string            `json:"name"`
+	URL      string            `json:"url"`
+	Devices  []string          `json:"devices"`
+	Method   string            `json:"method"`
+	Body     string            `json:"body"`
+	Headers  map[string]string `json:"headers"`
+	Username string            `json:"username"`
+	Password string            `json:"password"`
+	Fields   map[string]string `json:"fields"`
+	Time     string            `json:"time"`
+	Interval string            `json:"interval"`
+	Timeout  string            `json:"timeout"`
+
+	interval time.Duration
+	timeout  time.Duration
+	next     time.Time // 다음 폴링 시각
+}
+
+// HTTPPoll : HTTP 폴링 수집 공급원
+type HTTPPoll struct {
+	log       *zap.Logger
+	client    *http.Client
+	endpoints []*httpEndpoint
+
+	mu sync.Mutex // 수집 루프와 수동 수집이 동시에 폴링하지 않도록 (다음 폴링 시각 보호)
+}
+
+/*
+ * NewHTTPPoll : fx가 호출하는 HTTP 폴링 공급원 생성자
+ *  - APP_HTTP_SOURCE_TIMEOUT : 엔드포인트에 timeout이 없을 때의 요청 제한 시간 (기본 10s)
+ *  - 설정 파일 형식 오류, 잘못된 간격·제한 시간, {device} 없는 URL에 장치가 여럿인 엔드포인트는 Fatal
+ */
+func NewHTTPPoll(log *zap.Logger) Source {
+	p := &HTTPPoll{log: log, client: &http.Client{}}
+	path := config.String("APP_HTTP_SOURCE_CONFIG_FILE", "")
+	if path == "" {
+		return p
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("failed to read APP_HTTP_SOURCE_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+	var file struct {
+		Endpoints []*httpEndpoint `json:"endpoints"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Fatal("invalid APP_HTTP_SOURCE_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+	timeout := config.Duration(log, "APP_HTTP_SOURCE_TIMEOUT", 10*time.Second)
+	for _, e := range file.Endpoints {
+		if err := e.validate(timeout); err != nil {
+			log.Fatal("invalid APP_HTTP_SOURCE_CONFIG_FILE endpoint", zap.String("endpoint", e.Name), zap.Error(err))
+		}
+	}
+	p.endpoints = file.Endpoints
+	log.Info("http polling source configured", zap.String("path", path), zap.Int("endpoints", len(p.endpoints)))
+	return p
+}
+
+// validate : 엔드포인트 설정 검사 및 기본값 채움 (헤더·인증 값의 ${VAR}는 여기서 치환)
+func (e *httpEndpoint) validate(timeout time.Duration) error {
+	if e.Name == "" || e.URL == "" {
+		return errors.New("name and url are required")
+	}
+	if len(e.Devices) == 0 {
+		return errors.New("no devices")
+	}
+	if !strings.Contains(e.URL, "{device}") && len(e.Devices) > 1 {
+		return errors.New("url has no {device} placeholder but lists more than one device")
+	}
+	if len(e.Fields) == 0 {
+		return errors.New("no fields")
+	}
+	if e.Method == "" {
+		e.Method = http.MethodGet
+	}
+	e.timeout = timeout
+	if e.Timeout != "" {
+		t, err := time.ParseDuration(e.Timeout)
+		if err != nil || t <= 0 {
+			return fmt.Errorf("invalid timeout %q", e.Timeout)
+		}
+		e.timeout = t
+	}
+	if e.Interval != "" {
+		d, err := time.ParseDuration(e.Interval)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid interval %q", e.Interval)
+		}
+		e.interval = d
+	}
+	for k, v := range e.Headers {
+		e.Headers[k] = os.ExpandEnv(v)
+	}
+	e.Username, e.Password = os.ExpandEnv(e.Username), os.ExpandEnv(e.Password)
+	return nil
+}
+
+func (p *HTTPPoll) Name() string { return "http" }
+
+/*
+ * Collect : 폴링할 때가 된 엔드포인트마다 장치별로 요청하여 샘플 하나씩
+ *  - 실패한 장치는 경고 로그를 남기고 에러로 모아 반환 (다음 폴링은 간격대로)
+ */
+func (p *HTTPPoll) Collect(ctx context.Context) ([]telemetry.Sample, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	var samples []telemetry.Sample
+	var errs []error
+	for _, e := range p.endpoints {
+		if now.Before(e.next) {
+			continue
+		}
+		e.next = now.Add(e.interval)
+		for _, device := range e.Devices {
+			if err := ctx.Err(); err != nil {
+				return samples, err
+			}
+			s, err := p.poll(ctx, e, device)
+			if err != nil {
+				p.log.Warn("http source poll failed", zap.String("endpoint", e.Name), zap.String("device", device), zap.Error(err))
+				errs = append(errs, fmt.Errorf("endpoint %s device %s: %w", e.Name, device, err))
+				continue
+			}
+			samples = append(samples, s)
+		}
+	}
+	return samples, errors.Join(errs...)
+}
+
+// poll : 장치 하나의 요청과 필드 추출
+func (p *HTTPPoll) poll(ctx context.Context, e *httpEndpoint, device string) (telemetry.Sample, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	var body io.Reader
+	if e.Body != "" {
+		body = strings.NewReader(strings.ReplaceAll(e.Body, "{device}", device))
+	}
+	req, err := http.NewRequestWithContext(ctx, e.Method, strings.ReplaceAll(e.URL, "{device}", url.PathEscape(device)), body)
+	if err != nil {
+		return telemetry.Sample{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "generic-api-scaffold")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	if e.Username != "" {
+		req.SetBasicAuth(e.Username, e.Password)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return telemetry.Sample{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) // 서버가 알려 주는 실패 이유
+		return telemetry.Sample{}, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, httpPollMaxBody))
+	if err != nil {
+		return telemetry.Sample{}, err
+	}
+	if !gjson.ValidBytes(data) {
+		return telemetry.Sample{}, errors.New("response is not valid JSON")
+	}
+	return e.extract(device, data)
+}
+
+// extract : 응답 본문 → 샘플 (시각 경로가 없거나 값이 없으면 받은 시각)
+func (e *httpEndpoint) extract(device string, data []byte) (telemetry.Sample, error) {
+	s := telemetry.Sample{DeviceID: device, Time: time.Now(), Values: make(map[string]float64, len(e.Fields))}
+	for field, path := range e.Fields {
+		r := gjson.GetBytes(data, path)
+		switch r.Type {
+		case gjson.Number:
+			s.Values[field] = r.Float()
+		case gjson.True:
+			s.Values[field] = 1
+		case gjson.False:
+			s.Values[field] = 0
+		}
+	}
+	if len(s.Values) == 0 {
+		return s, errors.New("no configured field found in response")
+	}
+	if e.Time != "" {
+		switch r := gjson.GetBytes(data, e.Time); r.Type {
+		case gjson.String:
+			at, err := time.Parse(time.RFC3339Nano, r.Str)
+			if err != nil {
+				return s, fmt.Errorf("time %q: %w", r.Str, err)
+			}
+			s.Time = at
+		case gjson.Number:
+			s.Time = time.Unix(0, int64(r.Num*1e9))
+		}
+	}
+	return s, nil
+}
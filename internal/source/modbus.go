@@ -0,0 +1,360 @@
+//go:build !edge
+
+/*
+ * Modbus : 인버터·계량기 등 Modbus TCP / RTU 장치의 레지스터를 읽는 수집 공급원 (기본 빌드 전용)
+ *  - APP_MODBUS_CONFIG_FILE : 장치별 레지스터 맵 JSON 파일 (비어 있으면 장치 없음, 수집 결과 없음)
+ *      {"devices": [
+ *        {"id": "INV1", "transport": "tcp", "address": "10.0.0.5:502", "unit": 1,
+ *         "registers": [{"field": "ac_power", "table": "holding", "address": 40083, "type": "int16", "scale": 0.1},
+ *                       {"field": "energy", "table": "input", "address": 30529, "type": "uint32", "word_order": "little"}]},
+ *        {"id": "MTR1", "transport": "rtu", "address": "/dev/ttyUSB0", "unit": 3, "baud_rate": 9600, "parity": "E",
+ *         "registers": [{"field": "grid_on", "table": "coil", "address": 0}]}
+ *      ]}
+ *  - 레지스터 항목 :
+ *      field      : 샘플 필드 이름
+ *      table      : holding (기본) | input | coil | discrete (coil·discrete는 0/1)
+ *      address    : 0부터 시작하는 프로토콜 주소 (장치 문서의 4xxxx 표기가 아님)
+ *      type       : int16 | uint16 (기본) | int32 | uint32 | float32 | int64 | uint64 | float64 (coil·discrete는 무시)
+ *      word_order : big (기본, 상위 워드 먼저) | little
+ *      scale, offset : 값 = 원시값 × scale + offset (scale 기본 1)
+ *  - 연결 풀 : 같은 주소(TCP 게이트웨이, 시리얼 포트)의 장치는 연결 하나를 공유하며, 요청은 연결마다 한 번에 하나씩 보냄
+ *      연결은 처음 읽을 때 맺고, APP_MODBUS_IDLE_TIMEOUT 동안 쓰지 않으면 닫으며, 읽기 에러가 나면 닫아 다음 수집에서 다시 맺음
+ *  - APP_MODBUS_TIMEOUT : 요청 하나의 응답 제한 시간 (기본 2s, 장치별 "timeout"으로 바꿀 수 있음)
+ *  - 장치 하나를 읽지 못해도 나머지 장치는 수집하며, 한 장치 안에서 레지스터 하나라도 실패하면 그 장치의 샘플은 버림 (반쪽 샘플 방지)
+ */
+package source
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/goburrow/modbus" // Modbus TCP / RTU 클라이언트
+	"go.uber.org/fx"             // 연결 정리 라이프사이클
+	"go.uber.org/zap"            // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
+)
+
+// modbusRegister : 레지스터 맵 항목 하나
+type modbusRegister struct {
+	Field     string  `json:"field"`
+	Table     string  `json:"table"`
+	Address   uint16  `json:"address"`
+	Type      string  `json:"type"`
+	WordOrder string  `json:"word_order"`
+	Scale     float64 `json:"scale"`
+	Offset    float64 `json:"offset"`
+}
+
+// modbusDevice : 장치 하나의 연결 정보와 레지스터 맵
+type modbusDevice struct {
+	ID        string           `json:"id"`
+	Transport string           `json:"transport"` // tcp | rtu
+	Address   string           `json:"address"`   // host:port | 시리얼 장치 경로
+	Unit      byte             `json:"unit"`      // 슬레이브(유닛) ID
+	Timeout   string           `json:"timeout"`   // 비어 있으면 APP_MODBUS_TIMEOUT
+	BaudRate  int              `json:"baud_rate"` // RTU (기본 9600)
+	DataBits  int              `json:"data_bits"` // RTU (기본 8)
+	StopBits  int              `json:"stop_bits"` // RTU (기본 1)
+	Parity    string           `json:"parity"`    // RTU N | E | O (기본 N)
+	Registers []modbusRegister `json:"registers"`
+
+	timeout time.Duration
+	conn    *modbusConn
+}
+
+// 레지스터 타입별 워드(16비트) 수
+var modbusTypeWords = map[string]uint16{
+	"int16": 1, "uint16": 1, "int32": 2, "uint32": 2, "float32": 2, "int64": 4, "uint64": 4, "float64": 4,
+}
+
+/*
+ * modbusConn : 주소 하나의 공유 연결 (풀 항목)
+ *  - mu로 요청을 직렬화하고, 요청마다 유닛 ID와 제한 시간을 장치 값으로 바꿈
+ */
+type modbusConn struct {
+	mu      sync.Mutex
+	handler interface {
+		Close() error
+	}
+	client    modbus.Client
+	configure func(unit byte, timeout time.Duration) // 요청 전 유닛 ID·제한 시간 지정
+}
+
+// Modbus : Modbus 수집 공급원
+type Modbus struct {
+	log     *zap.Logger
+	devices []*modbusDevice
+	conns   map[string]*modbusConn // 연결 키(transport + 주소) → 공유 연결
+}
+
+/*
+ * NewModbus : fx가 호출하는 Modbus 공급원 생성자
+ *  - 설정 파일 형식 오류, 알 수 없는 table/type/word_order, 같은 시리얼 포트의 통신 설정 불일치는 Fatal
+ *  - OnStop 시 모든 연결을 닫음
+ */
+func NewModbus(lc fx.Lifecycle, log *zap.Logger) Source {
+	m := &Modbus{log: log, conns: make(map[string]*modbusConn)}
+	path := config.String("APP_MODBUS_CONFIG_FILE", "")
+	if path == "" {
+		return m
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("failed to read APP_MODBUS_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+	var file struct {
+		Devices []*modbusDevice `json:"devices"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Fatal("invalid APP_MODBUS_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+
+	timeout := config.Duration(log, "APP_MODBUS_TIMEOUT", 2*time.Second)
+	idle := config.Duration(log, "APP_MODBUS_IDLE_TIMEOUT", time.Minute)
+	serial := make(map[string]string) // 시리얼 포트 → 통신 설정 (같은 포트의 장치끼리 같아야 함)
+	for _, d := range file.Devices {
+		if err := d.validate(timeout); err != nil {
+			log.Fatal("invalid APP_MODBUS_CONFIG_FILE device", zap.String("device", d.ID), zap.Error(err))
+		}
+		key := d.Transport + "://" + d.Address
+		if d.Transport == "rtu" {
+			settings := fmt.Sprintf("%d/%d/%s/%d", d.BaudRate, d.DataBits, d.Parity, d.StopBits)
+			if prev, ok := serial[d.Address]; ok && prev != settings {
+				log.Fatal("modbus devices on the same serial port must share settings",
+					zap.String("port", d.Address), zap.String("device", d.ID), zap.String("settings", settings), zap.String("expected", prev))
+			}
+			serial[d.Address] = settings
+		}
+		if m.conns[key] == nil {
+			m.conns[key] = newModbusConn(d, idle)
+		}
+		d.conn = m.conns[key]
+		m.devices = append(m.devices, d)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			for _, c := range m.conns {
+				c.close()
+			}
+			return nil
+		},
+	})
+	log.Info("modbus source configured", zap.String("path", path), zap.Int("devices", len(m.devices)), zap.Int("connections", len(m.conns)))
+	return m
+}
+
+// validate : 장치 설정 검사 및 기본값 채움
+func (d *modbusDevice) validate(timeout time.Duration) error {
+	if d.ID == "" || d.Address == "" {
+		return errors.New("id and address are required")
+	}
+	switch d.Transport {
+	case "", "tcp":
+		d.Transport = "tcp"
+	case "rtu":
+		if d.BaudRate == 0 {
+			d.BaudRate = 9600
+		}
+		if d.DataBits == 0 {
+			d.DataBits = 8
+		}
+		if d.StopBits == 0 {
+			d.StopBits = 1
+		}
+		switch d.Parity {
+		case "":
+			d.Parity = "N"
+		case "N", "E", "O":
+		default:
+			return fmt.Errorf("invalid parity %q, expected N|E|O", d.Parity)
+		}
+	default:
+		return fmt.Errorf("invalid transport %q, expected tcp|rtu", d.Transport)
+	}
+	d.timeout = timeout
+	if d.Timeout != "" {
+		t, err := time.ParseDuration(d.Timeout)
+		if err != nil || t <= 0 {
+			return fmt.Errorf("invalid timeout %q", d.Timeout)
+		}
+		d.timeout = t
+	}
+	if len(d.Registers) == 0 {
+		return errors.New("no registers")
+	}
+	for i := range d.Registers {
+		r := &d.Registers[i]
+		if r.Field == "" {
+			return fmt.Errorf("register at address %d has no field", r.Address)
+		}
+		switch r.Table {
+		case "":
+			r.Table = "holding"
+		case "holding", "input", "coil", "discrete":
+		default:
+			return fmt.Errorf("register %s: invalid table %q, expected holding|input|coil|discrete", r.Field, r.Table)
+		}
+		if r.Type == "" {
+			r.Type = "uint16"
+		}
+		if _, ok := modbusTypeWords[r.Type]; !ok {
+			return fmt.Errorf("register %s: invalid type %q", r.Field, r.Type)
+		}
+		switch r.WordOrder {
+		case "":
+			r.WordOrder = "big"
+		case "big", "little":
+		default:
+			return fmt.Errorf("register %s: invalid word_order %q, expected big|little", r.Field, r.WordOrder)
+		}
+		if r.Scale == 0 {
+			r.Scale = 1
+		}
+	}
+	return nil
+}
+
+// newModbusConn : 장치 d의 주소로 연결 하나 (실제 연결은 첫 요청 때)
+func newModbusConn(d *modbusDevice, idle time.Duration) *modbusConn {
+	if d.Transport == "rtu" {
+		h := modbus.NewRTUClientHandler(d.Address)
+		h.BaudRate, h.DataBits, h.StopBits, h.Parity = d.BaudRate, d.DataBits, d.StopBits, d.Parity
+		h.IdleTimeout = idle
+		return &modbusConn{handler: h, client: modbus.NewClient(h),
+			configure: func(unit byte, timeout time.Duration) { h.SlaveId, h.Timeout = unit, timeout }}
+	}
+	h := modbus.NewTCPClientHandler(d.Address)
+	h.IdleTimeout = idle
+	return &modbusConn{handler: h, client: modbus.NewClient(h),
+		configure: func(unit byte, timeout time.Duration) { h.SlaveId, h.Timeout = unit, timeout }}
+}
+
+// close : 연결을 닫음 (다음 요청이 다시 맺음)
+func (c *modbusConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.handler.Close()
+}
+
+func (m *Modbus) Name() string { return "modbus" }
+
+/*
+ * Collect : 설정된 장치마다 레지스터 맵을 읽어 샘플 하나씩
+ *  - 읽지 못한 장치는 경고 로그를 남기고 에러로 모아 반환
+ */
+func (m *Modbus) Collect(ctx context.Context) ([]telemetry.Sample, error) {
+	samples := make([]telemetry.Sample, 0, len(m.devices))
+	var errs []error
+	for _, d := range m.devices {
+		if err := ctx.Err(); err != nil {
+			return samples, err
+		}
+		values, err := d.read()
+		if err != nil {
+			m.log.Warn("modbus read failed", zap.String("device", d.ID), zap.String("address", d.Address), zap.Error(err))
+			errs = append(errs, fmt.Errorf("device %s: %w", d.ID, err))
+			continue
+		}
+		samples = append(samples, telemetry.Sample{DeviceID: d.ID, Time: time.Now(), Values: values})
+	}
+	return samples, errors.Join(errs...)
+}
+
+// read : 장치의 레지스터를 모두 읽음 (공유 연결을 잡은 채로, 실패하면 연결을 닫음)
+func (d *modbusDevice) read() (map[string]float64, error) {
+	c := d.conn
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.configure(d.Unit, d.timeout)
+
+	values := make(map[string]float64, len(d.Registers))
+	for _, r := range d.Registers {
+		v, err := r.read(c.client)
+		if err != nil {
+			_ = c.handler.Close() // 응답이 어긋났을 수 있으므로 다음 요청은 새 연결로
+			return nil, fmt.Errorf("%s (%s %d): %w", r.Field, r.Table, r.Address, err)
+		}
+		values[r.Field] = v*r.Scale + r.Offset
+	}
+	return values, nil
+}
+
+// read : 레지스터 하나를 읽어 원시값으로 변환
+func (r modbusRegister) read(c modbus.Client) (float64, error) {
+	switch r.Table {
+	case "coil", "discrete":
+		read := c.ReadCoils
+		if r.Table == "discrete" {
+			read = c.ReadDiscreteInputs
+		}
+		b, err := read(r.Address, 1)
+		if err != nil {
+			return 0, err
+		}
+		if len(b) < 1 {
+			return 0, errors.New("short response")
+		}
+		return float64(b[0] & 1), nil
+	}
+
+	words := modbusTypeWords[r.Type]
+	read := c.ReadHoldingRegisters
+	if r.Table == "input" {
+		read = c.ReadInputRegisters
+	}
+	b, err := read(r.Address, words)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) < int(words)*2 {
+		return 0, fmt.Errorf("short response: %d bytes", len(b))
+	}
+	b = b[:words*2]
+	if r.WordOrder == "little" {
+		b = swapWords(b)
+	}
+	return decodeRegister(r.Type, b), nil
+}
+
+// swapWords : 워드(2바이트) 순서를 뒤집은 사본 (워드 안의 바이트 순서는 그대로 빅 엔디언)
+func swapWords(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := 0; i < len(b); i += 2 {
+		j := len(b) - 2 - i
+		out[j], out[j+1] = b[i], b[i+1]
+	}
+	return out
+}
+
+// decodeRegister : 빅 엔디언 바이트 → 값
+func decodeRegister(typ string, b []byte) float64 {
+	switch typ {
+	case "int16":
+		return float64(int16(binary.BigEndian.Uint16(b)))
+	case "uint16":
+		return float64(binary.BigEndian.Uint16(b))
+	case "int32":
+		return float64(int32(binary.BigEndian.Uint32(b)))
+	case "uint32":
+		return float64(binary.BigEndian.Uint32(b))
+	case "float32":
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+	case "int64":
+		return float64(int64(binary.BigEndian.Uint64(b)))
+	case "uint64":
+		return float64(binary.BigEndian.Uint64(b))
+	case "float64":
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
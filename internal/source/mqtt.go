@@ -0,0 +1,254 @@
+//go:build !edge
+
+/*
+ * MQTT : 장치가 MQTT로 밀어 넣는 텔레메트리를 받는 수집 공급원 (별도 브리지 서비스 없이 수집, 기본 빌드 전용)
+ *  - 구독한 주제로 들어온 JSON 본문을 필드 맵으로 바꿔 버퍼에 쌓고, Collector가 수집 주기마다 꺼내 DataCollectedEvent로 발행합니다.
+ *    측정 시각은 본문의 시각 필드(없으면 받은 시각)이므로 수집 주기만큼 늦게 발행돼도 저장 시각은 그대로입니다.
+ *  - 본문 형식 : {"temp": 23.5, "on": true, "inverter": {"power": 1200}, "time": "2024-01-01T00:00:00Z"}
+ *      숫자는 그대로, 불리언은 0/1, 중첩 객체는 "inverter_power"처럼 밑줄로 이은 이름, 그 밖의 값(문자열, 배열, null)은 무시
+ *      시각 필드(APP_MQTT_SOURCE_TIME_FIELD)는 RFC3339 문자열 또는 유닉스 초(소수 허용)
+ *  - 장치 ID : 주제 패턴의 {device} 자리 (예: devices/{device}/telemetry), 패턴에 없으면 본문의 "device" 문자열
+ *  - 해석할 수 없는 본문과 장치 ID가 없는 메시지는 드롭 기록(source="mqtt_source", reason="validation")
+ *    버퍼(APP_MQTT_SOURCE_BUFFER)가 가득 차면 새 메시지를 버리고 드롭 기록(reason="backpressure")
+ *  - 수동 수집(POST /api/collect)도 버퍼를 꺼내므로, 그 사이 받은 메시지가 응답에 포함될 수 있습니다.
+ *    장치를 지정한 수동 수집이어도 함께 꺼낸 다른 장치의 샘플은 버리지 않고 발행합니다. (source.Buffered)
+ */
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang" // MQTT 클라이언트
+	"go.uber.org/fx"                           // 연결 라이프사이클
+	"go.uber.org/zap"                          // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 해석 실패·버퍼 넘침 기록
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
+)
+
+// mqttTopic : 구독 주제 패턴 하나 ({device} 자리를 +로 바꾼 필터와 그 위치)
+type mqttTopic struct {
+	filter string
+	device int // {device}가 있는 단계 (없으면 -1)
+}
+
+// MQTT : MQTT 구독 수집 공급원
+type MQTT struct {
+	log       *zap.Logger
+	drops     *drops.Recorder
+	topics    []mqttTopic
+	timeField string
+	max       int
+
+	mu      sync.Mutex
+	pending []telemetry.Sample // 다음 수집 때 꺼낼 샘플 (받은 순서)
+}
+
+/*
+ * NewMQTT : fx가 호출하는 MQTT 공급원 생성자
+ *  - APP_MQTT_SOURCE_BROKER     : 브로커 주소 (비어 있으면 비활성, 예: tcp://localhost:1883)
+ *  - APP_MQTT_SOURCE_TOPICS     : 구독할 주제 패턴, 쉼표 구분 (기본 "devices/{device}/telemetry", MQTT 와일드카드 + # 사용 가능)
+ *  - APP_MQTT_SOURCE_QOS        : 0 | 1 | 2 (기본 1)
+ *  - APP_MQTT_SOURCE_CLIENT_ID  : 클라이언트 ID (기본 "scaffold-source")
+ *  - APP_MQTT_SOURCE_USERNAME / APP_MQTT_SOURCE_PASSWORD : 인증 정보 (기본 없음)
+ *  - APP_MQTT_SOURCE_TIME_FIELD : 본문의 측정 시각 필드 (기본 "time")
+ *  - APP_MQTT_SOURCE_BUFFER     : 수집 주기 사이에 쌓아 둘 최대 샘플 수 (기본 10000)
+ *  - 브로커가 내려가 있어도 앱 시작을 막지 않고 백그라운드에서 재연결하며, 연결될 때마다 다시 구독
+ */
+func NewMQTT(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder) Source {
+	m := &MQTT{log: log, drops: dr,
+		timeField: config.String("APP_MQTT_SOURCE_TIME_FIELD", "time"),
+		max:       config.Int(log, "APP_MQTT_SOURCE_BUFFER", 10000)}
+	broker := config.String("APP_MQTT_SOURCE_BROKER", "")
+	if broker == "" {
+		return m
+	}
+	qos := config.Int(log, "APP_MQTT_SOURCE_QOS", 1)
+	if qos < 0 || qos > 2 {
+		log.Fatal("APP_MQTT_SOURCE_QOS must be 0, 1 or 2", zap.Int("value", qos))
+	}
+	if m.max <= 0 {
+		log.Fatal("APP_MQTT_SOURCE_BUFFER must be positive", zap.Int("value", m.max))
+	}
+	for _, pattern := range config.List("APP_MQTT_SOURCE_TOPICS", []string{"devices/{device}/telemetry"}) {
+		t, err := parseMQTTTopic(pattern)
+		if err != nil {
+			log.Fatal("invalid APP_MQTT_SOURCE_TOPICS entry", zap.String("entry", pattern), zap.Error(err))
+		}
+		m.topics = append(m.topics, t)
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(config.String("APP_MQTT_SOURCE_CLIENT_ID", "scaffold-source")).
+		SetUsername(config.String("APP_MQTT_SOURCE_USERNAME", "")).
+		SetPassword(config.String("APP_MQTT_SOURCE_PASSWORD", "")).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Warn("mqtt source connection lost", zap.Error(err))
+		}).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			log.Info("mqtt source connected", zap.String("broker", broker))
+			for _, t := range m.topics {
+				t := t
+				tok := c.Subscribe(t.filter, byte(qos), func(_ mqtt.Client, msg mqtt.Message) { m.receive(t, msg) })
+				go func() {
+					if tok.Wait(); tok.Error() != nil {
+						log.Error("mqtt source subscribe failed", zap.String("topic", t.filter), zap.Error(tok.Error()))
+					}
+				}()
+			}
+		})
+	client := mqtt.NewClient(opts)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			client.Connect() // SetConnectRetry : 연결될 때까지 백그라운드에서 재시도
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			client.Disconnect(1000)
+			if n := m.buffered(); n > 0 {
+				dr.Record("mqtt_source", drops.ReasonShutdown, "", fmt.Sprintf("%d received samples not collected before shutdown", n))
+			}
+			return nil
+		},
+	})
+	return m
+}
+
+// parseMQTTTopic : "devices/{device}/telemetry" → 필터 "devices/+/telemetry"와 {device} 위치
+func parseMQTTTopic(pattern string) (mqttTopic, error) {
+	t := mqttTopic{device: -1}
+	levels := strings.Split(pattern, "/")
+	for i, l := range levels {
+		if l != "{device}" {
+			continue
+		}
+		if t.device >= 0 {
+			return t, fmt.Errorf("{device} appears more than once")
+		}
+		t.device = i
+		levels[i] = "+"
+	}
+	t.filter = strings.Join(levels, "/")
+	if t.filter == "" {
+		return t, fmt.Errorf("empty topic")
+	}
+	return t, nil
+}
+
+func (m *MQTT) Name() string { return "mqtt" }
+
+// Buffered : 받은 메시지를 버퍼에서 꺼내는 공급원 (source.Buffered)
+func (m *MQTT) Buffered() {}
+
+// Collect : 지난 수집 이후 받은 샘플을 모두 꺼냄
+func (m *MQTT) Collect(ctx context.Context) ([]telemetry.Sample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := m.pending
+	m.pending = nil
+	return out, nil
+}
+
+// buffered : 아직 꺼내지 않은 샘플 수
+func (m *MQTT) buffered() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
+}
+
+// receive : 메시지 하나를 샘플로 바꿔 버퍼에 추가 (paho 수신 고루틴에서 호출)
+func (m *MQTT) receive(t mqttTopic, msg mqtt.Message) {
+	var device string
+	if levels := strings.Split(msg.Topic(), "/"); t.device >= 0 && t.device < len(levels) {
+		device = levels[t.device]
+	}
+	s, err := m.parse(device, msg.Payload())
+	if err != nil {
+		m.drops.Record("mqtt_source", drops.ReasonValidation, device, fmt.Sprintf("topic %s: %v", msg.Topic(), err))
+		return
+	}
+
+	m.mu.Lock()
+	full := len(m.pending) >= m.max
+	if !full {
+		m.pending = append(m.pending, s)
+	}
+	m.mu.Unlock()
+	if full {
+		m.drops.Record("mqtt_source", drops.ReasonBackpressure, s.DeviceID, "mqtt source buffer full")
+	}
+}
+
+// parse : JSON 본문 → 샘플 (device가 비어 있으면 본문의 "device")
+func (m *MQTT) parse(device string, payload []byte) (telemetry.Sample, error) {
+	var body map[string]interface{}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return telemetry.Sample{}, fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+	s := telemetry.Sample{DeviceID: device, Time: time.Now(), Values: make(map[string]float64)}
+	if s.DeviceID == "" {
+		s.DeviceID, _ = body["device"].(string)
+		delete(body, "device")
+	}
+	if s.DeviceID == "" {
+		return s, fmt.Errorf("no device id in topic or payload")
+	}
+	if v, ok := body[m.timeField]; ok {
+		at, err := parseMQTTTime(v)
+		if err != nil {
+			return s, fmt.Errorf("%s: %w", m.timeField, err)
+		}
+		s.Time = at
+		delete(body, m.timeField)
+	}
+	flattenJSON("", body, s.Values)
+	if len(s.Values) == 0 {
+		return s, fmt.Errorf("payload has no numeric fields")
+	}
+	return s, nil
+}
+
+// parseMQTTTime : RFC3339 문자열 또는 유닉스 초
+func parseMQTTTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case string:
+		return time.Parse(time.RFC3339Nano, t)
+	case float64:
+		sec, frac := math.Modf(t)
+		return time.Unix(int64(sec), int64(frac*1e9)), nil
+	}
+	return time.Time{}, fmt.Errorf("expected RFC3339 string or unix seconds")
+}
+
+// flattenJSON : 숫자·불리언 값을 out에 (중첩 객체는 밑줄로 이은 이름)
+func flattenJSON(prefix string, v map[string]interface{}, out map[string]float64) {
+	for k, x := range v {
+		name := k
+		if prefix != "" {
+			name = prefix + "_" + k
+		}
+		switch x := x.(type) {
+		case float64:
+			out[name] = x
+		case bool:
+			if x {
+				out[name] = 1
+			} else {
+				out[name] = 0
+			}
+		case map[string]interface{}:
+			flattenJSON(name, x, out)
+		}
+	}
+}
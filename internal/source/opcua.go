@@ -0,0 +1,440 @@
+//go:build !edge
+
+/*
+ * OPC-UA : PLC·SCADA 서버의 노드 값을 구독(모니터링 항목)으로 받는 수집 공급원 (기본 빌드 전용)
+ *  - APP_OPCUA_CONFIG_FILE : 서버(엔드포인트)별 보안 설정과 노드 → 장치·필드 매핑 JSON 파일 (비어 있으면 서버 없음, 수집 결과 없음)
+ *      {"servers": [
+ *        {"name": "plant1", "endpoint": "opc.tcp://10.1.0.10:4840",
+ *         "security_policy": "Basic256Sha256", "security_mode": "SignAndEncrypt",
+ *         "certificate": "/etc/scaffold/opcua.crt", "private_key": "/etc/scaffold/opcua.key",
+ *         "username": "collector", "password": "${PLANT1_OPCUA_PASSWORD}", "interval": "1s",
+ *         "nodes": [{"device": "PLC1", "field": "line_speed", "node": "ns=2;s=Line1.Speed"},
+ *                   {"device": "PLC1", "field": "motor_temp", "node": "ns=2;i=1045", "scale": 0.1}]}
+ *      ]}
+ *  - 서버 항목 :
+ *      security_policy : None (기본) | Basic128Rsa15 | Basic256 | Basic256Sha256 | Aes128_Sha256_RsaOaep | Aes256_Sha256_RsaPss
+ *      security_mode   : None | Sign | SignAndEncrypt (기본 : 정책이 None이면 None, 아니면 SignAndEncrypt)
+ *      certificate, private_key : 클라이언트 인증서·개인키 파일 (정책이 None이 아니면 필수)
+ *      username, password : 사용자 인증 (비어 있으면 익명, 값의 ${VAR}는 환경변수로 치환)
+ *      interval : 구독 발행 간격 (기본 APP_OPCUA_INTERVAL, 1s)
+ *      nodes    : device, field, node (ns=2;s=... 형식의 노드 ID), scale, offset (값 = 원시값 × scale + offset, scale 기본 1)
+ *  - 서버가 한 번에 보내는 값 변경 알림을 장치별 샘플 하나로 묶어 버퍼에 쌓고, Collector가 수집 주기마다 꺼내 발행합니다.
+ *    측정 시각은 알림 안에서 가장 늦은 소스 시각(없으면 서버 시각, 그것도 없으면 받은 시각)이며, 값이 바뀐 필드만 샘플에 들어갑니다.
+ *  - 숫자·불리언(0/1) 값만 읽고, 상태 코드가 Good이 아닌 값과 그 밖의 형식은 건너뜀
+ *    장치를 지정한 수동 수집(POST /api/collect)이어도 함께 꺼낸 다른 장치의 샘플은 버리지 않고 발행합니다. (source.Buffered)
+ *  - 버퍼(APP_OPCUA_BUFFER)가 가득 차면 새 샘플을 버리고 드롭 기록(source="opcua_source", reason="backpressure")
+ *  - 서버가 내려가 있어도 앱 시작을 막지 않고 APP_OPCUA_RETRY 간격으로 연결·구독을 다시 시도하며, 연결 후 끊김은 클라이언트가 재연결·구독 복구
+ */
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gopcua/opcua"    // OPC-UA 클라이언트
+	"github.com/gopcua/opcua/ua" // 노드 ID, 모니터링 항목, 데이터 값
+	"go.uber.org/fx"             // 구독 라이프사이클
+	"go.uber.org/zap"            // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/drops"     // 버퍼 넘침 기록
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
+)
+
+// 알려진 보안 정책·모드
+var (
+	opcuaPolicies = map[string]bool{
+		"None": true, "Basic128Rsa15": true, "Basic256": true, "Basic256Sha256": true,
+		"Aes128_Sha256_RsaOaep": true, "Aes256_Sha256_RsaPss": true,
+	}
+	opcuaModes = map[string]bool{"None": true, "Sign": true, "SignAndEncrypt": true}
+)
+
+// opcuaNode : 모니터링 항목 하나 (노드 → 장치·필드)
+type opcuaNode struct {
+	Device string  `json:"device"`
+	Field  string  `json:"field"`
+	Node   string  `json:"node"`
+	Scale  float64 `json:"scale"`
+	Offset float64 `json:"offset"`
+
+	id *ua.NodeID
+}
+
+// opcuaServer : 서버 하나의 접속·보안 설정과 모니터링 항목
+type opcuaServer struct {
+	Name           string      `json:"name"`
+	Endpoint       string      `json:"endpoint"`
+	SecurityPolicy string      `json:"security_policy"`
+	SecurityMode   string      `json:"security_mode"`
+	Certificate    string      `json:"certificate"`
+	PrivateKey     string      `json:"private_key"`
+	Username       string      `json:"username"`
+	Password       string      `json:"password"`
+	Interval       string      `json:"interval"`
+	Nodes          []opcuaNode `json:"nodes"`
+
+	interval time.Duration
+}
+
+// OPCUA : OPC-UA 구독 수집 공급원
+type OPCUA struct {
+	log     *zap.Logger
+	drops   *drops.Recorder
+	servers []*opcuaServer
+	timeout time.Duration
+	retry   time.Duration
+	max     int
+
+	mu      sync.Mutex
+	pending []telemetry.Sample // 다음 수집 때 꺼낼 샘플 (받은 순서)
+}
+
+/*
+ * NewOPCUA : fx가 호출하는 OPC-UA 공급원 생성자
+ *  - APP_OPCUA_INTERVAL : 서버에 interval이 없을 때의 구독 발행 간격 (기본 1s)
+ *  - APP_OPCUA_TIMEOUT  : 연결·요청 제한 시간 (기본 10s)
+ *  - APP_OPCUA_RETRY    : 연결·구독 실패 후 다시 시도하기까지 대기 (기본 10s)
+ *  - APP_OPCUA_BUFFER   : 수집 주기 사이에 쌓아 둘 최대 샘플 수 (기본 10000)
+ *  - 설정 파일 형식 오류, 알 수 없는 보안 정책·모드, 인증서 없는 보안 정책, 잘못된 노드 ID는 Fatal
+ */
+func NewOPCUA(lc fx.Lifecycle, log *zap.Logger, dr *drops.Recorder) Source {
+	o := &OPCUA{log: log, drops: dr}
+	path := config.String("APP_OPCUA_CONFIG_FILE", "")
+	if path == "" {
+		return o
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("failed to read APP_OPCUA_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+	var file struct {
+		Servers []*opcuaServer `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Fatal("invalid APP_OPCUA_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+
+	interval := config.Duration(log, "APP_OPCUA_INTERVAL", time.Second)
+	o.timeout = config.Duration(log, "APP_OPCUA_TIMEOUT", 10*time.Second)
+	o.retry = config.Duration(log, "APP_OPCUA_RETRY", 10*time.Second)
+	o.max = config.Int(log, "APP_OPCUA_BUFFER", 10000)
+	if o.max <= 0 {
+		log.Fatal("APP_OPCUA_BUFFER must be positive", zap.Int("value", o.max))
+	}
+	for _, s := range file.Servers {
+		if err := s.validate(interval); err != nil {
+			log.Fatal("invalid APP_OPCUA_CONFIG_FILE server", zap.String("server", s.Name), zap.Error(err))
+		}
+	}
+	o.servers = file.Servers
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			for _, s := range o.servers {
+				wg.Add(1)
+				go func(s *opcuaServer) {
+					defer wg.Done()
+					o.run(ctx, s)
+				}(s)
+			}
+			return nil
+		},
+		OnStop: func(stop context.Context) error {
+			cancel()
+			done := make(chan struct{})
+			go func() { wg.Wait(); close(done) }()
+			select {
+			case <-done:
+			case <-stop.Done():
+			}
+			if n := o.buffered(); n > 0 {
+				dr.Record("opcua_source", drops.ReasonShutdown, "", fmt.Sprintf("%d received samples not collected before shutdown", n))
+			}
+			return nil
+		},
+	})
+	log.Info("opcua source configured", zap.String("path", path), zap.Int("servers", len(o.servers)))
+	return o
+}
+
+// validate : 서버 설정 검사 및 기본값 채움 (인증 값의 ${VAR}는 여기서 치환)
+func (s *opcuaServer) validate(interval time.Duration) error {
+	if s.Name == "" || s.Endpoint == "" {
+		return errors.New("name and endpoint are required")
+	}
+	if s.SecurityPolicy == "" {
+		s.SecurityPolicy = "None"
+	}
+	if !opcuaPolicies[s.SecurityPolicy] {
+		return fmt.Errorf("invalid security_policy %q", s.SecurityPolicy)
+	}
+	if s.SecurityMode == "" {
+		s.SecurityMode = "SignAndEncrypt"
+		if s.SecurityPolicy == "None" {
+			s.SecurityMode = "None"
+		}
+	}
+	if !opcuaModes[s.SecurityMode] {
+		return fmt.Errorf("invalid security_mode %q, expected None|Sign|SignAndEncrypt", s.SecurityMode)
+	}
+	if (s.SecurityPolicy == "None") != (s.SecurityMode == "None") {
+		return fmt.Errorf("security_policy %s does not match security_mode %s", s.SecurityPolicy, s.SecurityMode)
+	}
+	if s.SecurityPolicy != "None" && (s.Certificate == "" || s.PrivateKey == "") {
+		return fmt.Errorf("security_policy %s requires certificate and private_key", s.SecurityPolicy)
+	}
+	s.interval = interval
+	if s.Interval != "" {
+		d, err := time.ParseDuration(s.Interval)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid interval %q", s.Interval)
+		}
+		s.interval = d
+	}
+	if len(s.Nodes) == 0 {
+		return errors.New("no nodes")
+	}
+	for i := range s.Nodes {
+		n := &s.Nodes[i]
+		if n.Device == "" || n.Field == "" {
+			return fmt.Errorf("node %q: device and field are required", n.Node)
+		}
+		id, err := ua.ParseNodeID(n.Node)
+		if err != nil {
+			return fmt.Errorf("node %q: %w", n.Node, err)
+		}
+		n.id = id
+		if n.Scale == 0 {
+			n.Scale = 1
+		}
+	}
+	s.Username, s.Password = os.ExpandEnv(s.Username), os.ExpandEnv(s.Password)
+	return nil
+}
+
+// options : 클라이언트 옵션 (보안 정책·모드, 인증서, 사용자 인증, 자동 재연결)
+func (s *opcuaServer) options(timeout time.Duration) []opcua.Option {
+	opts := []opcua.Option{
+		opcua.ApplicationName("generic-api-scaffold"),
+		opcua.SecurityPolicy(s.SecurityPolicy),
+		opcua.SecurityModeString(s.SecurityMode),
+		opcua.DialTimeout(timeout),
+		opcua.RequestTimeout(timeout),
+		opcua.AutoReconnect(true),
+	}
+	if s.Certificate != "" {
+		opts = append(opts, opcua.CertificateFile(s.Certificate), opcua.PrivateKeyFile(s.PrivateKey))
+	}
+	if s.Username != "" {
+		opts = append(opts, opcua.AuthUsername(s.Username, s.Password))
+	} else {
+		opts = append(opts, opcua.AuthAnonymous())
+	}
+	return opts
+}
+
+func (o *OPCUA) Name() string { return "opcua" }
+
+// Buffered : 구독 알림을 버퍼에서 꺼내는 공급원 (source.Buffered)
+func (o *OPCUA) Buffered() {}
+
+// Collect : 지난 수집 이후 받은 샘플을 모두 꺼냄
+func (o *OPCUA) Collect(ctx context.Context) ([]telemetry.Sample, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := o.pending
+	o.pending = nil
+	return out, nil
+}
+
+// buffered : 아직 꺼내지 않은 샘플 수
+func (o *OPCUA) buffered() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.pending)
+}
+
+// run : 서버 하나의 연결·구독을 유지하며 알림을 버퍼로 (ctx가 끝나면 연결을 닫고 반환)
+func (o *OPCUA) run(ctx context.Context, s *opcuaServer) {
+	log := o.log.With(zap.String("server", s.Name), zap.String("endpoint", s.Endpoint))
+	for {
+		client, notify, err := o.subscribe(ctx, s)
+		if err == nil {
+			log.Info("opcua source subscribed", zap.Int("nodes", len(s.Nodes)))
+			o.consume(ctx, log, s, notify)
+			closeCtx, cancel := context.WithTimeout(context.Background(), o.timeout)
+			_ = client.Close(closeCtx)
+			cancel()
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("opcua source connect failed, retrying", zap.Duration("retry", o.retry), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(o.retry):
+		}
+	}
+}
+
+// subscribe : 연결, 구독 생성, 모니터링 항목 등록 (노드 하나의 등록 실패는 로그만 남기고 나머지로 계속)
+func (o *OPCUA) subscribe(ctx context.Context, s *opcuaServer) (*opcua.Client, chan *opcua.PublishNotificationData, error) {
+	client, err := opcua.NewClient(s.Endpoint, s.options(o.timeout)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 클라이언트는 Connect에 넘긴 ctx로 재연결 감시를 돌리므로 수명 ctx를 그대로 넘기고, 제한 시간은 DialTimeout·RequestTimeout으로
+	if err := client.Connect(ctx); err != nil {
+		return nil, nil, fmt.Errorf("connect: %w", err)
+	}
+	fail := func(err error) (*opcua.Client, chan *opcua.PublishNotificationData, error) {
+		_ = client.Close(context.Background())
+		return nil, nil, err
+	}
+
+	notify := make(chan *opcua.PublishNotificationData, 64)
+	sub, err := client.Subscribe(ctx, &opcua.SubscriptionParameters{Interval: s.interval}, notify)
+	if err != nil {
+		return fail(fmt.Errorf("subscribe: %w", err))
+	}
+	reqs := make([]*ua.MonitoredItemCreateRequest, len(s.Nodes))
+	for i, n := range s.Nodes {
+		reqs[i] = opcua.NewMonitoredItemCreateRequestWithDefaults(n.id, ua.AttributeIDValue, uint32(i))
+	}
+	resp, err := sub.Monitor(ctx, ua.TimestampsToReturnBoth, reqs...)
+	if err != nil {
+		return fail(fmt.Errorf("monitor: %w", err))
+	}
+	ok := 0
+	for i, r := range resp.Results {
+		if r.StatusCode != ua.StatusOK {
+			o.log.Error("opcua source monitored item rejected", zap.String("server", s.Name), zap.String("node", s.Nodes[i].Node), zap.Error(r.StatusCode))
+			continue
+		}
+		ok++
+	}
+	if ok == 0 {
+		return fail(errors.New("no monitored item was accepted"))
+	}
+	return client, notify, nil
+}
+
+// consume : 구독 알림을 ctx가 끝날 때까지 샘플로 바꿔 버퍼에 추가
+func (o *OPCUA) consume(ctx context.Context, log *zap.Logger, s *opcuaServer, notify <-chan *opcua.PublishNotificationData) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-notify:
+			if n.Error != nil {
+				log.Warn("opcua source notification error", zap.Error(n.Error))
+				continue
+			}
+			if dc, ok := n.Value.(*ua.DataChangeNotification); ok {
+				o.push(s.samples(dc))
+			}
+		}
+	}
+}
+
+// samples : 값 변경 알림 하나 → 장치별 샘플 (장치 순서는 처음 나온 순서)
+func (s *opcuaServer) samples(dc *ua.DataChangeNotification) []telemetry.Sample {
+	var out []telemetry.Sample
+	index := make(map[string]int) // 장치 → out 위치
+	for _, item := range dc.MonitoredItems {
+		if item == nil || item.Value == nil || int(item.ClientHandle) >= len(s.Nodes) {
+			continue
+		}
+		dv := item.Value
+		if dv.Status != ua.StatusOK || dv.Value == nil {
+			continue
+		}
+		v, ok := opcuaValue(dv.Value.Value())
+		if !ok {
+			continue
+		}
+		n := s.Nodes[item.ClientHandle]
+		i, seen := index[n.Device]
+		if !seen {
+			i = len(out)
+			index[n.Device] = i
+			out = append(out, telemetry.Sample{DeviceID: n.Device, Values: make(map[string]float64)})
+		}
+		out[i].Values[n.Field] = v*n.Scale + n.Offset
+		at := dv.SourceTimestamp
+		if at.IsZero() {
+			at = dv.ServerTimestamp
+		}
+		if at.After(out[i].Time) {
+			out[i].Time = at
+		}
+	}
+	for i := range out {
+		if out[i].Time.IsZero() {
+			out[i].Time = time.Now()
+		}
+	}
+	return out
+}
+
+// push : 샘플을 버퍼에 추가 (가득 차면 넘친 샘플을 드롭 기록)
+func (o *OPCUA) push(samples []telemetry.Sample) {
+	if len(samples) == 0 {
+		return
+	}
+	o.mu.Lock()
+	room := o.max - len(o.pending)
+	if room < 0 {
+		room = 0
+	}
+	if room > len(samples) {
+		room = len(samples)
+	}
+	o.pending = append(o.pending, samples[:room]...)
+	o.mu.Unlock()
+	for _, s := range samples[room:] {
+		o.drops.Record("opcua_source", drops.ReasonBackpressure, s.DeviceID, "opcua source buffer full")
+	}
+}
+
+// opcuaValue : 변형(Variant) 값 → 숫자 (숫자·불리언만)
+func opcuaValue(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	case int8:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
@@ -0,0 +1,347 @@
+//go:build !edge
+
+/*
+ * SNMP : UPS·PDU·스위치 등 SNMP v2c / v3 장치의 OID 값을 읽는 수집 공급원 (기본 빌드 전용)
+ *  - APP_SNMP_CONFIG_FILE : 프로파일(OID → 필드 매핑)과 장치 목록 JSON 파일 (비어 있으면 장치 없음, 수집 결과 없음)
+ *      {"profiles": {
+ *         "ups-rfc1628": [{"field": "battery_charge", "oid": "1.3.6.1.2.1.33.1.2.4.0"},
+ *                         {"field": "battery_voltage", "oid": ".1.3.6.1.2.1.33.1.2.5.0", "scale": 0.1},
+ *                         {"field": "output_load", "oid": "1.3.6.1.2.1.33.1.4.4.1.5.1"}],
+ *         "switch": [{"field": "uptime_s", "oid": "1.3.6.1.2.1.1.3.0", "scale": 0.01}]},
+ *       "devices": [
+ *         {"id": "UPS1", "address": "10.0.0.30", "profile": "ups-rfc1628", "version": "2c", "community": "${UPS_COMMUNITY}"},
+ *         {"id": "SW1", "address": "10.0.0.2:161", "profile": "switch", "version": "3", "user": "monitor",
+ *          "auth_protocol": "SHA256", "auth_password": "${SW_AUTH}", "priv_protocol": "AES", "priv_password": "${SW_PRIV}"}
+ *       ]}
+ *  - 프로파일 항목 : field (샘플 필드 이름), oid (앞의 점은 있어도 없어도 됨), scale, offset (값 = 원시값 × scale + offset, scale 기본 1)
+ *  - 장치 항목 :
+ *      address   : host 또는 host:port (기본 포트 161)
+ *      version   : 2c (기본) | 3
+ *      community : v2c 커뮤니티 (기본 public)
+ *      user, auth_protocol (MD5 | SHA | SHA224 | SHA256 | SHA384 | SHA512), auth_password,
+ *      priv_protocol (DES | AES | AES192 | AES256), priv_password : v3 USM 인증 (인증·암호화 프로토콜이 없으면 그 단계 없음)
+ *      timeout, retries : 요청 제한 시간 (기본 APP_SNMP_TIMEOUT, 2s), 재시도 횟수 (기본 APP_SNMP_RETRIES, 1)
+ *      커뮤니티·비밀번호의 ${VAR}는 환경변수로 치환하여 비밀 값을 파일에 두지 않음
+ *  - 정수·카운터·게이지·TimeTicks·Opaque 실수는 숫자로, 숫자 모양의 OctetString("23.5")도 숫자로 읽고, 장치에 없는 OID와 그 밖의 값은 건너뜀
+ *    꺼낸 필드가 하나도 없으면 그 장치의 수집 실패 (나머지 장치는 그대로)
+ */
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp" // SNMP v2c / v3 클라이언트
+	"go.uber.org/fx"           // 연결 정리 라이프사이클
+	"go.uber.org/zap"          // 로깅 도구
+
+	"generic-api-scaffold/internal/config"    // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
+)
+
+// snmpField : 프로파일 항목 하나 (OID → 필드)
+type snmpField struct {
+	Field  string  `json:"field"`
+	OID    string  `json:"oid"`
+	Scale  float64 `json:"scale"`
+	Offset float64 `json:"offset"`
+}
+
+// snmpDevice : 장치 하나의 접속 정보
+type snmpDevice struct {
+	ID           string `json:"id"`
+	Address      string `json:"address"`
+	Profile      string `json:"profile"`
+	Version      string `json:"version"`
+	Community    string `json:"community"`
+	User         string `json:"user"`
+	AuthProtocol string `json:"auth_protocol"`
+	AuthPassword string `json:"auth_password"`
+	PrivProtocol string `json:"priv_protocol"`
+	PrivPassword string `json:"priv_password"`
+	Timeout      string `json:"timeout"`
+	Retries      *int   `json:"retries"`
+
+	fields    []snmpField
+	oids      []string             // 요청할 OID (앞의 점 포함, gosnmp 응답 이름과 같은 형식)
+	byOID     map[string]snmpField // 응답 OID → 필드
+	client    *gosnmp.GoSNMP
+	connected bool
+}
+
+var (
+	snmpAuthProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+		"": gosnmp.NoAuth, "MD5": gosnmp.MD5, "SHA": gosnmp.SHA, "SHA224": gosnmp.SHA224,
+		"SHA256": gosnmp.SHA256, "SHA384": gosnmp.SHA384, "SHA512": gosnmp.SHA512,
+	}
+	snmpPrivProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+		"": gosnmp.NoPriv, "DES": gosnmp.DES, "AES": gosnmp.AES, "AES192": gosnmp.AES192, "AES256": gosnmp.AES256,
+	}
+)
+
+// SNMP : SNMP 폴링 수집 공급원
+type SNMP struct {
+	log     *zap.Logger
+	devices []*snmpDevice
+
+	mu sync.Mutex // 수집 루프와 수동 수집이 같은 소켓을 동시에 쓰지 않도록
+}
+
+/*
+ * NewSNMP : fx가 호출하는 SNMP 공급원 생성자
+ *  - APP_SNMP_TIMEOUT : 장치에 timeout이 없을 때의 요청 제한 시간 (기본 2s)
+ *  - APP_SNMP_RETRIES : 장치에 retries가 없을 때의 재시도 횟수 (기본 1)
+ *  - 설정 파일 형식 오류, 없는 프로파일, 알 수 없는 버전·인증·암호화 프로토콜은 Fatal
+ *  - OnStop 시 모든 소켓을 닫음
+ */
+func NewSNMP(lc fx.Lifecycle, log *zap.Logger) Source {
+	s := &SNMP{log: log}
+	path := config.String("APP_SNMP_CONFIG_FILE", "")
+	if path == "" {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("failed to read APP_SNMP_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+	var file struct {
+		Profiles map[string][]snmpField `json:"profiles"`
+		Devices  []*snmpDevice          `json:"devices"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Fatal("invalid APP_SNMP_CONFIG_FILE", zap.String("path", path), zap.Error(err))
+	}
+	for name, fields := range file.Profiles {
+		if err := validateSNMPProfile(fields); err != nil {
+			log.Fatal("invalid APP_SNMP_CONFIG_FILE profile", zap.String("profile", name), zap.Error(err))
+		}
+	}
+
+	timeout := config.Duration(log, "APP_SNMP_TIMEOUT", 2*time.Second)
+	retries := config.Int(log, "APP_SNMP_RETRIES", 1)
+	for _, d := range file.Devices {
+		fields, ok := file.Profiles[d.Profile]
+		if !ok {
+			log.Fatal("invalid APP_SNMP_CONFIG_FILE device", zap.String("device", d.ID), zap.Error(fmt.Errorf("unknown profile %q", d.Profile)))
+		}
+		if err := d.setup(fields, timeout, retries); err != nil {
+			log.Fatal("invalid APP_SNMP_CONFIG_FILE device", zap.String("device", d.ID), zap.Error(err))
+		}
+		s.devices = append(s.devices, d)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for _, d := range s.devices {
+				d.close()
+			}
+			return nil
+		},
+	})
+	log.Info("snmp source configured", zap.String("path", path), zap.Int("devices", len(s.devices)), zap.Int("profiles", len(file.Profiles)))
+	return s
+}
+
+// validateSNMPProfile : 프로파일 항목 검사 및 기본값 채움 (OID는 앞에 점을 붙인 형식으로)
+func validateSNMPProfile(fields []snmpField) error {
+	if len(fields) == 0 {
+		return errors.New("no fields")
+	}
+	seen := make(map[string]bool, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		if f.Field == "" || f.OID == "" {
+			return errors.New("field and oid are required")
+		}
+		f.OID = "." + strings.TrimPrefix(f.OID, ".")
+		if seen[f.OID] {
+			return fmt.Errorf("oid %s is mapped more than once", f.OID)
+		}
+		seen[f.OID] = true
+		if f.Scale == 0 {
+			f.Scale = 1
+		}
+	}
+	return nil
+}
+
+// setup : 장치 설정 검사 및 클라이언트 준비 (실제 소켓은 첫 요청 때)
+func (d *snmpDevice) setup(fields []snmpField, timeout time.Duration, retries int) error {
+	if d.ID == "" || d.Address == "" {
+		return errors.New("id and address are required")
+	}
+	host, port := d.Address, uint64(161)
+	if h, p, err := net.SplitHostPort(d.Address); err == nil {
+		if port, err = strconv.ParseUint(p, 10, 16); err != nil {
+			return fmt.Errorf("invalid port in address %q", d.Address)
+		}
+		host = h
+	}
+	if d.Timeout != "" {
+		t, err := time.ParseDuration(d.Timeout)
+		if err != nil || t <= 0 {
+			return fmt.Errorf("invalid timeout %q", d.Timeout)
+		}
+		timeout = t
+	}
+	if d.Retries != nil {
+		retries = *d.Retries
+	}
+	if retries < 0 {
+		return fmt.Errorf("invalid retries %d", retries)
+	}
+
+	c := &gosnmp.GoSNMP{Target: host, Port: uint16(port), Timeout: timeout, Retries: retries, MaxOids: gosnmp.MaxOids}
+	switch d.Version {
+	case "", "2c":
+		c.Version = gosnmp.Version2c
+		c.Community = os.ExpandEnv(d.Community)
+		if c.Community == "" {
+			c.Community = "public"
+		}
+	case "3":
+		if d.User == "" {
+			return errors.New("version 3 requires user")
+		}
+		auth, ok := snmpAuthProtocols[strings.ToUpper(d.AuthProtocol)]
+		if !ok {
+			return fmt.Errorf("invalid auth_protocol %q", d.AuthProtocol)
+		}
+		priv, ok := snmpPrivProtocols[strings.ToUpper(d.PrivProtocol)]
+		if !ok {
+			return fmt.Errorf("invalid priv_protocol %q", d.PrivProtocol)
+		}
+		c.MsgFlags = gosnmp.NoAuthNoPriv
+		switch {
+		case auth != gosnmp.NoAuth && priv != gosnmp.NoPriv:
+			c.MsgFlags = gosnmp.AuthPriv
+		case auth != gosnmp.NoAuth:
+			c.MsgFlags = gosnmp.AuthNoPriv
+		case priv != gosnmp.NoPriv:
+			return errors.New("priv_protocol requires auth_protocol")
+		}
+		c.Version = gosnmp.Version3
+		c.SecurityModel = gosnmp.UserSecurityModel
+		c.SecurityParameters = &gosnmp.UsmSecurityParameters{
+			UserName:                 d.User,
+			AuthenticationProtocol:   auth,
+			AuthenticationPassphrase: os.ExpandEnv(d.AuthPassword),
+			PrivacyProtocol:          priv,
+			PrivacyPassphrase:        os.ExpandEnv(d.PrivPassword),
+		}
+	default:
+		return fmt.Errorf("invalid version %q, expected 2c|3", d.Version)
+	}
+
+	d.client = c
+	d.fields = fields
+	d.byOID = make(map[string]snmpField, len(fields))
+	for _, f := range fields {
+		d.oids = append(d.oids, f.OID)
+		d.byOID[f.OID] = f
+	}
+	return nil
+}
+
+// close : 소켓을 닫음 (다음 요청이 다시 엶)
+func (d *snmpDevice) close() {
+	if d.connected && d.client.Conn != nil {
+		_ = d.client.Conn.Close()
+	}
+	d.connected = false
+}
+
+func (s *SNMP) Name() string { return "snmp" }
+
+/*
+ * Collect : 설정된 장치마다 프로파일의 OID를 GET으로 읽어 샘플 하나씩
+ *  - 읽지 못한 장치는 경고 로그를 남기고 에러로 모아 반환
+ */
+func (s *SNMP) Collect(ctx context.Context) ([]telemetry.Sample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := make([]telemetry.Sample, 0, len(s.devices))
+	var errs []error
+	for _, d := range s.devices {
+		if err := ctx.Err(); err != nil {
+			return samples, err
+		}
+		values, err := d.read()
+		if err != nil {
+			s.log.Warn("snmp read failed", zap.String("device", d.ID), zap.String("address", d.Address), zap.Error(err))
+			errs = append(errs, fmt.Errorf("device %s: %w", d.ID, err))
+			continue
+		}
+		samples = append(samples, telemetry.Sample{DeviceID: d.ID, Time: time.Now(), Values: values})
+	}
+	return samples, errors.Join(errs...)
+}
+
+// read : 장치의 OID를 MaxOids씩 나눠 읽음 (실패하면 소켓을 닫아 다음 수집에서 다시 엶)
+func (d *snmpDevice) read() (map[string]float64, error) {
+	if !d.connected {
+		if err := d.client.Connect(); err != nil {
+			return nil, fmt.Errorf("connect: %w", err)
+		}
+		d.connected = true
+	}
+	values := make(map[string]float64, len(d.fields))
+	for start := 0; start < len(d.oids); start += gosnmp.MaxOids {
+		end := start + gosnmp.MaxOids
+		if end > len(d.oids) {
+			end = len(d.oids)
+		}
+		pkt, err := d.client.Get(d.oids[start:end])
+		if err != nil {
+			d.close()
+			return nil, err
+		}
+		for _, v := range pkt.Variables {
+			f, ok := d.byOID[v.Name]
+			if !ok {
+				continue
+			}
+			if x, ok := snmpValue(v); ok {
+				values[f.Field] = x*f.Scale + f.Offset
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no configured oid returned a numeric value")
+	}
+	return values, nil
+}
+
+// snmpValue : 응답 값 하나 → 숫자 (숫자로 읽을 수 없는 값과 장치에 없는 OID는 false)
+func snmpValue(v gosnmp.SnmpPDU) (float64, bool) {
+	switch v.Type {
+	case gosnmp.Integer, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		f, _ := new(big.Float).SetInt(gosnmp.ToBigInt(v.Value)).Float64()
+		return f, true
+	case gosnmp.OpaqueFloat:
+		f, ok := v.Value.(float32)
+		return float64(f), ok
+	case gosnmp.OpaqueDouble:
+		f, ok := v.Value.(float64)
+		return f, ok
+	case gosnmp.OctetString:
+		b, ok := v.Value.([]byte)
+		if !ok {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(string(b)), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
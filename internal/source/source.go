@@ -0,0 +1,46 @@
+/*
+ * 수집 공급원(Source) : Collector가 주기마다 호출하여 장치 측정값을 가져오는 대상입니다.
+ *  - 실제 장치 프로토콜(Modbus, OPC UA, HTTP 폴링 등)을 Source로 구현하고 fx 값 그룹(group:"sources")으로 등록하면
+ *    Collector가 수집 주기마다 등록된 공급원을 차례로 호출하여 결과를 DataCollectedEvent로 발행합니다.
+ *  - 공급원 하나가 실패해도 나머지는 그대로 수집합니다. (실패는 로그와 수집 결과 에러로만 남음)
+ *  - APP_COLLECTOR_SOURCES로 사용할 공급원을 이름으로 고를 수 있습니다. (비어 있으면 등록된 모든 공급원)
+ */
+package source
+
+import (
+	"context"
+
+	"go.uber.org/fx" // 값 그룹 등록
+
+	"generic-api-scaffold/internal/telemetry" // 수집 결과 샘플
+)
+
+/*
+ * Source 인터페이스
+ *  - Name    : 로그와 APP_COLLECTOR_SOURCES에서 쓰는 이름 (공급원마다 달라야 함)
+ *  - Collect : 한 번 수집한 샘플 목록 (장치 여러 개 가능)
+ *              DeviceID가 없는 샘플은 버리고, Time이 비어 있으면 수집 시각을 씀 (Tags는 쓰지 않음, 저장 전 태그 보강은 infra.TagEnricher)
+ *              ctx는 수집 루프(종료 시 취소) 또는 수동 수집 요청의 값
+ */
+type Source interface {
+	Name() string
+	Collect(ctx context.Context) ([]telemetry.Sample, error)
+}
+
+/*
+ * Buffered : 장치가 밀어 넣은 샘플을 쌓아 두었다가 Collect에서 꺼내는 공급원 (예: mqtt, opcua)
+ *  - 꺼낸 샘플은 되돌릴 수 없으므로 Collector는 이 공급원의 샘플을 골라 버리지 않고 모두 발행합니다.
+ *    (장치를 지정한 수동 수집이어도 다른 장치의 샘플까지 발행하고, 응답에는 지정한 장치만 씀)
+ */
+type Buffered interface {
+	Source
+	Buffered()
+}
+
+/*
+ * AsSource : 생성자의 결과를 group:"sources"에 제공하도록 감쌈
+ *  - 예) fx.Provide(source.AsSource(modbus.NewSource))
+ */
+func AsSource(f interface{}) interface{} {
+	return fx.Annotate(f, fx.ResultTags(`group:"sources"`))
+}
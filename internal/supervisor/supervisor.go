@@ -0,0 +1,268 @@
+/*
+ * Supervisor : 개별 모듈(수집 워커, MQTT 클라이언트, 브로커 브리지 등)의 실행 감독자
+ *  - 모듈이 에러로 끝나거나 패닉이 나면 프로세스 전체를 재시작하지 않고 해당 모듈만 다시 시작합니다.
+ *  - 재시작 사이에는 지수 백오프(최소 → 최대)를 둡니다.
+ *  - 일정 구간(window) 안의 재시작 횟수가 예산(max restarts)을 넘으면 모듈을 "failed"로 표시하고
+ *    더 이상 재시작하지 않습니다. 이때 Healthy()가 에러를 반환하여 /readyz가 503이 됩니다.
+ */
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // 메트릭 레지스트리
+	"go.uber.org/fx"                                 // Fx 프레임워크를 통한 라이프사이클 관리
+	"go.uber.org/zap"                                // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+// State : 모듈 실행 상태
+type State string
+
+const (
+	StateRunning    State = "running"    // 실행 중
+	StateRestarting State = "restarting" // 실패 후 백오프 대기 중
+	StateFailed     State = "failed"     // 재시작 예산 소진, 더 이상 재시작하지 않음
+	StateStopped    State = "stopped"    // 정상 종료 (앱 종료)
+)
+
+/*
+ * RunFunc : 감독 대상 모듈의 실행 함수
+ *  - ctx가 취소될 때까지 블록해야 합니다.
+ *  - ctx가 취소되기 전에 반환하면(에러 여부와 무관하게) 실패로 간주하여 재시작합니다.
+ */
+type RunFunc func(ctx context.Context) error
+
+// Status : 모듈 하나의 현재 상태 (조회용)
+type Status struct {
+	Name      string    `json:"name"`
+	State     State     `json:"state"`
+	Restarts  int       `json:"restarts"`             // 누적 재시작 횟수
+	LastError string    `json:"last_error,omitempty"` // 마지막 실패 원인
+	Since     time.Time `json:"since"`                // 현재 상태가 된 시각
+}
+
+// module : 감독 중인 모듈 내부 상태
+type module struct {
+	name     string
+	run      RunFunc
+	status   Status
+	failures []time.Time // window 안의 실패 시각 (예산 계산용)
+}
+
+// Supervisor 구조체
+type Supervisor struct {
+	log      *zap.Logger
+	restarts *prometheus.CounterVec
+
+	maxRestarts int           // window 안에서 허용하는 재시작 횟수
+	window      time.Duration // 재시작 예산 구간
+	backoffMin  time.Duration // 첫 재시작 대기 시간
+	backoffMax  time.Duration // 재시작 대기 시간 상한
+
+	ctx    context.Context // 모든 모듈의 부모 컨텍스트 (앱 종료 시 취소)
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	modules map[string]*module
+}
+
+/*
+ * NewSupervisor : fx가 호출하는 Supervisor 생성자
+ *  - APP_SUPERVISOR_MAX_RESTARTS : window 안에서 허용하는 재시작 횟수 (기본 5)
+ *  - APP_SUPERVISOR_WINDOW       : 재시작 예산 구간 (기본 10m)
+ *  - APP_SUPERVISOR_BACKOFF_MIN  : 첫 재시작 대기 시간 (기본 1s)
+ *  - APP_SUPERVISOR_BACKOFF_MAX  : 재시작 대기 시간 상한 (기본 30s)
+ *  - OnStop : 모든 모듈의 컨텍스트를 취소하고 종료를 기다림
+ */
+func NewSupervisor(lc fx.Lifecycle, log *zap.Logger, reg *prometheus.Registry) *Supervisor {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Supervisor{
+		log: log,
+		restarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "scaffold",
+			Name:      "module_restarts_total",
+			Help:      "Module restarts performed by the supervisor, by module.",
+		}, []string{"module"}),
+		maxRestarts: config.Int(log, "APP_SUPERVISOR_MAX_RESTARTS", 5),
+		window:      config.Duration(log, "APP_SUPERVISOR_WINDOW", 10*time.Minute),
+		backoffMin:  config.Duration(log, "APP_SUPERVISOR_BACKOFF_MIN", time.Second),
+		backoffMax:  config.Duration(log, "APP_SUPERVISOR_BACKOFF_MAX", 30*time.Second),
+		ctx:         ctx,
+		cancel:      cancel,
+		modules:     make(map[string]*module),
+	}
+	if s.maxRestarts < 0 {
+		log.Fatal("APP_SUPERVISOR_MAX_RESTARTS must not be negative", zap.Int("value", s.maxRestarts))
+	}
+	if s.backoffMin <= 0 || s.backoffMax < s.backoffMin {
+		log.Fatal("invalid supervisor backoff", zap.Duration("min", s.backoffMin), zap.Duration("max", s.backoffMax))
+	}
+	reg.MustRegister(s.restarts)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			s.cancel()
+			done := make(chan struct{})
+			go func() {
+				s.wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+	return s
+}
+
+/*
+ * Go : 모듈을 감독 하에 고루틴으로 실행
+ *  - 같은 이름을 두 번 등록하면 에러
+ */
+func (s *Supervisor) Go(name string, run RunFunc) error {
+	s.mu.Lock()
+	if _, ok := s.modules[name]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("module %q already supervised", name)
+	}
+	m := &module{name: name, run: run, status: Status{Name: name, State: StateRunning, Since: time.Now()}}
+	s.modules[name] = m
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.loop(m)
+	return nil
+}
+
+/*
+ * loop : 모듈 실행 → 실패 시 백오프 후 재시작 (예산 소진 또는 앱 종료까지)
+ */
+func (s *Supervisor) loop(m *module) {
+	defer s.wg.Done()
+
+	backoff := s.backoffMin
+	for {
+		err := s.runOnce(m)
+		if s.ctx.Err() != nil {
+			s.setState(m, StateStopped)
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("module exited unexpectedly")
+		}
+
+		if !s.allowRestart(m, err) {
+			s.log.Error("module failed, restart budget exhausted",
+				zap.String("module", m.name), zap.Int("max_restarts", s.maxRestarts),
+				zap.Duration("window", s.window), zap.Error(err))
+			return
+		}
+
+		s.log.Warn("module failed, restarting",
+			zap.String("module", m.name), zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-s.ctx.Done():
+			s.setState(m, StateStopped)
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.backoffMax {
+			backoff = s.backoffMax
+		}
+		s.restarts.WithLabelValues(m.name).Inc()
+		s.mu.Lock()
+		m.status.Restarts++
+		s.mu.Unlock()
+		s.setState(m, StateRunning)
+	}
+}
+
+// runOnce : 모듈을 한 번 실행 (패닉은 에러로 변환)
+func (s *Supervisor) runOnce(m *module) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return m.run(s.ctx)
+}
+
+/*
+ * allowRestart : 실패를 기록하고 재시작 예산이 남았는지 판단
+ *  - window 밖의 오래된 실패는 버림
+ *  - 예산을 넘으면 상태를 failed로, 남았으면 restarting으로 바꿈
+ */
+func (s *Supervisor) allowRestart(m *module, cause error) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := m.failures[:0]
+	for _, t := range m.failures {
+		if now.Sub(t) < s.window {
+			kept = append(kept, t)
+		}
+	}
+	m.failures = append(kept, now)
+
+	m.status.LastError = cause.Error()
+	m.status.Since = now
+	if len(m.failures) > s.maxRestarts {
+		m.status.State = StateFailed
+		return false
+	}
+	m.status.State = StateRestarting
+	return true
+}
+
+// setState : 모듈 상태 변경
+func (s *Supervisor) setState(m *module, st State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m.status.State = st
+	m.status.Since = time.Now()
+}
+
+/*
+ * Statuses : 감독 중인 모든 모듈의 상태 (이름 순)
+ */
+func (s *Supervisor) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Status, 0, len(s.modules))
+	for _, m := range s.modules {
+		out = append(out, m.status)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+/*
+ * Healthy : 재시작 예산을 소진한(failed) 모듈이 있으면 에러
+ *  - 백오프 대기 중(restarting)인 모듈은 스스로 회복할 수 있으므로 정상으로 취급
+ */
+func (s *Supervisor) Healthy() error {
+	var failed []string
+	for _, st := range s.Statuses() {
+		if st.State == StateFailed {
+			failed = append(failed, st.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("modules failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
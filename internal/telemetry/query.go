@@ -0,0 +1,82 @@
+/*
+ * QuerySpec / Series : 저장소 조회 조건과 결과
+ *  - HTTP 데이터 조회 API, 알림 평가 등 저장소를 읽는 쪽이 백엔드(InfluxQL/Flux/SQL)를 몰라도 되도록 공통 형태로 정의합니다.
+ *  - 결과는 장치 × 필드마다 시계열 하나입니다.
+ */
+package telemetry
+
+import (
+	"errors"
+	"time"
+)
+
+// Aggregate : 구간 집계 함수 (비어 있으면 원시 값)
+type Aggregate string
+
+const (
+	AggNone  Aggregate = ""
+	AggMean  Aggregate = "mean"
+	AggMin   Aggregate = "min"
+	AggMax   Aggregate = "max"
+	AggSum   Aggregate = "sum"
+	AggCount Aggregate = "count"
+	AggFirst Aggregate = "first"
+	AggLast  Aggregate = "last"
+)
+
+// Valid : 알려진 집계 함수인지
+func (a Aggregate) Valid() bool {
+	switch a {
+	case AggNone, AggMean, AggMin, AggMax, AggSum, AggCount, AggFirst, AggLast:
+		return true
+	}
+	return false
+}
+
+/*
+ * QuerySpec : 조회 조건
+ *  - Devices   : 조회할 장치 ID (하나 이상)
+ *  - Fields    : 조회할 필드 (비어 있으면 숫자 필드 전부)
+ *  - From, To  : [From, To) 구간
+ *  - Aggregate : 집계 함수 (비어 있으면 원시 값)
+ *  - Every     : 집계 구간 폭 (0이면 전체 구간을 하나로 집계, 집계 시각은 구간 시작 - 원시 조회에서는 무시)
+ *  - Limit     : 시계열 하나의 최대 포인트 수 (0이면 제한 없음, 앞에서부터)
+ */
+type QuerySpec struct {
+	Devices   []string
+	Fields    []string
+	From, To  time.Time
+	Aggregate Aggregate
+	Every     time.Duration
+	Limit     int
+}
+
+// Validate : 조회 조건 검사
+func (q QuerySpec) Validate() error {
+	switch {
+	case len(q.Devices) == 0:
+		return errors.New("at least one device is required")
+	case q.From.IsZero() || q.To.IsZero() || !q.From.Before(q.To):
+		return errors.New("from must be before to")
+	case !q.Aggregate.Valid():
+		return errors.New("unknown aggregate " + string(q.Aggregate))
+	case q.Every < 0:
+		return errors.New("every must not be negative")
+	case q.Limit < 0:
+		return errors.New("limit must not be negative")
+	}
+	return nil
+}
+
+// Point : 시계열의 값 하나
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Series : 장치 하나의 필드 하나에 대한 시간순 값
+type Series struct {
+	DeviceID string  `json:"device"`
+	Field    string  `json:"field"`
+	Points   []Point `json:"points"`
+}
@@ -0,0 +1,15 @@
+/*
+ * Sample : 특정 시각에 장치 하나에서 측정된 값 묶음
+ *  - 저장소에서 읽어 온 과거 텔레메트리, 시뮬레이션 입력 등 여러 계층에서 공통으로 사용하는 값 타입입니다.
+ *  - 의존성이 없는 패키지에 두어 infra ↔ 상위 패키지 간 import 순환을 피합니다.
+ */
+package telemetry
+
+import "time"
+
+type Sample struct {
+	Time     time.Time          `json:"time"`
+	DeviceID string             `json:"device"`
+	Values   map[string]float64 `json:"values"`
+	Tags     map[string]string  `json:"tags,omitempty"` // 저장 전에 붙인 보강 태그 (infra.TagEnricher, 조회 결과에는 없음)
+}
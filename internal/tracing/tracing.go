@@ -0,0 +1,67 @@
+/*
+ * 분산 추적(OpenTelemetry) 설정
+ *  - APP_OTEL_ENDPOINT를 지정하면 OTLP/HTTP로 스팬을 내보내고, 비어 있으면 아무것도 기록하지 않는 no-op 제공자를 씁니다.
+ *  - 전역 제공자와 전파기(W3C traceparent + baggage)도 함께 설정하므로,
+ *    들어오는 HTTP 요청의 traceparent 헤더를 이어받아 HTTP 요청 → 버스 발행 → 구독자 처리(Influx 쓰기 등)가 하나의 트레이스로 묶입니다.
+ *  - 서비스 이름은 OTEL_SERVICE_NAME(OpenTelemetry 표준 환경변수)을 따릅니다.
+ */
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"                                        // 전역 제공자/전파기
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp" // OTLP/HTTP 내보내기
+	"go.opentelemetry.io/otel/propagation"                            // W3C Trace Context 전파
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"                     // 스팬 처리기/샘플러
+	"go.opentelemetry.io/otel/trace"                                  // TracerProvider 인터페이스
+	"go.opentelemetry.io/otel/trace/noop"                             // 비활성 시 제공자
+	"go.uber.org/fx"                                                  // 라이프사이클 훅
+	"go.uber.org/zap"                                                 // 로깅 도구
+
+	"generic-api-scaffold/internal/config" // 환경변수 읽기 도우미
+)
+
+/*
+ * NewTracerProvider : fx가 호출하는 TracerProvider 생성자
+ *  - APP_OTEL_ENDPOINT     : OTLP/HTTP 수집기 주소 host:port (비어 있으면 추적 비활성, 기본 비활성)
+ *  - APP_OTEL_INSECURE     : TLS 없이 전송 (기본 true, 사이드카/로컬 수집기용)
+ *  - APP_OTEL_SAMPLE_RATIO : 새 트레이스의 샘플링 비율 0~1 (기본 1, 상위 요청이 샘플링했으면 그 결정을 따름)
+ *  - OnStop : 버퍼에 남은 스팬을 내보내고 종료
+ */
+func NewTracerProvider(lc fx.Lifecycle, log *zap.Logger) trace.TracerProvider {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	endpoint := config.String("APP_OTEL_ENDPOINT", "")
+	if endpoint == "" {
+		tp := noop.NewTracerProvider()
+		otel.SetTracerProvider(tp)
+		return tp
+	}
+	ratio := config.Float(log, "APP_OTEL_SAMPLE_RATIO", 1)
+	if ratio < 0 || ratio > 1 {
+		log.Fatal("APP_OTEL_SAMPLE_RATIO must be between 0 and 1", zap.Float64("value", ratio))
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if config.Bool(log, "APP_OTEL_INSECURE", true) {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exp, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		log.Fatal("failed to create otlp exporter", zap.String("endpoint", endpoint), zap.Error(err))
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(tp)
+	log.Info("tracing enabled", zap.String("endpoint", endpoint), zap.Float64("sample_ratio", ratio))
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return tp.Shutdown(ctx)
+		},
+	})
+	return tp
+}
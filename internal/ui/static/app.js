@@ -0,0 +1,94 @@
+// 대시보드 스크립트 : 장치 목록을 주기적으로 갱신하고, 검색어를 /api/search로, 제어 폼을 /api/control로 전송합니다.
+(function () {
+  'use strict';
+
+  var REFRESH_MS = 3000;
+  var statusEl = document.getElementById('status');
+  var tbody = document.querySelector('#devices tbody');
+  var datalist = document.getElementById('device-ids');
+  var resultEl = document.getElementById('result');
+  var hitsEl = document.getElementById('hits');
+
+  function formatValues(values) {
+    return Object.keys(values || {}).sort().map(function (k) {
+      return k + '=' + values[k];
+    }).join(', ');
+  }
+
+  function render(devices) {
+    tbody.textContent = '';
+    datalist.textContent = '';
+    devices.forEach(function (d) {
+      var tr = document.createElement('tr');
+      var id = document.createElement('td');
+      var vals = document.createElement('td');
+      var code = document.createElement('code');
+      id.textContent = d.device;
+      code.textContent = formatValues(d.values);
+      vals.appendChild(code);
+      tr.appendChild(id);
+      tr.appendChild(vals);
+      tbody.appendChild(tr);
+
+      var opt = document.createElement('option');
+      opt.value = d.device;
+      datalist.appendChild(opt);
+    });
+  }
+
+  function refresh() {
+    fetch('/api/devices', { headers: { Accept: 'application/json' } })
+      .then(function (res) { return res.json(); })
+      .then(function (devices) {
+        render(devices);
+        statusEl.textContent = 'updated ' + new Date().toLocaleTimeString();
+      })
+      .catch(function (err) { statusEl.textContent = 'error: ' + err; });
+  }
+
+  function renderHits(hits) {
+    hitsEl.textContent = '';
+    if (hits.length === 0) {
+      var empty = document.createElement('li');
+      empty.textContent = '결과 없음';
+      hitsEl.appendChild(empty);
+      return;
+    }
+    hits.forEach(function (h) {
+      var li = document.createElement('li');
+      var kind = document.createElement('span');
+      var title = document.createElement('strong');
+      var text = document.createElement('small');
+      kind.className = 'kind';
+      kind.textContent = h.kind;
+      title.textContent = h.title;
+      text.textContent = h.text;
+      li.appendChild(kind);
+      li.appendChild(title);
+      li.appendChild(text);
+      hitsEl.appendChild(li);
+    });
+  }
+
+  document.getElementById('search').addEventListener('submit', function (ev) {
+    ev.preventDefault();
+    var params = new URLSearchParams(new FormData(ev.target));
+    fetch('/api/search?' + params.toString(), { headers: { Accept: 'application/json' } })
+      .then(function (res) { return res.json(); })
+      .then(function (body) { renderHits(body.hits || []); })
+      .catch(function (err) { hitsEl.textContent = 'error: ' + err; });
+  });
+
+  document.getElementById('control').addEventListener('submit', function (ev) {
+    ev.preventDefault();
+    var params = new URLSearchParams(new FormData(ev.target));
+    fetch('/api/control?' + params.toString(), { method: 'POST', headers: { Accept: 'application/json' } })
+      .then(function (res) {
+        return res.text().then(function (body) { resultEl.textContent = res.status + ' ' + body; });
+      })
+      .catch(function (err) { resultEl.textContent = 'error: ' + err; });
+  });
+
+  refresh();
+  setInterval(refresh, REFRESH_MS);
+})();
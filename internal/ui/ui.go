@@ -0,0 +1,27 @@
+/*
+ * 내장 웹 대시보드
+ *  - static/ 디렉터리의 단일 페이지(HTML/JS/CSS)를 embed.FS로 바이너리에 포함합니다.
+ *  - 별도 프론트엔드 배포 없이 /ui 에서 장치 목록, 최신 값, 제어 폼을 바로 사용할 수 있습니다.
+ *  - 페이지는 공개 API(/api/devices, /api/control)만 호출합니다.
+ */
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+/*
+ * Handler : prefix(예: "/ui/") 아래로 정적 파일을 제공하는 핸들러
+ */
+func Handler(prefix string) http.Handler {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // 빌드 시점에 포함된 경로이므로 실패할 수 없음
+	}
+	return http.StripPrefix(prefix, http.FileServer(http.FS(sub)))
+}
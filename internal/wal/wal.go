@@ -0,0 +1,285 @@
+/*
+ * WAL : 디스크 기반 추가 전용(append-only) 세그먼트 로그
+ *  - 레코드를 세그먼트 파일(<순번 16자리>.wal) 끝에 덧붙이고, 가장 오래된 레코드부터 순서대로 꺼내 씁니다.
+ *  - 레코드 형식 : 길이(4바이트 빅엔디언) + CRC32(4바이트) + 데이터
+ *  - 세그먼트가 segmentSize를 넘으면 새 세그먼트로 넘어가며, 다 읽은 세그먼트는 지웁니다.
+ *  - 전체 크기가 maxBytes를 넘으면 가장 오래된 세그먼트부터 통째로 지웁니다. (오래된 데이터부터 포기)
+ *  - 다시 열면 기존 세그먼트는 읽기 대상으로만 쓰고 새 세그먼트에 덧붙입니다.
+ *    (종료 직전에 쓰다 만 꼬리 레코드는 CRC/길이 검사에서 걸러져 ErrCorrupt로 알려짐)
+ *  - 읽은 위치는 세그먼트 단위로만 남으므로, 재시작하면 지우지 못한 세그먼트의 레코드가 다시 전달될 수 있습니다. (소비자는 멱등이어야 함)
+ */
+package wal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 레코드 머리 크기 (길이 + CRC32)
+const headerSize = 8
+
+// ErrCorrupt : 세그먼트의 남은 부분을 읽을 수 없어 버림 (다음 Next는 다음 세그먼트부터)
+var ErrCorrupt = errors.New("wal: corrupt segment tail discarded")
+
+// ErrClosed : 닫힌 WAL
+var ErrClosed = errors.New("wal: closed")
+
+// segment : 세그먼트 파일 하나
+type segment struct {
+	seq  uint64
+	size int64
+}
+
+/*
+ * WAL 구조체
+ *  - segments : 오래된 순, 마지막이 덧붙이는 중인 세그먼트
+ *  - off      : 가장 오래된 세그먼트(segments[0])에서 다음에 읽을 위치
+ */
+type WAL struct {
+	dir         string
+	segmentSize int64
+	maxBytes    int64
+
+	mu       sync.Mutex
+	segments []segment
+	active   *os.File
+	total    int64
+	off      int64
+	pending  int // Next로 꺼냈지만 아직 Commit하지 않은 레코드 크기 (0이면 없음)
+	closed   bool
+}
+
+/*
+ * Open : dir의 WAL을 열거나 새로 만듦
+ *  - segmentSize : 세그먼트 하나의 최대 크기 (넘으면 다음 세그먼트)
+ *  - maxBytes    : 전체 최대 크기 (넘으면 오래된 세그먼트부터 지움)
+ */
+func Open(dir string, segmentSize, maxBytes int64) (*WAL, error) {
+	if segmentSize <= 0 || maxBytes < segmentSize {
+		return nil, fmt.Errorf("wal: need 0 < segment size <= max bytes")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WAL{dir: dir, segmentSize: segmentSize, maxBytes: maxBytes}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".wal") {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, ".wal"), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		w.segments = append(w.segments, segment{seq: seq, size: info.Size()})
+		w.total += info.Size()
+	}
+	sort.Slice(w.segments, func(i, j int) bool { return w.segments[i].seq < w.segments[j].seq })
+
+	next := uint64(1)
+	if n := len(w.segments); n > 0 {
+		next = w.segments[n-1].seq + 1
+	}
+	if err := w.rotate(next); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// path : 세그먼트 파일 경로
+func (w *WAL) path(seq uint64) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%016d.wal", seq))
+}
+
+// rotate : 덧붙이는 세그먼트를 새 파일로 바꿈 (호출자가 잠금을 잡고 있거나 Open 중)
+func (w *WAL) rotate(seq uint64) error {
+	f, err := os.OpenFile(w.path(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if w.active != nil {
+		if err := w.active.Close(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	w.active = f
+	w.segments = append(w.segments, segment{seq: seq})
+	return nil
+}
+
+/*
+ * Append : 레코드를 끝에 덧붙이고 디스크에 동기화
+ *  - 크기 상한을 넘으면 오래된 세그먼트를 지우고, 지운 바이트 수를 반환
+ */
+func (w *WAL) Append(rec []byte) (evicted int64, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	size := int64(headerSize + len(rec))
+	if size > w.maxBytes {
+		return 0, fmt.Errorf("wal: record of %d bytes exceeds max size %d", size, w.maxBytes)
+	}
+	last := &w.segments[len(w.segments)-1]
+	if last.size > 0 && last.size+size > w.segmentSize {
+		if err := w.rotate(last.seq + 1); err != nil {
+			return 0, err
+		}
+	}
+	for w.total+size > w.maxBytes && len(w.segments) > 1 {
+		n, err := w.dropOldest()
+		if err != nil {
+			return evicted, err
+		}
+		evicted += n
+	}
+
+	buf := make([]byte, size)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(rec)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(rec))
+	copy(buf[headerSize:], rec)
+	if _, err := w.active.Write(buf); err != nil {
+		return evicted, err
+	}
+	if err := w.active.Sync(); err != nil {
+		return evicted, err
+	}
+	w.segments[len(w.segments)-1].size += size
+	w.total += size
+	return evicted, nil
+}
+
+// dropOldest : 가장 오래된 세그먼트 삭제 (덧붙이는 중인 세그먼트는 제외, 호출자가 잠금을 잡고 있어야 함)
+func (w *WAL) dropOldest() (int64, error) {
+	s := w.segments[0]
+	if err := os.Remove(w.path(s.seq)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	w.segments = w.segments[1:]
+	w.total -= s.size
+	w.off, w.pending = 0, 0
+	return s.size, nil
+}
+
+/*
+ * Next : 가장 오래된 미처리 레코드 (Commit 전까지는 같은 레코드를 반환)
+ *  - 남은 레코드가 없으면 ok=false
+ *  - 읽을 수 없는 꼬리를 만나면 그 세그먼트를 버리고 ErrCorrupt
+ */
+func (w *WAL) Next() (rec []byte, ok bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, false, ErrClosed
+	}
+
+	for {
+		head := w.segments[0]
+		if w.off >= head.size {
+			if len(w.segments) == 1 {
+				return nil, false, nil // 덧붙이는 중인 세그먼트까지 다 읽음
+			}
+			if _, err := w.dropOldest(); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+
+		rec, err := w.read(head)
+		if err != nil {
+			if len(w.segments) > 1 {
+				_, _ = w.dropOldest()
+			} else {
+				w.off = head.size // 덧붙이는 중인 세그먼트는 지우지 않고 건너뜀
+			}
+			return nil, false, fmt.Errorf("%w: segment %d: %v", ErrCorrupt, head.seq, err)
+		}
+		w.pending = headerSize + len(rec)
+		return rec, true, nil
+	}
+}
+
+// read : 세그먼트 s의 off 위치 레코드 읽기
+func (w *WAL) read(s segment) ([]byte, error) {
+	f, err := os.Open(w.path(s.seq))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var hdr [headerSize]byte
+	if _, err := f.ReadAt(hdr[:], w.off); err != nil {
+		return nil, err
+	}
+	n := int64(binary.BigEndian.Uint32(hdr[0:4]))
+	if w.off+headerSize+n > s.size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	rec := make([]byte, n)
+	if _, err := f.ReadAt(rec, w.off+headerSize); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(rec) != binary.BigEndian.Uint32(hdr[4:8]) {
+		return nil, fmt.Errorf("checksum mismatch at offset %d", w.off)
+	}
+	return rec, nil
+}
+
+/*
+ * Commit : Next로 꺼낸 레코드를 처리 완료로 표시
+ *  - 그 사이에 세그먼트가 밀려났으면(크기 상한) 아무것도 하지 않음
+ */
+func (w *WAL) Commit() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.off += int64(w.pending)
+	w.pending = 0
+}
+
+// Empty : 처리할 레코드가 남아 있지 않은지
+func (w *WAL) Empty() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.segments) == 1 && w.off >= w.segments[0].size
+}
+
+// Size : 디스크에 남아 있는 세그먼트 전체 크기 (바이트)
+func (w *WAL) Size() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.total
+}
+
+/*
+ * Close : 덧붙이는 세그먼트를 닫음 (남은 레코드는 다음 Open에서 이어서 읽음)
+ */
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.active.Close()
+}
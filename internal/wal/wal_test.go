@@ -0,0 +1,203 @@
+package wal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// drainAll : 남은 레코드를 모두 꺼내 Commit (ErrCorrupt는 건너뛰고 계속)
+func drainAll(t *testing.T, w *WAL) []string {
+	t.Helper()
+	var out []string
+	for {
+		rec, ok, err := w.Next()
+		if errors.Is(err, ErrCorrupt) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if !ok {
+			return out
+		}
+		out = append(out, string(rec))
+		w.Commit()
+	}
+}
+
+func appendAll(t *testing.T, w *WAL, recs ...string) {
+	t.Helper()
+	for _, r := range recs {
+		if _, err := w.Append([]byte(r)); err != nil {
+			t.Fatalf("Append(%q): %v", r, err)
+		}
+	}
+}
+
+func TestReplayAfterReopen(t *testing.T) {
+	tests := []struct {
+		name        string
+		segmentSize int64
+		before      []string // 닫기 전에 덧붙인 레코드
+		consumed    int      // 닫기 전에 꺼내 Commit한 수
+		after       []string // 다시 연 뒤 덧붙인 레코드
+		want        []string // 다시 연 뒤 꺼낸 레코드
+	}{
+		{
+			name:        "unread records survive restart in order",
+			segmentSize: 1 << 20,
+			before:      []string{"a", "b", "c"},
+			after:       []string{"d"},
+			want:        []string{"a", "b", "c", "d"},
+		},
+		{
+			// 읽은 위치는 세그먼트 단위로만 남으므로 같은 세그먼트의 레코드는 다시 전달됨
+			name:        "partially read segment is redelivered",
+			segmentSize: 1 << 20,
+			before:      []string{"a", "b", "c"},
+			consumed:    2,
+			want:        []string{"a", "b", "c"},
+		},
+		{
+			// 다 읽은 세그먼트는 다음 Next에서 지워지므로 마지막으로 Commit한 세그먼트만 다시 전달됨
+			name:        "only the last committed segment is redelivered",
+			segmentSize: headerSize + 1,
+			before:      []string{"a", "b", "c"},
+			consumed:    2,
+			after:       []string{"d"},
+			want:        []string{"b", "c", "d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := Open(dir, tt.segmentSize, 1<<20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			appendAll(t, w, tt.before...)
+			for i := 0; i < tt.consumed; i++ {
+				if _, ok, err := w.Next(); !ok || err != nil {
+					t.Fatalf("Next #%d: ok=%v err=%v", i, ok, err)
+				}
+				w.Commit()
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			w, err = Open(dir, tt.segmentSize, 1<<20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			appendAll(t, w, tt.after...)
+			if got := drainAll(t, w); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("replayed %v, want %v", got, tt.want)
+			}
+			if !w.Empty() {
+				t.Fatal("WAL not empty after draining")
+			}
+		})
+	}
+}
+
+func TestNextWithoutCommitRepeats(t *testing.T) {
+	w, err := Open(t.TempDir(), 1<<20, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	appendAll(t, w, "a", "b")
+
+	for i := 0; i < 2; i++ {
+		rec, ok, err := w.Next()
+		if err != nil || !ok || string(rec) != "a" {
+			t.Fatalf("Next #%d = %q, %v, %v; want a", i, rec, ok, err)
+		}
+	}
+	w.Commit()
+	if rec, _, _ := w.Next(); string(rec) != "b" {
+		t.Fatalf("Next after Commit = %q, want b", rec)
+	}
+}
+
+func TestEvictOldestSegments(t *testing.T) {
+	// 레코드 하나(머리 8바이트 + 1바이트)가 세그먼트 하나, 전체는 세그먼트 3개까지
+	const recSize = headerSize + 1
+	w, err := Open(t.TempDir(), recSize, 3*recSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	var evicted int64
+	for _, r := range []string{"a", "b", "c", "d", "e"} {
+		n, err := w.Append([]byte(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		evicted += n
+	}
+	if evicted != 2*recSize {
+		t.Fatalf("evicted %d bytes, want %d", evicted, 2*recSize)
+	}
+	if got := drainAll(t, w); fmt.Sprint(got) != "[c d e]" {
+		t.Fatalf("replayed %v, want [c d e]", got)
+	}
+	if _, err := w.Append(make([]byte, 3*recSize)); err == nil {
+		t.Fatal("record larger than max size was accepted")
+	}
+}
+
+func TestCorruptTailIsSkipped(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+	}{
+		{"truncated record", func(data []byte) []byte { return data[:len(data)-1] }},
+		{"checksum mismatch", func(data []byte) []byte { data[len(data)-1] ^= 0xff; return data }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := Open(dir, 1<<20, 1<<20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			appendAll(t, w, "a", "bb")
+			w.Close()
+
+			path := filepath.Join(dir, fmt.Sprintf("%016d.wal", 1))
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, tt.corrupt(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			w, err = Open(dir, 1<<20, 1<<20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			appendAll(t, w, "c")
+
+			rec, ok, err := w.Next()
+			if err != nil || !ok || string(rec) != "a" {
+				t.Fatalf("first Next = %q, %v, %v; want a", rec, ok, err)
+			}
+			w.Commit()
+			if _, _, err := w.Next(); !errors.Is(err, ErrCorrupt) {
+				t.Fatalf("Next on corrupt tail: err=%v, want ErrCorrupt", err)
+			}
+			if got := drainAll(t, w); fmt.Sprint(got) != "[c]" {
+				t.Fatalf("after corrupt segment got %v, want [c]", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID : 16진수 16자리 임의 ID 생성
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,73 @@
+/*
+ * 전달 대상 제한 : 웹훅 URL로 내부 주소(루프백, 사설망, 링크 로컬, 클라우드 메타데이터)를 찌르지 못하게 막습니다.
+ *  - 등록 시 : IP 리터럴과 localhost 호스트를 거절 (validate)
+ *  - 전달 시 : DNS 해석이 끝난 실제 연결 주소를 다이얼러 Control 훅에서 검사 (등록 후 DNS가 바뀌어도 막힘)
+ *  - 리다이렉트는 따라가지 않음 (3xx 응답은 실패로 보고 재시도)
+ *  - APP_WEBHOOK_ALLOW_PRIVATE=true이면 제한을 끕니다. (사내망 수신자용, 환경변수 프록시(HTTP_PROXY)도 이때만 씀)
+ */
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errBlockedTarget : 내부 주소로 가는 전달
+var errBlockedTarget = errors.New("webhook target is a loopback, private or link-local address")
+
+// 공유 주소 공간(CGNAT) 100.64.0.0/10 : net.IP.IsPrivate에 포함되지 않음
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// blockedIP : 웹훅이 갈 수 없는 주소인지 (루프백, 사설, 링크 로컬(메타데이터 169.254.169.254 포함), 미지정, 멀티캐스트, CGNAT)
+func blockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// checkHost : 등록 시 호스트 검사 (IP 리터럴과 localhost만, 이름은 전달 시 해석된 주소로 검사)
+func checkHost(host string) error {
+	h := strings.ToLower(strings.TrimSuffix(host, "."))
+	if h == "localhost" || strings.HasSuffix(h, ".localhost") {
+		return errBlockedTarget
+	}
+	if ip := net.ParseIP(h); ip != nil && blockedIP(ip) {
+		return errBlockedTarget
+	}
+	return nil
+}
+
+/*
+ * newClient : 전달용 HTTP 클라이언트
+ *  - allowPrivate가 false이면 연결 직전에 주소를 검사하고 프록시를 쓰지 않음 (프록시 뒤의 대상은 검사할 수 없으므로)
+ *  - 어느 경우든 리다이렉트는 따라가지 않음
+ */
+func newClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || blockedIP(ip) {
+				return fmt.Errorf("%w: %s", errBlockedTarget, host)
+			}
+			return nil
+		}
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
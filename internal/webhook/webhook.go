@@ -0,0 +1,413 @@
+/*
+ * Manager : 웹훅 구독 관리 및 전달
+ *  - 클라이언트가 콜백 URL, 이벤트 종류 필터, 공유 비밀(secret)을 등록합니다. (/api/webhooks CRUD)
+ *  - 버스 이벤트(데이터 수집), 명령 완료, 그룹 경보 변화, 운영자 오버라이드 시작/종료/만료가 생기면 필터에 맞는 구독마다 전달 작업을 큐에 넣고,
+ *    전달 워커가 JSON 본문을 POST 합니다.
+ *  - 서명 : X-Webhook-Signature: sha256=<hex(HMAC-SHA256(secret, "<timestamp>.<body>"))>
+ *           수신 측은 X-Webhook-Timestamp와 본문으로 같은 값을 계산해 위조·재전송을 거를 수 있습니다.
+ *  - 실패(네트워크 오류, 2xx가 아닌 응답) 시 지수 백오프로 재시도하고, 횟수를 넘기면 드롭으로 기록합니다.
+ */
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"  // Fx 프레임워크를 통한 라이프사이클 관리
+	"go.uber.org/zap" // 로깅 도구
+
+	"generic-api-scaffold/internal/bus"        // 데이터 수집 이벤트
+	"generic-api-scaffold/internal/config"     // 환경변수 읽기 도우미
+	"generic-api-scaffold/internal/control"    // 명령 완료 알림
+	"generic-api-scaffold/internal/drops"      // 전달 실패/큐 초과 기록
+	"generic-api-scaffold/internal/group"      // 그룹 경보 알림
+	"generic-api-scaffold/internal/supervisor" // 전달 워커 감독
+)
+
+// 웹훅으로 전달되는 이벤트 종류
+const (
+	EventDataCollected    = "data.collected"
+	EventCommandCompleted = "command.completed"
+	EventAlertFiring      = "alert.firing"
+	EventAlertResolved    = "alert.resolved"
+	EventOverrideStarted  = "override.started"
+	EventOverrideEnded    = "override.ended"
+	EventOverrideExpired  = "override.expired"
+)
+
+// 구독 필터에 쓸 수 있는 이벤트 종류 ("*"는 전체)
+var knownEvents = map[string]bool{
+	EventDataCollected: true, EventCommandCompleted: true, EventAlertFiring: true, EventAlertResolved: true,
+	EventOverrideStarted: true, EventOverrideEnded: true, EventOverrideExpired: true, "*": true,
+}
+
+// ErrNotFound : 존재하지 않는 구독
+var ErrNotFound = errors.New("webhook not found")
+
+/*
+ * Subscription : 웹훅 구독 하나
+ *  - Events : 받을 이벤트 종류 목록 ("*" 포함 시 전체)
+ *  - Secret : 서명용 공유 비밀 (응답에는 포함하지 않음)
+ */
+type Subscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// matches : 이벤트 종류가 구독 필터에 맞는지
+func (s Subscription) matches(eventType string) bool {
+	for _, e := range s.Events {
+		if e == "*" || e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Spec : 구독 생성/수정 요청 (Secret이 비어 있으면 수정 시 기존 값 유지)
+type Spec struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// Event : 웹훅 본문
+type Event struct {
+	ID   string      `json:"id"`
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// delivery : 전달 작업 하나 (구독 × 이벤트)
+type delivery struct {
+	subID   string
+	body    []byte
+	event   Event
+	attempt int
+}
+
+// Manager 구조체
+type Manager struct {
+	log    *zap.Logger
+	drops  *drops.Recorder
+	client *http.Client
+
+	allowPrivate bool // 내부 주소로의 전달 허용 (APP_WEBHOOK_ALLOW_PRIVATE, target.go)
+
+	queue       chan delivery
+	workers     int
+	maxAttempts int
+	backoffMin  time.Duration
+	backoffMax  time.Duration
+
+	mu   sync.RWMutex
+	subs map[string]*Subscription
+}
+
+/*
+ * NewManager : fx가 호출하는 Manager 생성자
+ *  - APP_WEBHOOK_QUEUE        : 전달 대기 큐 크기 (기본 1000, 가득 차면 드롭)
+ *  - APP_WEBHOOK_WORKERS      : 전달 워커 수 (기본 2)
+ *  - APP_WEBHOOK_TIMEOUT      : 전달 요청 하나의 타임아웃 (기본 5s)
+ *  - APP_WEBHOOK_MAX_ATTEMPTS : 최대 시도 횟수 (기본 5)
+ *  - APP_WEBHOOK_BACKOFF_MIN  : 첫 재시도 대기 (기본 1s)
+ *  - APP_WEBHOOK_BACKOFF_MAX  : 재시도 대기 상한 (기본 1m)
+ *  - APP_WEBHOOK_RATE_LIMIT   : 수집 이벤트를 웹훅으로 넘기는 초당 최대 수 (기본 10, 0이면 제한 없음)
+ *  - APP_WEBHOOK_COALESCE     : 제한에 걸려 기다리는 동안 같은 장치의 수집 이벤트는 최신 것만 남김 (기본 true)
+ *  - APP_WEBHOOK_ALLOW_PRIVATE : 루프백·사설망·링크 로컬 주소로의 전달 허용 (기본 false, target.go)
+ *  - OnStart : 전달 워커를 Supervisor 감독 하에 시작
+ */
+func NewManager(lc fx.Lifecycle, log *zap.Logger, b *bus.EventBus, d *control.Dispatcher, ov *control.OverrideManager, dr *drops.Recorder, sv *supervisor.Supervisor) *Manager {
+	allowPrivate := config.Bool(log, "APP_WEBHOOK_ALLOW_PRIVATE", false)
+	m := &Manager{
+		log:          log,
+		drops:        dr,
+		client:       newClient(config.Duration(log, "APP_WEBHOOK_TIMEOUT", 5*time.Second), allowPrivate),
+		allowPrivate: allowPrivate,
+		queue:        make(chan delivery, config.Int(log, "APP_WEBHOOK_QUEUE", 1000)),
+		workers:      config.Int(log, "APP_WEBHOOK_WORKERS", 2),
+		maxAttempts:  config.Int(log, "APP_WEBHOOK_MAX_ATTEMPTS", 5),
+		backoffMin:   config.Duration(log, "APP_WEBHOOK_BACKOFF_MIN", time.Second),
+		backoffMax:   config.Duration(log, "APP_WEBHOOK_BACKOFF_MAX", time.Minute),
+		subs:         make(map[string]*Subscription),
+	}
+	if m.workers < 1 || m.maxAttempts < 1 {
+		log.Fatal("APP_WEBHOOK_WORKERS and APP_WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+
+	// 이벤트 공급원 연결 (웹훅은 최선 노력 구독자이므로 낮은 우선순위)
+	// 많은 장치가 한꺼번에 보고해도 외부 수신자가 몰리지 않도록 속도를 제한하고, 밀린 동안에는 장치별 최신 값만 전달
+	opts := []bus.SubscribeOption{bus.WithName("webhook"), bus.WithPriority(bus.PriorityLow)}
+	if rate := config.Float(log, "APP_WEBHOOK_RATE_LIMIT", 10); rate > 0 {
+		opts = append(opts, bus.WithRateLimit(rate, int(math.Ceil(rate))))
+	}
+	if config.Bool(log, "APP_WEBHOOK_COALESCE", true) {
+		opts = append(opts, bus.WithCoalesce())
+	}
+	b.Subscribe(func(_ context.Context, e bus.DataCollectedEvent) {
+		m.Notify(EventDataCollected, e.DeviceID, map[string]interface{}{"device": e.DeviceID, "values": e.Values})
+	}, opts...)
+	d.OnComplete(func(c control.Command) {
+		m.Notify(EventCommandCompleted, c.DeviceID, c)
+	})
+	ov.OnChange(func(typ control.OverrideEventType, o control.Override) {
+		m.Notify("override."+string(typ), o.DeviceID, o)
+	})
+	// 경보 토픽(alert.firing, alert.resolved)은 웹훅 이벤트 이름과 같으므로 와일드카드로 한 번에 구독
+	b.SubscribeTopic("alert.*", func(_ context.Context, msg bus.Message) {
+		if e, ok := msg.Payload.(group.AlertChanged); ok {
+			m.Notify(msg.Topic, "", e.Alert)
+		}
+	}, bus.WithName("webhook"), bus.WithPriority(bus.PriorityLow))
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			for i := 0; i < m.workers; i++ {
+				if err := sv.Go(fmt.Sprintf("webhook-worker-%d", i), m.work); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	return m
+}
+
+/*
+ * Notify : 이벤트를 필터에 맞는 모든 구독의 전달 큐에 넣음
+ *  - 큐가 가득 차면 기다리지 않고 드롭으로 기록 (이벤트 발행 쪽을 막지 않기 위함)
+ */
+func (m *Manager) Notify(eventType, deviceID string, data interface{}) {
+	m.mu.RLock()
+	var targets []string
+	for id, s := range m.subs {
+		if s.matches(eventType) {
+			targets = append(targets, id)
+		}
+	}
+	m.mu.RUnlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	ev := Event{ID: newID(), Type: eventType, Time: time.Now().UTC(), Data: data}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		m.log.Error("webhook event encode failed", zap.String("type", eventType), zap.Error(err))
+		return
+	}
+	for _, id := range targets {
+		m.enqueue(delivery{subID: id, body: body, event: ev, attempt: 1}, deviceID)
+	}
+}
+
+// enqueue : 전달 작업을 큐에 넣음 (가득 차면 드롭 기록)
+func (m *Manager) enqueue(dl delivery, deviceID string) {
+	select {
+	case m.queue <- dl:
+	default:
+		m.drops.Record("webhook", drops.ReasonBackpressure, deviceID,
+			fmt.Sprintf("queue full, dropped %s for webhook %s", dl.event.Type, dl.subID))
+	}
+}
+
+/*
+ * work : 전달 워커 (큐에서 작업을 꺼내 전달, 실패 시 백오프 후 재투입)
+ */
+func (m *Manager) work(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case dl := <-m.queue:
+			m.deliver(ctx, dl)
+		}
+	}
+}
+
+// deliver : 전달 한 번 시도, 실패하면 재시도 예약 또는 드롭
+func (m *Manager) deliver(ctx context.Context, dl delivery) {
+	m.mu.RLock()
+	sub, ok := m.subs[dl.subID]
+	var s Subscription
+	if ok {
+		s = *sub
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return // 전달 대기 중 구독이 삭제됨
+	}
+
+	err := m.post(ctx, s, dl)
+	if err == nil {
+		return
+	}
+	if dl.attempt >= m.maxAttempts {
+		m.drops.Record("webhook", drops.ReasonWriteFailed, "",
+			fmt.Sprintf("webhook %s: %s %s gave up after %d attempts: %v", s.ID, dl.event.Type, dl.event.ID, dl.attempt, err))
+		return
+	}
+
+	wait := m.backoffMin << (dl.attempt - 1)
+	if wait <= 0 || wait > m.backoffMax {
+		wait = m.backoffMax
+	}
+	m.log.Warn("webhook delivery failed, retrying",
+		zap.String("webhook", s.ID), zap.String("event", dl.event.ID), zap.Int("attempt", dl.attempt),
+		zap.Duration("backoff", wait), zap.Error(err))
+	dl.attempt++
+	time.AfterFunc(wait, func() {
+		if ctx.Err() == nil {
+			m.enqueue(dl, "")
+		}
+	})
+}
+
+// post : 서명된 요청 한 번 전송 (2xx가 아니면 에러)
+func (m *Manager) post(ctx context.Context, s Subscription, dl delivery) error {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(dl.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", s.ID)
+	req.Header.Set("X-Webhook-Event", dl.event.Type)
+	req.Header.Set("X-Webhook-Delivery", dl.event.ID)
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(dl.attempt))
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", "sha256="+Sign(s.Secret, ts, dl.body))
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+/*
+ * Sign : 웹훅 서명 계산 (hex(HMAC-SHA256(secret, "<timestamp>.<body>")))
+ */
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ===== 구독 CRUD =====
+
+// validate : 구독 요청 검사 (requireSecret이면 secret 필수, allowPrivate가 아니면 내부 주소 거절)
+func (sp Spec) validate(requireSecret, allowPrivate bool) error {
+	u, err := url.Parse(sp.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	if !allowPrivate {
+		if err := checkHost(u.Hostname()); err != nil {
+			return err
+		}
+	}
+	if len(sp.Events) == 0 {
+		return fmt.Errorf("events must not be empty")
+	}
+	for _, e := range sp.Events {
+		if !knownEvents[e] {
+			return fmt.Errorf("unknown event type %q", e)
+		}
+	}
+	if requireSecret && sp.Secret == "" {
+		return fmt.Errorf("secret is required")
+	}
+	return nil
+}
+
+// Create : 구독 등록
+func (m *Manager) Create(sp Spec) (Subscription, error) {
+	if err := sp.validate(true, m.allowPrivate); err != nil {
+		return Subscription{}, err
+	}
+	now := time.Now()
+	s := &Subscription{ID: newID(), URL: sp.URL, Events: sp.Events, Secret: sp.Secret, CreatedAt: now, UpdatedAt: now}
+
+	m.mu.Lock()
+	m.subs[s.ID] = s
+	m.mu.Unlock()
+	m.log.Info("webhook registered", zap.String("id", s.ID), zap.String("url", s.URL), zap.Strings("events", s.Events))
+	return *s, nil
+}
+
+// Update : 구독 수정 (secret이 비어 있으면 기존 값 유지)
+func (m *Manager) Update(id string, sp Spec) (Subscription, error) {
+	if err := sp.validate(false, m.allowPrivate); err != nil {
+		return Subscription{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.subs[id]
+	if !ok {
+		return Subscription{}, ErrNotFound
+	}
+	s.URL, s.Events, s.UpdatedAt = sp.URL, sp.Events, time.Now()
+	if sp.Secret != "" {
+		s.Secret = sp.Secret
+	}
+	return *s, nil
+}
+
+// Get : 구독 조회
+func (m *Manager) Get(id string) (Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.subs[id]
+	if !ok {
+		return Subscription{}, ErrNotFound
+	}
+	return *s, nil
+}
+
+// List : 구독 목록 (등록 순)
+func (m *Manager) List() []Subscription {
+	m.mu.RLock()
+	out := make([]Subscription, 0, len(m.subs))
+	for _, s := range m.subs {
+		out = append(out, *s)
+	}
+	m.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Delete : 구독 삭제 (대기 중인 전달은 버려짐)
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.subs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.subs, id)
+	return nil
+}